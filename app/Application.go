@@ -13,21 +13,51 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/johannes-kuhfuss/pbreact/config"
 	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
 	"github.com/johannes-kuhfuss/pbreact/handler"
 	"github.com/johannes-kuhfuss/pbreact/repository"
 	"github.com/johannes-kuhfuss/pbreact/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
 	"github.com/johannes-kuhfuss/services_utils/logger"
 )
 
 var (
-	cfg          config.AppConfig
-	pbApiRepo    domain.PbApiRepository
-	pbApiService service.DefaultPbApiService
-	pbApiHandler handler.WebHookHandler
-	server       http.Server
-	appEnd       chan os.Signal
-	ctx          context.Context
-	cancel       context.CancelFunc
+	cfg                  config.AppConfig
+	pbApiRepo            domain.PbApiRepository
+	pbApiService         service.DefaultPbApiService
+	pbEventService       service.DefaultPbEventService
+	pbHierarchyService   service.DefaultPbHierarchyService
+	pbStatusService      service.DefaultPbStatusService
+	pbFeedbackService    service.DefaultPbFeedbackService
+	pbConnService        service.DefaultPbConnectionService
+	pbActionService      service.DefaultPbActionService
+	pbJiraService        service.DefaultPbJiraService
+	pbTrackerService     *service.DefaultPbTrackerService
+	pbStatusSyncService  *service.DefaultPbStatusSyncService
+	emailImportService   *service.DefaultEmailImportService
+	pbReconcileService   *service.DefaultPbReconcileService
+	featureExportService service.DefaultFeatureExportService
+	pbRoadmapService     service.DefaultPbRoadmapService
+	pbSlipService        service.DefaultPbSlipService
+	pbFlowService        service.DefaultPbFlowService
+	pbChangelogService   service.DefaultPbChangelogService
+	pbDigestService      service.DefaultPbDigestService
+	pbNotifyService      service.DefaultPbNotifyService
+	pbChatService        service.DefaultPbChatService
+	pbAutomationService  service.DefaultPbAutomationService
+	pbMirrorService      *service.DefaultPbMirrorService
+	pbApiHandler         handler.WebHookHandler
+	feedbackHandler      handler.FeedbackHandler
+	pluginHandler        handler.PluginHandler
+	trackerHandler       *handler.TrackerHandler
+	exportHandler        handler.ExportHandler
+	roadmapHandler       handler.RoadmapHandler
+	reportHandler        handler.ReportHandler
+	changelogHandler     handler.ChangelogHandler
+	server               http.Server
+	appEnd               chan os.Signal
+	ctx                  context.Context
+	cancel               context.CancelFunc
 )
 
 func StartApp() {
@@ -40,9 +70,40 @@ func StartApp() {
 	initRouter()
 	initServer()
 	wireApp()
+	validateStatuses()
 	mapUrls()
 	RegisterForOsSignals()
-	go RegisterForNotifications()
+	if cfg.Polling.Enabled {
+		logger.Info("Running in polling mode. Webhooks and plugin integration are disabled")
+	} else {
+		go RegisterForNotifications()
+	}
+	go pbEventService.ProcessEvents()
+	go pbFeedbackService.ProcessFeedback()
+	go pbNotifyService.ProcessMails()
+	go pbChatService.ProcessPosts()
+	if emailImportService != nil {
+		go emailImportService.WatchDirectory()
+	}
+	if pbReconcileService != nil {
+		go pbReconcileService.ScheduleReconcile()
+	}
+	if cfg.Slips.CheckInterval > 0 {
+		go pbSlipService.ScheduleChecks()
+	}
+	if cfg.Changelog.Window != "" {
+		go pbChangelogService.SchedulePublishing()
+	}
+	if cfg.Digest.Schedule != "" {
+		go pbDigestService.ScheduleDigests()
+	}
+	if cfg.Automation.RulesFile != "" && cfg.Automation.CheckInterval > 0 {
+		go pbAutomationService.ScheduleChecks()
+	}
+	if pbMirrorService != nil && cfg.Mirror.SyncInterval > 0 {
+		go pbMirrorService.ScheduleSync()
+	}
+	go refreshHierarchy()
 	go startServer()
 
 	<-appEnd
@@ -77,9 +138,8 @@ func initServer() {
 		MinVersion:               tls.VersionTLS12,
 		CurvePreferences:         []tls.CurveID{tls.CurveP521, tls.CurveP384, tls.CurveP256},
 	}
-	listenAddr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.TlsPort)
 	server = http.Server{
-		Addr:              listenAddr,
+		Addr:              listenAddr(),
 		Handler:           cfg.RunTime.Router,
 		TLSConfig:         &tlsConfig,
 		ReadTimeout:       5 * time.Second,
@@ -88,19 +148,154 @@ func initServer() {
 		IdleTimeout:       120 * time.Second,
 		MaxHeaderBytes:    0,
 		TLSNextProto:      make(map[string]func(*http.Server, *tls.Conn, http.Handler)),
+		ConnContext:       handler.ConnContext,
 	}
 }
 
 func wireApp() {
 	pbApiRepo = repository.NewPbApiRepository(&cfg)
 	pbApiService = service.NewPbApiService(&cfg, pbApiRepo)
-	pbApiHandler = handler.NewWebHookHandler(&cfg, pbApiService)
+	pbEventService = service.NewPbEventService(&cfg, pbApiRepo)
+	pbHierarchyService = service.NewPbHierarchyService(&cfg, pbApiRepo)
+	pbStatusService = service.NewPbStatusService(&cfg, pbApiRepo)
+	pbEventService.AddHandler(pbHierarchyService)
+	pbEventService.AddHandler(pbStatusService)
+	pbRoadmapService = service.NewPbRoadmapService(&cfg, pbApiRepo, pbHierarchyService)
+	pbEventService.AddHandler(pbRoadmapService)
+	journal := repository.NewFeatureJournalRepository(cfg.Journal.File)
+	webhooks := repository.NewHttpWebhookRepository(10 * time.Second)
+	pbSlipService = service.NewPbSlipService(&cfg, pbApiRepo, journal, webhooks)
+	pbEventService.AddHandler(pbSlipService)
+	pbFlowService = service.NewPbFlowService(&cfg, journal, pbHierarchyService)
+	pbEventService.AddHandler(pbFlowService)
+	var published domain.ImportLedgerRepository
+	if cfg.Changelog.Window != "" {
+		ledger, err := repository.NewImportLedgerRepository(cfg.Changelog.LedgerFile)
+		if err != nil {
+			panic(err)
+		}
+		published = ledger
+	}
+	pbChangelogService = service.NewPbChangelogService(&cfg, pbApiRepo, journal, pbHierarchyService, published, webhooks)
+	mails := repository.NewSmtpMailRepository(&cfg)
+	var err api_error.ApiErr
+	pbDigestService, err = service.NewPbDigestService(&cfg, journal, mails, webhooks)
+	if err != nil {
+		panic(err)
+	}
+	pbEventService.AddHandler(pbDigestService)
+	pbNotifyService, err = service.NewPbNotifyService(&cfg, pbHierarchyService, mails, repository.NewFeatureCacheRepository(cfg.Notify.SeenFile))
+	if err != nil {
+		panic(err)
+	}
+	pbEventService.AddHandler(pbNotifyService)
+	pbChatService, err = service.NewPbChatService(&cfg, pbHierarchyService, webhooks, repository.NewFeatureCacheRepository(cfg.Chat.SeenFile), repository.NewChatThreadRepository(cfg.Chat.ThreadFile))
+	if err != nil {
+		panic(err)
+	}
+	pbEventService.AddHandler(pbChatService)
+	pbAutomationService, err = service.NewPbAutomationService(&cfg, pbApiRepo, journal, pbFlowService, pbHierarchyService, pbStatusService, repository.NewFeatureCacheRepository(cfg.Automation.SeenFile))
+	if err != nil {
+		panic(err)
+	}
+	pbEventService.AddHandler(pbAutomationService)
+	pbJiraService = service.NewPbJiraService(&cfg, pbApiRepo)
+	if cfg.Jira.EnrichEvents {
+		pbEventService.AddEnricher(pbJiraService)
+	}
+	pbFeedbackService = service.NewPbFeedbackService(&cfg, pbApiRepo)
+	pbConnService, err = service.NewPbConnectionService(&cfg, pbApiRepo, repository.NewConnectionRepository(cfg.PluginIntegration.ConnectionFile))
+	if err != nil {
+		panic(err)
+	}
+	pbActionService = service.NewPbActionService(&cfg, pbConnService)
+	if cfg.Tracker.Type != "" {
+		wireTracker()
+	}
+	pbApiHandler = handler.NewWebHookHandler(&cfg, pbApiService, pbEventService)
+	feedbackHandler = handler.NewFeedbackHandler(&cfg, pbFeedbackService)
+	pluginHandler = handler.NewPluginHandler(&cfg, pbActionService)
+	featureExportService = service.NewFeatureExportService(&cfg, pbApiRepo)
+	exportHandler = handler.NewExportHandler(&cfg, featureExportService)
+	roadmapHandler = handler.NewRoadmapHandler(&cfg, pbRoadmapService)
+	reportHandler = handler.NewReportHandler(&cfg, pbSlipService, pbFlowService)
+	changelogHandler = handler.NewChangelogHandler(&cfg, pbChangelogService)
+	if cfg.EmailImport.WatchDir != "" {
+		ledger, err := repository.NewImportLedgerRepository(cfg.EmailImport.LedgerFile)
+		if err != nil {
+			panic(err)
+		}
+		importService := service.NewEmailImportService(&cfg, pbApiRepo, ledger)
+		emailImportService = &importService
+	}
+	if cfg.Reconcile.Interval > 0 || cfg.Polling.Enabled {
+		states := repository.NewFeatureStateRepository(cfg.Reconcile.StateFile)
+		reconcileService := service.NewPbReconcileService(&cfg, pbApiRepo, states, pbEventService)
+		pbReconcileService = &reconcileService
+		pbEventService.AddHandler(reconcileService)
+	}
+	if cfg.Mirror.Dir != "" {
+		mirrorService, err := service.NewPbMirrorService(&cfg, pbApiRepo, pbHierarchyService, repository.NewExecGitRepository(&cfg))
+		if err != nil {
+			panic(err)
+		}
+		pbMirrorService = &mirrorService
+		pbEventService.AddHandler(mirrorService)
+	}
+}
+
+func wireTracker() {
+	var tracker domain.IssueTrackerRepository
+	switch cfg.Tracker.Type {
+	case "rest":
+		restTracker, err := repository.NewRestTrackerRepository(&cfg)
+		if err != nil {
+			panic(err)
+		}
+		tracker = restTracker
+	case "memory":
+		tracker = repository.NewMemoryTrackerRepository()
+	default:
+		panic(fmt.Sprintf("Unknown tracker type %v", cfg.Tracker.Type))
+	}
+	trackerService, err := service.NewPbTrackerService(&cfg, pbApiRepo, tracker, repository.NewIssueLinkRepository(cfg.Tracker.LinkFile))
+	if err != nil {
+		panic(err)
+	}
+	pbTrackerService = &trackerService
+	pbActionService.AddHandler(dto.TriggerPush, service.ActionFunc{Handle: trackerService.PushFeature, Async: true})
+	pbActionService.AddHandler(dto.TriggerUnlink, service.ActionFunc{Handle: trackerService.UnlinkFeature})
+	pbActionService.AddHandler(dto.TriggerDismiss, service.ActionFunc{Handle: trackerService.DismissFeature})
+	if len(cfg.StatusSync.Mapping) > 0 {
+		syncService, err := service.NewPbStatusSyncService(&cfg, pbApiRepo, tracker, trackerService, pbStatusService)
+		if err != nil {
+			panic(err)
+		}
+		pbStatusSyncService = &syncService
+		pbEventService.AddHandler(syncService)
+		th := handler.NewTrackerHandler(&cfg, syncService)
+		trackerHandler = &th
+	}
 }
 
 func mapUrls() {
 	cfg.RunTime.Router.GET("/ping", handler.Ping)
-	cfg.RunTime.Router.GET("/pbwebhook", pbApiHandler.PbWhSubscription)
-	cfg.RunTime.Router.POST("/pbwebhook", pbApiHandler.PbWhEvents)
+	cfg.RunTime.Router.POST("/feedback", feedbackHandler.PostFeedback)
+	cfg.RunTime.Router.GET("/feedback/:id", feedbackHandler.GetFeedbackStatus)
+	cfg.RunTime.Router.GET("/export/features", exportHandler.ExportFeatures)
+	cfg.RunTime.Router.GET("/roadmap.ics", roadmapHandler.GetCalendar)
+	cfg.RunTime.Router.GET("/reports/slips", reportHandler.GetSlipReport)
+	cfg.RunTime.Router.GET("/reports/flow", reportHandler.GetFlowReport)
+	cfg.RunTime.Router.GET("/changelog", changelogHandler.GetChangelog)
+	if !cfg.Polling.Enabled {
+		cfg.RunTime.Router.GET("/pbwebhook", pbApiHandler.PbWhSubscription)
+		cfg.RunTime.Router.POST("/pbwebhook", pbApiHandler.PbWhEvents)
+		cfg.RunTime.Router.GET("/pbplugin", pluginHandler.PbActionProbe)
+		cfg.RunTime.Router.POST("/pbplugin", pluginHandler.PbActionNotification)
+	}
+	if trackerHandler != nil {
+		cfg.RunTime.Router.POST("/trackerwebhook", trackerHandler.TrackerEvents)
+	}
 }
 
 func RegisterForOsSignals() {
@@ -115,12 +310,66 @@ func RegisterForNotifications() {
 	if err != nil {
 		panic(err)
 	}
+	if cfg.PluginIntegration.Type != "" {
+		logger.Info("Registering plugin integration")
+		_, err = pbApiService.RegisterPluginIntegration()
+		if err != nil {
+			logger.Error("Could not register plugin integration", err)
+			return
+		}
+		err = pbConnService.SyncConnections()
+		if err != nil {
+			logger.Error("Could not sync plugin integration connections", err)
+		}
+	}
+}
+
+func validateStatuses() {
+	names := configuredStatusNames()
+	if len(names) == 0 {
+		return
+	}
+	logger.Info("Validating configured feature status names")
+	err := pbStatusService.ValidateStatusNames(names)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func configuredStatusNames() []string {
+	names := []string{}
+	names = append(names, cfg.Statuses.Done...)
+	if pbStatusSyncService != nil {
+		names = append(names, pbStatusSyncService.MappedStatusNames()...)
+	}
+	names = append(names, pbAutomationService.StatusNames()...)
+	return names
+}
+
+func refreshHierarchy() {
+	err := pbHierarchyService.RefreshTree()
+	if err != nil {
+		logger.Error("Could not build initial hierarchy", err)
+	}
+}
+
+// listenAddr uses the plain http port in polling mode, as nothing outside needs to reach pbreact then
+func listenAddr() string {
+	if cfg.Polling.Enabled {
+		return fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
+	}
+	return fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.TlsPort)
 }
 
 func startServer() {
-	listenAddr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.TlsPort)
-	logger.Info(fmt.Sprintf("Listening on %v", listenAddr))
-	if err := server.ListenAndServeTLS(cfg.Server.CertFile, cfg.Server.KeyFile); err != nil && err != http.ErrServerClosed {
+	logger.Info(fmt.Sprintf("Listening on %v", listenAddr()))
+	var err error
+	if cfg.Polling.Enabled {
+		err = server.ListenAndServe()
+	} else {
+		err = server.ListenAndServeTLS(cfg.Server.CertFile, cfg.Server.KeyFile)
+	}
+	if err != nil && err != http.ErrServerClosed {
 		logger.Error("Error while starting router", err)
 		panic(err)
 	}
@@ -131,7 +380,34 @@ func cleanUp() {
 	ctx, cancel = context.WithTimeout(context.Background(), shutdownTime)
 	defer func() {
 		logger.Info("Cleaning up")
-		pbApiService.UnregisterForNotifications()
+		if !cfg.Polling.Enabled {
+			pbApiService.UnregisterForNotifications()
+		}
+		pbEventService.StopProcessing()
+		pbFeedbackService.StopProcessing()
+		pbNotifyService.StopProcessing()
+		pbChatService.StopProcessing()
+		if emailImportService != nil {
+			emailImportService.StopWatching()
+		}
+		if pbReconcileService != nil {
+			pbReconcileService.StopReconcile()
+		}
+		if cfg.Changelog.Window != "" {
+			pbChangelogService.StopPublishing()
+		}
+		if cfg.Digest.Schedule != "" {
+			pbDigestService.StopDigests()
+		}
+		if cfg.Automation.RulesFile != "" && cfg.Automation.CheckInterval > 0 {
+			pbAutomationService.StopChecks()
+		}
+		if cfg.Slips.CheckInterval > 0 {
+			pbSlipService.StopChecks()
+		}
+		if pbMirrorService != nil && cfg.Mirror.SyncInterval > 0 {
+			pbMirrorService.StopSync()
+		}
 		logger.Info("Done cleaning up")
 		cancel()
 	}()
@@ -0,0 +1,379 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/emulator"
+	"github.com/johannes-kuhfuss/pbreact/repository"
+	"github.com/johannes-kuhfuss/pbreact/service"
+	"github.com/johannes-kuhfuss/services_utils/date"
+)
+
+func RunCommand(args []string) {
+	switch args[0] {
+	case "import-email":
+		importEmail(args[1:])
+	case "import-features":
+		importFeatures(args[1:])
+	case "export-features":
+		exportFeatures(args[1:])
+	case "export-flow":
+		exportFlow(args[1:])
+	case "changelog":
+		writeChangelog(args[1:])
+	case "send-digest":
+		sendDigest(args[1:])
+	case "run-automations":
+		runAutomations(args[1:])
+	case "mirror-features":
+		mirrorFeatures(args[1:])
+	case "emulate":
+		emulate(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command \"%v\"\n", args[0])
+		printUsage()
+		os.Exit(2)
+	}
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  pbreact                              start the service")
+	fmt.Fprintln(os.Stderr, "  pbreact import-email <file>...       create notes from .eml files or mbox archives")
+	fmt.Fprintln(os.Stderr, "  pbreact import-features [--dry-run] <file>")
+	fmt.Fprintln(os.Stderr, "                                       create or update features from a .csv or .json file")
+	fmt.Fprintln(os.Stderr, "  pbreact export-features [flags]      write all features as csv, jsonl or markdown (-h for flags)")
+	fmt.Fprintln(os.Stderr, "  pbreact export-flow [flags]          write time-in-status, cycle time or throughput as csv (-h for flags)")
+	fmt.Fprintln(os.Stderr, "  pbreact changelog [flags]            write the features released in a date range (-h for flags)")
+	fmt.Fprintln(os.Stderr, "  pbreact send-digest [flags]          send the feature digest for a period now (-h for flags)")
+	fmt.Fprintln(os.Stderr, "  pbreact run-automations [--dry-run]  run the automations with afterDays on all features now")
+	fmt.Fprintln(os.Stderr, "  pbreact mirror-features [dir]        write all features to the git mirror, defaults to MIRROR_DIR")
+	fmt.Fprintln(os.Stderr, "  pbreact emulate [flags]              serve a local Productboard API emulator (-h for flags)")
+}
+
+func initCommandConfig() {
+	err := config.InitConfig(config.EnvFile, &cfg)
+	if err != nil {
+		panic(err)
+	}
+	pbApiRepo = repository.NewPbApiRepository(&cfg)
+}
+
+func importEmail(files []string) {
+	if len(files) == 0 {
+		printUsage()
+		os.Exit(2)
+	}
+	initCommandConfig()
+	ledger, err := repository.NewImportLedgerRepository(cfg.EmailImport.LedgerFile)
+	if err != nil {
+		panic(err)
+	}
+	importService := service.NewEmailImportService(&cfg, pbApiRepo, ledger)
+	failed := false
+	for _, file := range files {
+		result, err := importService.ImportFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", file, err.Message())
+			failed = true
+			continue
+		}
+		fmt.Printf("%v: %v imported, %v skipped, %v failed\n", file, result.Imported, result.Skipped, result.Failed)
+		for _, msg := range result.Errors {
+			fmt.Fprintf(os.Stderr, "  %v\n", msg)
+		}
+		failed = failed || result.Failed > 0
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func importFeatures(args []string) {
+	dryRun := len(args) > 0 && args[0] == "--dry-run"
+	if dryRun {
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		printUsage()
+		os.Exit(2)
+	}
+	initCommandConfig()
+	progress, err := repository.NewImportLedgerRepository(cfg.FeatureImport.ProgressFile)
+	if err != nil {
+		panic(err)
+	}
+	statusService := service.NewPbStatusService(&cfg, pbApiRepo)
+	importService := service.NewFeatureImportService(&cfg, pbApiRepo, statusService, progress)
+	plan, err := importService.PlanImport(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v: %v\n", args[0], err.Message())
+		os.Exit(1)
+	}
+	printImportPlan(*plan)
+	if len(plan.Errors) > 0 {
+		fmt.Fprintln(os.Stderr, "Nothing was imported. Fix the errors above and try again.")
+		os.Exit(1)
+	}
+	if dryRun {
+		return
+	}
+	result, err := importService.RunImport(*plan)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v: %v\n", args[0], err.Message())
+		os.Exit(1)
+	}
+	fmt.Printf("%v: %v created, %v updated, %v skipped, %v failed\n", result.File, result.Created, result.Updated, result.Skipped, result.Failed)
+	for _, msg := range result.Errors {
+		fmt.Fprintf(os.Stderr, "  %v\n", msg)
+	}
+	if result.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func printImportPlan(plan dto.FeatureImportPlan) {
+	fmt.Printf("%v: %v to create, %v to update, %v unchanged, %v already imported\n", plan.File,
+		plan.Count(dto.ImportCreate), plan.Count(dto.ImportUpdate), plan.Count(dto.ImportUnchanged), plan.Count(dto.ImportDone))
+	for _, action := range plan.Actions {
+		switch action.Action {
+		case dto.ImportCreate:
+			fmt.Printf("  row %v: create \"%v\" below %v\n", action.Row, action.Name, action.Parent)
+		case dto.ImportUpdate:
+			fmt.Printf("  row %v: update \"%v\" (%v): %v\n", action.Row, action.Name, action.FeatureId, strings.Join(action.Changes, ", "))
+		}
+	}
+	for _, msg := range plan.Errors {
+		fmt.Fprintf(os.Stderr, "  %v\n", msg)
+	}
+}
+
+func exportFeatures(args []string) {
+	flags := flag.NewFlagSet("export-features", flag.ExitOnError)
+	format := flags.String("format", dto.ExportCsv, "output format: csv, jsonl or markdown")
+	status := flags.String("status", "", "comma separated status names to export")
+	archived := flags.String("archived", "", "only export archived (true) or active (false) features")
+	parent := flags.String("parent", "", "only export features below this product, component or feature (name or id)")
+	output := flags.String("output", "", "file to write to instead of stdout")
+	flags.Parse(args)
+	filter, err := service.ParseExportFilter([]string{*status}, *archived, *parent)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Message())
+		os.Exit(2)
+	}
+	initCommandConfig()
+	out := os.Stdout
+	if *output != "" {
+		f, createErr := os.Create(*output)
+		if createErr != nil {
+			fmt.Fprintf(os.Stderr, "Could not create %v: %v\n", *output, createErr)
+			os.Exit(1)
+		}
+		defer f.Close()
+		out = f
+	}
+	exportService := service.NewFeatureExportService(&cfg, pbApiRepo)
+	if err := exportService.ExportFeatures(out, *format, *filter); err != nil {
+		fmt.Fprintln(os.Stderr, err.Message())
+		os.Exit(1)
+	}
+}
+
+func exportFlow(args []string) {
+	flags := flag.NewFlagSet("export-flow", flag.ExitOnError)
+	table := flags.String("table", dto.FlowTableFeatures, "table to write: features or throughput")
+	product := flags.String("product", "", "comma separated products (name or id) to include")
+	component := flags.String("component", "", "comma separated components (name or id) to include")
+	since := flags.String("since", "", "only include features finished on or after this date (YYYY-MM-DD)")
+	output := flags.String("output", "", "file to write to instead of stdout")
+	flags.Parse(args)
+	filter, err := service.ParseFlowFilter([]string{*product}, []string{*component}, *since)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Message())
+		os.Exit(2)
+	}
+	initCommandConfig()
+	out := os.Stdout
+	if *output != "" {
+		f, createErr := os.Create(*output)
+		if createErr != nil {
+			fmt.Fprintf(os.Stderr, "Could not create %v: %v\n", *output, createErr)
+			os.Exit(1)
+		}
+		defer f.Close()
+		out = f
+	}
+	journal := repository.NewFeatureJournalRepository(cfg.Journal.File)
+	flowService := service.NewPbFlowService(&cfg, journal, service.NewPbHierarchyService(&cfg, pbApiRepo))
+	if err := flowService.WriteCsv(out, *table, *filter); err != nil {
+		fmt.Fprintln(os.Stderr, err.Message())
+		os.Exit(1)
+	}
+}
+
+func writeChangelog(args []string) {
+	flags := flag.NewFlagSet("changelog", flag.ExitOnError)
+	from := flags.String("from", "", "first day of the release range (YYYY-MM-DD), defaults to six days before -to")
+	to := flags.String("to", "", "last day of the release range (YYYY-MM-DD), defaults to today")
+	format := flags.String("format", "", "output format: markdown or html, defaults to CHANGELOG_FORMAT")
+	output := flags.String("output", "", "file to write to instead of stdout")
+	flags.Parse(args)
+	start, end, err := service.ParseChangelogRange(*from, *to)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Message())
+		os.Exit(2)
+	}
+	initCommandConfig()
+	if *format == "" {
+		*format = cfg.Changelog.Format
+	}
+	journal := repository.NewFeatureJournalRepository(cfg.Journal.File)
+	changelogService := service.NewPbChangelogService(&cfg, pbApiRepo, journal, service.NewPbHierarchyService(&cfg, pbApiRepo), nil, nil)
+	changelog, err := changelogService.GetChangelog(start, end, *format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Message())
+		os.Exit(1)
+	}
+	if *output == "" {
+		fmt.Print(changelog)
+		return
+	}
+	if writeErr := os.WriteFile(*output, []byte(changelog), 0644); writeErr != nil {
+		fmt.Fprintf(os.Stderr, "Could not write %v: %v\n", *output, writeErr)
+		os.Exit(1)
+	}
+}
+
+func sendDigest(args []string) {
+	flags := flag.NewFlagSet("send-digest", flag.ExitOnError)
+	hours := flags.Int("hours", 24, "length of the period in hours")
+	to := flags.String("to", "", "end of the period (RFC 3339), defaults to now")
+	flags.Parse(args)
+	end := date.GetNowUtc()
+	if *to != "" {
+		parsed, parseErr := time.Parse(time.RFC3339, *to)
+		if parseErr != nil {
+			fmt.Fprintf(os.Stderr, "Could not parse -to %v: %v\n", *to, parseErr)
+			os.Exit(2)
+		}
+		end = parsed.UTC()
+	}
+	initCommandConfig()
+	journal := repository.NewFeatureJournalRepository(cfg.Journal.File)
+	digestService, err := service.NewPbDigestService(&cfg, journal, repository.NewSmtpMailRepository(&cfg), repository.NewHttpWebhookRepository(10*time.Second))
+	if err == nil {
+		err = digestService.SendDigest(end.Add(-time.Duration(*hours)*time.Hour), end)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Message())
+		os.Exit(1)
+	}
+}
+
+func runAutomations(args []string) {
+	flags := flag.NewFlagSet("run-automations", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "log the updates instead of sending them")
+	flags.Parse(args)
+	initCommandConfig()
+	cfg.Automation.DryRun = cfg.Automation.DryRun || *dryRun
+	journal := repository.NewFeatureJournalRepository(cfg.Journal.File)
+	hierarchyService := service.NewPbHierarchyService(&cfg, pbApiRepo)
+	flowService := service.NewPbFlowService(&cfg, journal, hierarchyService)
+	automationService, err := service.NewPbAutomationService(&cfg, pbApiRepo, journal, flowService, hierarchyService, service.NewPbStatusService(&cfg, pbApiRepo), repository.NewFeatureCacheRepository(cfg.Automation.SeenFile))
+	if err == nil {
+		err = automationService.CheckAutomations()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Message())
+		os.Exit(1)
+	}
+}
+
+func mirrorFeatures(args []string) {
+	if len(args) > 1 {
+		printUsage()
+		os.Exit(2)
+	}
+	initCommandConfig()
+	if len(args) == 1 {
+		cfg.Mirror.Dir = args[0]
+	}
+	if cfg.Mirror.Dir == "" {
+		fmt.Fprintln(os.Stderr, "No mirror directory given and MIRROR_DIR is not set")
+		os.Exit(2)
+	}
+	git := repository.NewExecGitRepository(&cfg)
+	mirrorService, err := service.NewPbMirrorService(&cfg, pbApiRepo, service.NewPbHierarchyService(&cfg, pbApiRepo), git)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Message())
+		os.Exit(1)
+	}
+	result, err := mirrorService.Sync()
+	if err == nil && cfg.Mirror.Remote != "" {
+		err = git.Push()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Message())
+		os.Exit(1)
+	}
+	fmt.Printf("%v: %v written, %v removed\n", cfg.Mirror.Dir, result.Written, result.Removed)
+}
+
+func emulate(args []string) {
+	flags := flag.NewFlagSet("emulate", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8090", "address to listen on")
+	token := flags.String("token", "", "API token clients have to send, any token is accepted if empty")
+	rate := flags.Int("rate", 50, "requests per second before answering with 429, 0 disables the limit")
+	seed := flags.String("seed", "", "json file with statuses, products, components and features, demo data if empty")
+	insecure := flags.Bool("insecure", false, "skip certificate checks on webhook and plugin callbacks")
+	flags.Parse(args)
+	data := emulator.DemoSeed()
+	if *seed != "" {
+		loaded, err := emulator.LoadSeed(*seed)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not load seed %v: %v\n", *seed, err)
+			os.Exit(1)
+		}
+		data = *loaded
+	}
+	gin.SetMode(gin.ReleaseMode)
+	emu := emulator.New(emulator.Config{
+		Token:             *token,
+		RateLimit:         *rate,
+		BaseUrl:           "http://" + *addr,
+		InsecureCallbacks: *insecure,
+	})
+	emu.Seed(data)
+	server := http.Server{
+		Addr:    *addr,
+		Handler: emu,
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+	fmt.Printf("Productboard API emulator listening, start pbreact with PB_BASE_URL=http://%v/\n", *addr)
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	select {
+	case err := <-serverErr:
+		fmt.Fprintf(os.Stderr, "Could not serve on %v: %v\n", *addr, err)
+		os.Exit(1)
+	case <-stop:
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server.Shutdown(ctx)
+	emu.Close()
+}
@@ -23,11 +23,161 @@ type AppConfig struct {
 		ApiToken   string `envconfig:"API_TOKEN" required:"true"`
 		BaseUrl    string `envconfig:"PB_BASE_URL" default:"https://api.productboard.com/"`
 		WebHookUrl string `envconfig:"WEB_HOOK_URL" default:"https://jkuext.ddns.net/pbwebhook"`
+		PartnerId  string `envconfig:"PB_PARTNER_ID"`
+	}
+	PluginIntegration struct {
+		Type           string `envconfig:"PLUGIN_TYPE"`
+		Name           string `envconfig:"PLUGIN_NAME" default:"pbreact"`
+		ButtonLabel    string `envconfig:"PLUGIN_BUTTON_LABEL" default:"Push"`
+		ActionUrl      string `envconfig:"PLUGIN_ACTION_URL"`
+		ActionTimeout  int    `envconfig:"PLUGIN_ACTION_TIMEOUT" default:"4"`
+		ConnectionFile string `envconfig:"PLUGIN_CONNECTION_FILE" default:"./data/plugin-connections.json"`
+	}
+	Tracker struct {
+		Type         string `envconfig:"TRACKER_TYPE"`
+		AuthHeader   string `envconfig:"TRACKER_AUTH_HEADER"`
+		CreateUrl    string `envconfig:"TRACKER_CREATE_URL"`
+		IssueUrl     string `envconfig:"TRACKER_ISSUE_URL"`
+		BrowseUrl    string `envconfig:"TRACKER_BROWSE_URL"`
+		UpdateMethod string `envconfig:"TRACKER_UPDATE_METHOD" default:"PATCH"`
+		CreateBody   string `envconfig:"TRACKER_CREATE_BODY" default:"{\"title\": {{json .Title}}, \"description\": {{json .Description}}}"`
+		UpdateBody   string `envconfig:"TRACKER_UPDATE_BODY" default:"{\"title\": {{json .Title}}, \"description\": {{json .Description}}, \"status\": {{json .Status}}}"`
+		CloseBody    string `envconfig:"TRACKER_CLOSE_BODY" default:"{\"state\": \"closed\"}"`
+		KeyField     string `envconfig:"TRACKER_KEY_FIELD" default:"id"`
+		WebhookToken string `envconfig:"TRACKER_WEBHOOK_TOKEN"`
+		LinkFile     string `envconfig:"TRACKER_LINK_FILE" default:"./data/issue-links.json"`
+	}
+	Jira struct {
+		EnrichEvents bool   `envconfig:"JIRA_ENRICH_EVENTS" default:"false"`
+		BrowseUrl    string `envconfig:"JIRA_BROWSE_URL"`
+	}
+	StatusSync struct {
+		Mapping        []string `envconfig:"STATUS_MAPPING"`
+		ConflictWinner string   `envconfig:"STATUS_CONFLICT_WINNER" default:"productboard"`
+		EchoWindow     int      `envconfig:"STATUS_ECHO_WINDOW" default:"60"`
+	}
+	Statuses struct {
+		Done                []string `envconfig:"DONE_STATUSES"`
+		MissRefreshInterval int      `envconfig:"STATUS_MISS_REFRESH_INTERVAL" default:"300"`
+	}
+	Feedback struct {
+		AuthToken  string `envconfig:"FEEDBACK_AUTH_TOKEN"`
+		Origin     string `envconfig:"FEEDBACK_ORIGIN" default:"pbreact"`
+		QueueSize  int    `envconfig:"FEEDBACK_QUEUE_SIZE" default:"100"`
+		MaxRetries int    `envconfig:"FEEDBACK_MAX_RETRIES" default:"5"`
+		RetryDelay int    `envconfig:"FEEDBACK_RETRY_DELAY" default:"30"`
+		// Retention is how long (in hours) the status of finished feedback can be looked up. 0 keeps it forever.
+		Retention int `envconfig:"FEEDBACK_RETENTION" default:"24"`
+	}
+	EmailImport struct {
+		Origin        string   `envconfig:"EMAIL_IMPORT_ORIGIN" default:"email"`
+		Tags          []string `envconfig:"EMAIL_IMPORT_TAGS" default:"email"`
+		LedgerFile    string   `envconfig:"EMAIL_LEDGER_FILE" default:"./data/imported-emails.txt"`
+		WatchDir      string   `envconfig:"EMAIL_WATCH_DIR"`
+		WatchInterval int      `envconfig:"EMAIL_WATCH_INTERVAL" default:"60"`
+	}
+	Export struct {
+		AuthToken string `envconfig:"EXPORT_AUTH_TOKEN"`
+		// WriteTimeout (in seconds) replaces the server's write timeout for streamed exports. 0 lets them take as long as needed.
+		WriteTimeout int `envconfig:"EXPORT_WRITE_TIMEOUT" default:"600"`
+	}
+	Roadmap struct {
+		CalendarName string `envconfig:"ROADMAP_CALENDAR_NAME" default:"Productboard roadmap"`
+		UidDomain    string `envconfig:"ROADMAP_UID_DOMAIN" default:"pbreact"`
+	}
+	Journal struct {
+		File string `envconfig:"JOURNAL_FILE" default:"./data/feature-journal.jsonl"`
+	}
+	Slips struct {
+		WebhookUrls     []string `envconfig:"SLIP_WEBHOOK_URLS"`
+		CheckInterval   int      `envconfig:"SLIP_CHECK_INTERVAL" default:"60"`
+		RepeatThreshold int      `envconfig:"SLIP_REPEAT_THRESHOLD" default:"2"`
+	}
+	Flow struct {
+		StartStatuses []string `envconfig:"FLOW_START_STATUSES" default:"In progress"`
+		EndStatuses   []string `envconfig:"FLOW_END_STATUSES"`
+	}
+	Changelog struct {
+		ReleaseStatuses []string `envconfig:"CHANGELOG_RELEASE_STATUSES" default:"Released"`
+		Title           string   `envconfig:"CHANGELOG_TITLE" default:"Release notes"`
+		Format          string   `envconfig:"CHANGELOG_FORMAT" default:"markdown"`
+		Window          string   `envconfig:"CHANGELOG_WINDOW"`
+		OutputDir       string   `envconfig:"CHANGELOG_OUTPUT_DIR"`
+		WebhookUrl      string   `envconfig:"CHANGELOG_WEBHOOK_URL"`
+		LedgerFile      string   `envconfig:"CHANGELOG_LEDGER_FILE" default:"./data/published-changelogs.txt"`
+	}
+	Smtp struct {
+		Host     string `envconfig:"SMTP_HOST"`
+		Port     int    `envconfig:"SMTP_PORT" default:"25"`
+		Username string `envconfig:"SMTP_USERNAME"`
+		Password string `envconfig:"SMTP_PASSWORD"`
+		From     string `envconfig:"SMTP_FROM"`
+		Security string `envconfig:"SMTP_SECURITY" default:"auto"`
+		Timeout  int    `envconfig:"SMTP_TIMEOUT" default:"30"`
+	}
+	Notify struct {
+		RulesFile  string `envconfig:"NOTIFY_RULES_FILE"`
+		QueueSize  int    `envconfig:"NOTIFY_QUEUE_SIZE" default:"100"`
+		MaxRetries int    `envconfig:"NOTIFY_MAX_RETRIES" default:"5"`
+		RetryDelay int    `envconfig:"NOTIFY_RETRY_DELAY" default:"30"`
+		SeenFile   string `envconfig:"NOTIFY_SEEN_FILE" default:"./data/notify-seen.json"`
+	}
+	Chat struct {
+		ChannelsFile string `envconfig:"CHAT_CHANNELS_FILE"`
+		SlackApiUrl  string `envconfig:"CHAT_SLACK_API_URL" default:"https://slack.com/api/chat.postMessage"`
+		QueueSize    int    `envconfig:"CHAT_QUEUE_SIZE" default:"100"`
+		MaxRetries   int    `envconfig:"CHAT_MAX_RETRIES" default:"5"`
+		RetryDelay   int    `envconfig:"CHAT_RETRY_DELAY" default:"30"`
+		SeenFile     string `envconfig:"CHAT_SEEN_FILE" default:"./data/chat-seen.json"`
+		ThreadFile   string `envconfig:"CHAT_THREAD_FILE" default:"./data/chat-threads.json"`
+	}
+	Automation struct {
+		// RulesFile lists the automations. moveTo is not supported: the Productboard API cannot move features, rules using it are rejected.
+		RulesFile     string `envconfig:"AUTOMATION_RULES_FILE"`
+		CheckInterval int    `envconfig:"AUTOMATION_CHECK_INTERVAL" default:"60"`
+		EchoWindow    int    `envconfig:"AUTOMATION_ECHO_WINDOW" default:"60"`
+		MaxWrites     int    `envconfig:"AUTOMATION_MAX_WRITES" default:"3"`
+		DryRun        bool   `envconfig:"AUTOMATION_DRY_RUN"`
+		SeenFile      string `envconfig:"AUTOMATION_SEEN_FILE" default:"./data/automation-seen.json"`
+	}
+	Mirror struct {
+		Dir         string `envconfig:"MIRROR_DIR"`
+		AuthorName  string `envconfig:"MIRROR_AUTHOR_NAME" default:"pbreact"`
+		AuthorEmail string `envconfig:"MIRROR_AUTHOR_EMAIL" default:"pbreact@localhost"`
+		Remote      string `envconfig:"MIRROR_REMOTE"`
+		PushTimeout int    `envconfig:"MIRROR_PUSH_TIMEOUT" default:"60"`
+		// SyncInterval (in minutes) between full syncs, which also move the files of renamed products and components. 0 disables them.
+		SyncInterval int `envconfig:"MIRROR_SYNC_INTERVAL" default:"60"`
+	}
+	Digest struct {
+		Schedule     string   `envconfig:"DIGEST_SCHEDULE"`
+		Subject      string   `envconfig:"DIGEST_SUBJECT" default:"Feature digest"`
+		TemplateFile string   `envconfig:"DIGEST_TEMPLATE_FILE"`
+		SkipEmpty    bool     `envconfig:"DIGEST_SKIP_EMPTY" default:"true"`
+		MailTo       []string `envconfig:"DIGEST_MAIL_TO"`
+		WebhookUrl   string   `envconfig:"DIGEST_WEBHOOK_URL"`
+		OutputDir    string   `envconfig:"DIGEST_OUTPUT_DIR"`
+	}
+	FeatureImport struct {
+		ProgressFile string `envconfig:"FEATURE_IMPORT_PROGRESS_FILE" default:"./data/imported-features.txt"`
+		RateLimit    int    `envconfig:"FEATURE_IMPORT_RATE_LIMIT" default:"5"`
+	}
+	Events struct {
+		QueueSize int `envconfig:"EVENT_QUEUE_SIZE" default:"100"`
+	}
+	Polling struct {
+		Enabled  bool `envconfig:"POLLING_MODE" default:"false"`
+		Interval int  `envconfig:"POLLING_INTERVAL" default:"60"`
+	}
+	Reconcile struct {
+		Interval  int    `envconfig:"RECONCILE_INTERVAL" default:"60"`
+		StateFile string `envconfig:"RECONCILE_STATE_FILE" default:"./data/feature-state.json"`
 	}
 	GracefulShutdownTime int `envconfig:"GRACEFUL_SHUTDOWN_TIME" default:"10"`
 	RunTime              struct {
-		Router            *gin.Engine
-		CallbackAuthToken string
+		Router              *gin.Engine
+		CallbackAuthToken   string
+		PluginIntegrationId string
 	}
 }
 
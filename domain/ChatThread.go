@@ -0,0 +1,9 @@
+package domain
+
+import "github.com/johannes-kuhfuss/services_utils/api_error"
+
+//go:generate mockgen -destination=../mocks/domain/mockChatThreadRepository.go -package=domain github.com/johannes-kuhfuss/pbreact/domain ChatThreadRepository
+type ChatThreadRepository interface {
+	Load() (map[string]string, api_error.ApiErr)
+	Save(map[string]string) api_error.ApiErr
+}
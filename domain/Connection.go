@@ -0,0 +1,12 @@
+package domain
+
+import (
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+//go:generate mockgen -destination=../mocks/domain/mockConnectionRepository.go -package=domain github.com/johannes-kuhfuss/pbreact/domain ConnectionRepository
+type ConnectionRepository interface {
+	Load() (map[string]dto.Connection, api_error.ApiErr)
+	Save(map[string]dto.Connection) api_error.ApiErr
+}
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+//go:generate mockgen -destination=../mocks/domain/mockFeatureJournalRepository.go -package=domain github.com/johannes-kuhfuss/pbreact/domain FeatureJournalRepository
+type FeatureJournalRepository interface {
+	Append(dto.JournalEntry) api_error.ApiErr
+	Read(time.Time) ([]dto.JournalEntry, api_error.ApiErr)
+}
@@ -0,0 +1,12 @@
+package domain
+
+import (
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+//go:generate mockgen -destination=../mocks/domain/mockFeatureStateRepository.go -package=domain github.com/johannes-kuhfuss/pbreact/domain FeatureStateRepository
+type FeatureStateRepository interface {
+	Load() (map[string]dto.FeatureSnapshot, api_error.ApiErr)
+	Save(map[string]dto.FeatureSnapshot) api_error.ApiErr
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "github.com/johannes-kuhfuss/services_utils/api_error"
+
+//go:generate mockgen -destination=../mocks/domain/mockGitRepository.go -package=domain github.com/johannes-kuhfuss/pbreact/domain GitRepository
+type GitRepository interface {
+	Init() api_error.ApiErr
+	Commit(string) api_error.ApiErr
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "github.com/johannes-kuhfuss/services_utils/api_error"
+
+//go:generate mockgen -destination=../mocks/domain/mockImportLedgerRepository.go -package=domain github.com/johannes-kuhfuss/pbreact/domain ImportLedgerRepository
+type ImportLedgerRepository interface {
+	IsImported(string) bool
+	MarkImported(string) api_error.ApiErr
+}
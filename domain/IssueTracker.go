@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+//go:generate mockgen -destination=../mocks/domain/mockIssueTrackerRepository.go -package=domain github.com/johannes-kuhfuss/pbreact/domain IssueTrackerRepository
+type IssueTrackerRepository interface {
+	CreateIssue(dto.Issue) (*dto.IssueRef, api_error.ApiErr)
+	UpdateIssue(string, dto.Issue) api_error.ApiErr
+	CloseIssue(string) api_error.ApiErr
+	GetIssueUrl(string) string
+}
@@ -0,0 +1,11 @@
+package domain
+
+import (
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+//go:generate mockgen -destination=../mocks/domain/mockMailRepository.go -package=domain github.com/johannes-kuhfuss/pbreact/domain MailRepository
+type MailRepository interface {
+	Send(dto.MailMessage) api_error.ApiErr
+}
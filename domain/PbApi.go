@@ -10,4 +10,28 @@ type PbApiRepository interface {
 	RegisterForNotifications() api_error.ApiErr
 	GetNotifications() (*dto.PbSubscriptionResponse, api_error.ApiErr)
 	UnregisterForNotifications(dto.PbSubscriptionResponse) api_error.ApiErr
+	GetProducts() ([]dto.Product, api_error.ApiErr)
+	GetProduct(string) (*dto.Product, api_error.ApiErr)
+	GetComponents() ([]dto.Component, api_error.ApiErr)
+	GetComponent(string) (*dto.Component, api_error.ApiErr)
+	GetFeatures(dto.FeatureFilter) ([]dto.Feature, api_error.ApiErr)
+	GetFeaturePages(dto.FeatureFilter, func([]dto.Feature) api_error.ApiErr) api_error.ApiErr
+	GetFeature(string) (*dto.Feature, api_error.ApiErr)
+	CreateFeature(dto.FeatureCreate) (*dto.Feature, api_error.ApiErr)
+	UpdateFeature(string, dto.FeatureUpdate) (*dto.Feature, api_error.ApiErr)
+	GetFeatureStatuses() ([]dto.FeatureStatus, api_error.ApiErr)
+	CreateNote(dto.Note) (*dto.NoteResult, api_error.ApiErr)
+	CreatePluginIntegration(dto.PluginIntegrationData) (*dto.PluginIntegration, api_error.ApiErr)
+	GetPluginIntegrations() ([]dto.PluginIntegration, api_error.ApiErr)
+	GetPluginIntegration(string) (*dto.PluginIntegration, api_error.ApiErr)
+	UpdatePluginIntegration(string, dto.PluginIntegrationData) (*dto.PluginIntegration, api_error.ApiErr)
+	DeletePluginIntegration(string) api_error.ApiErr
+	GetPluginConnections(string) ([]dto.ConnectionData, api_error.ApiErr)
+	GetPluginConnection(string, string) (*dto.ConnectionData, api_error.ApiErr)
+	UpdatePluginConnection(string, string, dto.Connection) (*dto.ConnectionData, api_error.ApiErr)
+	DeletePluginConnection(string, string) api_error.ApiErr
+	GetJiraIntegrations() ([]dto.JiraIntegration, api_error.ApiErr)
+	GetJiraIntegration(string) (*dto.JiraIntegration, api_error.ApiErr)
+	GetJiraIntegrationConnections(string, dto.JiraConnectionFilter) ([]dto.JiraConnectionData, api_error.ApiErr)
+	GetJiraIntegrationConnection(string, string) (*dto.JiraConnectionData, api_error.ApiErr)
 }
@@ -0,0 +1,9 @@
+package domain
+
+import "github.com/johannes-kuhfuss/services_utils/api_error"
+
+//go:generate mockgen -destination=../mocks/domain/mockWebhookRepository.go -package=domain github.com/johannes-kuhfuss/pbreact/domain WebhookRepository
+type WebhookRepository interface {
+	PostJson(string, interface{}) api_error.ApiErr
+	PostJsonReply(string, string, interface{}, interface{}) api_error.ApiErr
+}
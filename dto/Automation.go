@@ -0,0 +1,18 @@
+package dto
+
+// Automation updates features matching its filter through the Productboard API. Without AfterDays it runs on feature
+// events. With AfterDays it runs on the scheduled check instead, for features that have been in their current status
+// for at least that many days according to the feature journal; Events and Changed are ignored then.
+// Actions run in the order listed here, actions that would not change the feature are skipped.
+// MoveTo is rejected on load: the Productboard API cannot change the parent of an existing feature.
+type Automation struct {
+	Name string `json:"name"`
+	EventFilter
+	AfterDays          int        `json:"afterDays"`
+	SetStatus          string     `json:"setStatus"`
+	Archive            bool       `json:"archive"`
+	MoveTo             string     `json:"moveTo"`
+	Timeframe          *Timeframe `json:"timeframe"`
+	ShiftTimeframeDays int        `json:"shiftTimeframeDays"`
+	NamePrefix         string     `json:"namePrefix"`
+}
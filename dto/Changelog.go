@@ -0,0 +1,42 @@
+package dto
+
+import "time"
+
+const (
+	ChangelogMarkdown = "markdown"
+	ChangelogHtml     = "html"
+	ChangelogWeekly   = "weekly"
+	ChangelogMonthly  = "monthly"
+)
+
+var (
+	ChangelogContentTypes = map[string]string{
+		ChangelogMarkdown: "text/markdown; charset=utf-8",
+		ChangelogHtml:     "text/html; charset=utf-8",
+	}
+	ChangelogFileExtensions = map[string]string{
+		ChangelogMarkdown: "md",
+		ChangelogHtml:     "html",
+	}
+)
+
+// ChangelogEntry is a feature that moved into a release status. Description is plain text.
+type ChangelogEntry struct {
+	FeatureId   string
+	Name        string
+	Description string
+	Url         string
+	Product     string
+	Component   string
+	ReleasedAt  time.Time
+}
+
+// ChangelogDocument is what gets posted to the changelog webhook when a release window closes. To is exclusive.
+type ChangelogDocument struct {
+	Title    string    `json:"title"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Format   string    `json:"format"`
+	Features int       `json:"features"`
+	Content  string    `json:"content"`
+}
@@ -0,0 +1,171 @@
+package dto
+
+const (
+	ChatSlack      = "slack"
+	ChatMattermost = "mattermost"
+	ChatTeams      = "teams"
+	ChatGoogleChat = "googlechat"
+	ChatDiscord    = "discord"
+)
+
+// ChatChannel posts feature events matching its filter to a chat incoming webhook. With Thread set, messages about the
+// same feature are kept in one thread: Google Chat threads by key, Discord creates a forum post per feature and Slack
+// replies to the first message, which needs a bot Token and a Channel instead of an incoming webhook Url.
+// Mattermost and Teams incoming webhooks cannot thread.
+type ChatChannel struct {
+	Name string `json:"name"`
+	EventFilter
+	Format  string `json:"format"`
+	Url     string `json:"url"`
+	Token   string `json:"token"`
+	Channel string `json:"channel"`
+	Thread  bool   `json:"thread"`
+}
+
+// SlackMessage is used for Slack and Mattermost, which both render Slack attachments
+type SlackMessage struct {
+	Channel     string            `json:"channel,omitempty"`
+	ThreadTs    string            `json:"thread_ts,omitempty"`
+	Text        string            `json:"text"`
+	Attachments []SlackAttachment `json:"attachments"`
+}
+
+type SlackAttachment struct {
+	Fallback  string       `json:"fallback"`
+	Color     string       `json:"color"`
+	Title     string       `json:"title"`
+	TitleLink string       `json:"title_link,omitempty"`
+	Text      string       `json:"text,omitempty"`
+	Fields    []SlackField `json:"fields,omitempty"`
+	Footer    string       `json:"footer,omitempty"`
+	Ts        int64        `json:"ts"`
+}
+
+type SlackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// SlackReply is the answer of chat.postMessage
+type SlackReply struct {
+	Ok    bool   `json:"ok"`
+	Error string `json:"error"`
+	Ts    string `json:"ts"`
+}
+
+type TeamsMessage struct {
+	Type            string         `json:"@type"`
+	Context         string         `json:"@context"`
+	Summary         string         `json:"summary"`
+	ThemeColor      string         `json:"themeColor"`
+	Title           string         `json:"title"`
+	Sections        []TeamsSection `json:"sections"`
+	PotentialAction []TeamsAction  `json:"potentialAction,omitempty"`
+}
+
+type TeamsSection struct {
+	ActivityTitle    string      `json:"activityTitle"`
+	ActivitySubtitle string      `json:"activitySubtitle"`
+	Facts            []TeamsFact `json:"facts"`
+	Text             string      `json:"text,omitempty"`
+}
+
+type TeamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type TeamsAction struct {
+	Type    string        `json:"@type"`
+	Name    string        `json:"name"`
+	Targets []TeamsTarget `json:"targets"`
+}
+
+type TeamsTarget struct {
+	Os  string `json:"os"`
+	Uri string `json:"uri"`
+}
+
+type GoogleChatMessage struct {
+	Text    string           `json:"text"`
+	CardsV2 []GoogleChatCard `json:"cardsV2"`
+}
+
+type GoogleChatCard struct {
+	CardId string             `json:"cardId"`
+	Card   GoogleChatCardBody `json:"card"`
+}
+
+type GoogleChatCardBody struct {
+	Header   GoogleChatHeader    `json:"header"`
+	Sections []GoogleChatSection `json:"sections"`
+}
+
+type GoogleChatHeader struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle"`
+}
+
+type GoogleChatSection struct {
+	Widgets []GoogleChatWidget `json:"widgets"`
+}
+
+// GoogleChatWidget holds exactly one of its fields
+type GoogleChatWidget struct {
+	DecoratedText *GoogleChatText    `json:"decoratedText,omitempty"`
+	ButtonList    *GoogleChatButtons `json:"buttonList,omitempty"`
+}
+
+type GoogleChatText struct {
+	TopLabel string `json:"topLabel"`
+	Text     string `json:"text"`
+}
+
+type GoogleChatButtons struct {
+	Buttons []GoogleChatButton `json:"buttons"`
+}
+
+type GoogleChatButton struct {
+	Text    string            `json:"text"`
+	OnClick GoogleChatOnClick `json:"onClick"`
+}
+
+type GoogleChatOnClick struct {
+	OpenLink GoogleChatLink `json:"openLink"`
+}
+
+type GoogleChatLink struct {
+	Url string `json:"url"`
+}
+
+type DiscordMessage struct {
+	ThreadName string         `json:"thread_name,omitempty"`
+	Embeds     []DiscordEmbed `json:"embeds"`
+}
+
+type DiscordEmbed struct {
+	Title       string              `json:"title"`
+	Url         string              `json:"url,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Color       int                 `json:"color"`
+	Fields      []DiscordField      `json:"fields,omitempty"`
+	Footer      *DiscordEmbedFooter `json:"footer,omitempty"`
+	Timestamp   string              `json:"timestamp"`
+}
+
+type DiscordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type DiscordEmbedFooter struct {
+	Text string `json:"text"`
+}
+
+// DiscordReply is the created message, returned when posting with wait=true. For a new forum post, ChannelId is the thread.
+type DiscordReply struct {
+	ID        string `json:"id"`
+	ChannelId string `json:"channel_id"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+// DigestItem is a single change in a digest. Old and New are only set for status and timeframe changes, Days only for slips.
+type DigestItem struct {
+	FeatureId   string    `json:"featureId"`
+	FeatureName string    `json:"featureName"`
+	Time        time.Time `json:"time"`
+	Old         string    `json:"old,omitempty"`
+	New         string    `json:"new,omitempty"`
+	Days        int       `json:"days,omitempty"`
+}
+
+// Digest summarizes the feature activity recorded in the journal between From and To (exclusive)
+type Digest struct {
+	Subject          string       `json:"subject"`
+	From             time.Time    `json:"from"`
+	To               time.Time    `json:"to"`
+	Created          []DigestItem `json:"created"`
+	Deleted          []DigestItem `json:"deleted"`
+	StatusChanges    []DigestItem `json:"statusChanges"`
+	TimeframeChanges []DigestItem `json:"timeframeChanges"`
+	Slips            []DigestItem `json:"slips"`
+}
+
+func (d Digest) Empty() bool {
+	return len(d.Created)+len(d.Deleted)+len(d.StatusChanges)+len(d.TimeframeChanges)+len(d.Slips) == 0
+}
+
+// DigestDocument is what gets posted to the digest webhook
+type DigestDocument struct {
+	Digest
+	Text string `json:"text"`
+	Html string `json:"html,omitempty"`
+}
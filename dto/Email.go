@@ -0,0 +1,16 @@
+package dto
+
+type EmailMessage struct {
+	MessageId string
+	Subject   string
+	From      string
+	Body      string
+}
+
+type EmailImportResult struct {
+	File     string   `json:"file"`
+	Imported int      `json:"imported"`
+	Skipped  int      `json:"skipped"`
+	Failed   int      `json:"failed"`
+	Errors   []string `json:"errors,omitempty"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+const (
+	ExportCsv      = "csv"
+	ExportJsonl    = "jsonl"
+	ExportMarkdown = "markdown"
+)
+
+var (
+	ExportContentTypes = map[string]string{
+		ExportCsv:      "text/csv; charset=utf-8",
+		ExportJsonl:    "application/x-ndjson",
+		ExportMarkdown: "text/markdown; charset=utf-8",
+	}
+	ExportFileExtensions = map[string]string{
+		ExportCsv:      "csv",
+		ExportJsonl:    "jsonl",
+		ExportMarkdown: "md",
+	}
+)
+
+type ExportFilter struct {
+	Statuses []string
+	Archived *bool
+	Parent   string
+}
+
+// ExportFeature is a feature flattened for export, with status and parents resolved to names
+type ExportFeature struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Status     string `json:"status"`
+	Archived   bool   `json:"archived"`
+	Product    string `json:"product"`
+	Component  string `json:"component"`
+	ParentPath string `json:"parentPath"`
+	StartDate  string `json:"startDate"`
+	EndDate    string `json:"endDate"`
+	Url        string `json:"url"`
+}
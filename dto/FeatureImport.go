@@ -0,0 +1,58 @@
+package dto
+
+const (
+	ImportCreate    = "create"
+	ImportUpdate    = "update"
+	ImportUnchanged = "unchanged"
+	ImportDone      = "done"
+)
+
+// FeatureImportRow is one feature as prepared in a CSV or JSON import file. Parents and status are given by name.
+type FeatureImportRow struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Product     string `json:"product"`
+	Component   string `json:"component"`
+	Status      string `json:"status"`
+	StartDate   string `json:"startDate"`
+	EndDate     string `json:"endDate"`
+}
+
+// FeatureImportAction is what the import will do for a single row once all names have been resolved
+type FeatureImportAction struct {
+	Row       int            `json:"row"`
+	Key       string         `json:"key"`
+	Action    string         `json:"action"`
+	Name      string         `json:"name"`
+	Parent    string         `json:"parent"`
+	FeatureId string         `json:"featureId,omitempty"`
+	Changes   []string       `json:"changes,omitempty"`
+	Create    *FeatureCreate `json:"-"`
+	Update    *FeatureUpdate `json:"-"`
+}
+
+type FeatureImportPlan struct {
+	File    string                `json:"file"`
+	Actions []FeatureImportAction `json:"actions"`
+	Errors  []string              `json:"errors,omitempty"`
+}
+
+type FeatureImportResult struct {
+	File    string   `json:"file"`
+	Created int      `json:"created"`
+	Updated int      `json:"updated"`
+	Skipped int      `json:"skipped"`
+	Failed  int      `json:"failed"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// Count returns the number of planned actions of the given kind
+func (p FeatureImportPlan) Count(action string) int {
+	count := 0
+	for _, a := range p.Actions {
+		if a.Action == action {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+const (
+	FeedbackQueued    = "queued"
+	FeedbackRetrying  = "retrying"
+	FeedbackCreated   = "created"
+	FeedbackDuplicate = "duplicate"
+	FeedbackFailed    = "failed"
+)
+
+type FeedbackRequest struct {
+	Title         string         `json:"title"`
+	Content       string         `json:"content"`
+	CustomerEmail string         `json:"customer_email"`
+	DisplayUrl    string         `json:"display_url"`
+	Source        FeedbackSource `json:"source"`
+	Tags          []string       `json:"tags"`
+}
+
+type FeedbackSource struct {
+	Origin   string `json:"origin"`
+	RecordId string `json:"record_id"`
+}
+
+type FeedbackStatus struct {
+	TrackingId string    `json:"trackingId"`
+	Status     string    `json:"status"`
+	Attempts   int       `json:"attempts"`
+	NoteId     string    `json:"noteId,omitempty"`
+	NoteUrl    string    `json:"noteUrl,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,55 @@
+package dto
+
+import "time"
+
+const (
+	FlowTableFeatures   = "features"
+	FlowTableThroughput = "throughput"
+)
+
+// FlowFilter restricts the flow report to features below products or components, and to changes since a point in time
+type FlowFilter struct {
+	Products   []string
+	Components []string
+	Since      time.Time
+}
+
+// StatusTime is how long a feature spent in a status, summed over all visits
+type StatusTime struct {
+	Status string  `json:"status"`
+	Days   float64 `json:"days"`
+}
+
+// FeatureFlow is the status history of a feature, as observed by pbreact
+type FeatureFlow struct {
+	FeatureId     string       `json:"featureId"`
+	Name          string       `json:"name"`
+	Product       string       `json:"product,omitempty"`
+	Component     string       `json:"component,omitempty"`
+	Status        string       `json:"status"`
+	FirstSeenAt   time.Time    `json:"firstSeenAt"`
+	StartedAt     *time.Time   `json:"startedAt,omitempty"`
+	FinishedAt    *time.Time   `json:"finishedAt,omitempty"`
+	LeadTimeDays  *float64     `json:"leadTimeDays,omitempty"`
+	CycleTimeDays *float64     `json:"cycleTimeDays,omitempty"`
+	TimeInStatus  []StatusTime `json:"timeInStatus"`
+}
+
+// WeeklyThroughput counts the features that reached an end status in an ISO week
+type WeeklyThroughput struct {
+	Week      string `json:"week"`
+	Product   string `json:"product,omitempty"`
+	Component string `json:"component,omitempty"`
+	Count     int    `json:"count"`
+}
+
+type FlowReport struct {
+	GeneratedAt      time.Time          `json:"generatedAt"`
+	StartStatuses    []string           `json:"startStatuses"`
+	EndStatuses      []string           `json:"endStatuses"`
+	Finished         int                `json:"finished"`
+	AvgLeadTimeDays  *float64           `json:"avgLeadTimeDays,omitempty"`
+	AvgCycleTimeDays *float64           `json:"avgCycleTimeDays,omitempty"`
+	Features         []FeatureFlow      `json:"features"`
+	Throughput       []WeeklyThroughput `json:"throughput"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+const (
+	ConflictProductboardWins = "productboard"
+	ConflictTrackerWins      = "tracker"
+)
+
+type Issue struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	FeatureId   string `json:"featureId"`
+	FeatureUrl  string `json:"featureUrl"`
+	Status      string `json:"status"`
+}
+
+type IssueRef struct {
+	Key string `json:"key"`
+	Url string `json:"url"`
+}
+
+// TrackerEvent is what the issue tracker posts to pbreact when the status of an issue changes
+type TrackerEvent struct {
+	Key       string    `json:"key"`
+	Status    string    `json:"status"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+const (
+	JournalTimeframe = "timeframe"
+	JournalOverdue   = "overdue"
+	JournalStatus    = "status"
+	JournalCreated   = "created"
+	JournalDeleted   = "deleted"
+)
+
+// JournalEntry is a single observed change of a feature, as recorded in the feature journal
+type JournalEntry struct {
+	Time         time.Time  `json:"time"`
+	FeatureId    string     `json:"featureId"`
+	FeatureName  string     `json:"featureName"`
+	Kind         string     `json:"kind"`
+	Status       string     `json:"status,omitempty"`
+	OldStatus    string     `json:"oldStatus,omitempty"`
+	ParentId     string     `json:"parentId,omitempty"`
+	Timeframe    *Timeframe `json:"timeframe,omitempty"`
+	OldTimeframe *Timeframe `json:"oldTimeframe,omitempty"`
+	DaysSlipped  int        `json:"daysSlipped,omitempty"`
+}
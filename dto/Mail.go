@@ -0,0 +1,17 @@
+package dto
+
+const (
+	SmtpAuto     = "auto"
+	SmtpStartTls = "starttls"
+	SmtpTls      = "tls"
+	SmtpNone     = "none"
+)
+
+// MailMessage is an outgoing mail. Text and Html are sent as alternatives if both are set.
+type MailMessage struct {
+	From    string
+	To      []string
+	Subject string
+	Text    string
+	Html    string
+}
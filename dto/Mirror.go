@@ -0,0 +1,31 @@
+package dto
+
+// MirrorFrontMatter is the YAML front matter of a feature file in the mirror
+type MirrorFrontMatter struct {
+	ID        string          `yaml:"id"`
+	Name      string          `yaml:"name"`
+	Status    string          `yaml:"status"`
+	Parent    MirrorParent    `yaml:"parent"`
+	Product   string          `yaml:"product,omitempty"`
+	Component string          `yaml:"component,omitempty"`
+	Timeframe MirrorTimeframe `yaml:"timeframe"`
+	Archived  bool            `yaml:"archived"`
+	Owner     string          `yaml:"owner,omitempty"`
+	Link      string          `yaml:"link,omitempty"`
+}
+
+type MirrorParent struct {
+	ID   string `yaml:"id,omitempty"`
+	Type string `yaml:"type,omitempty"`
+	Name string `yaml:"name,omitempty"`
+}
+
+type MirrorTimeframe struct {
+	Start string `yaml:"start,omitempty"`
+	End   string `yaml:"end,omitempty"`
+}
+
+type MirrorResult struct {
+	Written int
+	Removed int
+}
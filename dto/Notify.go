@@ -0,0 +1,49 @@
+package dto
+
+const (
+	NotifyFieldName        = "name"
+	NotifyFieldStatus      = "status"
+	NotifyFieldDescription = "description"
+	NotifyFieldTimeframe   = "timeframe"
+	NotifyFieldParent      = "parent"
+	NotifyFieldArchived    = "archived"
+	NotifyFieldOwner       = "owner"
+)
+
+// EventFilter selects feature events. An event matches if it matches all filters; empty filters match everything.
+// Events accepts event types with or without the "feature." prefix, Changed the NotifyField names.
+type EventFilter struct {
+	Events   []string `json:"events"`
+	Statuses []string `json:"statuses"`
+	Products []string `json:"products"`
+	Changed  []string `json:"changed"`
+}
+
+// NotifyRule sends a mail for every feature event matching its filter. To, Subject, Text and Html are Go templates
+// rendered with NotifyData; Html is escaped as HTML.
+type NotifyRule struct {
+	Name string `json:"name"`
+	EventFilter
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	Text    string   `json:"text"`
+	Html    string   `json:"html"`
+}
+
+// FieldChange is a feature field that differs from the state seen with the previous event
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// NotifyData describes a feature event for notifications. For deleted features, Feature holds the last state seen.
+type NotifyData struct {
+	Rule      string
+	Action    string
+	Event     FeatureEvent
+	Feature   Feature
+	Product   string
+	Component string
+	Changes   []FieldChange
+}
@@ -0,0 +1,220 @@
+package dto
+
+import "time"
+
+type PbFeaturesResponse struct {
+	Data  []Feature `json:"data"`
+	Links Links     `json:"links"`
+}
+
+type PbFeatureResponse struct {
+	Data Feature `json:"data"`
+}
+
+type PbProductsResponse struct {
+	Data  []Product `json:"data"`
+	Links Links     `json:"links"`
+}
+
+type PbProductResponse struct {
+	Data Product `json:"data"`
+}
+
+type PbComponentsResponse struct {
+	Data  []Component `json:"data"`
+	Links Links       `json:"links"`
+}
+
+type PbComponentResponse struct {
+	Data Component `json:"data"`
+}
+
+type PbFeatureStatusesResponse struct {
+	Data  []FeatureStatus `json:"data"`
+	Links Links           `json:"links"`
+}
+
+type Feature struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Type        string        `json:"type"`
+	Archived    bool          `json:"archived"`
+	Status      FeatureStatus `json:"status"`
+	Parent      Parent        `json:"parent"`
+	Links       EntityLinks   `json:"links"`
+	Timeframe   Timeframe     `json:"timeframe"`
+	Owner       *Owner        `json:"owner,omitempty"`
+}
+
+type Owner struct {
+	Email string `json:"email"`
+}
+
+type Product struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Links       EntityLinks `json:"links"`
+}
+
+type Component struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Parent      Parent      `json:"parent"`
+	Links       EntityLinks `json:"links"`
+}
+
+type FeatureStatus struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Parent struct {
+	Feature   *ParentRef `json:"feature,omitempty"`
+	Component *ParentRef `json:"component,omitempty"`
+	Product   *ParentRef `json:"product,omitempty"`
+}
+
+type ParentRef struct {
+	ID    string    `json:"id"`
+	Links SelfLinks `json:"links"`
+}
+
+type SelfLinks struct {
+	Self string `json:"self"`
+}
+
+type EntityLinks struct {
+	Self string `json:"self"`
+	Html string `json:"html"`
+}
+
+type Timeframe struct {
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+}
+
+const (
+	StatusAdded   = "added"
+	StatusRenamed = "renamed"
+	StatusRemoved = "removed"
+)
+
+type StatusChange struct {
+	ID      string `json:"id"`
+	Change  string `json:"change"`
+	OldName string `json:"oldName,omitempty"`
+	NewName string `json:"newName,omitempty"`
+}
+
+type PbFeatureUpdateRequest struct {
+	Data FeatureUpdate `json:"data"`
+}
+
+type FeatureUpdate struct {
+	Name        string     `json:"name,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Status      *StatusRef `json:"status,omitempty"`
+	Timeframe   *Timeframe `json:"timeframe,omitempty"`
+	Archived    *bool      `json:"archived,omitempty"`
+}
+
+type PbFeatureCreateRequest struct {
+	Data FeatureCreate `json:"data"`
+}
+
+type FeatureCreate struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Type        string      `json:"type"`
+	Status      StatusRef   `json:"status"`
+	Parent      ParentIdRef `json:"parent"`
+	Timeframe   *Timeframe  `json:"timeframe,omitempty"`
+}
+
+// ParentIdRef references the parent of a new feature. Exactly one of the fields has to be set.
+type ParentIdRef struct {
+	Feature   *IdRef `json:"feature,omitempty"`
+	Component *IdRef `json:"component,omitempty"`
+	Product   *IdRef `json:"product,omitempty"`
+}
+
+type IdRef struct {
+	ID string `json:"id"`
+}
+
+type StatusRef struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type FeatureFilter struct {
+	StatusId   string
+	StatusName string
+	Archived   *bool
+}
+
+// FeatureEvent is a webhook notification after it has been dequeued, enriched with the feature's current data
+type FeatureEvent struct {
+	ID         string
+	EventType  string
+	Target     string
+	ReceivedAt time.Time
+	Feature    *Feature
+	JiraIssues []JiraIssue
+	Reconciled bool
+}
+
+// FeatureSnapshot is the last known state of a feature, used to detect changes missed by webhooks
+type FeatureSnapshot struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+type ReconcileResult struct {
+	Created int
+	Updated int
+	Deleted int
+}
+
+const (
+	NodeTypeProduct    = "product"
+	NodeTypeComponent  = "component"
+	NodeTypeFeature    = "feature"
+	NodeTypeSubfeature = "subfeature"
+)
+
+type HierarchyNode struct {
+	ID       string           `json:"id"`
+	Name     string           `json:"name"`
+	Type     string           `json:"type"`
+	ParentId string           `json:"parentId,omitempty"`
+	Feature  *Feature         `json:"-"`
+	Children []*HierarchyNode `json:"children,omitempty"`
+}
+
+// HierarchyTree places products, components and features below their parents. Unassigned holds the components and
+// features without a parent, Orphans those whose parent is unknown.
+type HierarchyTree struct {
+	Products    []*HierarchyNode `json:"products"`
+	Unassigned  []*HierarchyNode `json:"unassigned,omitempty"`
+	Orphans     []*HierarchyNode `json:"orphans,omitempty"`
+	RefreshedAt time.Time        `json:"refreshedAt"`
+}
+
+// ParentId returns the ID of whichever parent entity is set
+func (p Parent) ParentId() string {
+	switch {
+	case p.Feature != nil:
+		return p.Feature.ID
+	case p.Component != nil:
+		return p.Component.ID
+	case p.Product != nil:
+		return p.Product.ID
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+type PbJiraIntegrationsResponse struct {
+	Data  []JiraIntegration `json:"data"`
+	Links Links             `json:"links"`
+}
+
+type PbJiraIntegrationResponse struct {
+	Data JiraIntegration `json:"data"`
+}
+
+type PbJiraConnectionsResponse struct {
+	Data  []JiraConnectionData `json:"data"`
+	Links Links                `json:"links"`
+}
+
+type PbJiraConnectionResponse struct {
+	Data JiraConnectionData `json:"data"`
+}
+
+type JiraIntegration struct {
+	ID                string               `json:"id"`
+	CreatedAt         time.Time            `json:"createdAt"`
+	IntegrationStatus string               `json:"integrationStatus"`
+	Name              string               `json:"name"`
+	Links             JiraIntegrationLinks `json:"links"`
+}
+
+type JiraIntegrationLinks struct {
+	Self        string `json:"self"`
+	Connections string `json:"connections"`
+}
+
+type JiraConnectionData struct {
+	FeatureId  string         `json:"featureId"`
+	Connection JiraConnection `json:"connection"`
+	Links      SelfLinks      `json:"links"`
+}
+
+type JiraConnection struct {
+	IssueKey string `json:"issueKey"`
+	IssueId  string `json:"issueId"`
+}
+
+type JiraConnectionFilter struct {
+	IssueKey string
+	IssueId  string
+}
+
+// JiraIssue is a Jira issue linked to a feature, as attached to feature events
+type JiraIssue struct {
+	IntegrationId string
+	IssueKey      string
+	IssueId       string
+	Url           string
+}
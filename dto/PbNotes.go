@@ -0,0 +1,40 @@
+package dto
+
+type Note struct {
+	Title         string      `json:"title"`
+	Content       string      `json:"content"`
+	CustomerEmail string      `json:"customer_email,omitempty"`
+	DisplayUrl    string      `json:"display_url,omitempty"`
+	Source        *NoteSource `json:"source,omitempty"`
+	Tags          []string    `json:"tags,omitempty"`
+}
+
+type NoteSource struct {
+	Origin   string `json:"origin"`
+	RecordId string `json:"record_id"`
+}
+
+type PbNoteResponse struct {
+	Links NoteLinks `json:"links"`
+	Data  NoteData  `json:"data"`
+}
+
+type PbNoteErrorResponse struct {
+	Ok     bool                `json:"ok"`
+	Errors map[string][]string `json:"errors"`
+}
+
+type NoteLinks struct {
+	Html string `json:"html"`
+}
+
+type NoteData struct {
+	ID string `json:"id"`
+}
+
+// NoteResult describes the outcome of creating a note. AlreadyExists is set if a note with the same source was created before.
+type NoteResult struct {
+	ID            string `json:"id,omitempty"`
+	Url           string `json:"url,omitempty"`
+	AlreadyExists bool   `json:"alreadyExists"`
+}
@@ -0,0 +1,65 @@
+package dto
+
+const (
+	TriggerPush    = "button.push"
+	TriggerUnlink  = "button.unlink"
+	TriggerDismiss = "button.dismiss"
+
+	ConnectionInitial   = "initial"
+	ConnectionProgress  = "progress"
+	ConnectionConnected = "connected"
+	ConnectionError     = "error"
+)
+
+var (
+	ActionTriggers = []string{TriggerPush, TriggerUnlink, TriggerDismiss}
+)
+
+type PbActionNotification struct {
+	Data ActionNotification `json:"data"`
+}
+
+type ActionNotification struct {
+	Trigger       string        `json:"trigger"`
+	IntegrationId string        `json:"integrationId"`
+	Feature       ActionFeature `json:"feature"`
+	Links         ActionLinks   `json:"links"`
+}
+
+type ActionFeature struct {
+	ID    string    `json:"id"`
+	Type  string    `json:"type"`
+	Links SelfLinks `json:"links"`
+}
+
+type ActionLinks struct {
+	Connection string `json:"connection"`
+}
+
+type PbConnectionRequest struct {
+	Data ConnectionData `json:"data"`
+}
+
+type PbConnectionResponse struct {
+	Data ConnectionData `json:"data"`
+}
+
+type PbConnectionsResponse struct {
+	Data  []ConnectionData `json:"data"`
+	Links Links            `json:"links"`
+}
+
+type ConnectionData struct {
+	FeatureId  string     `json:"featureId,omitempty"`
+	Connection Connection `json:"connection"`
+}
+
+type Connection struct {
+	State      string `json:"state"`
+	Label      string `json:"label,omitempty"`
+	HoverLabel string `json:"hoverLabel,omitempty"`
+	Tooltip    string `json:"tooltip,omitempty"`
+	Color      string `json:"color,omitempty"`
+	TargetUrl  string `json:"targetUrl,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
@@ -0,0 +1,49 @@
+package dto
+
+import "time"
+
+const (
+	IntegrationEnabled  = "enabled"
+	IntegrationDisabled = "disabled"
+)
+
+type PbPluginIntegrationRequest struct {
+	Data PluginIntegrationData `json:"data"`
+}
+
+type PbPluginIntegrationResponse struct {
+	Data PluginIntegration `json:"data"`
+}
+
+type PbPluginIntegrationsResponse struct {
+	Data  []PluginIntegration `json:"data"`
+	Links Links               `json:"links"`
+}
+
+type PluginIntegrationData struct {
+	IntegrationStatus string        `json:"integrationStatus,omitempty"`
+	Type              string        `json:"type,omitempty"`
+	Name              string        `json:"name,omitempty"`
+	InitialState      *InitialState `json:"initialState,omitempty"`
+	Action            *PluginAction `json:"action,omitempty"`
+}
+
+type InitialState struct {
+	Label string `json:"label"`
+}
+
+type PluginAction struct {
+	URL     string   `json:"url"`
+	Version int      `json:"version"`
+	Headers *Headers `json:"headers,omitempty"`
+}
+
+type PluginIntegration struct {
+	ID                string       `json:"id"`
+	CreatedAt         time.Time    `json:"createdAt"`
+	IntegrationStatus string       `json:"integrationStatus"`
+	Type              string       `json:"type"`
+	Name              string       `json:"name"`
+	InitialState      InitialState `json:"initialState"`
+	Links             SelfLinks    `json:"links"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+type RoadmapFilter struct {
+	Products   []string
+	Components []string
+	Statuses   []string
+}
@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+const (
+	SlipSlipped = "feature.slipped"
+	SlipOverdue = "feature.overdue"
+)
+
+// SlipRecord is what pbreact knows about the timeframe history of a feature
+type SlipRecord struct {
+	FeatureId       string     `json:"featureId"`
+	Name            string     `json:"name"`
+	Status          string     `json:"status"`
+	Url             string     `json:"url,omitempty"`
+	StartDate       string     `json:"startDate"`
+	EndDate         string     `json:"endDate"`
+	OriginalEndDate string     `json:"originalEndDate"`
+	SlipCount       int        `json:"slipCount"`
+	DaysSlipped     int        `json:"daysSlipped"`
+	LastSlipAt      *time.Time `json:"lastSlipAt,omitempty"`
+	DaysOverdue     int        `json:"daysOverdue,omitempty"`
+}
+
+type SlipNotification struct {
+	Event      string     `json:"event"`
+	Days       int        `json:"days"`
+	OldEndDate string     `json:"oldEndDate,omitempty"`
+	Feature    SlipRecord `json:"feature"`
+}
+
+type SlipReport struct {
+	GeneratedAt time.Time    `json:"generatedAt"`
+	Overdue     []SlipRecord `json:"overdue"`
+	Slipped     []SlipRecord `json:"slipped"`
+}
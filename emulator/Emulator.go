@@ -0,0 +1,344 @@
+// Package emulator is an in-process stand-in for the Productboard API. It keeps statuses, products, components,
+// features, notes, webhook subscriptions and plugin integrations in memory, probes and calls back webhook and plugin
+// receivers like Productboard does, and answers with 429 when the rate limit is exceeded. Tests use Start to get a
+// server, `pbreact emulate` serves it for running pbreact locally without a Productboard space.
+package emulator
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+)
+
+const (
+	defaultPageLimit       = 100
+	defaultCallbackTimeout = 5 * time.Second
+	deliveryQueueSize      = 1000
+)
+
+// Config configures the emulator. The zero value accepts any token and does not limit the request rate.
+type Config struct {
+	// Token is the API token clients have to send as bearer token. Any token is accepted if it is empty.
+	Token string
+	// RateLimit is the number of requests per second answered before responding with 429. 0 disables the limit.
+	RateLimit int
+	// PageLimit is the default and maximum page size of lists, 100 like Productboard if not set
+	PageLimit int
+	// BaseUrl is where the emulator can be reached, used for the links in responses and notifications. Start sets it.
+	BaseUrl string
+	// InsecureCallbacks skips certificate checks on probes and callbacks, e.g. for pbreact's self-signed certificate
+	InsecureCallbacks bool
+	// CallbackTimeout defaults to the 5 seconds Productboard waits for probes and callbacks
+	CallbackTimeout time.Duration
+}
+
+type Emulator struct {
+	cfg        Config
+	router     *gin.Engine
+	client     *http.Client
+	data       *store
+	rate       *rateWindow
+	deliveries *deliveryQueue
+	server     *httptest.Server
+}
+
+// Seed is the initial content of the emulator. Parents reference ids; missing ids are generated.
+type Seed struct {
+	Statuses   []dto.FeatureStatus `json:"statuses"`
+	Products   []dto.Product       `json:"products"`
+	Components []dto.Component     `json:"components"`
+	Features   []dto.Feature       `json:"features"`
+}
+
+type rateWindow struct {
+	sync.Mutex
+	second int64
+	count  int
+}
+
+// pbError is an error in the format of the Productboard API
+type pbError struct {
+	status int
+	Code   string `json:"code"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+type errorResponse struct {
+	Errors []*pbError `json:"errors"`
+}
+
+func New(c Config) *Emulator {
+	if c.PageLimit <= 0 || c.PageLimit > defaultPageLimit {
+		c.PageLimit = defaultPageLimit
+	}
+	if c.CallbackTimeout <= 0 {
+		c.CallbackTimeout = defaultCallbackTimeout
+	}
+	c.BaseUrl = strings.TrimSuffix(c.BaseUrl, "/")
+	e := Emulator{
+		cfg: c,
+		client: &http.Client{
+			Timeout: c.CallbackTimeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: c.InsecureCallbacks},
+			},
+		},
+		data:       newStore(),
+		rate:       &rateWindow{},
+		deliveries: newDeliveryQueue(),
+	}
+	e.initRouter()
+	go e.deliver()
+	return &e
+}
+
+func (e *Emulator) initRouter() {
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(e.limitRate, e.authenticate)
+	router.GET("/features", e.getFeatures)
+	router.POST("/features", e.postFeature)
+	router.GET("/features/:id", e.getFeature)
+	router.PUT("/features/:id", e.putFeature)
+	router.GET("/feature-statuses", e.getStatuses)
+	router.GET("/products", e.getProducts)
+	router.GET("/products/:id", e.getProduct)
+	router.GET("/components", e.getComponents)
+	router.GET("/components/:id", e.getComponent)
+	router.POST("/notes", e.postNote)
+	router.POST("/webhooks", e.postWebhook)
+	router.GET("/webhooks", e.getWebhooks)
+	router.GET("/webhooks/:id", e.getWebhook)
+	router.DELETE("/webhooks/:id", e.deleteWebhook)
+	router.POST("/plugin-integrations", e.postIntegration)
+	router.GET("/plugin-integrations", e.getIntegrations)
+	router.GET("/plugin-integrations/:id", e.getIntegration)
+	router.PUT("/plugin-integrations/:id", e.putIntegration)
+	router.DELETE("/plugin-integrations/:id", e.deleteIntegration)
+	router.GET("/plugin-integrations/:id/connections", e.getConnections)
+	router.GET("/plugin-integrations/:id/connections/:featureId", e.getConnection)
+	router.PUT("/plugin-integrations/:id/connections/:featureId", e.putConnection)
+	router.DELETE("/plugin-integrations/:id/connections/:featureId", e.deleteConnection)
+	e.router = router
+}
+
+func (e *Emulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.router.ServeHTTP(w, r)
+}
+
+// Start serves the emulator on a local port and returns its base URL, to be used as PB_BASE_URL
+func (e *Emulator) Start() string {
+	e.server = httptest.NewServer(e)
+	e.cfg.BaseUrl = e.server.URL
+	return e.server.URL + "/"
+}
+
+// Close waits for pending notifications and stops the server started with Start
+func (e *Emulator) Close() {
+	e.deliveries.close()
+	if e.server != nil {
+		e.server.Close()
+	}
+}
+
+// Seed adds statuses, products, components and features without sending notifications
+func (e *Emulator) Seed(seed Seed) {
+	e.data.Lock()
+	defer e.data.Unlock()
+	for _, status := range seed.Statuses {
+		if status.ID == "" {
+			status.ID = newId()
+		}
+		e.data.statuses = append(e.data.statuses, status)
+	}
+	for _, product := range seed.Products {
+		if product.ID == "" {
+			product.ID = newId()
+		}
+		e.data.products = append(e.data.products, product)
+	}
+	for _, component := range seed.Components {
+		if component.ID == "" {
+			component.ID = newId()
+		}
+		e.data.components = append(e.data.components, component)
+	}
+	for _, feature := range seed.Features {
+		if feature.ID == "" {
+			feature.ID = newId()
+		}
+		if feature.Type == "" {
+			feature.Type = dto.NodeTypeFeature
+			if feature.Parent.Feature != nil {
+				feature.Type = dto.NodeTypeSubfeature
+			}
+		}
+		if status, err := e.data.resolveStatus(dto.StatusRef{ID: feature.Status.ID}); err == nil {
+			feature.Status = *status
+		}
+		feature.Timeframe = noTimeframe(feature.Timeframe)
+		e.data.features = append(e.data.features, feature)
+	}
+}
+
+func LoadSeed(file string) (*Seed, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var seed Seed
+	if err := json.Unmarshal(content, &seed); err != nil {
+		return nil, err
+	}
+	return &seed, nil
+}
+
+// DemoSeed is a small workspace to try pbreact with
+func DemoSeed() Seed {
+	return Seed{
+		Statuses: []dto.FeatureStatus{
+			{ID: "s1", Name: "New idea"},
+			{ID: "s2", Name: "Planned"},
+			{ID: "s3", Name: "In progress"},
+			{ID: "s4", Name: "Released"},
+			{ID: "s5", Name: "Won't do"},
+		},
+		Products: []dto.Product{
+			{ID: "p1", Name: "Portal"},
+			{ID: "p2", Name: "Analytics"},
+		},
+		Components: []dto.Component{
+			{ID: "c1", Name: "Branding", Parent: dto.Parent{Product: &dto.ParentRef{ID: "p1"}}},
+			{ID: "c2", Name: "Login", Parent: dto.Parent{Product: &dto.ParentRef{ID: "p1"}}},
+			{ID: "c3", Name: "Dashboards", Parent: dto.Parent{Product: &dto.ParentRef{ID: "p2"}}},
+		},
+		Features: []dto.Feature{
+			{ID: "f1", Name: "Dark mode", Description: "<p>Follow the theme of the operating system.</p>",
+				Status: dto.FeatureStatus{ID: "s3", Name: "In progress"}, Parent: dto.Parent{Component: &dto.ParentRef{ID: "c1"}},
+				Timeframe: dto.Timeframe{StartDate: "2024-01-01", EndDate: "2024-03-31"}},
+			{ID: "f2", Name: "Custom logo", Status: dto.FeatureStatus{ID: "s2", Name: "Planned"},
+				Parent: dto.Parent{Feature: &dto.ParentRef{ID: "f1"}}},
+			{ID: "f3", Name: "Single sign-on", Status: dto.FeatureStatus{ID: "s1", Name: "New idea"},
+				Parent: dto.Parent{Component: &dto.ParentRef{ID: "c2"}}},
+			{ID: "f4", Name: "Export to CSV", Status: dto.FeatureStatus{ID: "s4", Name: "Released"},
+				Parent: dto.Parent{Component: &dto.ParentRef{ID: "c3"}}},
+		},
+	}
+}
+
+// limitRate counts requests per second like Productboard's API gateway and sends its rate limit headers
+func (e *Emulator) limitRate(c *gin.Context) {
+	if e.cfg.RateLimit <= 0 {
+		return
+	}
+	e.rate.Lock()
+	now := time.Now().Unix()
+	if now != e.rate.second {
+		e.rate.second, e.rate.count = now, 0
+	}
+	e.rate.count++
+	remaining := e.cfg.RateLimit - e.rate.count
+	e.rate.Unlock()
+	c.Header("X-RateLimit-Limit-Second", strconv.Itoa(e.cfg.RateLimit))
+	if remaining < 0 {
+		c.Header("X-RateLimit-Remaining-Second", "0")
+		c.Header("Retry-After", "1")
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"message": "API rate limit exceeded"})
+		return
+	}
+	c.Header("X-RateLimit-Remaining-Second", strconv.Itoa(remaining))
+}
+
+// authenticate checks the bearer token and, except for the older notes API, the X-Version header
+func (e *Emulator) authenticate(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") || (e.cfg.Token != "" && auth != "Bearer "+e.cfg.Token) {
+		err := &pbError{status: http.StatusUnauthorized, Code: "authentication.failed", Title: "Unauthorized",
+			Detail: "The access token is missing or invalid."}
+		c.AbortWithStatusJSON(err.status, errorResponse{Errors: []*pbError{err}})
+		return
+	}
+	if c.FullPath() != "/notes" && c.GetHeader("X-Version") != "1" {
+		err := validationError("Header X-Version is missing or not 1.")
+		c.AbortWithStatusJSON(err.status, errorResponse{Errors: []*pbError{err}})
+	}
+}
+
+// paginate returns the range of the requested page and the link to the next one
+func (e *Emulator) paginate(c *gin.Context, total int) (int, int, dto.Links, error) {
+	limit, offset := e.cfg.PageLimit, 0
+	var err error
+	if value := c.Query("pageLimit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > e.cfg.PageLimit {
+			return 0, 0, dto.Links{}, validationError(fmt.Sprintf("pageLimit has to be between 1 and %v.", e.cfg.PageLimit))
+		}
+	}
+	if value := c.Query("pageOffset"); value != "" {
+		if offset, err = strconv.Atoi(value); err != nil || offset < 0 {
+			return 0, 0, dto.Links{}, validationError("pageOffset has to be 0 or more.")
+		}
+	}
+	start, end := offset, offset+limit
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	links := dto.Links{}
+	if end < total {
+		query := c.Request.URL.Query()
+		query.Set("pageLimit", strconv.Itoa(limit))
+		query.Set("pageOffset", strconv.Itoa(end))
+		links.Next = fmt.Sprintf("%v%v?%v", e.baseUrl(c), c.Request.URL.Path, query.Encode())
+	}
+	return start, end, links, nil
+}
+
+// baseUrl prefers the configured URL, so links work behind proxies, and falls back to the host the request was sent to
+func (e *Emulator) baseUrl(c *gin.Context) string {
+	if e.cfg.BaseUrl != "" || c == nil {
+		return e.cfg.BaseUrl
+	}
+	return "http://" + c.Request.Host
+}
+
+func (e *Emulator) writeError(c *gin.Context, err error) {
+	var pe *pbError
+	if !errors.As(err, &pe) {
+		pe = &pbError{status: http.StatusInternalServerError, Code: "system.internalServerError", Title: "Internal server error", Detail: err.Error()}
+	}
+	c.JSON(pe.status, errorResponse{Errors: []*pbError{pe}})
+}
+
+func (e *pbError) Error() string {
+	return e.Detail
+}
+
+func validationError(detail string) *pbError {
+	return &pbError{status: http.StatusBadRequest, Code: "validation.request", Title: "Validation error", Detail: detail}
+}
+
+func notFoundError(code string, kind string, id string) *pbError {
+	return &pbError{status: http.StatusNotFound, Code: code, Title: kind + " not found",
+		Detail: fmt.Sprintf("%v with ID '%v' could not be found.", kind, id)}
+}
+
+// validCallbackUrl accepts http besides https, so receivers on the developer's machine can be used
+func validCallbackUrl(callback string) bool {
+	parsed, err := url.Parse(callback)
+	return err == nil && (parsed.Scheme == "https" || parsed.Scheme == "http") && parsed.Host != ""
+}
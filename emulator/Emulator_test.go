@@ -0,0 +1,89 @@
+package emulator
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	cfg  config.AppConfig
+	emu  *Emulator
+	repo repository.PbApiRepository
+)
+
+func setupEmulator(t *testing.T, c Config) func() {
+	gin.SetMode(gin.TestMode)
+	emu = New(c)
+	emu.Seed(DemoSeed())
+	cfg = config.AppConfig{}
+	cfg.PbApi.BaseUrl = emu.Start()
+	cfg.PbApi.ApiToken = "token"
+	repo = repository.NewPbApiRepository(&cfg)
+	return func() {
+		emu.Close()
+	}
+}
+
+func Test_Emulator_WrongToken_Returns_Unauthorized(t *testing.T) {
+	teardown := setupEmulator(t, Config{Token: "secret"})
+	defer teardown()
+
+	_, err := repo.GetProducts()
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Message(), "Status code: 401")
+}
+
+func Test_Emulator_MissingVersion_Returns_BadRequest(t *testing.T) {
+	teardown := setupEmulator(t, Config{})
+	defer teardown()
+	req, _ := repo.PrepareHttpRequest("GET", cfg.PbApi.BaseUrl+"products", nil)
+	req.Header.Del("X-Version")
+
+	status, body, err := repo.SendHttpRequest(req)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, status)
+	assert.Contains(t, string(*body), "X-Version")
+}
+
+func Test_Emulator_RateLimit_Returns_TooManyRequests(t *testing.T) {
+	teardown := setupEmulator(t, Config{RateLimit: 2})
+	defer teardown()
+	limited := 0
+	retryAfter := ""
+
+	for i := 0; i < 10; i++ {
+		req, _ := repo.PrepareHttpRequest("GET", cfg.PbApi.BaseUrl+"products", nil)
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusTooManyRequests {
+			limited++
+			retryAfter = resp.Header.Get("Retry-After")
+		}
+	}
+
+	assert.GreaterOrEqual(t, limited, 6)
+	assert.EqualValues(t, "1", retryAfter)
+}
+
+func Test_Emulator_Lists_Follow_NextLinks(t *testing.T) {
+	teardown := setupEmulator(t, Config{PageLimit: 3})
+	defer teardown()
+
+	features, err := repo.GetFeatures(dto.FeatureFilter{})
+	statuses, statusErr := repo.GetFeatureStatuses()
+
+	assert.Nil(t, err)
+	assert.Nil(t, statusErr)
+	assert.EqualValues(t, 4, len(features))
+	assert.EqualValues(t, "f4", features[3].ID)
+	assert.EqualValues(t, 5, len(statuses))
+}
@@ -0,0 +1,476 @@
+package emulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+)
+
+const (
+	noTimeframeDate = "none"
+)
+
+var (
+	// featureUpdateFields are the fields a feature update can set, everything else is rejected like the API does
+	featureUpdateFields = map[string]bool{"name": true, "description": true, "archived": true, "status": true, "timeframe": true}
+)
+
+type store struct {
+	sync.Mutex
+	statuses      []dto.FeatureStatus
+	products      []dto.Product
+	components    []dto.Component
+	features      []dto.Feature
+	notes         []Note
+	subscriptions []subscription
+	integrations  []integration
+	connections   map[string]map[string]dto.Connection
+}
+
+func newStore() *store {
+	return &store{
+		connections: make(map[string]map[string]dto.Connection),
+	}
+}
+
+func newId() string {
+	id, _ := uuid.NewV4()
+	return id.String()
+}
+
+func (e *Emulator) getFeatures(c *gin.Context) {
+	var archived *bool
+	if value := c.Query("archived"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			e.writeError(c, validationError("archived has to be true or false."))
+			return
+		}
+		archived = &parsed
+	}
+	statusId, statusName := c.Query("status.id"), c.Query("status.name")
+	features := []dto.Feature{}
+	e.data.Lock()
+	for _, feature := range e.data.features {
+		if (statusId == "" || feature.Status.ID == statusId) && (statusName == "" || feature.Status.Name == statusName) &&
+			(archived == nil || feature.Archived == *archived) {
+			features = append(features, e.featureLinks(c, feature))
+		}
+	}
+	e.data.Unlock()
+	start, end, links, err := e.paginate(c, len(features))
+	if err != nil {
+		e.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PbFeaturesResponse{Data: features[start:end], Links: links})
+}
+
+func (e *Emulator) getFeature(c *gin.Context) {
+	e.data.Lock()
+	feature, err := e.data.feature(c.Param("id"))
+	e.data.Unlock()
+	if err != nil {
+		e.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PbFeatureResponse{Data: e.featureLinks(c, *feature)})
+}
+
+func (e *Emulator) postFeature(c *gin.Context) {
+	var req dto.PbFeatureCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		e.writeError(c, validationError(fmt.Sprintf("Invalid request body: %v", err)))
+		return
+	}
+	feature, err := e.createFeature(req.Data)
+	if err != nil {
+		e.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.PbFeatureResponse{Data: e.featureLinks(c, *feature)})
+}
+
+func (e *Emulator) putFeature(c *gin.Context) {
+	body, _ := c.GetRawData()
+	var fields struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	var req dto.PbFeatureUpdateRequest
+	if err := json.Unmarshal(body, &fields); err != nil {
+		e.writeError(c, validationError(fmt.Sprintf("Invalid request body: %v", err)))
+		return
+	}
+	for field := range fields.Data {
+		if !featureUpdateFields[field] {
+			e.writeError(c, validationError(fmt.Sprintf("Field %v cannot be updated.", field)))
+			return
+		}
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		e.writeError(c, validationError(fmt.Sprintf("Invalid request body: %v", err)))
+		return
+	}
+	feature, err := e.updateFeature(c.Param("id"), req.Data)
+	if err != nil {
+		e.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PbFeatureResponse{Data: e.featureLinks(c, *feature)})
+}
+
+func (e *Emulator) getStatuses(c *gin.Context) {
+	e.data.Lock()
+	statuses := append([]dto.FeatureStatus{}, e.data.statuses...)
+	e.data.Unlock()
+	start, end, links, err := e.paginate(c, len(statuses))
+	if err != nil {
+		e.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PbFeatureStatusesResponse{Data: statuses[start:end], Links: links})
+}
+
+func (e *Emulator) getProducts(c *gin.Context) {
+	products := []dto.Product{}
+	e.data.Lock()
+	for _, product := range e.data.products {
+		products = append(products, e.productLinks(c, product))
+	}
+	e.data.Unlock()
+	start, end, links, err := e.paginate(c, len(products))
+	if err != nil {
+		e.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PbProductsResponse{Data: products[start:end], Links: links})
+}
+
+func (e *Emulator) getProduct(c *gin.Context) {
+	e.data.Lock()
+	defer e.data.Unlock()
+	for _, product := range e.data.products {
+		if product.ID == c.Param("id") {
+			c.JSON(http.StatusOK, dto.PbProductResponse{Data: e.productLinks(c, product)})
+			return
+		}
+	}
+	e.writeError(c, notFoundError("product.notFound", "Product", c.Param("id")))
+}
+
+func (e *Emulator) getComponents(c *gin.Context) {
+	components := []dto.Component{}
+	e.data.Lock()
+	for _, component := range e.data.components {
+		components = append(components, e.componentLinks(c, component))
+	}
+	e.data.Unlock()
+	start, end, links, err := e.paginate(c, len(components))
+	if err != nil {
+		e.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PbComponentsResponse{Data: components[start:end], Links: links})
+}
+
+func (e *Emulator) getComponent(c *gin.Context) {
+	e.data.Lock()
+	defer e.data.Unlock()
+	for _, component := range e.data.components {
+		if component.ID == c.Param("id") {
+			c.JSON(http.StatusOK, dto.PbComponentResponse{Data: e.componentLinks(c, component)})
+			return
+		}
+	}
+	e.writeError(c, notFoundError("component.notFound", "Component", c.Param("id")))
+}
+
+// CreateFeature adds a feature as if it was created in Productboard and notifies the webhook subscriptions
+func (e *Emulator) CreateFeature(create dto.FeatureCreate) (*dto.Feature, error) {
+	feature, err := e.createFeature(create)
+	if err != nil {
+		return nil, err
+	}
+	linked := e.featureLinks(nil, *feature)
+	return &linked, nil
+}
+
+// UpdateFeature changes a feature as if it was edited in Productboard and notifies the webhook subscriptions
+func (e *Emulator) UpdateFeature(id string, update dto.FeatureUpdate) (*dto.Feature, error) {
+	feature, err := e.updateFeature(id, update)
+	if err != nil {
+		return nil, err
+	}
+	linked := e.featureLinks(nil, *feature)
+	return &linked, nil
+}
+
+// DeleteFeature removes a feature with its subfeatures, which the API cannot do, and notifies the webhook subscriptions
+func (e *Emulator) DeleteFeature(id string) error {
+	e.data.Lock()
+	if _, err := e.data.feature(id); err != nil {
+		e.data.Unlock()
+		return err
+	}
+	deleted := []string{}
+	remaining := []dto.Feature{}
+	for _, feature := range e.data.features {
+		if feature.ID == id || feature.Parent.ParentId() == id {
+			deleted = append(deleted, feature.ID)
+			continue
+		}
+		remaining = append(remaining, feature)
+	}
+	e.data.features = remaining
+	for _, connections := range e.data.connections {
+		for _, featureId := range deleted {
+			delete(connections, featureId)
+		}
+	}
+	e.data.Unlock()
+	for _, featureId := range deleted {
+		e.notify(dto.PbEventTypes["featureDelete"], featureId)
+	}
+	return nil
+}
+
+func (e *Emulator) createFeature(create dto.FeatureCreate) (*dto.Feature, error) {
+	if create.Name == "" {
+		return nil, validationError("Feature name cannot be empty.")
+	}
+	if create.Type != dto.NodeTypeFeature && create.Type != dto.NodeTypeSubfeature {
+		return nil, validationError("Feature type has to be feature or subfeature.")
+	}
+	e.data.Lock()
+	status, err := e.data.resolveStatus(create.Status)
+	if err != nil {
+		e.data.Unlock()
+		return nil, err
+	}
+	parent, err := e.data.resolveParent(create.Parent, create.Type)
+	if err != nil {
+		e.data.Unlock()
+		return nil, err
+	}
+	timeframe := dto.Timeframe{}
+	if create.Timeframe != nil {
+		if timeframe, err = validTimeframe(*create.Timeframe); err != nil {
+			e.data.Unlock()
+			return nil, err
+		}
+	}
+	feature := dto.Feature{
+		ID:          newId(),
+		Name:        create.Name,
+		Description: create.Description,
+		Type:        create.Type,
+		Status:      *status,
+		Parent:      *parent,
+		Timeframe:   noTimeframe(timeframe),
+	}
+	e.data.features = append(e.data.features, feature)
+	e.data.Unlock()
+	e.notify(dto.PbEventTypes["featureCreate"], feature.ID)
+	return &feature, nil
+}
+
+func (e *Emulator) updateFeature(id string, update dto.FeatureUpdate) (*dto.Feature, error) {
+	e.data.Lock()
+	feature, err := e.data.feature(id)
+	if err != nil {
+		e.data.Unlock()
+		return nil, err
+	}
+	changed := *feature
+	if update.Name != "" {
+		changed.Name = update.Name
+	}
+	if update.Description != "" {
+		changed.Description = update.Description
+	}
+	if update.Status != nil {
+		status, err := e.data.resolveStatus(*update.Status)
+		if err != nil {
+			e.data.Unlock()
+			return nil, err
+		}
+		changed.Status = *status
+	}
+	if update.Timeframe != nil {
+		timeframe, err := validTimeframe(*update.Timeframe)
+		if err != nil {
+			e.data.Unlock()
+			return nil, err
+		}
+		changed.Timeframe = noTimeframe(timeframe)
+	}
+	if update.Archived != nil {
+		changed.Archived = *update.Archived
+	}
+	*feature = changed
+	e.data.Unlock()
+	e.notify(dto.PbEventTypes["featureUpdate"], id)
+	return &changed, nil
+}
+
+// feature returns a pointer into the store; the lock has to be held while using it
+func (s *store) feature(id string) (*dto.Feature, error) {
+	for i := range s.features {
+		if s.features[i].ID == id {
+			return &s.features[i], nil
+		}
+	}
+	return nil, notFoundError("feature.notFound", "Feature", id)
+}
+
+// resolveStatus finds a status by id or by name, with the errors Productboard answers for both, neither or duplicate names
+func (s *store) resolveStatus(ref dto.StatusRef) (*dto.FeatureStatus, error) {
+	switch {
+	case ref.ID != "" && ref.Name != "":
+		return nil, &pbError{status: http.StatusBadRequest, Code: "featureStatus.idAndNameSpecified", Title: "Feature status ambiguous",
+			Detail: "Specify either the id or the name of the feature status, not both."}
+	case ref.ID == "" && ref.Name == "":
+		return nil, &pbError{status: http.StatusBadRequest, Code: "featureStatus.notSpecified", Title: "Feature status not specified",
+			Detail: "Specify the id or the name of the feature status."}
+	}
+	found := []dto.FeatureStatus{}
+	for _, status := range s.statuses {
+		if (ref.ID != "" && status.ID == ref.ID) || (ref.Name != "" && status.Name == ref.Name) {
+			found = append(found, status)
+		}
+	}
+	switch {
+	case len(found) > 1:
+		return nil, &pbError{status: http.StatusBadRequest, Code: "featureStatus.name.ambiguous", Title: "Feature status name ambiguous",
+			Detail: fmt.Sprintf("There is more than one feature status named '%v'.", ref.Name)}
+	case len(found) == 0 && ref.ID != "":
+		return nil, notFoundError("featureStatus.notFound", "Feature status", ref.ID)
+	case len(found) == 0:
+		return nil, &pbError{status: http.StatusNotFound, Code: "featureStatus.notFound", Title: "Feature status not found",
+			Detail: fmt.Sprintf("Feature status named '%v' could not be found.", ref.Name)}
+	}
+	return &found[0], nil
+}
+
+// resolveParent checks that exactly one parent is given and that it can hold a feature of the given type: features go
+// below products and components, subfeatures below features
+func (s *store) resolveParent(ref dto.ParentIdRef, featureType string) (*dto.Parent, error) {
+	set := 0
+	for _, r := range []*dto.IdRef{ref.Feature, ref.Component, ref.Product} {
+		if r != nil {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, validationError("Exactly one parent has to be specified.")
+	}
+	typeError := &pbError{status: http.StatusBadRequest, Code: "type.invalid", Title: "Invalid type",
+		Detail: fmt.Sprintf("The parent cannot hold a %v.", featureType)}
+	switch {
+	case ref.Feature != nil:
+		parent, err := s.feature(ref.Feature.ID)
+		if err != nil {
+			return nil, notFoundError("parent.notFound", "Parent", ref.Feature.ID)
+		}
+		if parent.Type != dto.NodeTypeFeature {
+			return nil, &pbError{status: http.StatusBadRequest, Code: "parent.invalid", Title: "Invalid parent",
+				Detail: "A subfeature cannot be the parent of another feature."}
+		}
+		if featureType != dto.NodeTypeSubfeature {
+			return nil, typeError
+		}
+		return &dto.Parent{Feature: &dto.ParentRef{ID: parent.ID}}, nil
+	case featureType != dto.NodeTypeFeature:
+		return nil, typeError
+	case ref.Component != nil:
+		for _, component := range s.components {
+			if component.ID == ref.Component.ID {
+				return &dto.Parent{Component: &dto.ParentRef{ID: component.ID}}, nil
+			}
+		}
+		return nil, notFoundError("parent.notFound", "Parent", ref.Component.ID)
+	default:
+		for _, product := range s.products {
+			if product.ID == ref.Product.ID {
+				return &dto.Parent{Product: &dto.ParentRef{ID: product.ID}}, nil
+			}
+		}
+		return nil, notFoundError("parent.notFound", "Parent", ref.Product.ID)
+	}
+}
+
+func validTimeframe(timeframe dto.Timeframe) (dto.Timeframe, error) {
+	for _, date := range []string{timeframe.StartDate, timeframe.EndDate} {
+		if date == "" || date == noTimeframeDate {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return timeframe, &pbError{status: http.StatusBadRequest, Code: "startDate.invalidValue", Title: "Invalid date",
+				Detail: fmt.Sprintf("'%v' is not a date in the format YYYY-MM-DD.", date)}
+		}
+	}
+	return timeframe, nil
+}
+
+// noTimeframe fills unset dates with "none", as Productboard returns them
+func noTimeframe(timeframe dto.Timeframe) dto.Timeframe {
+	if timeframe.StartDate == "" {
+		timeframe.StartDate = noTimeframeDate
+	}
+	if timeframe.EndDate == "" {
+		timeframe.EndDate = noTimeframeDate
+	}
+	return timeframe
+}
+
+func (e *Emulator) featureLinks(c *gin.Context, feature dto.Feature) dto.Feature {
+	base := e.baseUrl(c)
+	feature.Links = dto.EntityLinks{
+		Self: fmt.Sprintf("%v/features/%v", base, feature.ID),
+		Html: fmt.Sprintf("%v/app/features/%v", base, feature.ID),
+	}
+	// like the API, only the parent of subfeatures is returned; the product or component of a feature is kept internally
+	feature.Parent = parentLinks(base, dto.Parent{Feature: feature.Parent.Feature})
+	return feature
+}
+
+func (e *Emulator) productLinks(c *gin.Context, product dto.Product) dto.Product {
+	base := e.baseUrl(c)
+	product.Links = dto.EntityLinks{
+		Self: fmt.Sprintf("%v/products/%v", base, product.ID),
+		Html: fmt.Sprintf("%v/app/products/%v", base, product.ID),
+	}
+	return product
+}
+
+func (e *Emulator) componentLinks(c *gin.Context, component dto.Component) dto.Component {
+	base := e.baseUrl(c)
+	component.Links = dto.EntityLinks{
+		Self: fmt.Sprintf("%v/components/%v", base, component.ID),
+		Html: fmt.Sprintf("%v/app/components/%v", base, component.ID),
+	}
+	// the API does not return the parent of components either
+	component.Parent = dto.Parent{}
+	return component
+}
+
+func parentLinks(base string, parent dto.Parent) dto.Parent {
+	link := func(ref *dto.ParentRef, kind string) *dto.ParentRef {
+		if ref == nil {
+			return nil
+		}
+		return &dto.ParentRef{ID: ref.ID, Links: dto.SelfLinks{Self: fmt.Sprintf("%v/%v/%v", base, kind, ref.ID)}}
+	}
+	return dto.Parent{
+		Feature:   link(parent.Feature, "features"),
+		Component: link(parent.Component, "components"),
+		Product:   link(parent.Product, "products"),
+	}
+}
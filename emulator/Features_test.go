@@ -0,0 +1,91 @@
+package emulator
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CreateFeature_Via_Api_Resolves_StatusName(t *testing.T) {
+	teardown := setupEmulator(t, Config{})
+	defer teardown()
+
+	created, err := repo.CreateFeature(dto.FeatureCreate{Name: "Audit log", Type: dto.NodeTypeFeature,
+		Status: dto.StatusRef{Name: "Planned"}, Parent: dto.ParentIdRef{Product: &dto.IdRef{ID: "p2"}}})
+	read, readErr := repo.GetFeature(created.ID)
+
+	assert.Nil(t, err)
+	assert.Nil(t, readErr)
+	assert.EqualValues(t, dto.FeatureStatus{ID: "s2", Name: "Planned"}, read.Status)
+	assert.EqualValues(t, dto.Timeframe{StartDate: "none", EndDate: "none"}, read.Timeframe)
+	assert.EqualValues(t, "", read.Parent.ParentId())
+	assert.EqualValues(t, cfg.PbApi.BaseUrl+"features/"+created.ID, read.Links.Self)
+}
+
+func Test_CreateFeature_InvalidParentOrStatus_Returns_Error(t *testing.T) {
+	teardown := setupEmulator(t, Config{})
+	defer teardown()
+	emu.Seed(Seed{Statuses: []dto.FeatureStatus{{ID: "s6", Name: "Planned"}}})
+
+	_, typeErr := repo.CreateFeature(dto.FeatureCreate{Name: "Logo", Type: dto.NodeTypeSubfeature,
+		Status: dto.StatusRef{ID: "s1"}, Parent: dto.ParentIdRef{Component: &dto.IdRef{ID: "c1"}}})
+	_, nestedErr := repo.CreateFeature(dto.FeatureCreate{Name: "Logo", Type: dto.NodeTypeSubfeature,
+		Status: dto.StatusRef{ID: "s1"}, Parent: dto.ParentIdRef{Feature: &dto.IdRef{ID: "f2"}}})
+	_, statusErr := repo.UpdateFeature("f1", dto.FeatureUpdate{Status: &dto.StatusRef{Name: "Planned"}})
+
+	assert.Contains(t, typeErr.Message(), "type.invalid")
+	assert.Contains(t, nestedErr.Message(), "parent.invalid")
+	assert.Contains(t, statusErr.Message(), "featureStatus.name.ambiguous")
+}
+
+func Test_UpdateFeature_Via_Api_Changes_Fields_And_Filters(t *testing.T) {
+	teardown := setupEmulator(t, Config{})
+	defer teardown()
+	archived := true
+
+	updated, err := repo.UpdateFeature("f3", dto.FeatureUpdate{
+		Status:    &dto.StatusRef{ID: "s5"},
+		Archived:  &archived,
+		Timeframe: &dto.Timeframe{StartDate: "2024-04-01", EndDate: "none"},
+	})
+	archivedFeatures, _ := repo.GetFeatures(dto.FeatureFilter{Archived: &archived})
+	wontDo, _ := repo.GetFeatures(dto.FeatureFilter{StatusName: "Won't do"})
+	_, dateErr := repo.UpdateFeature("f3", dto.FeatureUpdate{Timeframe: &dto.Timeframe{StartDate: "April"}})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Won't do", updated.Status.Name)
+	assert.EqualValues(t, []string{"f3"}, []string{archivedFeatures[0].ID})
+	assert.EqualValues(t, 1, len(wontDo))
+	assert.Contains(t, dateErr.Message(), "startDate.invalidValue")
+}
+
+func Test_UpdateFeature_Via_Api_UnknownField_Returns_BadRequest(t *testing.T) {
+	teardown := setupEmulator(t, Config{})
+	defer teardown()
+	body := strings.NewReader(`{"data": {"name": "Moved", "parent": {"component": {"id": "c3"}}}}`)
+	req, _ := repo.PrepareHttpRequest("PUT", cfg.PbApi.BaseUrl+"features/f3", body)
+
+	status, response, err := repo.SendHttpRequest(req)
+	feature, _ := repo.GetFeature("f3")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, status)
+	assert.Contains(t, string(*response), "Field parent cannot be updated.")
+	assert.NotEqualValues(t, "Moved", feature.Name)
+}
+
+func Test_DeleteFeature_Removes_Subfeatures(t *testing.T) {
+	teardown := setupEmulator(t, Config{})
+	defer teardown()
+
+	err := emu.DeleteFeature("f1")
+	_, getErr := repo.GetFeature("f2")
+	features, _ := repo.GetFeatures(dto.FeatureFilter{})
+
+	assert.Nil(t, err)
+	assert.Contains(t, getErr.Message(), "feature.notFound")
+	assert.EqualValues(t, 2, len(features))
+}
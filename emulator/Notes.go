@@ -0,0 +1,67 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+)
+
+// Note is a note as it was pushed, with the partner id it was pushed with
+type Note struct {
+	ID        string
+	PartnerId string
+	dto.Note
+}
+
+func (e *Emulator) postNote(c *gin.Context) {
+	var note dto.Note
+	if err := c.ShouldBindJSON(&note); err != nil {
+		c.JSON(http.StatusBadRequest, dto.PbNoteErrorResponse{Errors: map[string][]string{"body": {err.Error()}}})
+		return
+	}
+	errs := map[string][]string{}
+	if note.Title == "" {
+		errs["title"] = []string{"is missing"}
+	}
+	if note.Content == "" {
+		errs["content"] = []string{"is missing"}
+	}
+	if note.DisplayUrl != "" {
+		if parsed, err := url.ParseRequestURI(note.DisplayUrl); err != nil || parsed.Host == "" {
+			errs["display_url"] = []string{"is not a properly formatted url"}
+		}
+	}
+	e.data.Lock()
+	defer e.data.Unlock()
+	if note.Source != nil {
+		for _, existing := range e.data.notes {
+			if existing.Source != nil && *existing.Source == *note.Source {
+				errs["source"] = []string{"already exists"}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, dto.PbNoteErrorResponse{Ok: false, Errors: errs})
+		return
+	}
+	stored := Note{
+		ID:        newId(),
+		PartnerId: c.GetHeader("Productboard-Partner-Id"),
+		Note:      note,
+	}
+	e.data.notes = append(e.data.notes, stored)
+	c.JSON(http.StatusCreated, dto.PbNoteResponse{
+		Links: dto.NoteLinks{Html: fmt.Sprintf("%v/app/inbox/notes/%v", e.baseUrl(c), stored.ID)},
+		Data:  dto.NoteData{ID: stored.ID},
+	})
+}
+
+// Notes returns all notes pushed so far
+func (e *Emulator) Notes() []Note {
+	e.data.Lock()
+	defer e.data.Unlock()
+	return append([]Note{}, e.data.notes...)
+}
@@ -0,0 +1,28 @@
+package emulator
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CreateNote_SameSource_Returns_AlreadyExists(t *testing.T) {
+	teardown := setupEmulator(t, Config{})
+	defer teardown()
+	cfg.PbApi.PartnerId = "partner"
+	note := dto.Note{Title: "Dark mode please", Content: "My eyes hurt", Source: &dto.NoteSource{Origin: "mail", RecordId: "m1"}}
+
+	first, err := repo.CreateNote(note)
+	second, secondErr := repo.CreateNote(note)
+	_, invalidErr := repo.CreateNote(dto.Note{Content: "No title"})
+
+	assert.Nil(t, err)
+	assert.Nil(t, secondErr)
+	assert.EqualValues(t, cfg.PbApi.BaseUrl+"app/inbox/notes/"+first.ID, first.Url)
+	assert.True(t, second.AlreadyExists)
+	assert.EqualValues(t, http.StatusBadRequest, invalidErr.StatusCode())
+	assert.EqualValues(t, 1, len(emu.Notes()))
+	assert.EqualValues(t, "partner", emu.Notes()[0].PartnerId)
+}
@@ -0,0 +1,327 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/date"
+)
+
+type integration struct {
+	dto.PluginIntegration
+	action dto.PluginAction
+}
+
+func (e *Emulator) postIntegration(c *gin.Context) {
+	var req dto.PbPluginIntegrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		e.writeError(c, validationError(fmt.Sprintf("Invalid request body: %v", err)))
+		return
+	}
+	data := req.Data
+	switch {
+	case data.Type == "" || data.Name == "":
+		e.writeError(c, validationError("Plugin integration type and name cannot be empty."))
+		return
+	case data.InitialState == nil || data.InitialState.Label == "":
+		e.writeError(c, validationError("The label of the initial state cannot be empty."))
+		return
+	case data.Action == nil:
+		e.writeError(c, validationError("The action of the plugin integration is missing."))
+		return
+	}
+	created := integration{
+		PluginIntegration: dto.PluginIntegration{
+			ID:                newId(),
+			CreatedAt:         date.GetNowUtc(),
+			IntegrationStatus: dto.IntegrationEnabled,
+			Type:              data.Type,
+			Name:              data.Name,
+			InitialState:      *data.InitialState,
+		},
+		action: *data.Action,
+	}
+	if data.IntegrationStatus != "" {
+		created.IntegrationStatus = data.IntegrationStatus
+	}
+	if err := e.checkIntegration(created); err != nil {
+		e.writeError(c, err)
+		return
+	}
+	e.data.Lock()
+	e.data.integrations = append(e.data.integrations, created)
+	e.data.Unlock()
+	c.JSON(http.StatusCreated, dto.PbPluginIntegrationResponse{Data: e.integrationLinks(c, created.PluginIntegration)})
+}
+
+func (e *Emulator) getIntegrations(c *gin.Context) {
+	integrations := []dto.PluginIntegration{}
+	e.data.Lock()
+	for _, i := range e.data.integrations {
+		integrations = append(integrations, e.integrationLinks(c, i.PluginIntegration))
+	}
+	e.data.Unlock()
+	start, end, links, err := e.paginate(c, len(integrations))
+	if err != nil {
+		e.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PbPluginIntegrationsResponse{Data: integrations[start:end], Links: links})
+}
+
+func (e *Emulator) getIntegration(c *gin.Context) {
+	e.data.Lock()
+	found, err := e.data.integration(c.Param("id"))
+	e.data.Unlock()
+	if err != nil {
+		e.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PbPluginIntegrationResponse{Data: e.integrationLinks(c, found.PluginIntegration)})
+}
+
+// putIntegration keeps the current value of every field not set. An enabled integration is probed again.
+func (e *Emulator) putIntegration(c *gin.Context) {
+	var req dto.PbPluginIntegrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		e.writeError(c, validationError(fmt.Sprintf("Invalid request body: %v", err)))
+		return
+	}
+	e.data.Lock()
+	found, err := e.data.integration(c.Param("id"))
+	e.data.Unlock()
+	if err != nil {
+		e.writeError(c, err)
+		return
+	}
+	data := req.Data
+	updated := *found
+	if data.IntegrationStatus != "" {
+		updated.IntegrationStatus = data.IntegrationStatus
+	}
+	if data.Type != "" {
+		updated.Type = data.Type
+	}
+	if data.Name != "" {
+		updated.Name = data.Name
+	}
+	if data.InitialState != nil && data.InitialState.Label != "" {
+		updated.InitialState = *data.InitialState
+	}
+	if data.Action != nil {
+		updated.action = *data.Action
+	}
+	if err := e.checkIntegration(updated); err != nil {
+		e.writeError(c, err)
+		return
+	}
+	e.data.Lock()
+	defer e.data.Unlock()
+	current, err := e.data.integration(updated.ID)
+	if err != nil {
+		e.writeError(c, err)
+		return
+	}
+	*current = updated
+	c.JSON(http.StatusOK, dto.PbPluginIntegrationResponse{Data: e.integrationLinks(c, updated.PluginIntegration)})
+}
+
+func (e *Emulator) deleteIntegration(c *gin.Context) {
+	e.data.Lock()
+	defer e.data.Unlock()
+	for i, found := range e.data.integrations {
+		if found.ID == c.Param("id") {
+			e.data.integrations = append(e.data.integrations[:i], e.data.integrations[i+1:]...)
+			delete(e.data.connections, found.ID)
+			c.Status(http.StatusNoContent)
+			return
+		}
+	}
+	e.writeError(c, notFoundError("pluginIntegration.notFound", "Plugin integration", c.Param("id")))
+}
+
+// getConnections lists all connections except those in the initial state, which do not exist as far as Productboard is concerned
+func (e *Emulator) getConnections(c *gin.Context) {
+	connections := []dto.ConnectionData{}
+	e.data.Lock()
+	_, err := e.data.integration(c.Param("id"))
+	if err == nil {
+		for _, feature := range e.data.features {
+			if conn, found := e.data.connections[c.Param("id")][feature.ID]; found {
+				connections = append(connections, dto.ConnectionData{FeatureId: feature.ID, Connection: conn})
+			}
+		}
+	}
+	e.data.Unlock()
+	if err != nil {
+		e.writeError(c, err)
+		return
+	}
+	start, end, links, err := e.paginate(c, len(connections))
+	if err != nil {
+		e.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PbConnectionsResponse{Data: connections[start:end], Links: links})
+}
+
+func (e *Emulator) getConnection(c *gin.Context) {
+	e.data.Lock()
+	conn, err := e.data.connection(c.Param("id"), c.Param("featureId"))
+	e.data.Unlock()
+	if err != nil {
+		e.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PbConnectionResponse{Data: dto.ConnectionData{FeatureId: c.Param("featureId"), Connection: *conn}})
+}
+
+func (e *Emulator) putConnection(c *gin.Context) {
+	var req dto.PbConnectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		e.writeError(c, validationError(fmt.Sprintf("Invalid request body: %v", err)))
+		return
+	}
+	e.data.Lock()
+	defer e.data.Unlock()
+	if _, err := e.data.connection(c.Param("id"), c.Param("featureId")); err != nil {
+		e.writeError(c, err)
+		return
+	}
+	if err := e.data.setConnection(c.Param("id"), c.Param("featureId"), req.Data.Connection); err != nil {
+		e.writeError(c, err)
+		return
+	}
+	conn, _ := e.data.connection(c.Param("id"), c.Param("featureId"))
+	c.JSON(http.StatusOK, dto.PbConnectionResponse{Data: dto.ConnectionData{FeatureId: c.Param("featureId"), Connection: *conn}})
+}
+
+func (e *Emulator) deleteConnection(c *gin.Context) {
+	e.data.Lock()
+	defer e.data.Unlock()
+	if _, err := e.data.connection(c.Param("id"), c.Param("featureId")); err != nil {
+		e.writeError(c, err)
+		return
+	}
+	delete(e.data.connections[c.Param("id")], c.Param("featureId"))
+	c.Status(http.StatusNoContent)
+}
+
+// PushButton acts like a user clicking a button of the plugin integration on a feature. The answer of the action
+// receiver becomes the new connection, just as Productboard handles it.
+func (e *Emulator) PushButton(integrationId string, featureId string, trigger string) (*dto.Connection, error) {
+	e.data.Lock()
+	found, err := e.data.integration(integrationId)
+	var feature *dto.Feature
+	if err == nil {
+		feature, err = e.data.feature(featureId)
+	}
+	var action dto.PluginAction
+	var featureType string
+	if err == nil {
+		action, featureType = found.action, feature.Type
+		if found.IntegrationStatus != dto.IntegrationEnabled {
+			err = validationError(fmt.Sprintf("Plugin integration %v is disabled.", integrationId))
+		}
+	}
+	e.data.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	notification := dto.PbActionNotification{
+		Data: dto.ActionNotification{
+			Trigger:       trigger,
+			IntegrationId: integrationId,
+			Feature: dto.ActionFeature{
+				ID:    featureId,
+				Type:  featureType,
+				Links: dto.SelfLinks{Self: fmt.Sprintf("%v/features/%v", e.cfg.BaseUrl, featureId)},
+			},
+			Links: dto.ActionLinks{
+				Connection: fmt.Sprintf("%v/plugin-integrations/%v/connections/%v", e.cfg.BaseUrl, integrationId, featureId),
+			},
+		},
+	}
+	var reply dto.PbConnectionResponse
+	status, err := e.postJson(action.URL, actionAuthorization(action), notification, &reply)
+	if err != nil {
+		return nil, err
+	}
+	if status > 299 {
+		return nil, fmt.Errorf("action receiver answered with status code %v", status)
+	}
+	e.data.Lock()
+	defer e.data.Unlock()
+	if err := e.data.setConnection(integrationId, featureId, reply.Data.Connection); err != nil {
+		return nil, err
+	}
+	return &reply.Data.Connection, nil
+}
+
+func (e *Emulator) checkIntegration(i integration) error {
+	switch {
+	case i.IntegrationStatus != dto.IntegrationEnabled && i.IntegrationStatus != dto.IntegrationDisabled:
+		return validationError(fmt.Sprintf("Integration status has to be %v or %v.", dto.IntegrationEnabled, dto.IntegrationDisabled))
+	case !validCallbackUrl(i.action.URL):
+		return &pbError{status: http.StatusBadRequest, Code: "callback.invalidUrl", Title: "Invalid callback URL",
+			Detail: fmt.Sprintf("'%v' cannot receive callbacks.", i.action.URL)}
+	case i.IntegrationStatus == dto.IntegrationEnabled:
+		return e.probe(i.action.URL, actionAuthorization(i.action))
+	}
+	return nil
+}
+
+// integration returns a pointer into the store; the lock has to be held while using it
+func (s *store) integration(id string) (*integration, error) {
+	for i := range s.integrations {
+		if s.integrations[i].ID == id {
+			return &s.integrations[i], nil
+		}
+	}
+	return nil, notFoundError("pluginIntegration.notFound", "Plugin integration", id)
+}
+
+// connection returns the connection of a feature, which is in the initial state if it was never set
+func (s *store) connection(integrationId string, featureId string) (*dto.Connection, error) {
+	if _, err := s.integration(integrationId); err != nil {
+		return nil, err
+	}
+	if _, err := s.feature(featureId); err != nil {
+		return nil, notFoundError("pluginIntegration.connection.notFound", "Plugin integration connection", featureId)
+	}
+	if conn, found := s.connections[integrationId][featureId]; found {
+		return &conn, nil
+	}
+	return &dto.Connection{State: dto.ConnectionInitial}, nil
+}
+
+// setConnection stores a connection; setting the initial state removes it
+func (s *store) setConnection(integrationId string, featureId string, conn dto.Connection) error {
+	switch conn.State {
+	case dto.ConnectionInitial:
+		delete(s.connections[integrationId], featureId)
+		return nil
+	case dto.ConnectionProgress, dto.ConnectionConnected, dto.ConnectionError:
+	default:
+		return validationError(fmt.Sprintf("Unknown connection state '%v'.", conn.State))
+	}
+	if s.connections[integrationId] == nil {
+		s.connections[integrationId] = make(map[string]dto.Connection)
+	}
+	s.connections[integrationId][featureId] = conn
+	return nil
+}
+
+func (e *Emulator) integrationLinks(c *gin.Context, i dto.PluginIntegration) dto.PluginIntegration {
+	i.Links = dto.SelfLinks{Self: fmt.Sprintf("%v/plugin-integrations/%v", e.baseUrl(c), i.ID)}
+	return i
+}
+
+func actionAuthorization(action dto.PluginAction) string {
+	if action.Headers == nil {
+		return ""
+	}
+	return action.Headers.Authorization
+}
@@ -0,0 +1,66 @@
+package emulator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func pluginIntegrationData(url string) dto.PluginIntegrationData {
+	return dto.PluginIntegrationData{
+		Type:         "com.example.tracker",
+		Name:         "Tracker",
+		InitialState: &dto.InitialState{Label: "Push"},
+		Action:       &dto.PluginAction{URL: url, Version: 1, Headers: &dto.Headers{Authorization: "plugin-secret"}},
+	}
+}
+
+func Test_PushButton_Stores_Connection_From_Receiver(t *testing.T) {
+	teardown := setupEmulator(t, Config{})
+	defer teardown()
+	receiver := newCallbackReceiver(dto.PbConnectionResponse{Data: dto.ConnectionData{
+		Connection: dto.Connection{State: dto.ConnectionConnected, Label: "T-1", Tooltip: "Open", Color: "blue", TargetUrl: "https://tracker.example/T-1"}}})
+	defer receiver.server.Close()
+	var action dto.PbActionNotification
+	var raw map[string]map[string]interface{}
+
+	created, err := repo.CreatePluginIntegration(pluginIntegrationData(receiver.server.URL))
+	conn, pushErr := emu.PushButton(created.ID, "f3", dto.TriggerPush)
+	connections, _ := repo.GetPluginConnections(created.ID)
+	deleteErr := repo.DeletePluginConnection(created.ID, "f3")
+	reset, _ := repo.GetPluginConnection(created.ID, "f3")
+
+	assert.Nil(t, err)
+	assert.Nil(t, pushErr)
+	assert.EqualValues(t, []string{"plugin-secret"}, receiver.probes)
+	json.Unmarshal(receiver.bodies[0], &action)
+	json.Unmarshal(receiver.bodies[0], &raw)
+	assert.EqualValues(t, dto.TriggerPush, action.Data.Trigger)
+	assert.EqualValues(t, created.ID, raw["data"]["integrationId"])
+	assert.EqualValues(t, created.ID, action.Data.IntegrationId)
+	assert.EqualValues(t, "f3", action.Data.Feature.ID)
+	assert.EqualValues(t, dto.ConnectionConnected, conn.State)
+	assert.EqualValues(t, 1, len(connections))
+	assert.EqualValues(t, "T-1", connections[0].Connection.Label)
+	assert.Nil(t, deleteErr)
+	assert.EqualValues(t, dto.ConnectionInitial, reset.Connection.State)
+}
+
+func Test_PushButton_DisabledIntegration_Returns_Error(t *testing.T) {
+	teardown := setupEmulator(t, Config{})
+	defer teardown()
+	receiver := newCallbackReceiver(nil)
+	defer receiver.server.Close()
+	created, _ := repo.CreatePluginIntegration(pluginIntegrationData(receiver.server.URL))
+
+	updated, err := repo.UpdatePluginIntegration(created.ID, dto.PluginIntegrationData{IntegrationStatus: dto.IntegrationDisabled})
+	_, pushErr := emu.PushButton(created.ID, "f1", dto.TriggerPush)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Tracker", updated.Name)
+	assert.EqualValues(t, dto.IntegrationDisabled, updated.IntegrationStatus)
+	assert.NotNil(t, pushErr)
+	assert.EqualValues(t, 0, len(receiver.bodies))
+}
@@ -0,0 +1,296 @@
+package emulator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+// Delivery is a webhook notification sent by the emulator. StatusCode is 0 if the receiver could not be reached.
+type Delivery struct {
+	Url        string
+	EventType  string
+	FeatureId  string
+	StatusCode int
+	Error      string
+}
+
+type subscription struct {
+	dto.SubRespData
+	notification dto.Notification
+}
+
+type subscriptionResponse struct {
+	Data dto.SubRespData `json:"data"`
+}
+
+// deliveryQueue sends notifications one after the other, so receivers get them in the order of the changes
+type deliveryQueue struct {
+	sync.Mutex
+	items   chan delivery
+	pending sync.WaitGroup
+	closed  bool
+	log     []Delivery
+}
+
+type delivery struct {
+	url           string
+	authorization string
+	payload       dto.PbEventNotification
+}
+
+func newDeliveryQueue() *deliveryQueue {
+	return &deliveryQueue{
+		items: make(chan delivery, deliveryQueueSize),
+		log:   []Delivery{},
+	}
+}
+
+func (e *Emulator) postWebhook(c *gin.Context) {
+	var req dto.PbSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		e.writeError(c, validationError(fmt.Sprintf("Invalid request body: %v", err)))
+		return
+	}
+	data := req.Data
+	switch {
+	case data.Name == "":
+		e.writeError(c, validationError("Subscription name cannot be empty."))
+		return
+	case len(data.Events) == 0:
+		e.writeError(c, validationError("At least one event type has to be given."))
+		return
+	case data.Notification.Version != 1:
+		e.writeError(c, validationError("Notification version has to be 1."))
+		return
+	case !validCallbackUrl(data.Notification.URL):
+		e.writeError(c, &pbError{status: http.StatusBadRequest, Code: "callback.invalidUrl", Title: "Invalid callback URL",
+			Detail: fmt.Sprintf("'%v' cannot receive callbacks.", data.Notification.URL)})
+		return
+	}
+	for _, event := range data.Events {
+		if !validEventType(event.EventType) {
+			e.writeError(c, validationError(fmt.Sprintf("Unknown event type '%v'.", event.EventType)))
+			return
+		}
+	}
+	if err := e.probe(data.Notification.URL, data.Notification.Headers.Authorization); err != nil {
+		e.writeError(c, err)
+		return
+	}
+	sub := subscription{
+		SubRespData: dto.SubRespData{
+			ID:        newId(),
+			CreatedAt: date.GetNowUtc(),
+			Name:      data.Name,
+			Events:    data.Events,
+		},
+		notification: data.Notification,
+	}
+	e.data.Lock()
+	e.data.subscriptions = append(e.data.subscriptions, sub)
+	e.data.Unlock()
+	c.JSON(http.StatusCreated, subscriptionResponse{Data: sub.SubRespData})
+}
+
+func (e *Emulator) getWebhooks(c *gin.Context) {
+	subs := []dto.SubRespData{}
+	e.data.Lock()
+	for _, sub := range e.data.subscriptions {
+		subs = append(subs, sub.SubRespData)
+	}
+	e.data.Unlock()
+	start, end, links, err := e.paginate(c, len(subs))
+	if err != nil {
+		e.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PbSubscriptionResponse{Data: subs[start:end], Links: links})
+}
+
+func (e *Emulator) getWebhook(c *gin.Context) {
+	e.data.Lock()
+	defer e.data.Unlock()
+	for _, sub := range e.data.subscriptions {
+		if sub.ID == c.Param("id") {
+			c.JSON(http.StatusOK, subscriptionResponse{Data: sub.SubRespData})
+			return
+		}
+	}
+	e.writeError(c, notFoundError("webhook.notFound", "Webhook subscription", c.Param("id")))
+}
+
+func (e *Emulator) deleteWebhook(c *gin.Context) {
+	e.data.Lock()
+	defer e.data.Unlock()
+	for i, sub := range e.data.subscriptions {
+		if sub.ID == c.Param("id") {
+			e.data.subscriptions = append(e.data.subscriptions[:i], e.data.subscriptions[i+1:]...)
+			c.Status(http.StatusNoContent)
+			return
+		}
+	}
+	e.writeError(c, notFoundError("webhook.notFound", "Webhook subscription", c.Param("id")))
+}
+
+// Wait blocks until all queued notifications have been sent
+func (e *Emulator) Wait() {
+	e.deliveries.pending.Wait()
+}
+
+// Deliveries returns the notifications sent so far, oldest first
+func (e *Emulator) Deliveries() []Delivery {
+	e.deliveries.Lock()
+	defer e.deliveries.Unlock()
+	return append([]Delivery{}, e.deliveries.log...)
+}
+
+// notify queues a notification for every subscription to the event type
+func (e *Emulator) notify(eventType string, featureId string) {
+	payload := dto.PbEventNotification{
+		Data: dto.EventData{
+			ID:        featureId,
+			EventType: eventType,
+			Links: dto.EventLinks{
+				Target: fmt.Sprintf("%v/features/%v", e.cfg.BaseUrl, featureId),
+			},
+		},
+	}
+	e.data.Lock()
+	defer e.data.Unlock()
+	for _, sub := range e.data.subscriptions {
+		for _, event := range sub.Events {
+			if event.EventType == eventType {
+				e.deliveries.push(delivery{url: sub.notification.URL, authorization: sub.notification.Headers.Authorization, payload: payload})
+			}
+		}
+	}
+}
+
+func (e *Emulator) deliver() {
+	for d := range e.deliveries.items {
+		result := Delivery{
+			Url:       d.url,
+			EventType: d.payload.Data.EventType,
+			FeatureId: d.payload.Data.ID,
+		}
+		status, err := e.postJson(d.url, d.authorization, d.payload, nil)
+		result.StatusCode = status
+		switch {
+		case err != nil:
+			result.Error = err.Error()
+			logger.Error(fmt.Sprintf("Could not deliver %v notification to %v", result.EventType, d.url), err)
+		case status > 299:
+			result.Error = fmt.Sprintf("receiver answered with status code %v", status)
+			logger.Warn(fmt.Sprintf("Receiver %v rejected %v notification with status code %v", d.url, result.EventType, status))
+		}
+		e.deliveries.Lock()
+		e.deliveries.log = append(e.deliveries.log, result)
+		e.deliveries.Unlock()
+		e.deliveries.pending.Done()
+	}
+}
+
+func (q *deliveryQueue) push(d delivery) {
+	q.Lock()
+	defer q.Unlock()
+	if q.closed {
+		return
+	}
+	q.pending.Add(1)
+	select {
+	case q.items <- d:
+	default:
+		q.pending.Done()
+		logger.Error(fmt.Sprintf("Dropping %v notification to %v", d.payload.Data.EventType, d.url), nil)
+	}
+}
+
+func (q *deliveryQueue) close() {
+	q.Lock()
+	if q.closed {
+		q.Unlock()
+		return
+	}
+	q.closed = true
+	q.Unlock()
+	q.pending.Wait()
+	close(q.items)
+}
+
+// probe sends the validation request Productboard sends before it accepts a webhook or plugin action receiver.
+// The receiver has to answer with status 200 and the validation token as body.
+func (e *Emulator) probe(target string, authorization string) error {
+	token := newId()
+	probeUrl, _ := url.Parse(target)
+	query := probeUrl.Query()
+	query.Set("validationToken", token)
+	probeUrl.RawQuery = query.Encode()
+	req, err := http.NewRequest(http.MethodGet, probeUrl.String(), nil)
+	if err != nil {
+		return probeError(target, err.Error())
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return probeError(target, err.Error())
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != token {
+		return probeError(target, fmt.Sprintf("expected status code 200 with the validation token, got %v with \"%v\"", resp.StatusCode, string(body)))
+	}
+	return nil
+}
+
+// postJson sends a callback and parses a successful answer into reply (if any)
+func (e *Emulator) postJson(target string, authorization string, payload interface{}, reply interface{}) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if reply != nil && resp.StatusCode < 300 {
+		if err := json.NewDecoder(resp.Body).Decode(reply); err != nil {
+			return resp.StatusCode, err
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+func probeError(target string, detail string) *pbError {
+	return &pbError{status: http.StatusBadRequest, Code: "callback.probeFailed", Title: "Callback probe failed",
+		Detail: fmt.Sprintf("Probe of %v failed: %v", target, detail)}
+}
+
+func validEventType(eventType string) bool {
+	for _, known := range dto.PbEventTypes {
+		if eventType == known {
+			return true
+		}
+	}
+	return false
+}
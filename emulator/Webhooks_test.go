@@ -0,0 +1,112 @@
+package emulator
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+// callbackReceiver stands in for pbreact's webhook and plugin action endpoints
+type callbackReceiver struct {
+	sync.Mutex
+	server    *httptest.Server
+	wrongEcho bool
+	reply     interface{}
+	probes    []string
+	auth      []string
+	bodies    [][]byte
+}
+
+func newCallbackReceiver(reply interface{}) *callbackReceiver {
+	r := callbackReceiver{reply: reply}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.Lock()
+		defer r.Unlock()
+		if req.Method == http.MethodGet {
+			r.probes = append(r.probes, req.Header.Get("Authorization"))
+			if r.wrongEcho {
+				w.Write([]byte("wrong"))
+				return
+			}
+			w.Write([]byte(req.URL.Query().Get("validationToken")))
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		r.auth = append(r.auth, req.Header.Get("Authorization"))
+		r.bodies = append(r.bodies, body)
+		if r.reply == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(r.reply)
+	}))
+	return &r
+}
+
+func Test_RegisterForNotifications_Probes_And_Delivers_Events(t *testing.T) {
+	teardown := setupEmulator(t, Config{})
+	defer teardown()
+	receiver := newCallbackReceiver(nil)
+	defer receiver.server.Close()
+	cfg.PbApi.WebHookUrl = receiver.server.URL + "/pbwebhook"
+	cfg.RunTime.CallbackAuthToken = "callback-secret"
+	var event dto.PbEventNotification
+
+	err := repo.RegisterForNotifications()
+	emu.UpdateFeature("f1", dto.FeatureUpdate{Status: &dto.StatusRef{ID: "s3"}})
+	emu.Wait()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"callback-secret"}, receiver.probes)
+	assert.EqualValues(t, []string{"callback-secret"}, receiver.auth)
+	json.Unmarshal(receiver.bodies[0], &event)
+	assert.EqualValues(t, "f1", event.Data.ID)
+	assert.EqualValues(t, dto.PbEventTypes["featureUpdate"], event.Data.EventType)
+	assert.EqualValues(t, cfg.PbApi.BaseUrl+"features/f1", event.Data.Links.Target)
+	assert.EqualValues(t, []Delivery{{Url: cfg.PbApi.WebHookUrl, EventType: event.Data.EventType, FeatureId: "f1", StatusCode: http.StatusNoContent}}, emu.Deliveries())
+}
+
+func Test_UnregisterForNotifications_Stops_Deliveries(t *testing.T) {
+	teardown := setupEmulator(t, Config{})
+	defer teardown()
+	receiver := newCallbackReceiver(nil)
+	defer receiver.server.Close()
+	cfg.PbApi.WebHookUrl = receiver.server.URL
+	repo.RegisterForNotifications()
+
+	subs, err := repo.GetNotifications()
+	unregErr := repo.UnregisterForNotifications(*subs)
+	_, notFoundErr := repo.GetNotifications()
+	emu.CreateFeature(dto.FeatureCreate{Name: "Quiet", Type: dto.NodeTypeFeature,
+		Status: dto.StatusRef{ID: "s1"}, Parent: dto.ParentIdRef{Product: &dto.IdRef{ID: "p1"}}})
+	emu.Wait()
+
+	assert.Nil(t, err)
+	assert.Nil(t, unregErr)
+	assert.EqualValues(t, 1, len(subs.Data))
+	assert.EqualValues(t, http.StatusNotFound, notFoundErr.StatusCode())
+	assert.EqualValues(t, 0, len(emu.Deliveries()))
+}
+
+func Test_RegisterForNotifications_FailedProbe_Returns_Error(t *testing.T) {
+	teardown := setupEmulator(t, Config{})
+	defer teardown()
+	receiver := newCallbackReceiver(nil)
+	receiver.wrongEcho = true
+	defer receiver.server.Close()
+	cfg.PbApi.WebHookUrl = receiver.server.URL
+
+	err := repo.RegisterForNotifications()
+	_, listErr := repo.GetNotifications()
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Message(), "callback.probeFailed")
+	assert.EqualValues(t, http.StatusNotFound, listErr.StatusCode())
+}
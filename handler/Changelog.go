@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/service"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+type ChangelogHandler struct {
+	Cfg              *config.AppConfig
+	ChangelogService *service.PbChangelogService
+}
+
+func NewChangelogHandler(cfg *config.AppConfig, changelogService service.PbChangelogService) ChangelogHandler {
+	return ChangelogHandler{
+		Cfg:              cfg,
+		ChangelogService: &changelogService,
+	}
+}
+
+func (ch *ChangelogHandler) GetChangelog(c *gin.Context) {
+	err := validateBearerToken(c, ch.Cfg.Export.AuthToken)
+	if err != nil {
+		logger.Error("Could not handle changelog request", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	from, to, err := service.ParseChangelogRange(c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	format := c.DefaultQuery("format", ch.Cfg.Changelog.Format)
+	changelog, err := (*ch.ChangelogService).GetChangelog(from, to, format)
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.Data(http.StatusOK, dto.ChangelogContentTypes[format], []byte(changelog))
+}
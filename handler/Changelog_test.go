@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ch                   ChangelogHandler
+	mockChangelogService *service.MockPbChangelogService
+)
+
+func setupChangelogTest(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockChangelogService = service.NewMockPbChangelogService(ctrl)
+	cfg.Export.AuthToken = "export"
+	cfg.Changelog.Format = dto.ChangelogMarkdown
+	ch = NewChangelogHandler(&cfg, mockChangelogService)
+	router = gin.Default()
+	gin.SetMode(gin.TestMode)
+	recorder = httptest.NewRecorder()
+	return func() {
+		router = nil
+		ctrl.Finish()
+	}
+}
+
+func Test_GetChangelog_NoToken_Returns_UnauthenticatedError(t *testing.T) {
+	teardown := setupChangelogTest(t)
+	defer teardown()
+	apiError := api_error.NewUnauthenticatedError("Wrong or missing auth key")
+	errorJson, _ := json.Marshal(apiError)
+	router.GET("/changelog", ch.GetChangelog)
+	req, _ := http.NewRequest(http.MethodGet, "/changelog", nil)
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, apiError.StatusCode(), recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_GetChangelog_Returns_Html(t *testing.T) {
+	teardown := setupChangelogTest(t)
+	defer teardown()
+	from := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	router.GET("/changelog", ch.GetChangelog)
+	req, _ := http.NewRequest(http.MethodGet, "/changelog?from=2024-01-08&to=2024-01-14&format=html", nil)
+	req.Header.Set("Authorization", "Bearer export")
+
+	mockChangelogService.EXPECT().GetChangelog(from, to, dto.ChangelogHtml).Return("<h1>Release notes</h1>", nil)
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, "text/html; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.EqualValues(t, "<h1>Release notes</h1>", recorder.Body.String())
+}
+
+func Test_GetChangelog_InvalidDate_Returns_BadRequestError(t *testing.T) {
+	teardown := setupChangelogTest(t)
+	defer teardown()
+	router.GET("/changelog", ch.GetChangelog)
+	req, _ := http.NewRequest(http.MethodGet, "/changelog?from=last-monday", nil)
+	req.Header.Set("Authorization", "Bearer export")
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+type ExportHandler struct {
+	Cfg           *config.AppConfig
+	ExportService *service.FeatureExportService
+}
+
+type connKey struct{}
+
+func NewExportHandler(cfg *config.AppConfig, service service.FeatureExportService) ExportHandler {
+	return ExportHandler{
+		Cfg:           cfg,
+		ExportService: &service,
+	}
+}
+
+func (eh *ExportHandler) ExportFeatures(c *gin.Context) {
+	err := validateBearerToken(c, eh.Cfg.Export.AuthToken)
+	if err != nil {
+		logger.Error("Could not handle feature export", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	format := c.DefaultQuery("format", dto.ExportCsv)
+	contentType, known := dto.ExportContentTypes[format]
+	if !known {
+		apiErr := api_error.NewBadRequestError(fmt.Sprintf("Unknown export format \"%v\"", format))
+		logger.Error(apiErr.Message(), nil)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	filter, err := service.ParseExportFilter(c.QueryArray("status"), c.Query("archived"), c.Query("parent"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	extendWriteDeadline(c, time.Duration(eh.Cfg.Export.WriteTimeout)*time.Second)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"features.%v\"", dto.ExportFileExtensions[format]))
+	err = (*eh.ExportService).ExportFeatures(c.Writer, format, *filter)
+	if err != nil && !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		c.JSON(err.StatusCode(), err)
+	}
+}
+
+// ConnContext keeps the connection of a request in its context, so a handler can change its deadlines. It is meant
+// as http.Server.ConnContext.
+func ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, connKey{}, conn)
+}
+
+// extendWriteDeadline replaces the write timeout of the server for a long running response. A timeout of 0 removes it.
+func extendWriteDeadline(c *gin.Context, timeout time.Duration) {
+	conn, found := c.Request.Context().Value(connKey{}).(net.Conn)
+	if !found {
+		return
+	}
+	deadline := time.Time{}
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	conn.SetWriteDeadline(deadline)
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	eh                ExportHandler
+	mockExportService *service.MockFeatureExportService
+)
+
+func setupExportTest(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockExportService = service.NewMockFeatureExportService(ctrl)
+	cfg.Export.AuthToken = "export"
+	eh = NewExportHandler(&cfg, mockExportService)
+	router = gin.Default()
+	gin.SetMode(gin.TestMode)
+	recorder = httptest.NewRecorder()
+	return func() {
+		router = nil
+		ctrl.Finish()
+	}
+}
+
+func Test_ExportFeatures_NoAuthKey_Returns_UnauthenticatedError(t *testing.T) {
+	teardown := setupExportTest(t)
+	defer teardown()
+	apiError := api_error.NewUnauthenticatedError("Wrong or missing auth key")
+	errorJson, _ := json.Marshal(apiError)
+	router.GET("/export/features", eh.ExportFeatures)
+	req, _ := http.NewRequest(http.MethodGet, "/export/features", nil)
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, apiError.StatusCode(), recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_ExportFeatures_UnknownFormat_Returns_BadRequestError(t *testing.T) {
+	teardown := setupExportTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("Unknown export format \"xml\"")
+	errorJson, _ := json.Marshal(apiError)
+	router.GET("/export/features", eh.ExportFeatures)
+	req, _ := http.NewRequest(http.MethodGet, "/export/features?format=xml", nil)
+	req.Header.Set("Authorization", "Bearer export")
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, apiError.StatusCode(), recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_ExportFeatures_Markdown_Streams_Export(t *testing.T) {
+	teardown := setupExportTest(t)
+	defer teardown()
+	archived := false
+	filter := dto.ExportFilter{Statuses: []string{"New idea", "Done"}, Archived: &archived, Parent: "Portal"}
+	router.GET("/export/features", eh.ExportFeatures)
+	req, _ := http.NewRequest(http.MethodGet, "/export/features?format=markdown&status=New+idea,Done&archived=false&parent=Portal", nil)
+	req.Header.Set("Authorization", "Bearer export")
+
+	mockExportService.EXPECT().ExportFeatures(gomock.Any(), dto.ExportMarkdown, filter).DoAndReturn(func(w io.Writer, _ string, _ dto.ExportFilter) api_error.ApiErr {
+		io.WriteString(w, "# Features\n")
+		return nil
+	})
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, "text/markdown; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.EqualValues(t, "attachment; filename=\"features.md\"", recorder.Header().Get("Content-Disposition"))
+	assert.EqualValues(t, "# Features\n", recorder.Body.String())
+}
+
+func Test_ExportFeatures_ExportFails_Returns_Error(t *testing.T) {
+	teardown := setupExportTest(t)
+	defer teardown()
+	apiError := api_error.NewInternalServerError("something went wrong", nil)
+	router.GET("/export/features", eh.ExportFeatures)
+	req, _ := http.NewRequest(http.MethodGet, "/export/features", nil)
+	req.Header.Set("Authorization", "Bearer export")
+
+	mockExportService.EXPECT().ExportFeatures(gomock.Any(), dto.ExportCsv, gomock.Any()).Return(apiError)
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, http.StatusInternalServerError, recorder.Code)
+	assert.EqualValues(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Empty(t, recorder.Header().Get("Content-Disposition"))
+}
+
+func Test_ExportFeatures_SlowExport_Outlasts_ServerWriteTimeout(t *testing.T) {
+	teardown := setupExportTest(t)
+	defer teardown()
+	cfg.Export.WriteTimeout = 5
+	router.GET("/export/features", eh.ExportFeatures)
+	srv := httptest.NewUnstartedServer(router)
+	srv.Config.WriteTimeout = 100 * time.Millisecond
+	srv.Config.ConnContext = ConnContext
+	srv.Start()
+	defer srv.Close()
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/export/features", nil)
+	req.Header.Set("Authorization", "Bearer export")
+
+	mockExportService.EXPECT().ExportFeatures(gomock.Any(), dto.ExportCsv, gomock.Any()).DoAndReturn(func(w io.Writer, _ string, _ dto.ExportFilter) api_error.ApiErr {
+		time.Sleep(300 * time.Millisecond)
+		io.WriteString(w, "id,name\n")
+		return nil
+	})
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	body, readErr := io.ReadAll(resp.Body)
+
+	assert.Nil(t, readErr)
+	assert.EqualValues(t, http.StatusOK, resp.StatusCode)
+	assert.EqualValues(t, "id,name\n", string(body))
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+type FeedbackHandler struct {
+	Cfg             *config.AppConfig
+	FeedbackService *service.PbFeedbackService
+}
+
+func NewFeedbackHandler(cfg *config.AppConfig, service service.PbFeedbackService) FeedbackHandler {
+	return FeedbackHandler{
+		Cfg:             cfg,
+		FeedbackService: &service,
+	}
+}
+
+func (fh *FeedbackHandler) PostFeedback(c *gin.Context) {
+	var feedback dto.FeedbackRequest
+
+	err := validateBearerToken(c, fh.Cfg.Feedback.AuthToken)
+	if err != nil {
+		logger.Error("Could not handle feedback", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	if c.ContentType() == gin.MIMEJSON {
+		if err := c.ShouldBindJSON(&feedback); err != nil {
+			logger.Error("Invalid JSON body in feedback", err)
+			apiErr := api_error.NewBadRequestError("Invalid json body")
+			c.JSON(apiErr.StatusCode(), apiErr)
+			return
+		}
+	} else {
+		feedback = feedbackFromForm(c)
+	}
+	status, err := (*fh.FeedbackService).SubmitFeedback(feedback)
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.Header("Location", fmt.Sprintf("/feedback/%v", status.TrackingId))
+	c.JSON(http.StatusAccepted, status)
+}
+
+func (fh *FeedbackHandler) GetFeedbackStatus(c *gin.Context) {
+	err := validateBearerToken(c, fh.Cfg.Feedback.AuthToken)
+	if err != nil {
+		logger.Error("Could not handle feedback status request", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	status, err := (*fh.FeedbackService).GetFeedbackStatus(c.Param("id"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+func feedbackFromForm(c *gin.Context) dto.FeedbackRequest {
+	tags := []string{}
+	for _, val := range c.PostFormArray("tags") {
+		tags = append(tags, strings.Split(val, ",")...)
+	}
+	return dto.FeedbackRequest{
+		Title:         c.PostForm("title"),
+		Content:       c.PostForm("content"),
+		CustomerEmail: c.PostForm("customer_email"),
+		DisplayUrl:    c.PostForm("display_url"),
+		Source: dto.FeedbackSource{
+			Origin:   c.PostForm("source.origin"),
+			RecordId: c.PostForm("source.record_id"),
+		},
+		Tags: tags,
+	}
+}
+
+// validateBearerToken checks for "Authorization: Bearer <token>". Endpoints without a configured token reject all requests.
+func validateBearerToken(c *gin.Context, token string) api_error.ApiErr {
+	authKey, found := cutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found || !tokenMatches(strings.TrimSpace(authKey), token) {
+		return api_error.NewUnauthenticatedError("Wrong or missing auth key")
+	}
+	return nil
+}
+
+// tokenMatches compares in constant time, so the time taken does not reveal how much of the token was right
+func tokenMatches(authKey string, token string) bool {
+	return token != "" && subtle.ConstantTimeCompare([]byte(authKey), []byte(token)) == 1
+}
+
+func cutPrefix(value string, prefix string) (string, bool) {
+	if !strings.HasPrefix(value, prefix) {
+		return value, false
+	}
+	return value[len(prefix):], true
+}
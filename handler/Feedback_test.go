@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	fh                  FeedbackHandler
+	mockFeedbackService *service.MockPbFeedbackService
+)
+
+func setupFeedbackTest(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockFeedbackService = service.NewMockPbFeedbackService(ctrl)
+	cfg.Feedback.AuthToken = "secret"
+	fh = NewFeedbackHandler(&cfg, mockFeedbackService)
+	router = gin.Default()
+	gin.SetMode(gin.TestMode)
+	recorder = httptest.NewRecorder()
+	return func() {
+		router = nil
+		ctrl.Finish()
+	}
+}
+
+func Test_PostFeedback_NoAuthKey_Returns_UnauthenticatedError(t *testing.T) {
+	teardown := setupFeedbackTest(t)
+	defer teardown()
+	apiError := api_error.NewUnauthenticatedError("Wrong or missing auth key")
+	errorJson, _ := json.Marshal(apiError)
+	router.POST("/feedback", fh.PostFeedback)
+	req, _ := http.NewRequest(http.MethodPost, "/feedback", nil)
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, apiError.StatusCode(), recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_PostFeedback_TokenWithoutScheme_Returns_UnauthenticatedError(t *testing.T) {
+	teardown := setupFeedbackTest(t)
+	defer teardown()
+	apiError := api_error.NewUnauthenticatedError("Wrong or missing auth key")
+	errorJson, _ := json.Marshal(apiError)
+	router.POST("/feedback", fh.PostFeedback)
+	req, _ := http.NewRequest(http.MethodPost, "/feedback", strings.NewReader("{}"))
+	req.Header.Set("Authorization", "secret")
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, apiError.StatusCode(), recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_PostFeedback_InvalidJson_Returns_BadRequestError(t *testing.T) {
+	teardown := setupFeedbackTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("Invalid json body")
+	errorJson, _ := json.Marshal(apiError)
+	router.POST("/feedback", fh.PostFeedback)
+	req, _ := http.NewRequest(http.MethodPost, "/feedback", strings.NewReader("{"))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, apiError.StatusCode(), recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_PostFeedback_Json_Returns_Accepted(t *testing.T) {
+	teardown := setupFeedbackTest(t)
+	defer teardown()
+	feedback := dto.FeedbackRequest{
+		Title:   "Dark mode",
+		Content: "Please add dark mode",
+		Source:  dto.FeedbackSource{Origin: "helpdesk", RecordId: "42"},
+	}
+	feedbackJson, _ := json.Marshal(feedback)
+	status := dto.FeedbackStatus{TrackingId: "t1", Status: dto.FeedbackQueued}
+	statusJson, _ := json.Marshal(status)
+	router.POST("/feedback", fh.PostFeedback)
+	req, _ := http.NewRequest(http.MethodPost, "/feedback", strings.NewReader(string(feedbackJson)))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Content-Type", "application/json")
+
+	mockFeedbackService.EXPECT().SubmitFeedback(feedback).Return(&status, nil)
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, http.StatusAccepted, recorder.Code)
+	assert.EqualValues(t, "/feedback/t1", recorder.Header().Get("Location"))
+	assert.EqualValues(t, statusJson, recorder.Body.String())
+}
+
+func Test_PostFeedback_Form_Returns_Accepted(t *testing.T) {
+	teardown := setupFeedbackTest(t)
+	defer teardown()
+	form := url.Values{}
+	form.Set("content", "Please add dark mode")
+	form.Set("customer_email", "jane@example.com")
+	form.Set("source.record_id", "42")
+	form.Add("tags", "ui,wish")
+	expected := dto.FeedbackRequest{
+		Content:       "Please add dark mode",
+		CustomerEmail: "jane@example.com",
+		Source:        dto.FeedbackSource{RecordId: "42"},
+		Tags:          []string{"ui", "wish"},
+	}
+	router.POST("/feedback", fh.PostFeedback)
+	req, _ := http.NewRequest(http.MethodPost, "/feedback", strings.NewReader(form.Encode()))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	mockFeedbackService.EXPECT().SubmitFeedback(expected).Return(&dto.FeedbackStatus{TrackingId: "t1"}, nil)
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, http.StatusAccepted, recorder.Code)
+}
+
+func Test_GetFeedbackStatus_Unknown_Returns_NotFoundError(t *testing.T) {
+	teardown := setupFeedbackTest(t)
+	defer teardown()
+	apiError := api_error.NewNotFoundError("No feedback with tracking id t1 found")
+	router.GET("/feedback/:id", fh.GetFeedbackStatus)
+	req, _ := http.NewRequest(http.MethodGet, "/feedback/t1", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+
+	mockFeedbackService.EXPECT().GetFeedbackStatus("t1").Return(nil, apiError)
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+type PluginHandler struct {
+	Cfg             *config.AppConfig
+	PbActionService *service.PbActionService
+}
+
+func NewPluginHandler(cfg *config.AppConfig, actionService service.PbActionService) PluginHandler {
+	return PluginHandler{
+		Cfg:             cfg,
+		PbActionService: &actionService,
+	}
+}
+
+func (ph *PluginHandler) PbActionProbe(c *gin.Context) {
+	err := validateCallbackAuthKey(c, ph.Cfg.RunTime.CallbackAuthToken)
+	if err != nil {
+		logger.Error("Could not handle action probe", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	token, exists := c.GetQuery("validationToken")
+	if !exists {
+		msg := "Could not find validation token"
+		err := api_error.NewBadRequestError(msg)
+		logger.Error(msg, nil)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.String(http.StatusOK, token)
+}
+
+func (ph *PluginHandler) PbActionNotification(c *gin.Context) {
+	var actionData = dto.PbActionNotification{}
+
+	err := validateCallbackAuthKey(c, ph.Cfg.RunTime.CallbackAuthToken)
+	if err != nil {
+		logger.Error("Could not handle action notification", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	if err := c.ShouldBindJSON(&actionData); err != nil {
+		logger.Error("Invalid JSON body in action notification", err)
+		apiErr := api_error.NewBadRequestError("Invalid json body")
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	conn, err := (*ph.PbActionService).HandleAction(actionData.Data)
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PbConnectionResponse{
+		Data: dto.ConnectionData{
+			Connection: *conn,
+		},
+	})
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ph                *PluginHandler
+	mockActionService *service.MockPbActionService
+)
+
+func setupPluginTest(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockActionService = service.NewMockPbActionService(ctrl)
+	cfg.RunTime.CallbackAuthToken = "callback"
+	handler := NewPluginHandler(&cfg, mockActionService)
+	ph = &handler
+	router = gin.Default()
+	gin.SetMode(gin.TestMode)
+	recorder = httptest.NewRecorder()
+	return func() {
+		router = nil
+		ctrl.Finish()
+	}
+}
+
+func Test_PbActionProbe_NoAuthKey_Returns_UnauthenticatedError(t *testing.T) {
+	teardown := setupPluginTest(t)
+	defer teardown()
+	apiError := api_error.NewUnauthenticatedError("Wrong or missing auth key")
+	errorJson, _ := json.Marshal(apiError)
+	router.GET("/pbplugin", ph.PbActionProbe)
+	req, _ := http.NewRequest(http.MethodGet, "/pbplugin?validationToken=abc", nil)
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, apiError.StatusCode(), recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_PbActionProbe_Returns_ValidationToken(t *testing.T) {
+	teardown := setupPluginTest(t)
+	defer teardown()
+	router.GET("/pbplugin", ph.PbActionProbe)
+	req, _ := http.NewRequest(http.MethodGet, "/pbplugin?validationToken=abc", nil)
+	req.Header.Set("Authorization", "callback")
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, "abc", recorder.Body.String())
+}
+
+func Test_PbActionNotification_InvalidBody_Returns_BadRequestError(t *testing.T) {
+	teardown := setupPluginTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("Invalid json body")
+	errorJson, _ := json.Marshal(apiError)
+	router.POST("/pbplugin", ph.PbActionNotification)
+	req, _ := http.NewRequest(http.MethodPost, "/pbplugin", strings.NewReader("{"))
+	req.Header.Set("Authorization", "callback")
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, apiError.StatusCode(), recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_PbActionNotification_ServiceError_Returns_Error(t *testing.T) {
+	teardown := setupPluginTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("No handler registered for action trigger button.dismiss")
+	errorJson, _ := json.Marshal(apiError)
+	mockActionService.EXPECT().HandleAction(dto.ActionNotification{Trigger: dto.TriggerDismiss}).Return(nil, apiError)
+	router.POST("/pbplugin", ph.PbActionNotification)
+	req, _ := http.NewRequest(http.MethodPost, "/pbplugin", strings.NewReader(`{"data":{"trigger":"button.dismiss"}}`))
+	req.Header.Set("Authorization", "callback")
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, apiError.StatusCode(), recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_PbActionNotification_Returns_Connection(t *testing.T) {
+	teardown := setupPluginTest(t)
+	defer teardown()
+	action := dto.ActionNotification{Trigger: dto.TriggerPush, IntegrationId: "i1", Feature: dto.ActionFeature{ID: "f1"}}
+	mockActionService.EXPECT().HandleAction(action).Return(&dto.Connection{State: dto.ConnectionProgress}, nil)
+	router.POST("/pbplugin", ph.PbActionNotification)
+	req, _ := http.NewRequest(http.MethodPost, "/pbplugin", strings.NewReader(`{"data":{"integrationId":"i1","trigger":"button.push","feature":{"id":"f1"}}}`))
+	req.Header.Set("Authorization", "callback")
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, `{"data":{"connection":{"state":"progress"}}}`, recorder.Body.String())
+}
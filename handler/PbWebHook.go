@@ -12,14 +12,16 @@ import (
 )
 
 type WebHookHandler struct {
-	Cfg          *config.AppConfig
-	PbApiService *service.PbApiService
+	Cfg            *config.AppConfig
+	PbApiService   *service.PbApiService
+	PbEventService *service.PbEventService
 }
 
-func NewWebHookHandler(cfg *config.AppConfig, service service.PbApiService) WebHookHandler {
+func NewWebHookHandler(cfg *config.AppConfig, service service.PbApiService, eventService service.PbEventService) WebHookHandler {
 	return WebHookHandler{
-		Cfg:          cfg,
-		PbApiService: &service,
+		Cfg:            cfg,
+		PbApiService:   &service,
+		PbEventService: &eventService,
 	}
 }
 
@@ -41,8 +43,13 @@ func (whh *WebHookHandler) PbWhSubscription(c *gin.Context) {
 }
 
 func (whh *WebHookHandler) validateAuthKey(c *gin.Context) api_error.ApiErr {
+	return validateCallbackAuthKey(c, whh.Cfg.RunTime.CallbackAuthToken)
+}
+
+// validateCallbackAuthKey checks the authorization header Productboard was told to send on its callbacks
+func validateCallbackAuthKey(c *gin.Context, token string) api_error.ApiErr {
 	authKey := c.GetHeader("Authorization")
-	if (authKey == "") || (authKey != whh.Cfg.RunTime.CallbackAuthToken) {
+	if (authKey == "") || (authKey != token) {
 		return api_error.NewUnauthenticatedError("Wrong or missing auth key")
 	}
 	return nil
@@ -63,6 +70,9 @@ func (whh *WebHookHandler) PbWhEvents(c *gin.Context) {
 		c.JSON(apiErr.StatusCode(), apiErr)
 		return
 	}
-	// queue element for processing
+	if err := (*whh.PbEventService).QueueEvent(eventData); err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
 	c.JSON(http.StatusNoContent, nil)
 }
@@ -23,14 +23,16 @@ var (
 	whh    WebHookHandler
 	router *gin.Engine
 	//mockService *service.MockPbApiService
-	recorder *httptest.ResponseRecorder
-	ctx      *gin.Context
+	mockEventService *service.MockPbEventService
+	recorder         *httptest.ResponseRecorder
+	ctx              *gin.Context
 )
 
 func setupTest(t *testing.T) func() {
 	ctrl := gomock.NewController(t)
 	mockService := service.NewMockPbApiService(ctrl)
-	whh = NewWebHookHandler(&cfg, mockService)
+	mockEventService = service.NewMockPbEventService(ctrl)
+	whh = NewWebHookHandler(&cfg, mockService, mockEventService)
 	router = gin.Default()
 	gin.SetMode(gin.TestMode)
 	recorder = httptest.NewRecorder()
@@ -133,8 +135,36 @@ func Test_PbWhEvents_ValidEvent_Returns_StatusNoContent(t *testing.T) {
 	req, _ := http.NewRequest(http.MethodPost, "/pbwebhook", strings.NewReader(string(eventJson)))
 	req.Header.Set("Authorization", authKey.String())
 
+	mockEventService.EXPECT().QueueEvent(gomock.Any()).Return(nil)
+
 	router.ServeHTTP(recorder, req)
 
 	assert.EqualValues(t, http.StatusNoContent, recorder.Code)
 	assert.EqualValues(t, "", recorder.Body.String())
 }
+
+func Test_PbWhEvents_QueueFull_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	apiError := api_error.NewInternalServerError("Event queue is full", nil)
+	errorJson, _ := json.Marshal(apiError)
+	authKey, _ := uuid.NewV4()
+	cfg.RunTime.CallbackAuthToken = authKey.String()
+	eventData := dto.PbEventNotification{
+		Data: dto.EventData{
+			ID:        "abc",
+			EventType: dto.PbEventTypes["featureUpdate"],
+		},
+	}
+	eventJson, _ := json.Marshal(eventData)
+	router.POST("/pbwebhook", whh.PbWhEvents)
+	req, _ := http.NewRequest(http.MethodPost, "/pbwebhook", strings.NewReader(string(eventJson)))
+	req.Header.Set("Authorization", authKey.String())
+
+	mockEventService.EXPECT().QueueEvent(eventData).Return(apiError)
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, apiError.StatusCode(), recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
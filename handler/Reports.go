@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+type ReportHandler struct {
+	Cfg         *config.AppConfig
+	SlipService *service.PbSlipService
+	FlowService *service.PbFlowService
+}
+
+func NewReportHandler(cfg *config.AppConfig, slipService service.PbSlipService, flowService service.PbFlowService) ReportHandler {
+	return ReportHandler{
+		Cfg:         cfg,
+		SlipService: &slipService,
+		FlowService: &flowService,
+	}
+}
+
+func (rh *ReportHandler) GetSlipReport(c *gin.Context) {
+	err := validateBearerToken(c, rh.Cfg.Export.AuthToken)
+	if err != nil {
+		logger.Error("Could not handle slip report request", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	report, err := (*rh.SlipService).GetReport()
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func (rh *ReportHandler) GetFlowReport(c *gin.Context) {
+	err := validateBearerToken(c, rh.Cfg.Export.AuthToken)
+	if err != nil {
+		logger.Error("Could not handle flow report request", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	filter, err := service.ParseFlowFilter(c.QueryArray("product"), c.QueryArray("component"), c.Query("since"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	switch format := c.DefaultQuery("format", "json"); format {
+	case "json":
+		report, err := (*rh.FlowService).GetReport(*filter)
+		if err != nil {
+			c.JSON(err.StatusCode(), err)
+			return
+		}
+		c.JSON(http.StatusOK, report)
+	case dto.ExportCsv:
+		table := c.DefaultQuery("table", dto.FlowTableFeatures)
+		c.Header("Content-Type", dto.ExportContentTypes[dto.ExportCsv])
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"flow-%v.csv\"", table))
+		err = (*rh.FlowService).WriteCsv(c.Writer, table, *filter)
+		if err != nil && !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			c.JSON(err.StatusCode(), err)
+		}
+	default:
+		apiErr := api_error.NewBadRequestError(fmt.Sprintf("Unknown report format \"%v\"", format))
+		logger.Error(apiErr.Message(), nil)
+		c.JSON(apiErr.StatusCode(), apiErr)
+	}
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	reph            ReportHandler
+	mockSlipService *service.MockPbSlipService
+	mockFlowService *service.MockPbFlowService
+)
+
+func setupReportTest(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockSlipService = service.NewMockPbSlipService(ctrl)
+	mockFlowService = service.NewMockPbFlowService(ctrl)
+	cfg.Export.AuthToken = "export"
+	reph = NewReportHandler(&cfg, mockSlipService, mockFlowService)
+	router = gin.Default()
+	gin.SetMode(gin.TestMode)
+	recorder = httptest.NewRecorder()
+	return func() {
+		router = nil
+		ctrl.Finish()
+	}
+}
+
+func Test_GetSlipReport_NoToken_Returns_UnauthenticatedError(t *testing.T) {
+	teardown := setupReportTest(t)
+	defer teardown()
+	apiError := api_error.NewUnauthenticatedError("Wrong or missing auth key")
+	errorJson, _ := json.Marshal(apiError)
+	router.GET("/reports/slips", reph.GetSlipReport)
+	req, _ := http.NewRequest(http.MethodGet, "/reports/slips", nil)
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, apiError.StatusCode(), recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_GetSlipReport_Returns_Report(t *testing.T) {
+	teardown := setupReportTest(t)
+	defer teardown()
+	report := dto.SlipReport{
+		Overdue: []dto.SlipRecord{{FeatureId: "f1", Name: "Dark mode", EndDate: "2024-03-31", DaysOverdue: 5}},
+		Slipped: []dto.SlipRecord{},
+	}
+	reportJson, _ := json.Marshal(report)
+	router.GET("/reports/slips", reph.GetSlipReport)
+	req, _ := http.NewRequest(http.MethodGet, "/reports/slips", nil)
+	req.Header.Set("Authorization", "Bearer export")
+
+	mockSlipService.EXPECT().GetReport().Return(&report, nil)
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, reportJson, recorder.Body.String())
+}
+
+func Test_GetFlowReport_Returns_Report(t *testing.T) {
+	teardown := setupReportTest(t)
+	defer teardown()
+	filter := dto.FlowFilter{Products: []string{"Portal"}, Components: []string{}, Since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	report := dto.FlowReport{Features: []dto.FeatureFlow{}, Throughput: []dto.WeeklyThroughput{{Week: "2024-W05", Product: "Portal", Count: 2}}}
+	reportJson, _ := json.Marshal(report)
+	router.GET("/reports/flow", reph.GetFlowReport)
+	req, _ := http.NewRequest(http.MethodGet, "/reports/flow?product=Portal&since=2024-01-01", nil)
+	req.Header.Set("Authorization", "Bearer export")
+
+	mockFlowService.EXPECT().GetReport(filter).Return(&report, nil)
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, reportJson, recorder.Body.String())
+}
+
+func Test_GetFlowReport_Csv_Writes_Table(t *testing.T) {
+	teardown := setupReportTest(t)
+	defer teardown()
+	filter := dto.FlowFilter{Products: []string{}, Components: []string{}}
+	router.GET("/reports/flow", reph.GetFlowReport)
+	req, _ := http.NewRequest(http.MethodGet, "/reports/flow?format=csv&table=throughput", nil)
+	req.Header.Set("Authorization", "Bearer export")
+
+	mockFlowService.EXPECT().WriteCsv(gomock.Any(), dto.FlowTableThroughput, filter).DoAndReturn(func(w io.Writer, _ string, _ dto.FlowFilter) api_error.ApiErr {
+		io.WriteString(w, "week,product,component,count\n")
+		return nil
+	})
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.EqualValues(t, "attachment; filename=\"flow-throughput.csv\"", recorder.Header().Get("Content-Disposition"))
+	assert.EqualValues(t, "week,product,component,count\n", recorder.Body.String())
+}
+
+func Test_GetFlowReport_InvalidSince_Returns_BadRequestError(t *testing.T) {
+	teardown := setupReportTest(t)
+	defer teardown()
+	router.GET("/reports/flow", reph.GetFlowReport)
+	req, _ := http.NewRequest(http.MethodGet, "/reports/flow?since=yesterday", nil)
+	req.Header.Set("Authorization", "Bearer export")
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+type RoadmapHandler struct {
+	Cfg            *config.AppConfig
+	RoadmapService *service.PbRoadmapService
+}
+
+func NewRoadmapHandler(cfg *config.AppConfig, service service.PbRoadmapService) RoadmapHandler {
+	return RoadmapHandler{
+		Cfg:            cfg,
+		RoadmapService: &service,
+	}
+}
+
+func (rh *RoadmapHandler) GetCalendar(c *gin.Context) {
+	err := validateFeedToken(c, rh.Cfg.Export.AuthToken)
+	if err != nil {
+		logger.Error("Could not handle roadmap calendar request", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	filter := service.ParseRoadmapFilter(c.QueryArray("product"), c.QueryArray("component"), c.QueryArray("status"))
+	cal, err := (*rh.RoadmapService).GetCalendar(filter)
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(cal))
+}
+
+// validateFeedToken also accepts the token as query parameter, as calendar apps subscribe to plain URLs and cannot send headers
+func validateFeedToken(c *gin.Context, token string) api_error.ApiErr {
+	if queryToken, found := c.GetQuery("token"); found {
+		if !tokenMatches(queryToken, token) {
+			return api_error.NewUnauthenticatedError("Wrong or missing auth key")
+		}
+		return nil
+	}
+	return validateBearerToken(c, token)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	rh                 RoadmapHandler
+	mockRoadmapService *service.MockPbRoadmapService
+)
+
+func setupRoadmapTest(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockRoadmapService = service.NewMockPbRoadmapService(ctrl)
+	cfg.Export.AuthToken = "export"
+	rh = NewRoadmapHandler(&cfg, mockRoadmapService)
+	router = gin.Default()
+	gin.SetMode(gin.TestMode)
+	recorder = httptest.NewRecorder()
+	return func() {
+		router = nil
+		ctrl.Finish()
+	}
+}
+
+func Test_GetCalendar_WrongQueryToken_Returns_UnauthenticatedError(t *testing.T) {
+	teardown := setupRoadmapTest(t)
+	defer teardown()
+	apiError := api_error.NewUnauthenticatedError("Wrong or missing auth key")
+	errorJson, _ := json.Marshal(apiError)
+	router.GET("/roadmap.ics", rh.GetCalendar)
+	req, _ := http.NewRequest(http.MethodGet, "/roadmap.ics?token=wrong", nil)
+	req.Header.Set("Authorization", "Bearer export")
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, apiError.StatusCode(), recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_GetCalendar_QueryToken_Returns_Calendar(t *testing.T) {
+	teardown := setupRoadmapTest(t)
+	defer teardown()
+	filter := dto.RoadmapFilter{Products: []string{"Portal"}, Components: []string{}, Statuses: []string{"Done", "Released"}}
+	router.GET("/roadmap.ics", rh.GetCalendar)
+	req, _ := http.NewRequest(http.MethodGet, "/roadmap.ics?token=export&product=Portal&status=Done&status=Released", nil)
+
+	mockRoadmapService.EXPECT().GetCalendar(filter).Return("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", nil)
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, "text/calendar; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.EqualValues(t, "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", recorder.Body.String())
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+type TrackerHandler struct {
+	Cfg               *config.AppConfig
+	StatusSyncService *service.PbStatusSyncService
+}
+
+func NewTrackerHandler(cfg *config.AppConfig, service service.PbStatusSyncService) TrackerHandler {
+	return TrackerHandler{
+		Cfg:               cfg,
+		StatusSyncService: &service,
+	}
+}
+
+func (th *TrackerHandler) TrackerEvents(c *gin.Context) {
+	var event dto.TrackerEvent
+
+	err := validateBearerToken(c, th.Cfg.Tracker.WebhookToken)
+	if err != nil {
+		logger.Error("Could not handle tracker event", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	if err := c.ShouldBindJSON(&event); err != nil {
+		logger.Error("Invalid JSON body in tracker event", err)
+		apiErr := api_error.NewBadRequestError("Invalid json body")
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	if event.Key == "" || event.Status == "" {
+		apiErr := api_error.NewBadRequestError("Tracker event needs key and status")
+		logger.Error(apiErr.Message(), nil)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	if err := (*th.StatusSyncService).HandleTrackerEvent(event); err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
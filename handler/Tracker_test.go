@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	th                    TrackerHandler
+	mockStatusSyncService *service.MockPbStatusSyncService
+)
+
+func setupTrackerTest(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockStatusSyncService = service.NewMockPbStatusSyncService(ctrl)
+	cfg.Tracker.WebhookToken = "tracker"
+	th = NewTrackerHandler(&cfg, mockStatusSyncService)
+	router = gin.Default()
+	gin.SetMode(gin.TestMode)
+	recorder = httptest.NewRecorder()
+	return func() {
+		router = nil
+		ctrl.Finish()
+	}
+}
+
+func Test_TrackerEvents_NoAuthKey_Returns_UnauthenticatedError(t *testing.T) {
+	teardown := setupTrackerTest(t)
+	defer teardown()
+	apiError := api_error.NewUnauthenticatedError("Wrong or missing auth key")
+	errorJson, _ := json.Marshal(apiError)
+	router.POST("/trackerwebhook", th.TrackerEvents)
+	req, _ := http.NewRequest(http.MethodPost, "/trackerwebhook", strings.NewReader(`{"key":"ENG-1","status":"done"}`))
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, apiError.StatusCode(), recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_TrackerEvents_MissingStatus_Returns_BadRequestError(t *testing.T) {
+	teardown := setupTrackerTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("Tracker event needs key and status")
+	errorJson, _ := json.Marshal(apiError)
+	router.POST("/trackerwebhook", th.TrackerEvents)
+	req, _ := http.NewRequest(http.MethodPost, "/trackerwebhook", strings.NewReader(`{"key":"ENG-1"}`))
+	req.Header.Set("Authorization", "Bearer tracker")
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, apiError.StatusCode(), recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_TrackerEvents_Returns_NoContent(t *testing.T) {
+	teardown := setupTrackerTest(t)
+	defer teardown()
+	mockStatusSyncService.EXPECT().HandleTrackerEvent(dto.TrackerEvent{Key: "ENG-1", Status: "done"}).Return(nil)
+	router.POST("/trackerwebhook", th.TrackerEvents)
+	req, _ := http.NewRequest(http.MethodPost, "/trackerwebhook", strings.NewReader(`{"key":"ENG-1","status":"done"}`))
+	req.Header.Set("Authorization", "Bearer tracker")
+
+	router.ServeHTTP(recorder, req)
+
+	assert.EqualValues(t, http.StatusNoContent, recorder.Code)
+}
@@ -1,7 +1,15 @@
 package main
 
-import "github.com/johannes-kuhfuss/pbreact/app"
+import (
+	"os"
+
+	"github.com/johannes-kuhfuss/pbreact/app"
+)
 
 func main() {
+	if len(os.Args) > 1 {
+		app.RunCommand(os.Args[1:])
+		return
+	}
 	app.StartApp()
 }
@@ -35,6 +35,228 @@ func (m *MockPbApiRepository) EXPECT() *MockPbApiRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CreateFeature mocks base method.
+func (m *MockPbApiRepository) CreateFeature(arg0 dto.FeatureCreate) (*dto.Feature, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFeature", arg0)
+	ret0, _ := ret[0].(*dto.Feature)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// CreateFeature indicates an expected call of CreateFeature.
+func (mr *MockPbApiRepositoryMockRecorder) CreateFeature(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeature", reflect.TypeOf((*MockPbApiRepository)(nil).CreateFeature), arg0)
+}
+
+// CreateNote mocks base method.
+func (m *MockPbApiRepository) CreateNote(arg0 dto.Note) (*dto.NoteResult, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNote", arg0)
+	ret0, _ := ret[0].(*dto.NoteResult)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// CreateNote indicates an expected call of CreateNote.
+func (mr *MockPbApiRepositoryMockRecorder) CreateNote(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNote", reflect.TypeOf((*MockPbApiRepository)(nil).CreateNote), arg0)
+}
+
+// CreatePluginIntegration mocks base method.
+func (m *MockPbApiRepository) CreatePluginIntegration(arg0 dto.PluginIntegrationData) (*dto.PluginIntegration, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePluginIntegration", arg0)
+	ret0, _ := ret[0].(*dto.PluginIntegration)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// CreatePluginIntegration indicates an expected call of CreatePluginIntegration.
+func (mr *MockPbApiRepositoryMockRecorder) CreatePluginIntegration(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePluginIntegration", reflect.TypeOf((*MockPbApiRepository)(nil).CreatePluginIntegration), arg0)
+}
+
+// DeletePluginConnection mocks base method.
+func (m *MockPbApiRepository) DeletePluginConnection(arg0, arg1 string) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePluginConnection", arg0, arg1)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// DeletePluginConnection indicates an expected call of DeletePluginConnection.
+func (mr *MockPbApiRepositoryMockRecorder) DeletePluginConnection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePluginConnection", reflect.TypeOf((*MockPbApiRepository)(nil).DeletePluginConnection), arg0, arg1)
+}
+
+// DeletePluginIntegration mocks base method.
+func (m *MockPbApiRepository) DeletePluginIntegration(arg0 string) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePluginIntegration", arg0)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// DeletePluginIntegration indicates an expected call of DeletePluginIntegration.
+func (mr *MockPbApiRepositoryMockRecorder) DeletePluginIntegration(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePluginIntegration", reflect.TypeOf((*MockPbApiRepository)(nil).DeletePluginIntegration), arg0)
+}
+
+// GetComponent mocks base method.
+func (m *MockPbApiRepository) GetComponent(arg0 string) (*dto.Component, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComponent", arg0)
+	ret0, _ := ret[0].(*dto.Component)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetComponent indicates an expected call of GetComponent.
+func (mr *MockPbApiRepositoryMockRecorder) GetComponent(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComponent", reflect.TypeOf((*MockPbApiRepository)(nil).GetComponent), arg0)
+}
+
+// GetComponents mocks base method.
+func (m *MockPbApiRepository) GetComponents() ([]dto.Component, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComponents")
+	ret0, _ := ret[0].([]dto.Component)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetComponents indicates an expected call of GetComponents.
+func (mr *MockPbApiRepositoryMockRecorder) GetComponents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComponents", reflect.TypeOf((*MockPbApiRepository)(nil).GetComponents))
+}
+
+// GetFeature mocks base method.
+func (m *MockPbApiRepository) GetFeature(arg0 string) (*dto.Feature, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeature", arg0)
+	ret0, _ := ret[0].(*dto.Feature)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetFeature indicates an expected call of GetFeature.
+func (mr *MockPbApiRepositoryMockRecorder) GetFeature(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeature", reflect.TypeOf((*MockPbApiRepository)(nil).GetFeature), arg0)
+}
+
+// GetFeaturePages mocks base method.
+func (m *MockPbApiRepository) GetFeaturePages(arg0 dto.FeatureFilter, arg1 func([]dto.Feature) api_error.ApiErr) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeaturePages", arg0, arg1)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// GetFeaturePages indicates an expected call of GetFeaturePages.
+func (mr *MockPbApiRepositoryMockRecorder) GetFeaturePages(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeaturePages", reflect.TypeOf((*MockPbApiRepository)(nil).GetFeaturePages), arg0, arg1)
+}
+
+// GetFeatureStatuses mocks base method.
+func (m *MockPbApiRepository) GetFeatureStatuses() ([]dto.FeatureStatus, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeatureStatuses")
+	ret0, _ := ret[0].([]dto.FeatureStatus)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetFeatureStatuses indicates an expected call of GetFeatureStatuses.
+func (mr *MockPbApiRepositoryMockRecorder) GetFeatureStatuses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatureStatuses", reflect.TypeOf((*MockPbApiRepository)(nil).GetFeatureStatuses))
+}
+
+// GetFeatures mocks base method.
+func (m *MockPbApiRepository) GetFeatures(arg0 dto.FeatureFilter) ([]dto.Feature, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeatures", arg0)
+	ret0, _ := ret[0].([]dto.Feature)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetFeatures indicates an expected call of GetFeatures.
+func (mr *MockPbApiRepositoryMockRecorder) GetFeatures(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatures", reflect.TypeOf((*MockPbApiRepository)(nil).GetFeatures), arg0)
+}
+
+// GetJiraIntegration mocks base method.
+func (m *MockPbApiRepository) GetJiraIntegration(arg0 string) (*dto.JiraIntegration, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJiraIntegration", arg0)
+	ret0, _ := ret[0].(*dto.JiraIntegration)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetJiraIntegration indicates an expected call of GetJiraIntegration.
+func (mr *MockPbApiRepositoryMockRecorder) GetJiraIntegration(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJiraIntegration", reflect.TypeOf((*MockPbApiRepository)(nil).GetJiraIntegration), arg0)
+}
+
+// GetJiraIntegrationConnection mocks base method.
+func (m *MockPbApiRepository) GetJiraIntegrationConnection(arg0, arg1 string) (*dto.JiraConnectionData, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJiraIntegrationConnection", arg0, arg1)
+	ret0, _ := ret[0].(*dto.JiraConnectionData)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetJiraIntegrationConnection indicates an expected call of GetJiraIntegrationConnection.
+func (mr *MockPbApiRepositoryMockRecorder) GetJiraIntegrationConnection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJiraIntegrationConnection", reflect.TypeOf((*MockPbApiRepository)(nil).GetJiraIntegrationConnection), arg0, arg1)
+}
+
+// GetJiraIntegrationConnections mocks base method.
+func (m *MockPbApiRepository) GetJiraIntegrationConnections(arg0 string, arg1 dto.JiraConnectionFilter) ([]dto.JiraConnectionData, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJiraIntegrationConnections", arg0, arg1)
+	ret0, _ := ret[0].([]dto.JiraConnectionData)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetJiraIntegrationConnections indicates an expected call of GetJiraIntegrationConnections.
+func (mr *MockPbApiRepositoryMockRecorder) GetJiraIntegrationConnections(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJiraIntegrationConnections", reflect.TypeOf((*MockPbApiRepository)(nil).GetJiraIntegrationConnections), arg0, arg1)
+}
+
+// GetJiraIntegrations mocks base method.
+func (m *MockPbApiRepository) GetJiraIntegrations() ([]dto.JiraIntegration, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJiraIntegrations")
+	ret0, _ := ret[0].([]dto.JiraIntegration)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetJiraIntegrations indicates an expected call of GetJiraIntegrations.
+func (mr *MockPbApiRepositoryMockRecorder) GetJiraIntegrations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJiraIntegrations", reflect.TypeOf((*MockPbApiRepository)(nil).GetJiraIntegrations))
+}
+
 // GetNotifications mocks base method.
 func (m *MockPbApiRepository) GetNotifications() (*dto.PbSubscriptionResponse, api_error.ApiErr) {
 	m.ctrl.T.Helper()
@@ -50,6 +272,96 @@ func (mr *MockPbApiRepositoryMockRecorder) GetNotifications() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotifications", reflect.TypeOf((*MockPbApiRepository)(nil).GetNotifications))
 }
 
+// GetPluginConnection mocks base method.
+func (m *MockPbApiRepository) GetPluginConnection(arg0, arg1 string) (*dto.ConnectionData, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPluginConnection", arg0, arg1)
+	ret0, _ := ret[0].(*dto.ConnectionData)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetPluginConnection indicates an expected call of GetPluginConnection.
+func (mr *MockPbApiRepositoryMockRecorder) GetPluginConnection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPluginConnection", reflect.TypeOf((*MockPbApiRepository)(nil).GetPluginConnection), arg0, arg1)
+}
+
+// GetPluginConnections mocks base method.
+func (m *MockPbApiRepository) GetPluginConnections(arg0 string) ([]dto.ConnectionData, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPluginConnections", arg0)
+	ret0, _ := ret[0].([]dto.ConnectionData)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetPluginConnections indicates an expected call of GetPluginConnections.
+func (mr *MockPbApiRepositoryMockRecorder) GetPluginConnections(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPluginConnections", reflect.TypeOf((*MockPbApiRepository)(nil).GetPluginConnections), arg0)
+}
+
+// GetPluginIntegration mocks base method.
+func (m *MockPbApiRepository) GetPluginIntegration(arg0 string) (*dto.PluginIntegration, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPluginIntegration", arg0)
+	ret0, _ := ret[0].(*dto.PluginIntegration)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetPluginIntegration indicates an expected call of GetPluginIntegration.
+func (mr *MockPbApiRepositoryMockRecorder) GetPluginIntegration(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPluginIntegration", reflect.TypeOf((*MockPbApiRepository)(nil).GetPluginIntegration), arg0)
+}
+
+// GetPluginIntegrations mocks base method.
+func (m *MockPbApiRepository) GetPluginIntegrations() ([]dto.PluginIntegration, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPluginIntegrations")
+	ret0, _ := ret[0].([]dto.PluginIntegration)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetPluginIntegrations indicates an expected call of GetPluginIntegrations.
+func (mr *MockPbApiRepositoryMockRecorder) GetPluginIntegrations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPluginIntegrations", reflect.TypeOf((*MockPbApiRepository)(nil).GetPluginIntegrations))
+}
+
+// GetProduct mocks base method.
+func (m *MockPbApiRepository) GetProduct(arg0 string) (*dto.Product, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProduct", arg0)
+	ret0, _ := ret[0].(*dto.Product)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetProduct indicates an expected call of GetProduct.
+func (mr *MockPbApiRepositoryMockRecorder) GetProduct(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProduct", reflect.TypeOf((*MockPbApiRepository)(nil).GetProduct), arg0)
+}
+
+// GetProducts mocks base method.
+func (m *MockPbApiRepository) GetProducts() ([]dto.Product, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProducts")
+	ret0, _ := ret[0].([]dto.Product)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetProducts indicates an expected call of GetProducts.
+func (mr *MockPbApiRepositoryMockRecorder) GetProducts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProducts", reflect.TypeOf((*MockPbApiRepository)(nil).GetProducts))
+}
+
 // RegisterForNotifications mocks base method.
 func (m *MockPbApiRepository) RegisterForNotifications() api_error.ApiErr {
 	m.ctrl.T.Helper()
@@ -77,3 +389,48 @@ func (mr *MockPbApiRepositoryMockRecorder) UnregisterForNotifications(arg0 inter
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnregisterForNotifications", reflect.TypeOf((*MockPbApiRepository)(nil).UnregisterForNotifications), arg0)
 }
+
+// UpdateFeature mocks base method.
+func (m *MockPbApiRepository) UpdateFeature(arg0 string, arg1 dto.FeatureUpdate) (*dto.Feature, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFeature", arg0, arg1)
+	ret0, _ := ret[0].(*dto.Feature)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// UpdateFeature indicates an expected call of UpdateFeature.
+func (mr *MockPbApiRepositoryMockRecorder) UpdateFeature(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFeature", reflect.TypeOf((*MockPbApiRepository)(nil).UpdateFeature), arg0, arg1)
+}
+
+// UpdatePluginConnection mocks base method.
+func (m *MockPbApiRepository) UpdatePluginConnection(arg0, arg1 string, arg2 dto.Connection) (*dto.ConnectionData, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePluginConnection", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*dto.ConnectionData)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// UpdatePluginConnection indicates an expected call of UpdatePluginConnection.
+func (mr *MockPbApiRepositoryMockRecorder) UpdatePluginConnection(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePluginConnection", reflect.TypeOf((*MockPbApiRepository)(nil).UpdatePluginConnection), arg0, arg1, arg2)
+}
+
+// UpdatePluginIntegration mocks base method.
+func (m *MockPbApiRepository) UpdatePluginIntegration(arg0 string, arg1 dto.PluginIntegrationData) (*dto.PluginIntegration, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePluginIntegration", arg0, arg1)
+	ret0, _ := ret[0].(*dto.PluginIntegration)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// UpdatePluginIntegration indicates an expected call of UpdatePluginIntegration.
+func (mr *MockPbApiRepositoryMockRecorder) UpdatePluginIntegration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePluginIntegration", reflect.TypeOf((*MockPbApiRepository)(nil).UpdatePluginIntegration), arg0, arg1)
+}
@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	dto "github.com/johannes-kuhfuss/pbreact/dto"
 	api_error "github.com/johannes-kuhfuss/services_utils/api_error"
 )
 
@@ -62,6 +63,21 @@ func (mr *MockPbApiServiceMockRecorder) RegisterForNotifications() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterForNotifications", reflect.TypeOf((*MockPbApiService)(nil).RegisterForNotifications))
 }
 
+// RegisterPluginIntegration mocks base method.
+func (m *MockPbApiService) RegisterPluginIntegration() (*dto.PluginIntegration, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterPluginIntegration")
+	ret0, _ := ret[0].(*dto.PluginIntegration)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// RegisterPluginIntegration indicates an expected call of RegisterPluginIntegration.
+func (mr *MockPbApiServiceMockRecorder) RegisterPluginIntegration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterPluginIntegration", reflect.TypeOf((*MockPbApiService)(nil).RegisterPluginIntegration))
+}
+
 // UnregisterForNotifications mocks base method.
 func (m *MockPbApiService) UnregisterForNotifications() api_error.ApiErr {
 	m.ctrl.T.Helper()
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+// ChatThreadRepository keeps the chat threads of features in a json file, keyed by channel and feature id
+type ChatThreadRepository struct {
+	file string
+}
+
+func NewChatThreadRepository(file string) ChatThreadRepository {
+	return ChatThreadRepository{
+		file: file,
+	}
+}
+
+// Load returns the persisted threads. A missing file results in an empty map.
+func (r ChatThreadRepository) Load() (map[string]string, api_error.ApiErr) {
+	threads := make(map[string]string)
+	raw, err := os.ReadFile(r.file)
+	if os.IsNotExist(err) {
+		return threads, nil
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Could not read chat thread file %v", r.file)
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, err)
+	}
+	if err := json.Unmarshal(raw, &threads); err != nil {
+		msg := fmt.Sprintf("Error parsing chat thread file %v", r.file)
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, err)
+	}
+	return threads, nil
+}
+
+// Save replaces the persisted threads. The file is written to a temporary file first so a crash cannot truncate it.
+func (r ChatThreadRepository) Save(threads map[string]string) api_error.ApiErr {
+	raw, err := json.Marshal(threads)
+	if err != nil {
+		msg := "Could not generate chat threads"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.file), 0755); err != nil {
+		msg := fmt.Sprintf("Could not create directory for chat thread file %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	tmpFile := r.file + ".tmp"
+	if err := os.WriteFile(tmpFile, raw, 0644); err != nil {
+		msg := fmt.Sprintf("Could not write chat thread file %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	if err := os.Rename(tmpFile, r.file); err != nil {
+		msg := fmt.Sprintf("Could not write chat thread file %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	return nil
+}
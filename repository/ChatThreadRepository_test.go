@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ChatThreadRepository_NoFile_Returns_NoThreads(t *testing.T) {
+	threads := NewChatThreadRepository(filepath.Join(t.TempDir(), "threads.json"))
+
+	loaded, err := threads.Load()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(loaded))
+}
+
+func Test_ChatThreadRepository_Save_And_Load(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "data", "threads.json")
+	saved := map[string]string{
+		"forum/f1": "t1",
+	}
+
+	err := NewChatThreadRepository(file).Save(saved)
+	loaded, loadErr := NewChatThreadRepository(file).Load()
+
+	assert.Nil(t, err)
+	assert.Nil(t, loadErr)
+	assert.EqualValues(t, saved, loaded)
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+// ConnectionRepository keeps the plugin integration connections of features in a json file, keyed by feature id
+type ConnectionRepository struct {
+	file string
+}
+
+func NewConnectionRepository(file string) ConnectionRepository {
+	return ConnectionRepository{
+		file: file,
+	}
+}
+
+// Load returns the persisted connections. A missing file results in an empty map.
+func (r ConnectionRepository) Load() (map[string]dto.Connection, api_error.ApiErr) {
+	connections := make(map[string]dto.Connection)
+	raw, err := os.ReadFile(r.file)
+	if os.IsNotExist(err) {
+		return connections, nil
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Could not read connection file %v", r.file)
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, err)
+	}
+	if err := json.Unmarshal(raw, &connections); err != nil {
+		msg := fmt.Sprintf("Error parsing connection file %v", r.file)
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, err)
+	}
+	return connections, nil
+}
+
+// Save replaces the persisted connections. The file is written to a temporary file first so a crash cannot truncate it.
+func (r ConnectionRepository) Save(connections map[string]dto.Connection) api_error.ApiErr {
+	raw, err := json.Marshal(connections)
+	if err != nil {
+		msg := "Could not generate connections"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.file), 0755); err != nil {
+		msg := fmt.Sprintf("Could not create directory for connection file %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	tmpFile := r.file + ".tmp"
+	if err := os.WriteFile(tmpFile, raw, 0644); err != nil {
+		msg := fmt.Sprintf("Could not write connection file %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	if err := os.Rename(tmpFile, r.file); err != nil {
+		msg := fmt.Sprintf("Could not write connection file %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConnectionRepository_NoFile_Returns_NoConnections(t *testing.T) {
+	connections := NewConnectionRepository(filepath.Join(t.TempDir(), "connections.json"))
+
+	loaded, err := connections.Load()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(loaded))
+}
+
+func Test_ConnectionRepository_Save_And_Load(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "data", "connections.json")
+	saved := map[string]dto.Connection{
+		"f1": {State: dto.ConnectionConnected, Label: "PB-1", TargetUrl: "https://tracker/PB-1"},
+	}
+
+	err := NewConnectionRepository(file).Save(saved)
+	loaded, loadErr := NewConnectionRepository(file).Load()
+
+	assert.Nil(t, err)
+	assert.Nil(t, loadErr)
+	assert.EqualValues(t, saved, loaded)
+}
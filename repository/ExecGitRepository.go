@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+// ExecGitRepository commits to the local git repository in the mirror directory by running the git command line client.
+// Pushes run in the background, one at a time; commits made while a push runs are sent with the next one.
+type ExecGitRepository struct {
+	cfg     *config.AppConfig
+	pushes  chan bool
+	pusher  *sync.Once
+	pushing *sync.Mutex
+}
+
+func NewExecGitRepository(c *config.AppConfig) ExecGitRepository {
+	return ExecGitRepository{
+		cfg:     c,
+		pushes:  make(chan bool, 1),
+		pusher:  &sync.Once{},
+		pushing: &sync.Mutex{},
+	}
+}
+
+// Init creates the mirror directory and initializes a git repository in it, unless there already is one
+func (r ExecGitRepository) Init() api_error.ApiErr {
+	if err := os.MkdirAll(r.cfg.Mirror.Dir, 0755); err != nil {
+		msg := fmt.Sprintf("Could not create mirror directory %v", r.cfg.Mirror.Dir)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	if _, err := os.Stat(filepath.Join(r.cfg.Mirror.Dir, ".git")); err == nil {
+		return nil
+	}
+	if _, err := r.git("init", "-q"); err != nil {
+		msg := fmt.Sprintf("Could not initialize git repository in %v", r.cfg.Mirror.Dir)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	logger.Info(fmt.Sprintf("Initialized git repository in %v", r.cfg.Mirror.Dir))
+	return nil
+}
+
+// Commit stages all changes in the mirror directory, including removed files, and commits them. Nothing is committed
+// without changes. With a remote configured, a push is started in the background; a failed push is only logged, the
+// next one catches up.
+func (r ExecGitRepository) Commit(message string) api_error.ApiErr {
+	if _, err := r.git("add", "-A", "."); err != nil {
+		msg := fmt.Sprintf("Could not stage changes in %v", r.cfg.Mirror.Dir)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	if _, err := r.git("diff", "--cached", "--quiet"); err == nil {
+		return nil
+	}
+	if _, err := r.git("-c", "user.name="+r.cfg.Mirror.AuthorName, "-c", "user.email="+r.cfg.Mirror.AuthorEmail, "commit", "-q", "-m", message); err != nil {
+		msg := fmt.Sprintf("Could not commit changes in %v", r.cfg.Mirror.Dir)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	if r.cfg.Mirror.Remote != "" {
+		r.pusher.Do(func() {
+			go r.pushInBackground()
+		})
+		select {
+		case r.pushes <- true:
+		default:
+		}
+	}
+	return nil
+}
+
+// Push sends the commits to the remote and waits for it, at most for the push timeout
+func (r ExecGitRepository) Push() api_error.ApiErr {
+	r.pushing.Lock()
+	defer r.pushing.Unlock()
+	ctx := context.Background()
+	if r.cfg.Mirror.PushTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(r.cfg.Mirror.PushTimeout)*time.Second)
+		defer cancel()
+	}
+	if _, err := r.gitContext(ctx, "push", "-q", r.cfg.Mirror.Remote, "HEAD"); err != nil {
+		msg := fmt.Sprintf("Could not push mirror to %v", r.cfg.Mirror.Remote)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	return nil
+}
+
+func (r ExecGitRepository) pushInBackground() {
+	for range r.pushes {
+		r.Push()
+	}
+}
+
+func (r ExecGitRepository) git(args ...string) (string, error) {
+	return r.gitContext(context.Background(), args...)
+}
+
+func (r ExecGitRepository) gitContext(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = r.cfg.Mirror.Dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if stderr.Len() > 0 {
+			return "", fmt.Errorf("%v: %v", err, strings.TrimSpace(stderr.String()))
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
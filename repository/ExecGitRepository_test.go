@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func gitRepository(t *testing.T) ExecGitRepository {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	var c config.AppConfig
+	c.Mirror.Dir = filepath.Join(t.TempDir(), "mirror")
+	c.Mirror.AuthorName = "pbreact"
+	c.Mirror.AuthorEmail = "pbreact@localhost"
+	return NewExecGitRepository(&c)
+}
+
+func gitLog(t *testing.T, r ExecGitRepository) []string {
+	out, err := r.git("log", "--format=%an <%ae> %s")
+	assert.Nil(t, err)
+	return strings.Split(strings.TrimSpace(out), "\n")
+}
+
+func Test_ExecGitRepository_Commit_Adds_And_Removes_Files(t *testing.T) {
+	r := gitRepository(t)
+	file := filepath.Join(r.cfg.Mirror.Dir, "portal", "dark-mode-f1.md")
+
+	initErr := r.Init()
+	os.MkdirAll(filepath.Dir(file), 0755)
+	os.WriteFile(file, []byte("# Dark mode\n"), 0644)
+	addErr := r.Commit("Add feature: Dark mode")
+	os.Remove(file)
+	removeErr := r.Commit("Remove feature: Dark mode")
+	tracked, _ := r.git("ls-files")
+
+	assert.Nil(t, initErr)
+	assert.Nil(t, addErr)
+	assert.Nil(t, removeErr)
+	assert.Empty(t, tracked)
+	assert.EqualValues(t, []string{"pbreact <pbreact@localhost> Remove feature: Dark mode", "pbreact <pbreact@localhost> Add feature: Dark mode"}, gitLog(t, r))
+}
+
+func Test_ExecGitRepository_Commit_NoChange_Commits_Nothing(t *testing.T) {
+	r := gitRepository(t)
+	r.Init()
+	os.WriteFile(filepath.Join(r.cfg.Mirror.Dir, "a.md"), []byte("a\n"), 0644)
+	r.Commit("first")
+
+	err := r.Commit("second")
+	reinitErr := r.Init()
+
+	assert.Nil(t, err)
+	assert.Nil(t, reinitErr)
+	assert.EqualValues(t, []string{"pbreact <pbreact@localhost> first"}, gitLog(t, r))
+}
+
+func Test_ExecGitRepository_Commit_Pushes_To_Remote(t *testing.T) {
+	r := gitRepository(t)
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	exec.Command("git", "init", "-q", "--bare", remote).Run()
+	r.cfg.Mirror.Remote = remote
+	r.Init()
+	os.WriteFile(filepath.Join(r.cfg.Mirror.Dir, "a.md"), []byte("a\n"), 0644)
+
+	err := r.Commit("first")
+
+	assert.Nil(t, err)
+	assert.Eventually(t, func() bool {
+		out, logErr := exec.Command("git", "--git-dir", remote, "log", "--all", "--format=%s").Output()
+		return logErr == nil && string(out) == "first\n"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_ExecGitRepository_Push_UnknownRemote_Returns_Error(t *testing.T) {
+	r := gitRepository(t)
+	r.cfg.Mirror.Remote = filepath.Join(t.TempDir(), "missing.git")
+	r.cfg.Mirror.PushTimeout = 5
+	r.Init()
+	os.WriteFile(filepath.Join(r.cfg.Mirror.Dir, "a.md"), []byte("a\n"), 0644)
+	r.git("add", "-A", ".")
+	r.git("-c", "user.name=pbreact", "-c", "user.email=pbreact@localhost", "commit", "-q", "-m", "first")
+
+	err := r.Push()
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Could not push mirror to "+r.cfg.Mirror.Remote, err.Message())
+}
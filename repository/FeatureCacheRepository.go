@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+// FeatureCacheRepository keeps the last state seen of every feature in a json file, keyed by feature id
+type FeatureCacheRepository struct {
+	file string
+}
+
+func NewFeatureCacheRepository(file string) FeatureCacheRepository {
+	return FeatureCacheRepository{
+		file: file,
+	}
+}
+
+// Load returns the persisted features. A missing file results in an empty map.
+func (r FeatureCacheRepository) Load() (map[string]dto.Feature, api_error.ApiErr) {
+	features := make(map[string]dto.Feature)
+	raw, err := os.ReadFile(r.file)
+	if os.IsNotExist(err) {
+		return features, nil
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Could not read feature cache file %v", r.file)
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, err)
+	}
+	if err := json.Unmarshal(raw, &features); err != nil {
+		msg := fmt.Sprintf("Error parsing feature cache file %v", r.file)
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, err)
+	}
+	return features, nil
+}
+
+// Save replaces the persisted features. The file is written to a temporary file first so a crash cannot truncate it.
+func (r FeatureCacheRepository) Save(features map[string]dto.Feature) api_error.ApiErr {
+	raw, err := json.Marshal(features)
+	if err != nil {
+		msg := "Could not generate feature cache"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.file), 0755); err != nil {
+		msg := fmt.Sprintf("Could not create directory for feature cache file %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	tmpFile := r.file + ".tmp"
+	if err := os.WriteFile(tmpFile, raw, 0644); err != nil {
+		msg := fmt.Sprintf("Could not write feature cache file %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	if err := os.Rename(tmpFile, r.file); err != nil {
+		msg := fmt.Sprintf("Could not write feature cache file %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FeatureCacheRepository_NoFile_Returns_NoFeatures(t *testing.T) {
+	cache := NewFeatureCacheRepository(filepath.Join(t.TempDir(), "seen.json"))
+
+	loaded, err := cache.Load()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(loaded))
+}
+
+func Test_FeatureCacheRepository_Save_And_Load(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "data", "seen.json")
+	saved := map[string]dto.Feature{
+		"f1": {ID: "f1", Name: "Dark mode", Status: dto.FeatureStatus{ID: "s1", Name: "Planned"}, Timeframe: dto.Timeframe{StartDate: "2024-01-01", EndDate: "none"}},
+	}
+
+	err := NewFeatureCacheRepository(file).Save(saved)
+	loaded, loadErr := NewFeatureCacheRepository(file).Load()
+
+	assert.Nil(t, err)
+	assert.Nil(t, loadErr)
+	assert.EqualValues(t, saved, loaded)
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+// FeatureJournalRepository appends observed feature changes to a JSON Lines file, one entry per line
+type FeatureJournalRepository struct {
+	file string
+	mu   *sync.Mutex
+}
+
+func NewFeatureJournalRepository(file string) FeatureJournalRepository {
+	return FeatureJournalRepository{
+		file: file,
+		mu:   &sync.Mutex{},
+	}
+}
+
+func (r FeatureJournalRepository) Append(entry dto.JournalEntry) api_error.ApiErr {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		msg := "Could not generate journal entry"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(r.file), 0755); err != nil {
+		msg := fmt.Sprintf("Could not create directory for feature journal %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	f, err := os.OpenFile(r.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		msg := fmt.Sprintf("Could not open feature journal %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		msg := fmt.Sprintf("Could not write to feature journal %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	return nil
+}
+
+// Read returns all entries recorded at or after since, oldest first. Lines that cannot be parsed are skipped.
+func (r FeatureJournalRepository) Read(since time.Time) ([]dto.JournalEntry, api_error.ApiErr) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := []dto.JournalEntry{}
+	f, err := os.Open(r.file)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Could not open feature journal %v", r.file)
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry dto.JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			logger.Warn(fmt.Sprintf("Skipping unreadable line in feature journal %v", r.file))
+			continue
+		}
+		if !entry.Time.Before(since) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		msg := fmt.Sprintf("Could not read feature journal %v", r.file)
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FeatureJournalRepository_NoFile_Returns_NoEntries(t *testing.T) {
+	journal := NewFeatureJournalRepository(filepath.Join(t.TempDir(), "journal.jsonl"))
+
+	entries, err := journal.Read(time.Time{})
+
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+}
+
+func Test_FeatureJournalRepository_Append_And_Read_Since(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "data", "journal.jsonl")
+	journal := NewFeatureJournalRepository(file)
+	first := dto.JournalEntry{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), FeatureId: "f1", Kind: dto.JournalTimeframe, Timeframe: &dto.Timeframe{StartDate: "none", EndDate: "2024-03-31"}}
+	second := dto.JournalEntry{Time: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), FeatureId: "f1", Kind: dto.JournalTimeframe, DaysSlipped: 30}
+
+	err1 := journal.Append(first)
+	err2 := journal.Append(second)
+	all, readErr := NewFeatureJournalRepository(file).Read(time.Time{})
+	recent, _ := journal.Read(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, readErr)
+	assert.EqualValues(t, []dto.JournalEntry{first, second}, all)
+	assert.EqualValues(t, []dto.JournalEntry{second}, recent)
+}
+
+func Test_FeatureJournalRepository_Read_Skips_BrokenLines(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "journal.jsonl")
+	os.WriteFile(file, []byte("{\"featureId\":\"f1\"}\nnot json\n{\"featureId\":\"f2\"}\n"), 0644)
+
+	entries, err := NewFeatureJournalRepository(file).Read(time.Time{})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(entries))
+	assert.EqualValues(t, "f2", entries[1].FeatureId)
+}
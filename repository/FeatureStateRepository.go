@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+// FeatureStateRepository keeps the last known state of all features in a json file
+type FeatureStateRepository struct {
+	file string
+}
+
+func NewFeatureStateRepository(file string) FeatureStateRepository {
+	return FeatureStateRepository{
+		file: file,
+	}
+}
+
+// Load returns the persisted feature states. A missing file results in a nil map, meaning there is no baseline yet.
+func (r FeatureStateRepository) Load() (map[string]dto.FeatureSnapshot, api_error.ApiErr) {
+	raw, err := os.ReadFile(r.file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Could not read feature state file %v", r.file)
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, err)
+	}
+	states := make(map[string]dto.FeatureSnapshot)
+	if err := json.Unmarshal(raw, &states); err != nil {
+		msg := fmt.Sprintf("Error parsing feature state file %v", r.file)
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, err)
+	}
+	return states, nil
+}
+
+// Save replaces the persisted feature states. The file is written to a temporary file first so a crash cannot truncate it.
+func (r FeatureStateRepository) Save(states map[string]dto.FeatureSnapshot) api_error.ApiErr {
+	raw, err := json.Marshal(states)
+	if err != nil {
+		msg := "Could not generate feature state"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.file), 0755); err != nil {
+		msg := fmt.Sprintf("Could not create directory for feature state file %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	tmpFile := r.file + ".tmp"
+	if err := os.WriteFile(tmpFile, raw, 0644); err != nil {
+		msg := fmt.Sprintf("Could not write feature state file %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	if err := os.Rename(tmpFile, r.file); err != nil {
+		msg := fmt.Sprintf("Could not write feature state file %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FeatureStateRepository_NoFile_Returns_NoBaseline(t *testing.T) {
+	states := NewFeatureStateRepository(filepath.Join(t.TempDir(), "state.json"))
+
+	loaded, err := states.Load()
+
+	assert.Nil(t, err)
+	assert.Nil(t, loaded)
+}
+
+func Test_FeatureStateRepository_Save_And_Load(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "data", "state.json")
+	saved := map[string]dto.FeatureSnapshot{
+		"f1": {ID: "f1", Name: "Feature 1", Status: "Planned", Fingerprint: "abc"},
+	}
+
+	err := NewFeatureStateRepository(file).Save(saved)
+	loaded, loadErr := NewFeatureStateRepository(file).Load()
+
+	assert.Nil(t, err)
+	assert.Nil(t, loadErr)
+	assert.EqualValues(t, saved, loaded)
+}
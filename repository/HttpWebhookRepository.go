@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+// HttpWebhookRepository posts JSON payloads to outgoing webhooks
+type HttpWebhookRepository struct {
+	client *http.Client
+}
+
+func NewHttpWebhookRepository(timeout time.Duration) HttpWebhookRepository {
+	return HttpWebhookRepository{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (r HttpWebhookRepository) PostJson(url string, payload interface{}) api_error.ApiErr {
+	return r.PostJsonReply(url, "", payload, nil)
+}
+
+// PostJsonReply posts the payload with an optional bearer token and decodes the JSON reply into reply, unless it is nil
+func (r HttpWebhookRepository) PostJsonReply(url string, token string, payload interface{}, reply interface{}) api_error.ApiErr {
+	body, jsonErr := json.Marshal(payload)
+	if jsonErr != nil {
+		msg := "Could not generate webhook payload"
+		logger.Error(msg, jsonErr)
+		return api_error.NewInternalServerError(msg, jsonErr)
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		msg := fmt.Sprintf("Could not create request for webhook %v", url)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		msg := fmt.Sprintf("Could not post to webhook %v", url)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(resp.Body)
+		msg := fmt.Sprintf("Webhook %v answered with status code %v. Message: %v", url, resp.StatusCode, string(respBody))
+		logger.Error(msg, nil)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	if reply == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(reply); err != nil {
+		msg := fmt.Sprintf("Could not parse reply of webhook %v", url)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PostJson_Sends_Payload(t *testing.T) {
+	var contentType string
+	var sent map[string]string
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contentType = r.Header.Get("Content-Type")
+			json.NewDecoder(r.Body).Decode(&sent)
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer srv.Close()
+
+	err := NewHttpWebhookRepository(time.Second).PostJson(srv.URL, map[string]string{"event": "feature.slipped"})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "application/json", contentType)
+	assert.EqualValues(t, "feature.slipped", sent["event"])
+}
+
+func Test_PostJson_ErrorStatus_Returns_InternalServerError(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("down"))
+		}),
+	)
+	defer srv.Close()
+
+	err := NewHttpWebhookRepository(time.Second).PostJson(srv.URL, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Webhook "+srv.URL+" answered with status code 502. Message: down", err.Message())
+}
+
+func Test_PostJsonReply_Sends_Token_And_Decodes_Reply(t *testing.T) {
+	var authorization string
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			w.Write([]byte(`{"ok": true, "ts": "1700000000.000100"}`))
+		}),
+	)
+	defer srv.Close()
+	var reply map[string]interface{}
+
+	err := NewHttpWebhookRepository(time.Second).PostJsonReply(srv.URL, "xoxb-token", map[string]string{"text": "Hello"}, &reply)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Bearer xoxb-token", authorization)
+	assert.EqualValues(t, "1700000000.000100", reply["ts"])
+}
+
+func Test_PostJsonReply_InvalidReply_Returns_InternalServerError(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Write([]byte("ok"))
+		}),
+	)
+	defer srv.Close()
+	var reply map[string]interface{}
+
+	err := NewHttpWebhookRepository(time.Second).PostJsonReply(srv.URL, "", nil, &reply)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Could not parse reply of webhook "+srv.URL, err.Message())
+}
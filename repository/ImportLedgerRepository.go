@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+// ImportLedgerRepository remembers imported record ids in a plain text file, one id per line
+type ImportLedgerRepository struct {
+	file     string
+	mu       *sync.Mutex
+	imported map[string]bool
+}
+
+func NewImportLedgerRepository(file string) (ImportLedgerRepository, api_error.ApiErr) {
+	ledger := ImportLedgerRepository{
+		file:     file,
+		mu:       &sync.Mutex{},
+		imported: make(map[string]bool),
+	}
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return ledger, nil
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Could not open import ledger %v", file)
+		logger.Error(msg, err)
+		return ledger, api_error.NewInternalServerError(msg, err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if id := strings.TrimSpace(scanner.Text()); id != "" {
+			ledger.imported[id] = true
+		}
+	}
+	return ledger, nil
+}
+
+func (l ImportLedgerRepository) IsImported(id string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.imported[id]
+}
+
+func (l ImportLedgerRepository) MarkImported(id string) api_error.ApiErr {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.imported[id] {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(l.file), 0755); err != nil {
+		msg := fmt.Sprintf("Could not create directory for import ledger %v", l.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	f, err := os.OpenFile(l.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		msg := fmt.Sprintf("Could not open import ledger %v", l.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(id + "\n"); err != nil {
+		msg := fmt.Sprintf("Could not write to import ledger %v", l.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	l.imported[id] = true
+	return nil
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ImportLedger_MarkImported_Persists_Id(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "ledger", "imported.txt")
+	ledger, err := NewImportLedgerRepository(file)
+	assert.Nil(t, err)
+
+	markErr := ledger.MarkImported("<abc@example.com>")
+	reloaded, reloadErr := NewImportLedgerRepository(file)
+
+	assert.Nil(t, markErr)
+	assert.Nil(t, reloadErr)
+	assert.True(t, ledger.IsImported("<abc@example.com>"))
+	assert.True(t, reloaded.IsImported("<abc@example.com>"))
+	assert.False(t, reloaded.IsImported("<other@example.com>"))
+}
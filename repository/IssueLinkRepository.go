@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+// IssueLinkRepository keeps the links between features and tracker issues in a json file, keyed by feature id
+type IssueLinkRepository struct {
+	file string
+}
+
+func NewIssueLinkRepository(file string) IssueLinkRepository {
+	return IssueLinkRepository{
+		file: file,
+	}
+}
+
+// Load returns the persisted links. A missing file results in an empty map.
+func (r IssueLinkRepository) Load() (map[string]dto.IssueRef, api_error.ApiErr) {
+	links := make(map[string]dto.IssueRef)
+	raw, err := os.ReadFile(r.file)
+	if os.IsNotExist(err) {
+		return links, nil
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Could not read issue link file %v", r.file)
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, err)
+	}
+	if err := json.Unmarshal(raw, &links); err != nil {
+		msg := fmt.Sprintf("Error parsing issue link file %v", r.file)
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, err)
+	}
+	return links, nil
+}
+
+// Save replaces the persisted links. The file is written to a temporary file first so a crash cannot truncate it.
+func (r IssueLinkRepository) Save(links map[string]dto.IssueRef) api_error.ApiErr {
+	raw, err := json.Marshal(links)
+	if err != nil {
+		msg := "Could not generate issue links"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.file), 0755); err != nil {
+		msg := fmt.Sprintf("Could not create directory for issue link file %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	tmpFile := r.file + ".tmp"
+	if err := os.WriteFile(tmpFile, raw, 0644); err != nil {
+		msg := fmt.Sprintf("Could not write issue link file %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	if err := os.Rename(tmpFile, r.file); err != nil {
+		msg := fmt.Sprintf("Could not write issue link file %v", r.file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IssueLinkRepository_NoFile_Returns_NoLinks(t *testing.T) {
+	links := NewIssueLinkRepository(filepath.Join(t.TempDir(), "links.json"))
+
+	loaded, err := links.Load()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(loaded))
+}
+
+func Test_IssueLinkRepository_Save_And_Load(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "data", "links.json")
+	saved := map[string]dto.IssueRef{
+		"f1": {Key: "PB-1", Url: "https://tracker/PB-1"},
+	}
+
+	err := NewIssueLinkRepository(file).Save(saved)
+	loaded, loadErr := NewIssueLinkRepository(file).Load()
+
+	assert.Nil(t, err)
+	assert.Nil(t, loadErr)
+	assert.EqualValues(t, saved, loaded)
+}
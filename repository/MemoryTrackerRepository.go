@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+// MemoryTrackerRepository keeps issues in memory. It stands in for a real tracker in tests and local setups.
+type MemoryTrackerRepository struct {
+	store *memoryIssues
+}
+
+type memoryIssues struct {
+	sync.Mutex
+	next   int
+	issues map[string]dto.Issue
+	closed map[string]bool
+}
+
+func NewMemoryTrackerRepository() MemoryTrackerRepository {
+	return MemoryTrackerRepository{
+		store: &memoryIssues{
+			issues: make(map[string]dto.Issue),
+			closed: make(map[string]bool),
+		},
+	}
+}
+
+func (r MemoryTrackerRepository) CreateIssue(issue dto.Issue) (*dto.IssueRef, api_error.ApiErr) {
+	r.store.Lock()
+	defer r.store.Unlock()
+	r.store.next++
+	key := fmt.Sprintf("MEM-%v", r.store.next)
+	r.store.issues[key] = issue
+	return &dto.IssueRef{
+		Key: key,
+		Url: r.GetIssueUrl(key),
+	}, nil
+}
+
+func (r MemoryTrackerRepository) UpdateIssue(key string, issue dto.Issue) api_error.ApiErr {
+	r.store.Lock()
+	defer r.store.Unlock()
+	if _, found := r.store.issues[key]; !found {
+		return issueNotFound(key)
+	}
+	r.store.issues[key] = issue
+	return nil
+}
+
+func (r MemoryTrackerRepository) CloseIssue(key string) api_error.ApiErr {
+	r.store.Lock()
+	defer r.store.Unlock()
+	if _, found := r.store.issues[key]; !found {
+		return issueNotFound(key)
+	}
+	r.store.closed[key] = true
+	return nil
+}
+
+func (r MemoryTrackerRepository) GetIssueUrl(key string) string {
+	return fmt.Sprintf("memory://issues/%v", key)
+}
+
+// GetIssue returns an issue and whether it was closed
+func (r MemoryTrackerRepository) GetIssue(key string) (*dto.Issue, bool) {
+	r.store.Lock()
+	defer r.store.Unlock()
+	issue, found := r.store.issues[key]
+	if !found {
+		return nil, false
+	}
+	return &issue, r.store.closed[key]
+}
+
+func issueNotFound(key string) api_error.ApiErr {
+	msg := fmt.Sprintf("No issue with key %v found", key)
+	logger.Error(msg, nil)
+	return api_error.NewNotFoundError(msg)
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+func (r PbApiRepository) GetProducts() ([]dto.Product, api_error.ApiErr) {
+	products := []dto.Product{}
+	reqUrl := r.apiUrl("/products", nil)
+	for reqUrl != "" {
+		var pbResp dto.PbProductsResponse
+		err := r.GetJson(reqUrl, &pbResp, "Error parsing product list")
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, pbResp.Data...)
+		reqUrl = nextPageUrl(pbResp.Links)
+	}
+	return products, nil
+}
+
+func (r PbApiRepository) GetProduct(id string) (*dto.Product, api_error.ApiErr) {
+	var pbResp dto.PbProductResponse
+	reqUrl := r.apiUrl(fmt.Sprintf("/products/%v", id), nil)
+	err := r.GetJson(reqUrl, &pbResp, "Error parsing product")
+	if err != nil {
+		return nil, err
+	}
+	return &pbResp.Data, nil
+}
+
+func (r PbApiRepository) GetComponents() ([]dto.Component, api_error.ApiErr) {
+	components := []dto.Component{}
+	reqUrl := r.apiUrl("/components", nil)
+	for reqUrl != "" {
+		var pbResp dto.PbComponentsResponse
+		err := r.GetJson(reqUrl, &pbResp, "Error parsing component list")
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, pbResp.Data...)
+		reqUrl = nextPageUrl(pbResp.Links)
+	}
+	return components, nil
+}
+
+func (r PbApiRepository) GetComponent(id string) (*dto.Component, api_error.ApiErr) {
+	var pbResp dto.PbComponentResponse
+	reqUrl := r.apiUrl(fmt.Sprintf("/components/%v", id), nil)
+	err := r.GetJson(reqUrl, &pbResp, "Error parsing component")
+	if err != nil {
+		return nil, err
+	}
+	return &pbResp.Data, nil
+}
+
+func (r PbApiRepository) GetFeatures(filter dto.FeatureFilter) ([]dto.Feature, api_error.ApiErr) {
+	features := []dto.Feature{}
+	err := r.GetFeaturePages(filter, func(page []dto.Feature) api_error.ApiErr {
+		features = append(features, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return features, nil
+}
+
+// GetFeaturePages hands each page of features to handlePage as soon as it arrives. An error from handlePage stops paging.
+func (r PbApiRepository) GetFeaturePages(filter dto.FeatureFilter, handlePage func([]dto.Feature) api_error.ApiErr) api_error.ApiErr {
+	reqUrl := r.apiUrl("/features", featureFilterQuery(filter))
+	for reqUrl != "" {
+		var pbResp dto.PbFeaturesResponse
+		err := r.GetJson(reqUrl, &pbResp, "Error parsing feature list")
+		if err != nil {
+			return err
+		}
+		if err := handlePage(pbResp.Data); err != nil {
+			return err
+		}
+		reqUrl = nextPageUrl(pbResp.Links)
+	}
+	return nil
+}
+
+func (r PbApiRepository) GetFeature(id string) (*dto.Feature, api_error.ApiErr) {
+	var pbResp dto.PbFeatureResponse
+	reqUrl := r.apiUrl(fmt.Sprintf("/features/%v", id), nil)
+	err := r.GetJson(reqUrl, &pbResp, "Error parsing feature")
+	if err != nil {
+		return nil, err
+	}
+	return &pbResp.Data, nil
+}
+
+func (r PbApiRepository) CreateFeature(feature dto.FeatureCreate) (*dto.Feature, api_error.ApiErr) {
+	var pbResp dto.PbFeatureResponse
+	reqUrl := r.apiUrl("/features", nil)
+	err := r.SendJson("POST", reqUrl, dto.PbFeatureCreateRequest{Data: feature}, &pbResp, "Error parsing feature")
+	if err != nil {
+		return nil, err
+	}
+	return &pbResp.Data, nil
+}
+
+func (r PbApiRepository) UpdateFeature(id string, update dto.FeatureUpdate) (*dto.Feature, api_error.ApiErr) {
+	var pbResp dto.PbFeatureResponse
+	reqUrl := r.apiUrl(fmt.Sprintf("/features/%v", id), nil)
+	err := r.SendJson("PUT", reqUrl, dto.PbFeatureUpdateRequest{Data: update}, &pbResp, "Error parsing feature")
+	if err != nil {
+		return nil, err
+	}
+	return &pbResp.Data, nil
+}
+
+func (r PbApiRepository) GetFeatureStatuses() ([]dto.FeatureStatus, api_error.ApiErr) {
+	statuses := []dto.FeatureStatus{}
+	reqUrl := r.apiUrl("/feature-statuses", nil)
+	for reqUrl != "" {
+		var pbResp dto.PbFeatureStatusesResponse
+		err := r.GetJson(reqUrl, &pbResp, "Error parsing feature status list")
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, pbResp.Data...)
+		reqUrl = nextPageUrl(pbResp.Links)
+	}
+	return statuses, nil
+}
+
+func featureFilterQuery(filter dto.FeatureFilter) url.Values {
+	query := url.Values{}
+	if filter.StatusId != "" {
+		query.Set("status.id", filter.StatusId)
+	}
+	if filter.StatusName != "" {
+		query.Set("status.name", filter.StatusName)
+	}
+	if filter.Archived != nil {
+		query.Set("archived", strconv.FormatBool(*filter.Archived))
+	}
+	if len(query) == 0 {
+		return nil
+	}
+	return query
+}
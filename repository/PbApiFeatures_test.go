@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetProducts_ExecFails_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	cfg.PbApi.BaseUrl = ""
+
+	products, err := repo.GetProducts()
+
+	assert.Nil(t, products)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Error when executing http request", err.Message())
+}
+
+func Test_GetProducts_BodyParsingFails_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("Not JSON"))
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	products, err := repo.GetProducts()
+
+	assert.Nil(t, products)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Error parsing product list", err.Message())
+}
+
+func Test_GetProducts_FollowsPages_Returns_AllProducts(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	var srv *httptest.Server
+	srv = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resp := dto.PbProductsResponse{}
+			if r.URL.Query().Get("pageOffset") == "" {
+				resp.Data = []dto.Product{{ID: "p1", Name: "Product 1"}}
+				resp.Links.Next = fmt.Sprintf("%v/products?pageOffset=1", srv.URL)
+			} else {
+				resp.Data = []dto.Product{{ID: "p2", Name: "Product 2"}}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	products, err := repo.GetProducts()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(products))
+	assert.EqualValues(t, "p1", products[0].ID)
+	assert.EqualValues(t, "p2", products[1].ID)
+}
+
+func Test_GetProduct_Returns_Product(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	var reqPath string
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqPath = r.URL.Path
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(dto.PbProductResponse{Data: dto.Product{ID: "p1", Name: "Product 1"}})
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	product, err := repo.GetProduct("p1")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "/products/p1", reqPath)
+	assert.EqualValues(t, "Product 1", product.Name)
+}
+
+func Test_GetComponents_FollowsPages_Returns_AllComponents(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	var srv *httptest.Server
+	srv = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resp := dto.PbComponentsResponse{}
+			if r.URL.Query().Get("pageOffset") == "" {
+				resp.Data = []dto.Component{{ID: "c1"}}
+				resp.Links.Next = fmt.Sprintf("%v/components?pageOffset=1", srv.URL)
+			} else {
+				resp.Data = []dto.Component{{ID: "c2"}}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	components, err := repo.GetComponents()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(components))
+	assert.EqualValues(t, "c2", components[1].ID)
+}
+
+func Test_GetComponent_ExecFails_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Not found"))
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	component, err := repo.GetComponent("c1")
+
+	assert.Nil(t, component)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+}
+
+func Test_GetFeatures_WithFilter_Sends_Query(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	var query string
+	archived := false
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(dto.PbFeaturesResponse{Data: []dto.Feature{{ID: "f1"}}})
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	features, err := repo.GetFeatures(dto.FeatureFilter{StatusName: "In progress", Archived: &archived})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(features))
+	assert.EqualValues(t, "archived=false&status.name=In+progress", query)
+}
+
+func Test_GetFeaturePages_HandlerError_Stops_Paging(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	requests := 0
+	var srv *httptest.Server
+	srv = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests++
+			json.NewEncoder(w).Encode(dto.PbFeaturesResponse{
+				Data:  []dto.Feature{{ID: fmt.Sprintf("f%v", requests)}},
+				Links: dto.Links{Next: fmt.Sprintf("%v/features?pageCursor=%v", srv.URL, requests)},
+			})
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+	apiError := api_error.NewInternalServerError("stop", nil)
+	pages := 0
+
+	err := repo.GetFeaturePages(dto.FeatureFilter{}, func(page []dto.Feature) api_error.ApiErr {
+		pages++
+		if pages == 2 {
+			return apiError
+		}
+		return nil
+	})
+
+	assert.EqualValues(t, apiError, err)
+	assert.EqualValues(t, 2, requests)
+}
+
+func Test_GetFeature_Returns_Feature(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{"id":"f1","name":"Feature 1","type":"subfeature","parent":{"feature":{"id":"f0"}}}}`))
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	feature, err := repo.GetFeature("f1")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Feature 1", feature.Name)
+	assert.EqualValues(t, "f0", feature.Parent.ParentId())
+}
+
+func Test_GetFeatureStatuses_BodyParsingFails_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("Not JSON"))
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	statuses, err := repo.GetFeatureStatuses()
+
+	assert.Nil(t, statuses)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Error parsing feature status list", err.Message())
+}
+
+func Test_GetFeatureStatuses_Returns_Statuses(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	var reqPath string
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqPath = r.URL.Path
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(dto.PbFeatureStatusesResponse{Data: []dto.FeatureStatus{{ID: "s1", Name: "New idea"}}})
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	statuses, err := repo.GetFeatureStatuses()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "/feature-statuses", reqPath)
+	assert.EqualValues(t, []dto.FeatureStatus{{ID: "s1", Name: "New idea"}}, statuses)
+}
+
+func Test_CreateFeature_Sends_FeatureWithParent(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	var sent map[string]map[string]interface{}
+	var method, path string
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method, path = r.Method, r.URL.Path
+			json.NewDecoder(r.Body).Decode(&sent)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(dto.PbFeatureResponse{Data: dto.Feature{ID: "f1", Name: "Dark mode"}})
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+	create := dto.FeatureCreate{
+		Name:   "Dark mode",
+		Type:   dto.NodeTypeFeature,
+		Status: dto.StatusRef{ID: "s1"},
+		Parent: dto.ParentIdRef{Component: &dto.IdRef{ID: "c1"}},
+	}
+
+	feature, err := repo.CreateFeature(create)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "POST", method)
+	assert.EqualValues(t, "/features", path)
+	assert.EqualValues(t, map[string]interface{}{"component": map[string]interface{}{"id": "c1"}}, sent["data"]["parent"])
+	assert.NotContains(t, sent["data"], "timeframe")
+	assert.EqualValues(t, "f1", feature.ID)
+}
+
+func Test_UpdateFeature_Sends_Status(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	var sent dto.PbFeatureUpdateRequest
+	var method, path string
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method, path = r.Method, r.URL.Path
+			json.NewDecoder(r.Body).Decode(&sent)
+			json.NewEncoder(w).Encode(dto.PbFeatureResponse{Data: dto.Feature{ID: "f1", Status: dto.FeatureStatus{ID: "s3", Name: "Released"}}})
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	feature, err := repo.UpdateFeature("f1", dto.FeatureUpdate{Status: &dto.StatusRef{ID: "s3"}})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "PUT", method)
+	assert.EqualValues(t, "/features/f1", path)
+	assert.EqualValues(t, "s3", sent.Data.Status.ID)
+	assert.EqualValues(t, "Released", feature.Status.Name)
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+func (r PbApiRepository) GetJiraIntegrations() ([]dto.JiraIntegration, api_error.ApiErr) {
+	integrations := []dto.JiraIntegration{}
+	reqUrl := r.apiUrl("/jira-integrations", nil)
+	for reqUrl != "" {
+		var pbResp dto.PbJiraIntegrationsResponse
+		err := r.GetJson(reqUrl, &pbResp, "Error parsing Jira integration list")
+		if err != nil {
+			return nil, err
+		}
+		integrations = append(integrations, pbResp.Data...)
+		reqUrl = nextPageUrl(pbResp.Links)
+	}
+	return integrations, nil
+}
+
+func (r PbApiRepository) GetJiraIntegration(id string) (*dto.JiraIntegration, api_error.ApiErr) {
+	var pbResp dto.PbJiraIntegrationResponse
+	reqUrl := r.apiUrl(fmt.Sprintf("/jira-integrations/%v", id), nil)
+	err := r.GetJson(reqUrl, &pbResp, "Error parsing Jira integration")
+	if err != nil {
+		return nil, err
+	}
+	return &pbResp.Data, nil
+}
+
+func (r PbApiRepository) GetJiraIntegrationConnections(integrationId string, filter dto.JiraConnectionFilter) ([]dto.JiraConnectionData, api_error.ApiErr) {
+	connections := []dto.JiraConnectionData{}
+	reqUrl := r.apiUrl(fmt.Sprintf("/jira-integrations/%v/connections", integrationId), jiraConnectionFilterQuery(filter))
+	for reqUrl != "" {
+		var pbResp dto.PbJiraConnectionsResponse
+		err := r.GetJson(reqUrl, &pbResp, "Error parsing Jira integration connection list")
+		if err != nil {
+			return nil, err
+		}
+		connections = append(connections, pbResp.Data...)
+		reqUrl = nextPageUrl(pbResp.Links)
+	}
+	return connections, nil
+}
+
+// GetJiraIntegrationConnection returns the Jira issue a feature is linked to. Unlinked features result in a not found error.
+func (r PbApiRepository) GetJiraIntegrationConnection(integrationId string, featureId string) (*dto.JiraConnectionData, api_error.ApiErr) {
+	reqUrl := r.apiUrl(fmt.Sprintf("/jira-integrations/%v/connections/%v", integrationId, featureId), nil)
+	req, err := r.PrepareHttpRequest("GET", reqUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	statusCode, body, err := r.SendHttpRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case statusCode == http.StatusNotFound:
+		return nil, api_error.NewNotFoundError(fmt.Sprintf("Feature %v is not linked in Jira integration %v", featureId, integrationId))
+	case statusCode > 299:
+		msg := fmt.Sprintf("Error when sending request to Productboard API. Status code: %v. Message: %v", statusCode, string(*body))
+		logger.Error(msg, nil)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	var pbResp dto.PbJiraConnectionResponse
+	if jsonErr := json.Unmarshal(*body, &pbResp); jsonErr != nil {
+		msg := "Error parsing Jira integration connection"
+		logger.Error(msg, jsonErr)
+		return nil, api_error.NewInternalServerError(msg, jsonErr)
+	}
+	return &pbResp.Data, nil
+}
+
+func jiraConnectionFilterQuery(filter dto.JiraConnectionFilter) url.Values {
+	query := url.Values{}
+	if filter.IssueKey != "" {
+		query.Set("connection.issueKey", filter.IssueKey)
+	}
+	if filter.IssueId != "" {
+		query.Set("connection.issueId", filter.IssueId)
+	}
+	if len(query) == 0 {
+		return nil
+	}
+	return query
+}
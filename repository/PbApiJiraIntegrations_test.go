@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetJiraIntegrationConnection_NotLinked_Returns_NotFoundError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	conn, err := repo.GetJiraIntegrationConnection("j1", "f1")
+
+	assert.Nil(t, conn)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+}
+
+func Test_GetJiraIntegrationConnection_Returns_Connection(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	var path string
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			w.Write([]byte(`{"data": {"featureId": "f1", "connection": {"issueKey": "PROJ-123", "issueId": "10001"}, "links": {"self": "https://api.productboard.com/jira-integrations/j1/connections/f1"}}}`))
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	conn, err := repo.GetJiraIntegrationConnection("j1", "f1")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "/jira-integrations/j1/connections/f1", path)
+	assert.EqualValues(t, "PROJ-123", conn.Connection.IssueKey)
+}
+
+func Test_GetJiraIntegrationConnections_Sends_Filter(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	var issueKey string
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			issueKey = r.URL.Query().Get("connection.issueKey")
+			json.NewEncoder(w).Encode(dto.PbJiraConnectionsResponse{Data: []dto.JiraConnectionData{{FeatureId: "f1"}}})
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	conns, err := repo.GetJiraIntegrationConnections("j1", dto.JiraConnectionFilter{IssueKey: "PROJ-123"})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "PROJ-123", issueKey)
+	assert.EqualValues(t, 1, len(conns))
+}
+
+func Test_GetJiraIntegrations_Follows_NextLink(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	var srv *httptest.Server
+	srv = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("pageOffset") == "" {
+				json.NewEncoder(w).Encode(dto.PbJiraIntegrationsResponse{
+					Data:  []dto.JiraIntegration{{ID: "j1"}},
+					Links: dto.Links{Next: srv.URL + "/jira-integrations?pageOffset=100"},
+				})
+				return
+			}
+			json.NewEncoder(w).Encode(dto.PbJiraIntegrationsResponse{Data: []dto.JiraIntegration{{ID: "j2"}}})
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	integrations, err := repo.GetJiraIntegrations()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(integrations))
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+	"github.com/johannes-kuhfuss/services_utils/misc"
+)
+
+// CreateNote pushes a note into Productboard. A note whose source has been pushed before is treated as success.
+func (r PbApiRepository) CreateNote(note dto.Note) (*dto.NoteResult, api_error.ApiErr) {
+	noteJson, jsonErr := json.Marshal(note)
+	if jsonErr != nil {
+		msg := "Could not generate note request"
+		logger.Error(msg, jsonErr)
+		return nil, api_error.NewInternalServerError(msg, jsonErr)
+	}
+	req, err := r.PrepareHttpRequest("POST", r.apiUrl("/notes", nil), bytes.NewBuffer(noteJson))
+	if err != nil {
+		return nil, err
+	}
+	if r.cfg.PbApi.PartnerId != "" {
+		req.Header.Set("Productboard-Partner-Id", r.cfg.PbApi.PartnerId)
+	}
+	statusCode, body, err := r.SendHttpRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case statusCode == http.StatusUnprocessableEntity && sourceAlreadyExists(*body):
+		logger.Info(fmt.Sprintf("Note from source %v already exists", noteSourceId(note)))
+		return &dto.NoteResult{AlreadyExists: true}, nil
+	case statusCode == http.StatusUnprocessableEntity:
+		msg := fmt.Sprintf("Productboard rejected note. Message: %v", string(*body))
+		logger.Error(msg, nil)
+		return nil, api_error.NewBadRequestError(msg)
+	case statusCode > 299:
+		msg := fmt.Sprintf("Error when sending request to Productboard API. Status code: %v. Message: %v", statusCode, string(*body))
+		logger.Error(msg, nil)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	var pbResp dto.PbNoteResponse
+	jsonErr = json.Unmarshal(*body, &pbResp)
+	if jsonErr != nil {
+		msg := "Error parsing note response"
+		logger.Error(msg, jsonErr)
+		return nil, api_error.NewInternalServerError(msg, jsonErr)
+	}
+	logger.Info(fmt.Sprintf("Created note %v", pbResp.Data.ID))
+	return &dto.NoteResult{
+		ID:  pbResp.Data.ID,
+		Url: pbResp.Links.Html,
+	}, nil
+}
+
+func sourceAlreadyExists(body []byte) bool {
+	var errResp dto.PbNoteErrorResponse
+	if err := json.Unmarshal(body, &errResp); err != nil {
+		return false
+	}
+	return misc.SliceContainsString(errResp.Errors["source"], "already exists")
+}
+
+func noteSourceId(note dto.Note) string {
+	if note.Source == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v/%v", note.Source.Origin, note.Source.RecordId)
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func testNote() dto.Note {
+	return dto.Note{
+		Title:         "Note title",
+		Content:       "Here is some <b>exciting</b> content",
+		CustomerEmail: "customer@example.com",
+		Source: &dto.NoteSource{
+			Origin:   "deskdesk",
+			RecordId: "123",
+		},
+		Tags: []string{"important"},
+	}
+}
+
+func Test_CreateNote_ExecFails_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	cfg.PbApi.BaseUrl = ""
+
+	result, err := repo.CreateNote(testNote())
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Error when executing http request", err.Message())
+}
+
+func Test_CreateNote_Created_Returns_Url(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	var sent dto.Note
+	var partnerId string
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			partnerId = r.Header.Get("Productboard-Partner-Id")
+			json.NewDecoder(r.Body).Decode(&sent)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"links":{"html":"https://space.productboard.com/inbox/notes/123456"},"data":{"id":"n1"}}`))
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+	cfg.PbApi.PartnerId = "pbreact"
+
+	result, err := repo.CreateNote(testNote())
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "pbreact", partnerId)
+	assert.EqualValues(t, testNote(), sent)
+	assert.EqualValues(t, dto.NoteResult{ID: "n1", Url: "https://space.productboard.com/inbox/notes/123456"}, *result)
+}
+
+func Test_CreateNote_SourceExists_Returns_AlreadyExists(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"ok":false,"errors":{"source":["already exists"]}}`))
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	result, err := repo.CreateNote(testNote())
+
+	assert.Nil(t, err)
+	assert.True(t, result.AlreadyExists)
+}
+
+func Test_CreateNote_InvalidUrl_Returns_BadRequestError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"ok":false,"errors":{"display_url":["is invalid"]}}`))
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	result, err := repo.CreateNote(testNote())
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+}
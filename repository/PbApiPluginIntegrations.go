@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+func (r PbApiRepository) CreatePluginIntegration(data dto.PluginIntegrationData) (*dto.PluginIntegration, api_error.ApiErr) {
+	var pbResp dto.PbPluginIntegrationResponse
+	reqUrl := r.apiUrl("/plugin-integrations", nil)
+	err := r.SendJson("POST", reqUrl, dto.PbPluginIntegrationRequest{Data: data}, &pbResp, "Error parsing plugin integration")
+	if err != nil {
+		return nil, err
+	}
+	return &pbResp.Data, nil
+}
+
+func (r PbApiRepository) GetPluginIntegrations() ([]dto.PluginIntegration, api_error.ApiErr) {
+	integrations := []dto.PluginIntegration{}
+	reqUrl := r.apiUrl("/plugin-integrations", nil)
+	for reqUrl != "" {
+		var pbResp dto.PbPluginIntegrationsResponse
+		err := r.GetJson(reqUrl, &pbResp, "Error parsing plugin integration list")
+		if err != nil {
+			return nil, err
+		}
+		integrations = append(integrations, pbResp.Data...)
+		reqUrl = nextPageUrl(pbResp.Links)
+	}
+	return integrations, nil
+}
+
+func (r PbApiRepository) GetPluginIntegration(id string) (*dto.PluginIntegration, api_error.ApiErr) {
+	var pbResp dto.PbPluginIntegrationResponse
+	reqUrl := r.apiUrl(fmt.Sprintf("/plugin-integrations/%v", id), nil)
+	err := r.GetJson(reqUrl, &pbResp, "Error parsing plugin integration")
+	if err != nil {
+		return nil, err
+	}
+	return &pbResp.Data, nil
+}
+
+func (r PbApiRepository) UpdatePluginIntegration(id string, data dto.PluginIntegrationData) (*dto.PluginIntegration, api_error.ApiErr) {
+	var pbResp dto.PbPluginIntegrationResponse
+	reqUrl := r.apiUrl(fmt.Sprintf("/plugin-integrations/%v", id), nil)
+	err := r.SendJson("PUT", reqUrl, dto.PbPluginIntegrationRequest{Data: data}, &pbResp, "Error parsing plugin integration")
+	if err != nil {
+		return nil, err
+	}
+	return &pbResp.Data, nil
+}
+
+func (r PbApiRepository) DeletePluginIntegration(id string) api_error.ApiErr {
+	reqUrl := r.apiUrl(fmt.Sprintf("/plugin-integrations/%v", id), nil)
+	return r.SendJson("DELETE", reqUrl, nil, nil, "")
+}
+
+func (r PbApiRepository) GetPluginConnections(integrationId string) ([]dto.ConnectionData, api_error.ApiErr) {
+	connections := []dto.ConnectionData{}
+	reqUrl := r.apiUrl(fmt.Sprintf("/plugin-integrations/%v/connections", integrationId), nil)
+	for reqUrl != "" {
+		var pbResp dto.PbConnectionsResponse
+		err := r.GetJson(reqUrl, &pbResp, "Error parsing plugin integration connection list")
+		if err != nil {
+			return nil, err
+		}
+		connections = append(connections, pbResp.Data...)
+		reqUrl = nextPageUrl(pbResp.Links)
+	}
+	return connections, nil
+}
+
+func (r PbApiRepository) GetPluginConnection(integrationId string, featureId string) (*dto.ConnectionData, api_error.ApiErr) {
+	var pbResp dto.PbConnectionResponse
+	reqUrl := r.apiUrl(fmt.Sprintf("/plugin-integrations/%v/connections/%v", integrationId, featureId), nil)
+	err := r.GetJson(reqUrl, &pbResp, "Error parsing plugin integration connection")
+	if err != nil {
+		return nil, err
+	}
+	return &pbResp.Data, nil
+}
+
+func (r PbApiRepository) UpdatePluginConnection(integrationId string, featureId string, conn dto.Connection) (*dto.ConnectionData, api_error.ApiErr) {
+	var pbResp dto.PbConnectionResponse
+	reqUrl := r.apiUrl(fmt.Sprintf("/plugin-integrations/%v/connections/%v", integrationId, featureId), nil)
+	connReq := dto.PbConnectionRequest{
+		Data: dto.ConnectionData{
+			Connection: conn,
+		},
+	}
+	err := r.SendJson("PUT", reqUrl, connReq, &pbResp, "Error parsing plugin integration connection")
+	if err != nil {
+		return nil, err
+	}
+	return &pbResp.Data, nil
+}
+
+// DeletePluginConnection resets the connection of a feature to its initial state
+func (r PbApiRepository) DeletePluginConnection(integrationId string, featureId string) api_error.ApiErr {
+	reqUrl := r.apiUrl(fmt.Sprintf("/plugin-integrations/%v/connections/%v", integrationId, featureId), nil)
+	return r.SendJson("DELETE", reqUrl, nil, nil, "")
+}
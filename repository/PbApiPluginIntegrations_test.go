@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPluginIntegrationData() dto.PluginIntegrationData {
+	return dto.PluginIntegrationData{
+		IntegrationStatus: dto.IntegrationEnabled,
+		Type:              "com.example.pbreact",
+		Name:              "pbreact",
+		InitialState:      &dto.InitialState{Label: "Push"},
+		Action:            &dto.PluginAction{URL: "https://example.com/pbplugin", Version: 1},
+	}
+}
+
+func Test_CreatePluginIntegration_ExecFails_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	cfg.PbApi.BaseUrl = ""
+
+	integration, err := repo.CreatePluginIntegration(testPluginIntegrationData())
+
+	assert.Nil(t, integration)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Error when executing http request", err.Message())
+}
+
+func Test_CreatePluginIntegration_Returns_Integration(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	var sent dto.PbPluginIntegrationRequest
+	var method string
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			json.NewDecoder(r.Body).Decode(&sent)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(dto.PbPluginIntegrationResponse{Data: dto.PluginIntegration{ID: "i1", Type: sent.Data.Type}})
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	integration, err := repo.CreatePluginIntegration(testPluginIntegrationData())
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "POST", method)
+	assert.EqualValues(t, testPluginIntegrationData(), sent.Data)
+	assert.EqualValues(t, "i1", integration.ID)
+}
+
+func Test_GetPluginIntegrations_Returns_Integrations(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			json.NewEncoder(w).Encode(dto.PbPluginIntegrationsResponse{Data: []dto.PluginIntegration{{ID: "i1"}, {ID: "i2"}}})
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	integrations, err := repo.GetPluginIntegrations()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(integrations))
+}
+
+func Test_UpdatePluginIntegration_Sends_Put(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	var method, path string
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method, path = r.Method, r.URL.Path
+			json.NewEncoder(w).Encode(dto.PbPluginIntegrationResponse{Data: dto.PluginIntegration{ID: "i1"}})
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	integration, err := repo.UpdatePluginIntegration("i1", dto.PluginIntegrationData{Name: "renamed"})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "PUT", method)
+	assert.EqualValues(t, "/plugin-integrations/i1", path)
+	assert.EqualValues(t, "i1", integration.ID)
+}
+
+func Test_DeletePluginIntegration_Returns_NoError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	var method string
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	err := repo.DeletePluginIntegration("i1")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "DELETE", method)
+}
+
+func Test_GetPluginConnections_Follows_NextLink(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	var srv *httptest.Server
+	srv = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("pageCursor") == "" {
+				json.NewEncoder(w).Encode(dto.PbConnectionsResponse{
+					Data:  []dto.ConnectionData{{FeatureId: "f1"}},
+					Links: dto.Links{Next: srv.URL + "/plugin-integrations/i1/connections?pageCursor=2"},
+				})
+				return
+			}
+			json.NewEncoder(w).Encode(dto.PbConnectionsResponse{Data: []dto.ConnectionData{{FeatureId: "f2"}}})
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+
+	connections, err := repo.GetPluginConnections("i1")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(connections))
+	assert.EqualValues(t, "f2", connections[1].FeatureId)
+}
+
+func Test_UpdatePluginConnection_Sends_Connection(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	var sent dto.PbConnectionRequest
+	var method, path string
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method, path = r.Method, r.URL.Path
+			json.NewDecoder(r.Body).Decode(&sent)
+			json.NewEncoder(w).Encode(dto.PbConnectionResponse{Data: sent.Data})
+		}),
+	)
+	defer srv.Close()
+	cfg.PbApi.BaseUrl = srv.URL
+	conn := dto.Connection{State: dto.ConnectionConnected, Label: "PB-1", TargetUrl: "https://tracker/PB-1"}
+
+	result, err := repo.UpdatePluginConnection("i1", "f1", conn)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "PUT", method)
+	assert.EqualValues(t, "/plugin-integrations/i1/connections/f1", path)
+	assert.EqualValues(t, conn, sent.Data.Connection)
+	assert.EqualValues(t, conn, result.Connection)
+}
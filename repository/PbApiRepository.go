@@ -30,7 +30,7 @@ func (r PbApiRepository) RegisterForNotifications() api_error.ApiErr {
 		return err
 	}
 	reqUrl, _ := url.Parse(r.cfg.PbApi.BaseUrl)
-	reqUrl.Path = "/webhook"
+	reqUrl.Path = "/webhooks"
 	req, err := r.PrepareHttpRequest("POST", reqUrl.String(), bytes.NewBuffer(*subReq))
 	if err != nil {
 		return err
@@ -46,7 +46,7 @@ func (r PbApiRepository) GetNotifications() (*dto.PbSubscriptionResponse, api_er
 	var pbResp dto.PbSubscriptionResponse
 
 	reqUrl, _ := url.Parse(r.cfg.PbApi.BaseUrl)
-	reqUrl.Path = "/webhook"
+	reqUrl.Path = "/webhooks"
 	req, err := r.PrepareHttpRequest("GET", reqUrl.String(), nil)
 	if err != nil {
 		return nil, err
@@ -72,7 +72,7 @@ func (r PbApiRepository) GetNotifications() (*dto.PbSubscriptionResponse, api_er
 func (r PbApiRepository) UnregisterForNotifications(notifs dto.PbSubscriptionResponse) api_error.ApiErr {
 	for _, val := range notifs.Data {
 		reqUrl, _ := url.Parse(r.cfg.PbApi.BaseUrl)
-		reqUrl.Path = fmt.Sprintf("/webhook/%v", val.ID)
+		reqUrl.Path = fmt.Sprintf("/webhooks/%v", val.ID)
 		req, err := r.PrepareHttpRequest("DELETE", reqUrl.String(), nil)
 		if err != nil {
 			return err
@@ -85,10 +85,6 @@ func (r PbApiRepository) UnregisterForNotifications(notifs dto.PbSubscriptionRes
 	return nil
 }
 
-func (r PbApiRepository) GetFeatureData() api_error.ApiErr {
-	return nil
-}
-
 func (r PbApiRepository) PrepareHttpRequest(reqType string, url string, body io.Reader) (*http.Request, api_error.ApiErr) {
 	if reqType == "" {
 		msg := "Request type cannot be empty"
@@ -111,23 +107,84 @@ func (r PbApiRepository) PrepareHttpRequest(reqType string, url string, body io.
 }
 
 func (r PbApiRepository) ExecHttpRequest(req *http.Request) (*[]byte, api_error.ApiErr) {
+	statusCode, body, err := r.SendHttpRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode > 299 {
+		msg := fmt.Sprintf("Error when sending request to Productboard API. Status code: %v. Message: %v", statusCode, string(*body))
+		logger.Error(msg, nil)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	} else {
+		logger.Info(fmt.Sprintf("Successfully sent request to Productboard API. Status code: %v", statusCode))
+		return body, nil
+	}
+}
+
+// SendHttpRequest executes the request and hands back status code and body without interpreting the status
+func (r PbApiRepository) SendHttpRequest(req *http.Request) (int, *[]byte, api_error.ApiErr) {
 	client := http.Client{}
 	resp, resErr := client.Do(req)
 	if resErr != nil {
 		msg := "Error when executing http request"
 		logger.Error(msg, resErr)
-		return nil, api_error.NewInternalServerError(msg, resErr)
+		return 0, nil, api_error.NewInternalServerError(msg, resErr)
 	}
 	defer resp.Body.Close()
 	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode > 299 {
-		msg := fmt.Sprintf("Error when sending request to Productboard API. Status code: %v. Message: %v", resp.StatusCode, string(body))
-		logger.Error(msg, nil)
-		return nil, api_error.NewInternalServerError(msg, nil)
-	} else {
-		logger.Info(fmt.Sprintf("Successfully sent request to Productboard API. Status code: %v", resp.StatusCode))
-		return &body, nil
+	return resp.StatusCode, &body, nil
+}
+
+func (r PbApiRepository) GetJson(reqUrl string, target interface{}, parseErrMsg string) api_error.ApiErr {
+	return r.SendJson("GET", reqUrl, nil, target, parseErrMsg)
+}
+
+// SendJson sends payload (if any) as JSON and parses the response into target (if any)
+func (r PbApiRepository) SendJson(reqType string, reqUrl string, payload interface{}, target interface{}, parseErrMsg string) api_error.ApiErr {
+	var reqBody io.Reader
+	if payload != nil {
+		payloadJson, jsonErr := json.Marshal(payload)
+		if jsonErr != nil {
+			msg := "Could not generate request body"
+			logger.Error(msg, jsonErr)
+			return api_error.NewInternalServerError(msg, jsonErr)
+		}
+		reqBody = bytes.NewBuffer(payloadJson)
+	}
+	req, err := r.PrepareHttpRequest(reqType, reqUrl, reqBody)
+	if err != nil {
+		return err
+	}
+	body, err := r.ExecHttpRequest(req)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return nil
+	}
+	jsonErr := json.Unmarshal(*body, target)
+	if jsonErr != nil {
+		logger.Error(parseErrMsg, jsonErr)
+		return api_error.NewInternalServerError(parseErrMsg, jsonErr)
+	}
+	return nil
+}
+
+func (r PbApiRepository) apiUrl(path string, query url.Values) string {
+	reqUrl, _ := url.Parse(r.cfg.PbApi.BaseUrl)
+	reqUrl.Path = path
+	if query != nil {
+		reqUrl.RawQuery = query.Encode()
+	}
+	return reqUrl.String()
+}
+
+func nextPageUrl(links dto.Links) string {
+	next, ok := links.Next.(string)
+	if !ok {
+		return ""
 	}
+	return next
 }
 
 func (r PbApiRepository) CreateSubscriptionRequest() (*[]byte, api_error.ApiErr) {
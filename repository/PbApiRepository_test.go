@@ -11,6 +11,7 @@ import (
 
 	"github.com/johannes-kuhfuss/pbreact/config"
 	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/emulator"
 	"github.com/johannes-kuhfuss/services_utils/api_error"
 	"github.com/stretchr/testify/assert"
 )
@@ -18,14 +19,35 @@ import (
 var (
 	cfg  config.AppConfig
 	repo PbApiRepository
+	emu  *emulator.Emulator
 )
 
 func setupTest(t *testing.T) func() {
+	cfg = config.AppConfig{}
 	repo = NewPbApiRepository(&cfg)
 	return func() {
 	}
 }
 
+// setupEmulatorTest points the repository at an emulator with the demo workspace and a webhook receiver that answers
+// the emulator's probe like pbreact does
+func setupEmulatorTest(t *testing.T) func() {
+	teardown := setupTest(t)
+	emu = emulator.New(emulator.Config{})
+	emu.Seed(emulator.DemoSeed())
+	cfg.PbApi.BaseUrl = emu.Start()
+	cfg.PbApi.ApiToken = "test-token"
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Query().Get("validationToken")))
+	}))
+	cfg.PbApi.WebHookUrl = receiver.URL + "/pbwebhook"
+	return func() {
+		emu.Close()
+		receiver.Close()
+		teardown()
+	}
+}
+
 func Test_CreateSubscriptionRequest_Returns_Request(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
@@ -101,43 +123,28 @@ func Test_ExecHttpRequest_ExecError_Returns_InternalServerError(t *testing.T) {
 }
 
 func Test_ExecHttpRequest_ErrorStatus_Returns_InternalServerError(t *testing.T) {
-	teardown := setupTest(t)
+	teardown := setupEmulatorTest(t)
 	defer teardown()
-	apiErr := api_error.NewInternalServerError("Error when sending request to Productboard API. Status code: 500. Message: Something bad happened", nil)
-	srv := httptest.NewServer(
-		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Something bad happened"))
-		}),
-	)
-	defer srv.Close()
-	req, _ := repo.PrepareHttpRequest("GET", srv.URL, nil)
+	req, _ := repo.PrepareHttpRequest("GET", cfg.PbApi.BaseUrl+"features/unknown", nil)
 
 	resp, respErr := repo.ExecHttpRequest(req)
 
 	assert.Nil(t, resp)
 	assert.NotNil(t, respErr)
-	assert.EqualValues(t, apiErr.StatusCode(), respErr.StatusCode())
-	assert.EqualValues(t, apiErr.Message(), respErr.Message())
+	assert.EqualValues(t, http.StatusInternalServerError, respErr.StatusCode())
+	assert.Contains(t, respErr.Message(), "Error when sending request to Productboard API. Status code: 404. Message: ")
 }
 
 func Test_ExecHttpRequest_Success_Returns_Body(t *testing.T) {
-	teardown := setupTest(t)
+	teardown := setupEmulatorTest(t)
 	defer teardown()
-	srv := httptest.NewServer(
-		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("Success"))
-		}),
-	)
-	defer srv.Close()
-	req, _ := repo.PrepareHttpRequest("GET", srv.URL, nil)
+	req, _ := repo.PrepareHttpRequest("GET", cfg.PbApi.BaseUrl+"features/f1", nil)
 
 	resp, respErr := repo.ExecHttpRequest(req)
 
 	assert.NotNil(t, resp)
 	assert.Nil(t, respErr)
-	assert.EqualValues(t, "Success", string(*resp))
+	assert.Contains(t, string(*resp), "Dark mode")
 }
 
 func Test_RegisterForNotifications_ExecFails_Returns_InternalServerError(t *testing.T) {
@@ -153,20 +160,27 @@ func Test_RegisterForNotifications_ExecFails_Returns_InternalServerError(t *test
 }
 
 func Test_RegisterForNotifications_ReturnsNoError(t *testing.T) {
-	teardown := setupTest(t)
+	teardown := setupEmulatorTest(t)
 	defer teardown()
-	srv := httptest.NewServer(
-		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("Success"))
-		}),
-	)
-	defer srv.Close()
-	cfg.PbApi.BaseUrl = srv.URL
 
 	err := repo.RegisterForNotifications()
+	notifs, getErr := repo.GetNotifications()
 
 	assert.Nil(t, err)
+	assert.Nil(t, getErr)
+	assert.EqualValues(t, 1, len(notifs.Data))
+}
+
+func Test_RegisterForNotifications_ProbeFails_Returns_InternalServerError(t *testing.T) {
+	teardown := setupEmulatorTest(t)
+	defer teardown()
+	cfg.PbApi.WebHookUrl = "http://127.0.0.1:1/pbwebhook"
+
+	err := repo.RegisterForNotifications()
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.Contains(t, err.Message(), "Status code: 400")
 }
 
 func Test_GetNotifications_ExecFails_Returns_InternalServerErr(t *testing.T) {
@@ -182,6 +196,7 @@ func Test_GetNotifications_ExecFails_Returns_InternalServerErr(t *testing.T) {
 	assert.EqualValues(t, "Error when executing http request", err.Message())
 }
 
+// The emulator only answers with valid json, so the broken body comes from a plain test server
 func Test_GetNotifications_BodyParsingFails_Returns_InternalServerErr(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
@@ -203,21 +218,8 @@ func Test_GetNotifications_BodyParsingFails_Returns_InternalServerErr(t *testing
 }
 
 func Test_GetNotifications_NoSubs_Returns_NotFoundError(t *testing.T) {
-	teardown := setupTest(t)
+	teardown := setupEmulatorTest(t)
 	defer teardown()
-	subs := dto.PbSubscriptionResponse{
-		Data:  []dto.SubRespData{},
-		Links: dto.Links{},
-	}
-	srv := httptest.NewServer(
-		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(subs)
-		}),
-	)
-	defer srv.Close()
-	cfg.PbApi.BaseUrl = srv.URL
 
 	notifs, err := repo.GetNotifications()
 
@@ -228,33 +230,21 @@ func Test_GetNotifications_NoSubs_Returns_NotFoundError(t *testing.T) {
 }
 
 func Test_GetNotifications_Returns_NoError(t *testing.T) {
-	teardown := setupTest(t)
+	teardown := setupEmulatorTest(t)
 	defer teardown()
-	now := time.Now().UTC()
-	subs := dto.PbSubscriptionResponse{
-		Data: []dto.SubRespData{{
-			ID:        "abc",
-			CreatedAt: now,
-			Name:      "my sub",
-			Events:    []dto.Events{},
-		}},
-		Links: dto.Links{},
-	}
-	srv := httptest.NewServer(
-		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(subs)
-		}),
-	)
-	defer srv.Close()
-	cfg.PbApi.BaseUrl = srv.URL
+	repo.RegisterForNotifications()
 
 	notifs, err := repo.GetNotifications()
 
 	assert.NotNil(t, notifs)
 	assert.Nil(t, err)
-	assert.EqualValues(t, subs, *notifs)
+	assert.EqualValues(t, 1, len(notifs.Data))
+	assert.EqualValues(t, "Feature Webhooks", notifs.Data[0].Name)
+	assert.EqualValues(t, []dto.Events{
+		{EventType: dto.PbEventTypes["featureCreate"]},
+		{EventType: dto.PbEventTypes["featureUpdate"]},
+		{EventType: dto.PbEventTypes["featureDelete"]},
+	}, notifs.Data[0].Events)
 }
 
 func Test_UnregisterForNotifications_ExecFails_Returns_InternalServerError(t *testing.T) {
@@ -279,28 +269,45 @@ func Test_UnregisterForNotifications_ExecFails_Returns_InternalServerError(t *te
 	assert.EqualValues(t, "Error when executing http request", err.Message())
 }
 
-func Test_UnregisterForNotifications_Returns_NoError(t *testing.T) {
-	teardown := setupTest(t)
+func Test_UnregisterForNotifications_UnknownSub_Returns_InternalServerError(t *testing.T) {
+	teardown := setupEmulatorTest(t)
 	defer teardown()
-	srv := httptest.NewServer(
-		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("Success"))
-		}),
-	)
-	cfg.PbApi.BaseUrl = srv.URL
-	now := time.Now().UTC()
 	subs := dto.PbSubscriptionResponse{
-		Data: []dto.SubRespData{{
-			ID:        "abc",
-			CreatedAt: now,
-			Name:      "my sub",
-			Events:    []dto.Events{},
-		}},
-		Links: dto.Links{},
+		Data: []dto.SubRespData{{ID: "abc"}},
 	}
 
 	err := repo.UnregisterForNotifications(subs)
 
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.Contains(t, err.Message(), "Status code: 404")
+}
+
+func Test_UnregisterForNotifications_Returns_NoError(t *testing.T) {
+	teardown := setupEmulatorTest(t)
+	defer teardown()
+	repo.RegisterForNotifications()
+	subs, _ := repo.GetNotifications()
+
+	err := repo.UnregisterForNotifications(*subs)
+	_, getErr := repo.GetNotifications()
+
 	assert.Nil(t, err)
+	assert.NotNil(t, getErr)
+	assert.EqualValues(t, http.StatusNotFound, getErr.StatusCode())
+}
+
+// The emulator serves subscriptions only below /webhooks, so a full round trip shows the path is right
+func Test_Notifications_Use_WebhooksPath(t *testing.T) {
+	teardown := setupEmulatorTest(t)
+	defer teardown()
+
+	regErr := repo.RegisterForNotifications()
+	subs, getErr := repo.GetNotifications()
+	unregErr := repo.UnregisterForNotifications(*subs)
+
+	assert.Nil(t, regErr)
+	assert.Nil(t, getErr)
+	assert.Nil(t, unregErr)
+	assert.EqualValues(t, 1, len(subs.Data))
 }
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+// RestTrackerRepository talks to any REST issue tracker. Urls and request bodies are configured as templates
+// that get the issue fields and, once known, the issue key.
+type RestTrackerRepository struct {
+	cfg       *config.AppConfig
+	templates map[string]*template.Template
+}
+
+type trackerTemplateData struct {
+	dto.Issue
+	Key string
+}
+
+var (
+	trackerTemplateFuncs = template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			out, err := json.Marshal(v)
+			return string(out), err
+		},
+	}
+)
+
+func NewRestTrackerRepository(c *config.AppConfig) (RestTrackerRepository, api_error.ApiErr) {
+	r := RestTrackerRepository{
+		cfg:       c,
+		templates: make(map[string]*template.Template),
+	}
+	sources := map[string]string{
+		"createUrl":  c.Tracker.CreateUrl,
+		"issueUrl":   c.Tracker.IssueUrl,
+		"browseUrl":  c.Tracker.BrowseUrl,
+		"createBody": c.Tracker.CreateBody,
+		"updateBody": c.Tracker.UpdateBody,
+		"closeBody":  c.Tracker.CloseBody,
+	}
+	for name, src := range sources {
+		tmpl, err := template.New(name).Funcs(trackerTemplateFuncs).Parse(src)
+		if err != nil {
+			msg := fmt.Sprintf("Could not parse tracker template %v", name)
+			logger.Error(msg, err)
+			return r, api_error.NewInternalServerError(msg, err)
+		}
+		r.templates[name] = tmpl
+	}
+	if c.Tracker.CreateUrl == "" || c.Tracker.IssueUrl == "" {
+		msg := "Tracker create and issue urls must be configured"
+		logger.Error(msg, nil)
+		return r, api_error.NewInternalServerError(msg, nil)
+	}
+	return r, nil
+}
+
+func (r RestTrackerRepository) CreateIssue(issue dto.Issue) (*dto.IssueRef, api_error.ApiErr) {
+	data := trackerTemplateData{Issue: issue}
+	body, err := r.send("POST", "createUrl", "createBody", data)
+	if err != nil {
+		return nil, err
+	}
+	var resp map[string]interface{}
+	if jsonErr := json.Unmarshal(*body, &resp); jsonErr != nil {
+		msg := "Error parsing tracker response"
+		logger.Error(msg, jsonErr)
+		return nil, api_error.NewInternalServerError(msg, jsonErr)
+	}
+	key := lookupField(resp, r.cfg.Tracker.KeyField)
+	if key == "" {
+		msg := fmt.Sprintf("Tracker response has no %v field", r.cfg.Tracker.KeyField)
+		logger.Error(msg, nil)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	logger.Info(fmt.Sprintf("Created tracker issue %v for feature %v", key, issue.FeatureId))
+	return &dto.IssueRef{
+		Key: key,
+		Url: r.GetIssueUrl(key),
+	}, nil
+}
+
+func (r RestTrackerRepository) UpdateIssue(key string, issue dto.Issue) api_error.ApiErr {
+	_, err := r.send(r.cfg.Tracker.UpdateMethod, "issueUrl", "updateBody", trackerTemplateData{Issue: issue, Key: key})
+	return err
+}
+
+func (r RestTrackerRepository) CloseIssue(key string) api_error.ApiErr {
+	_, err := r.send(r.cfg.Tracker.UpdateMethod, "issueUrl", "closeBody", trackerTemplateData{Key: key})
+	return err
+}
+
+// GetIssueUrl returns the browsable url of an issue, falling back to its api url
+func (r RestTrackerRepository) GetIssueUrl(key string) string {
+	name := "browseUrl"
+	if r.cfg.Tracker.BrowseUrl == "" {
+		name = "issueUrl"
+	}
+	issueUrl, err := r.render(name, trackerTemplateData{Key: key})
+	if err != nil {
+		return ""
+	}
+	return issueUrl
+}
+
+func (r RestTrackerRepository) send(method string, urlTemplate string, bodyTemplate string, data trackerTemplateData) (*[]byte, api_error.ApiErr) {
+	reqUrl, err := r.render(urlTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+	reqBody, err := r.render(bodyTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+	req, reqErr := http.NewRequest(method, reqUrl, strings.NewReader(reqBody))
+	if reqErr != nil {
+		msg := "Could not create http request"
+		logger.Error(msg, reqErr)
+		return nil, api_error.NewInternalServerError(msg, reqErr)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.cfg.Tracker.AuthHeader != "" {
+		req.Header.Set("Authorization", r.cfg.Tracker.AuthHeader)
+	}
+	client := http.Client{}
+	resp, resErr := client.Do(req)
+	if resErr != nil {
+		msg := "Error when executing tracker request"
+		logger.Error(msg, resErr)
+		return nil, api_error.NewInternalServerError(msg, resErr)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode > 299 {
+		msg := fmt.Sprintf("Error when sending request to issue tracker. Status code: %v. Message: %v", resp.StatusCode, string(body))
+		logger.Error(msg, nil)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &body, nil
+}
+
+func (r RestTrackerRepository) render(name string, data trackerTemplateData) (string, api_error.ApiErr) {
+	var out bytes.Buffer
+	if err := r.templates[name].Execute(&out, data); err != nil {
+		msg := fmt.Sprintf("Could not render tracker template %v", name)
+		logger.Error(msg, err)
+		return "", api_error.NewInternalServerError(msg, err)
+	}
+	return out.String(), nil
+}
+
+// lookupField resolves a dotted path like "data.key" in a decoded json object
+func lookupField(obj map[string]interface{}, path string) string {
+	var current interface{} = obj
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		current = m[part]
+	}
+	switch val := current.(type) {
+	case string:
+		return val
+	case float64:
+		return fmt.Sprintf("%.0f", val)
+	default:
+		return ""
+	}
+}
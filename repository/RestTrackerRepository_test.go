@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTracker(t *testing.T, srvUrl string) RestTrackerRepository {
+	trackerCfg := config.AppConfig{}
+	trackerCfg.Tracker.AuthHeader = "Token secret"
+	trackerCfg.Tracker.CreateUrl = srvUrl + "/issues"
+	trackerCfg.Tracker.IssueUrl = srvUrl + "/issues/{{.Key}}"
+	trackerCfg.Tracker.BrowseUrl = "https://tracker.example.com/browse/{{.Key}}"
+	trackerCfg.Tracker.UpdateMethod = "PATCH"
+	trackerCfg.Tracker.CreateBody = `{"title": {{json .Title}}, "description": {{json .Description}}}`
+	trackerCfg.Tracker.UpdateBody = `{"title": {{json .Title}}}`
+	trackerCfg.Tracker.CloseBody = `{"state": "closed"}`
+	trackerCfg.Tracker.KeyField = "data.key"
+	tracker, err := NewRestTrackerRepository(&trackerCfg)
+	assert.Nil(t, err)
+	return tracker
+}
+
+func Test_NewRestTrackerRepository_InvalidTemplate_Returns_Error(t *testing.T) {
+	trackerCfg := config.AppConfig{}
+	trackerCfg.Tracker.CreateUrl = "{{.Key"
+
+	_, err := NewRestTrackerRepository(&trackerCfg)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Could not parse tracker template createUrl", err.Message())
+}
+
+func Test_CreateIssue_Renders_Body_And_Returns_IssueRef(t *testing.T) {
+	var sent map[string]string
+	var auth string
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth = r.Header.Get("Authorization")
+			json.NewDecoder(r.Body).Decode(&sent)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"data": {"key": "ENG-7"}}`))
+		}),
+	)
+	defer srv.Close()
+	tracker := setupTracker(t, srv.URL)
+
+	ref, err := tracker.CreateIssue(dto.Issue{Title: "Say \"hi\"", Description: "<p>desc</p>"})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Token secret", auth)
+	assert.EqualValues(t, "Say \"hi\"", sent["title"])
+	assert.EqualValues(t, "<p>desc</p>", sent["description"])
+	assert.EqualValues(t, "ENG-7", ref.Key)
+	assert.EqualValues(t, "https://tracker.example.com/browse/ENG-7", ref.Url)
+}
+
+func Test_CreateIssue_NoKey_Returns_Error(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Write([]byte(`{"data": {}}`))
+		}),
+	)
+	defer srv.Close()
+	tracker := setupTracker(t, srv.URL)
+
+	ref, err := tracker.CreateIssue(dto.Issue{Title: "t"})
+
+	assert.Nil(t, ref)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Tracker response has no data.key field", err.Message())
+}
+
+func Test_CloseIssue_Sends_CloseBody_To_IssueUrl(t *testing.T) {
+	var method, path, body string
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, _ := io.ReadAll(r.Body)
+			method, path, body = r.Method, r.URL.Path, string(raw)
+		}),
+	)
+	defer srv.Close()
+	tracker := setupTracker(t, srv.URL)
+
+	err := tracker.CloseIssue("ENG-7")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "PATCH", method)
+	assert.EqualValues(t, "/issues/ENG-7", path)
+	assert.EqualValues(t, `{"state": "closed"}`, body)
+}
+
+func Test_UpdateIssue_TrackerError_Returns_Error(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer srv.Close()
+	tracker := setupTracker(t, srv.URL)
+
+	err := tracker.UpdateIssue("ENG-7", dto.Issue{Title: "t"})
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+// SmtpMailRepository sends mails through the configured SMTP server. Security "auto" uses STARTTLS if the server offers it,
+// "starttls" requires it, "tls" connects with implicit TLS and "none" never encrypts.
+type SmtpMailRepository struct {
+	cfg     *config.AppConfig
+	rootCAs *x509.CertPool
+}
+
+func NewSmtpMailRepository(c *config.AppConfig) SmtpMailRepository {
+	return SmtpMailRepository{
+		cfg: c,
+	}
+}
+
+func (r SmtpMailRepository) Send(message dto.MailMessage) api_error.ApiErr {
+	if message.From == "" {
+		message.From = r.cfg.Smtp.From
+	}
+	body, err := buildMail(message)
+	if err != nil {
+		msg := fmt.Sprintf("Could not build mail \"%v\"", message.Subject)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	addr := net.JoinHostPort(r.cfg.Smtp.Host, strconv.Itoa(r.cfg.Smtp.Port))
+	if err := r.send(addr, message.From, message.To, body); err != nil {
+		msg := fmt.Sprintf("Could not send mail \"%v\" via %v", message.Subject, addr)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	return nil
+}
+
+func (r SmtpMailRepository) send(addr string, from string, to []string, body []byte) error {
+	timeout := time.Duration(r.cfg.Smtp.Timeout) * time.Second
+	tlsConfig := &tls.Config{ServerName: r.cfg.Smtp.Host, RootCAs: r.rootCAs}
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	switch r.cfg.Smtp.Security {
+	case dto.SmtpTls:
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	case dto.SmtpAuto, dto.SmtpStartTls, dto.SmtpNone:
+		conn, err = dialer.Dial("tcp", addr)
+	default:
+		return fmt.Errorf("unknown SMTP security %v", r.cfg.Smtp.Security)
+	}
+	if err != nil {
+		return err
+	}
+	if timeout > 0 {
+		conn.SetDeadline(time.Now().Add(timeout))
+	}
+	client, err := smtp.NewClient(conn, r.cfg.Smtp.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	offered, _ := client.Extension("STARTTLS")
+	if r.cfg.Smtp.Security == dto.SmtpStartTls && !offered {
+		return fmt.Errorf("server does not offer STARTTLS")
+	}
+	if offered && (r.cfg.Smtp.Security == dto.SmtpStartTls || r.cfg.Smtp.Security == dto.SmtpAuto) {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if r.cfg.Smtp.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", r.cfg.Smtp.Username, r.cfg.Smtp.Password, r.cfg.Smtp.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMail renders the message as MIME mail with CRLF line endings and quoted-printable bodies
+func buildMail(message dto.MailMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %v\r\n", message.From)
+	fmt.Fprintf(&buf, "To: %v\r\n", strings.Join(message.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %v\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %v\r\n", date.GetNowUtc().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	if message.Text == "" || message.Html == "" {
+		contentType, content := "text/plain; charset=utf-8", message.Text
+		if message.Html != "" {
+			contentType, content = "text/html; charset=utf-8", message.Html
+		}
+		fmt.Fprintf(&buf, "Content-Type: %v\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n", contentType)
+		if err := writeQuotedPrintable(&buf, content); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	parts := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%v\r\n\r\n", parts.Boundary())
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.Html},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\n", "\r\n"))); err != nil {
+		return err
+	}
+	return qp.Close()
+}
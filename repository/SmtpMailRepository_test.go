@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSmtpServer accepts a single connection and records the dialog. With startTls set it offers STARTTLS,
+// with implicitTls set it only accepts TLS connections.
+type fakeSmtpServer struct {
+	listener   net.Listener
+	rejectRcpt bool
+	startTls   *tls.Config
+	upgraded   bool
+	auth       string
+	from       string
+	to         []string
+	data       string
+	done       chan bool
+}
+
+func newFakeSmtpServer(t *testing.T, srv *fakeSmtpServer, implicitTls *tls.Config) *fakeSmtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if implicitTls != nil {
+		listener = tls.NewListener(listener, implicitTls)
+	}
+	srv.listener = listener
+	srv.done = make(chan bool)
+	go srv.serve()
+	return srv
+}
+
+// fakeSmtpCertificate returns a server TLS config for 127.0.0.1 and a pool that trusts it
+func fakeSmtpCertificate(t *testing.T) (*tls.Config, *x509.CertPool) {
+	httpsSrv := httptest.NewTLSServer(nil)
+	defer httpsSrv.Close()
+	pool := x509.NewCertPool()
+	pool.AddCert(httpsSrv.Certificate())
+	return &tls.Config{Certificates: httpsSrv.TLS.Certificates}, pool
+}
+
+func (s *fakeSmtpServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSmtpServer) close() {
+	s.listener.Close()
+}
+
+func (s *fakeSmtpServer) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer func() {
+		conn.Close()
+	}()
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		fmt.Fprintf(conn, "%v\r\n", line)
+	}
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			reply("250-fake")
+			if s.startTls != nil && !s.upgraded {
+				reply("250-STARTTLS")
+			}
+			reply("250 AUTH PLAIN")
+		case "STARTTLS":
+			reply("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.startTls)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, r, s.upgraded = tlsConn, bufio.NewReader(tlsConn), true
+		case "AUTH":
+			s.auth = line
+			reply("235 Authenticated")
+		case "MAIL":
+			s.from = line
+			reply("250 OK")
+		case "RCPT":
+			if s.rejectRcpt {
+				reply("550 No such user")
+				continue
+			}
+			s.to = append(s.to, line)
+			reply("250 OK")
+		case "DATA":
+			reply("354 Go ahead")
+			var data strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil || dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.data = data.String()
+			reply("250 Queued")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func smtpConfig(port int) *config.AppConfig {
+	c := config.AppConfig{}
+	c.Smtp.Host = "127.0.0.1"
+	c.Smtp.Port = port
+	c.Smtp.From = "pbreact@example.com"
+	c.Smtp.Security = dto.SmtpAuto
+	c.Smtp.Timeout = 5
+	return &c
+}
+
+func Test_Send_Delivers_MultipartMail(t *testing.T) {
+	srv := newFakeSmtpServer(t, &fakeSmtpServer{}, nil)
+	defer srv.close()
+	c := smtpConfig(srv.port())
+	c.Smtp.Username = "user"
+	c.Smtp.Password = "secret"
+
+	err := NewSmtpMailRepository(c).Send(dto.MailMessage{
+		To:      []string{"a@example.com", "b@example.com"},
+		Subject: "Feature digest für heute",
+		Text:    "Created (1)\n- Dark mode",
+		Html:    "<p>Dark mode</p>",
+	})
+	<-srv.done
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "AUTH PLAIN AHVzZXIAc2VjcmV0", srv.auth)
+	assert.EqualValues(t, "MAIL FROM:<pbreact@example.com>", srv.from)
+	assert.EqualValues(t, []string{"RCPT TO:<a@example.com>", "RCPT TO:<b@example.com>"}, srv.to)
+	assert.Contains(t, srv.data, "To: a@example.com, b@example.com\r\n")
+	assert.Contains(t, srv.data, "Subject: =?utf-8?q?Feature_digest_f=C3=BCr_heute?=\r\n")
+	assert.Contains(t, srv.data, "Content-Type: multipart/alternative; boundary=")
+	assert.Contains(t, srv.data, "Created (1)\r\n- Dark mode")
+	assert.Contains(t, srv.data, "<p>Dark mode</p>")
+}
+
+func Test_Send_RejectedRecipient_Returns_InternalServerError(t *testing.T) {
+	srv := newFakeSmtpServer(t, &fakeSmtpServer{rejectRcpt: true}, nil)
+	defer srv.close()
+
+	err := NewSmtpMailRepository(smtpConfig(srv.port())).Send(dto.MailMessage{To: []string{"nobody@example.com"}, Subject: "Digest", Text: "Hello"})
+	<-srv.done
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, 500, err.StatusCode())
+	assert.Contains(t, err.Message(), "Could not send mail \"Digest\" via 127.0.0.1:")
+}
+
+func Test_Send_StartTls_Upgrades_Connection(t *testing.T) {
+	serverTls, pool := fakeSmtpCertificate(t)
+	srv := newFakeSmtpServer(t, &fakeSmtpServer{startTls: serverTls}, nil)
+	defer srv.close()
+	c := smtpConfig(srv.port())
+	c.Smtp.Security = dto.SmtpStartTls
+	c.Smtp.Username = "user"
+	c.Smtp.Password = "secret"
+	repo := NewSmtpMailRepository(c)
+	repo.rootCAs = pool
+
+	err := repo.Send(dto.MailMessage{To: []string{"a@example.com"}, Subject: "Digest", Text: "Hello"})
+	<-srv.done
+
+	assert.Nil(t, err)
+	assert.True(t, srv.upgraded)
+	assert.EqualValues(t, "AUTH PLAIN AHVzZXIAc2VjcmV0", srv.auth)
+	assert.Contains(t, srv.data, "Hello")
+}
+
+func Test_Send_StartTlsNotOffered_Returns_InternalServerError(t *testing.T) {
+	srv := newFakeSmtpServer(t, &fakeSmtpServer{}, nil)
+	defer srv.close()
+	c := smtpConfig(srv.port())
+	c.Smtp.Security = dto.SmtpStartTls
+
+	err := NewSmtpMailRepository(c).Send(dto.MailMessage{To: []string{"a@example.com"}, Subject: "Digest", Text: "Hello"})
+	srv.close()
+	<-srv.done
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "", srv.from)
+}
+
+func Test_Send_ImplicitTls_Delivers_Mail(t *testing.T) {
+	serverTls, pool := fakeSmtpCertificate(t)
+	srv := newFakeSmtpServer(t, &fakeSmtpServer{}, serverTls)
+	defer srv.close()
+	c := smtpConfig(srv.port())
+	c.Smtp.Security = dto.SmtpTls
+	repo := NewSmtpMailRepository(c)
+	repo.rootCAs = pool
+
+	err := repo.Send(dto.MailMessage{To: []string{"a@example.com"}, Subject: "Digest", Html: "<p>Hello</p>"})
+	<-srv.done
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "MAIL FROM:<pbreact@example.com>", srv.from)
+	assert.Contains(t, srv.data, "Content-Type: text/html; charset=utf-8\r\n")
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseCron_Invalid_Returns_Error(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := parseCron(expr)
+		assert.NotNil(t, err, expr)
+	}
+}
+
+func Test_next_Daily_Returns_NextRun(t *testing.T) {
+	schedule, err := parseCron("30 8 * * *")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, time.Date(2024, 1, 10, 8, 30, 0, 0, time.UTC), schedule.next(time.Date(2024, 1, 10, 8, 29, 59, 0, time.UTC)))
+	assert.EqualValues(t, time.Date(2024, 1, 11, 8, 30, 0, 0, time.UTC), schedule.next(time.Date(2024, 1, 10, 8, 30, 0, 0, time.UTC)))
+	assert.EqualValues(t, time.Date(2024, 1, 9, 8, 30, 0, 0, time.UTC), schedule.prev(time.Date(2024, 1, 10, 8, 30, 0, 0, time.UTC)))
+	assert.EqualValues(t, time.Date(2024, 1, 10, 8, 30, 0, 0, time.UTC), schedule.prev(time.Date(2024, 1, 10, 8, 30, 1, 0, time.UTC)))
+}
+
+func Test_next_WeekdaysAndSteps_Returns_NextRun(t *testing.T) {
+	schedule, _ := parseCron("*/15 9-17 * * 1-5")
+
+	// friday evening to monday morning
+	assert.EqualValues(t, time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), schedule.next(time.Date(2024, 1, 12, 17, 50, 0, 0, time.UTC)))
+	assert.EqualValues(t, time.Date(2024, 1, 12, 17, 45, 0, 0, time.UTC), schedule.prev(time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)))
+}
+
+func Test_next_DayOfMonthOrWeek_Matches_Either(t *testing.T) {
+	schedule, _ := parseCron("0 0 1 * 7")
+
+	// sunday the 7th comes before the 1st of february
+	assert.EqualValues(t, time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC), schedule.next(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.EqualValues(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), schedule.next(time.Date(2024, 1, 28, 0, 0, 0, 0, time.UTC)))
+}
+
+func Test_next_Macro_Returns_NextRun(t *testing.T) {
+	schedule, err := parseCron("@weekly")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC), schedule.next(time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)))
+}
+
+func Test_next_NeverFires_Returns_ZeroTime(t *testing.T) {
+	schedule, _ := parseCron("0 0 31 2 *")
+
+	assert.True(t, schedule.next(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero())
+}
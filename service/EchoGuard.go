@@ -0,0 +1,63 @@
+package service
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// echoGuard remembers the values pbreact itself wrote so that the notifications caused by those writes
+// can be told apart from real changes and ignored
+type echoGuard struct {
+	sync.Mutex
+	window   time.Duration
+	expected map[string]echoEntry
+}
+
+type echoEntry struct {
+	value   string
+	expires time.Time
+}
+
+func newEchoGuard(window time.Duration) *echoGuard {
+	return &echoGuard{
+		window:   window,
+		expected: make(map[string]echoEntry),
+	}
+}
+
+func (g *echoGuard) expect(key string, value string) {
+	g.Lock()
+	defer g.Unlock()
+	g.expected[key] = echoEntry{
+		value:   value,
+		expires: time.Now().Add(g.window),
+	}
+}
+
+// isEcho reports whether value is the one pbreact wrote for key, ignoring case as both Productboard and trackers may
+// normalise it. A matching entry is consumed.
+func (g *echoGuard) isEcho(key string, value string) bool {
+	g.Lock()
+	defer g.Unlock()
+	entry, found := g.expected[key]
+	if !found {
+		return false
+	}
+	if time.Now().After(entry.expires) {
+		delete(g.expected, key)
+		return false
+	}
+	if !strings.EqualFold(entry.value, value) {
+		return false
+	}
+	delete(g.expected, key)
+	return true
+}
+
+// forget drops an expected value again, e.g. when the write that should have caused it failed
+func (g *echoGuard) forget(key string) {
+	g.Lock()
+	defer g.Unlock()
+	delete(g.expected, key)
+}
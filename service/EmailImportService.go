@@ -0,0 +1,185 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+const (
+	importedDir = "imported"
+)
+
+//go:generate mockgen -destination=../mocks/service/mockEmailImportService.go -package=service github.com/johannes-kuhfuss/pbreact/service EmailImportService
+type EmailImportService interface {
+	ImportFile(string) (*dto.EmailImportResult, api_error.ApiErr)
+	WatchDirectory()
+	StopWatching()
+}
+
+type DefaultEmailImportService struct {
+	repo   domain.PbApiRepository
+	ledger domain.ImportLedgerRepository
+	cfg    *config.AppConfig
+	done   chan bool
+	stop   *sync.Once
+}
+
+func NewEmailImportService(c *config.AppConfig, r domain.PbApiRepository, l domain.ImportLedgerRepository) DefaultEmailImportService {
+	return DefaultEmailImportService{
+		repo:   r,
+		ledger: l,
+		cfg:    c,
+		done:   make(chan bool),
+		stop:   &sync.Once{},
+	}
+}
+
+// ImportFile creates a note for every message in an .eml file or mbox archive that has not been imported before
+func (is DefaultEmailImportService) ImportFile(path string) (*dto.EmailImportResult, api_error.ApiErr) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		msg := fmt.Sprintf("Could not read email file %v", path)
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, err)
+	}
+	messages := [][]byte{raw}
+	if isMbox(path, raw) {
+		messages, err = splitMbox(bytes.NewReader(raw))
+		if err != nil {
+			msg := fmt.Sprintf("Could not split mbox archive %v", path)
+			logger.Error(msg, err)
+			return nil, api_error.NewInternalServerError(msg, err)
+		}
+	}
+	result := dto.EmailImportResult{File: path}
+	for i, message := range messages {
+		imported, err := is.importMessage(message)
+		switch {
+		case err != nil:
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("message %v: %v", i+1, err.Message()))
+		case imported:
+			result.Imported++
+		default:
+			result.Skipped++
+		}
+	}
+	logger.Info(fmt.Sprintf("Imported %v, skipped %v and failed %v message(s) from %v", result.Imported, result.Skipped, result.Failed, path))
+	return &result, nil
+}
+
+func (is DefaultEmailImportService) WatchDirectory() {
+	logger.Info(fmt.Sprintf("Watching %v for email files", is.cfg.EmailImport.WatchDir))
+	ticker := time.NewTicker(time.Duration(is.cfg.EmailImport.WatchInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		is.scanDirectory()
+		select {
+		case <-ticker.C:
+		case <-is.done:
+			logger.Info("Stopped watching for email files")
+			return
+		}
+	}
+}
+
+func (is DefaultEmailImportService) StopWatching() {
+	is.stop.Do(func() {
+		close(is.done)
+	})
+}
+
+func (is DefaultEmailImportService) scanDirectory() {
+	dir := is.cfg.EmailImport.WatchDir
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Could not read email directory %v", dir), err)
+		return
+	}
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".eml" && ext != ".mbox") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		result, err := is.ImportFile(path)
+		if err != nil || result.Failed > 0 {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Join(dir, importedDir), 0755); err != nil {
+			logger.Error("Could not create directory for imported email files", err)
+			continue
+		}
+		if err := os.Rename(path, filepath.Join(dir, importedDir, entry.Name())); err != nil {
+			logger.Error(fmt.Sprintf("Could not move imported email file %v", path), err)
+		}
+	}
+}
+
+func (is DefaultEmailImportService) importMessage(raw []byte) (bool, api_error.ApiErr) {
+	email, parseErr := parseEmail(raw)
+	if parseErr != nil {
+		msg := "Could not parse email"
+		logger.Error(msg, parseErr)
+		return false, api_error.NewBadRequestError(fmt.Sprintf("%v: %v", msg, parseErr))
+	}
+	if is.ledger.IsImported(email.MessageId) {
+		return false, nil
+	}
+	note, err := is.emailToNote(*email)
+	if err != nil {
+		return false, err
+	}
+	result, err := is.repo.CreateNote(*note)
+	if err != nil {
+		return false, err
+	}
+	if err := is.ledger.MarkImported(email.MessageId); err != nil {
+		return false, err
+	}
+	return !result.AlreadyExists, nil
+}
+
+func (is DefaultEmailImportService) emailToNote(email dto.EmailMessage) (*dto.Note, api_error.ApiErr) {
+	if email.Subject == "" && email.Body == "" {
+		msg := fmt.Sprintf("Email %v has no content", email.MessageId)
+		logger.Error(msg, nil)
+		return nil, api_error.NewBadRequestError(msg)
+	}
+	title := email.Subject
+	if title == "" {
+		title = strings.SplitN(email.Body, "\n", 2)[0]
+	}
+	if runes := []rune(title); len(runes) > 255 {
+		title = string(runes[:255])
+	}
+	content := email.Body
+	if content == "" {
+		content = email.Subject
+	}
+	return &dto.Note{
+		Title:         title,
+		Content:       textToNoteContent(content),
+		CustomerEmail: email.From,
+		Source: &dto.NoteSource{
+			Origin:   is.cfg.EmailImport.Origin,
+			RecordId: email.MessageId,
+		},
+		Tags: is.cfg.EmailImport.Tags,
+	}, nil
+}
+
+func isMbox(path string, raw []byte) bool {
+	return strings.ToLower(filepath.Ext(path)) == ".mbox" || bytes.HasPrefix(raw, []byte("From "))
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	is             DefaultEmailImportService
+	mockLedgerRepo *domain.MockImportLedgerRepository
+)
+
+func setupEmailImport(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	mockLedgerRepo = domain.NewMockImportLedgerRepository(pbApiCtrl)
+	cfg.EmailImport.Origin = "email"
+	cfg.EmailImport.Tags = []string{"email"}
+	is = NewEmailImportService(&cfg, mockPbApiRepo, mockLedgerRepo)
+	return func() {
+		pbApiCtrl.Finish()
+	}
+}
+
+func writeEmailFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	os.WriteFile(path, []byte(content), 0644)
+	return path
+}
+
+func Test_ImportFile_NoFile_Returns_InternalServerError(t *testing.T) {
+	teardown := setupEmailImport(t)
+	defer teardown()
+
+	result, err := is.ImportFile(filepath.Join(t.TempDir(), "missing.eml"))
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+}
+
+func Test_ImportFile_Eml_Creates_Note(t *testing.T) {
+	teardown := setupEmailImport(t)
+	defer teardown()
+	path := writeEmailFile(t, "feedback.eml", plainEmail)
+	expected := dto.Note{
+		Title:         "Dark mode ✨",
+		Content:       "Please add dark mode.<br>It would be gräat.",
+		CustomerEmail: "jane@example.com",
+		Source:        &dto.NoteSource{Origin: "email", RecordId: "<abc@example.com>"},
+		Tags:          []string{"email"},
+	}
+
+	mockLedgerRepo.EXPECT().IsImported("<abc@example.com>").Return(false)
+	mockPbApiRepo.EXPECT().CreateNote(expected).Return(&dto.NoteResult{ID: "n1"}, nil)
+	mockLedgerRepo.EXPECT().MarkImported("<abc@example.com>").Return(nil)
+
+	result, err := is.ImportFile(path)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, dto.EmailImportResult{File: path, Imported: 1}, *result)
+}
+
+func Test_ImportFile_Mbox_Skips_ImportedMessages(t *testing.T) {
+	teardown := setupEmailImport(t)
+	defer teardown()
+	mbox := "From a@example.com Mon Jan  1 00:00:00 2022\n" +
+		"From: a@example.com\nSubject: One\nMessage-ID: <1@example.com>\n\nFirst\n\n" +
+		"From b@example.com Mon Jan  1 00:00:00 2022\n" +
+		"From: b@example.com\nSubject: Two\nMessage-ID: <2@example.com>\n\nSecond\n\n" +
+		"From c@example.com Mon Jan  1 00:00:00 2022\n" +
+		"From: c@example.com\nSubject: Three\nMessage-ID: <3@example.com>\n\nThird\n"
+	path := writeEmailFile(t, "archive.mbox", mbox)
+	apiError := api_error.NewInternalServerError("something went wrong", nil)
+
+	mockLedgerRepo.EXPECT().IsImported("<1@example.com>").Return(true)
+	mockLedgerRepo.EXPECT().IsImported("<2@example.com>").Return(false)
+	mockPbApiRepo.EXPECT().CreateNote(gomock.Any()).Return(&dto.NoteResult{AlreadyExists: true}, nil)
+	mockLedgerRepo.EXPECT().MarkImported("<2@example.com>").Return(nil)
+	mockLedgerRepo.EXPECT().IsImported("<3@example.com>").Return(false)
+	mockPbApiRepo.EXPECT().CreateNote(gomock.Any()).Return(nil, apiError)
+
+	result, err := is.ImportFile(path)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, result.Imported)
+	assert.EqualValues(t, 2, result.Skipped)
+	assert.EqualValues(t, 1, result.Failed)
+	assert.EqualValues(t, []string{"message 3: something went wrong"}, result.Errors)
+}
+
+func Test_scanDirectory_Moves_ImportedFiles(t *testing.T) {
+	teardown := setupEmailImport(t)
+	defer teardown()
+	dir := t.TempDir()
+	cfg.EmailImport.WatchDir = dir
+	os.WriteFile(filepath.Join(dir, "feedback.eml"), []byte(plainEmail), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644)
+
+	mockLedgerRepo.EXPECT().IsImported("<abc@example.com>").Return(true)
+
+	is.scanDirectory()
+
+	_, movedErr := os.Stat(filepath.Join(dir, importedDir, "feedback.eml"))
+	_, ignoredErr := os.Stat(filepath.Join(dir, "notes.txt"))
+	assert.Nil(t, movedErr)
+	assert.Nil(t, ignoredErr)
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+var (
+	htmlDropBlocks  = regexp.MustCompile(`(?is)<(script|style|head)[^>]*>.*?</(script|style|head)>`)
+	htmlComments    = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlLineBreaks  = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|tr|h[1-6]|blockquote)>`)
+	htmlTags        = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankRuns       = regexp.MustCompile(`\n{3,}`)
+	spaceRuns       = regexp.MustCompile(`[ \t\r\f\v]+`)
+	mboxEscapedFrom = regexp.MustCompile(`^>+From `)
+	wordDecoder     = mime.WordDecoder{CharsetReader: charsetReader}
+)
+
+// splitMbox splits an mbox archive into raw messages, undoing the ">From " quoting
+func splitMbox(r io.Reader) ([][]byte, error) {
+	messages := [][]byte{}
+	var current *bytes.Buffer
+	reader := bufio.NewReader(r)
+	prevBlank := true
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			if prevBlank && strings.HasPrefix(line, "From ") {
+				if current != nil {
+					messages = append(messages, current.Bytes())
+				}
+				current = &bytes.Buffer{}
+			} else if current != nil {
+				if mboxEscapedFrom.MatchString(line) {
+					line = line[1:]
+				}
+				current.WriteString(line)
+			}
+			prevBlank = strings.TrimRight(line, "\r\n") == ""
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if current != nil {
+		messages = append(messages, current.Bytes())
+	}
+	return messages, nil
+}
+
+// parseEmail extracts everything needed for a note from a single RFC 5322 message
+func parseEmail(raw []byte) (*dto.EmailMessage, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	email := dto.EmailMessage{
+		MessageId: strings.TrimSpace(msg.Header.Get("Message-Id")),
+	}
+	if email.MessageId == "" {
+		email.MessageId = fmt.Sprintf("sha256:%x", sha256.Sum256(raw))
+	}
+	if subject, err := wordDecoder.DecodeHeader(msg.Header.Get("Subject")); err == nil {
+		email.Subject = strings.TrimSpace(subject)
+	} else {
+		email.Subject = strings.TrimSpace(msg.Header.Get("Subject"))
+	}
+	if from, err := (&mail.AddressParser{WordDecoder: &wordDecoder}).ParseList(msg.Header.Get("From")); err == nil && len(from) > 0 {
+		email.From = strings.ToLower(from[0].Address)
+	}
+	body, err := extractBody(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return nil, err
+	}
+	email.Body = body
+	return &email, nil
+}
+
+func extractBody(contentType string, transferEncoding string, body io.Reader) (string, error) {
+	if contentType == "" {
+		contentType = "text/plain; charset=us-ascii"
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		return extractMultipartBody(mediaType, params["boundary"], body)
+	}
+	if !strings.HasPrefix(mediaType, "text/") {
+		return "", nil
+	}
+	text, err := decodeText(transferEncoding, params["charset"], body)
+	if err != nil {
+		return "", err
+	}
+	if mediaType == "text/html" {
+		return htmlToText(text), nil
+	}
+	return normalizeText(text), nil
+}
+
+// extractMultipartBody prefers a text/plain alternative and falls back to the sanitised html one
+func extractMultipartBody(mediaType string, boundary string, body io.Reader) (string, error) {
+	reader := multipart.NewReader(body, boundary)
+	plain, htmlText := "", ""
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if strings.HasPrefix(strings.ToLower(part.Header.Get("Content-Disposition")), "attachment") {
+			continue
+		}
+		partType := part.Header.Get("Content-Type")
+		text, err := extractBody(partType, part.Header.Get("Content-Transfer-Encoding"), part)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case text == "":
+		case strings.HasPrefix(strings.ToLower(partType), "text/html"):
+			if htmlText == "" {
+				htmlText = text
+			}
+		case plain == "":
+			plain = text
+		case mediaType == "multipart/mixed":
+			plain = plain + "\n\n" + text
+		}
+	}
+	if plain != "" {
+		return plain, nil
+	}
+	return htmlText, nil
+}
+
+func decodeText(transferEncoding string, charset string, body io.Reader) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(transferEncoding)) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	decoded, err := charsetReader(charset, body)
+	if err != nil {
+		return "", err
+	}
+	text, err := io.ReadAll(decoded)
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
+
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	charset = strings.ToLower(strings.TrimSpace(charset))
+	if charset == "" || charset == "utf-8" || charset == "us-ascii" {
+		return input, nil
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported charset %v", charset)
+	}
+	return enc.NewDecoder().Reader(input), nil
+}
+
+// htmlToText reduces an html body to plain text, dropping scripts, styles and all markup
+func htmlToText(body string) string {
+	text := htmlDropBlocks.ReplaceAllString(body, "")
+	text = htmlComments.ReplaceAllString(text, "")
+	text = htmlLineBreaks.ReplaceAllString(text, "\n")
+	text = htmlTags.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	return normalizeText(text)
+}
+
+func normalizeText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(spaceRuns.ReplaceAllString(line, " "))
+	}
+	text = strings.Join(lines, "\n")
+	text = blankRuns.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// textToNoteContent turns plain text into the limited html Productboard expects for note content
+func textToNoteContent(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+}
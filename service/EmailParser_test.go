@@ -0,0 +1,65 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	plainEmail = "From: \"Jane Doe\" <Jane@Example.com>\r\n" +
+		"Subject: =?utf-8?q?Dark_mode_=E2=9C=A8?=\r\n" +
+		"Message-ID: <abc@example.com>\r\n" +
+		"Content-Type: text/plain; charset=iso-8859-1\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"\r\n" +
+		"Please add dark mode.=0D=0AIt would be gr=E4at.\r\n"
+	alternativeEmail = "From: jane@example.com\r\n" +
+		"Subject: Export\r\n" +
+		"Content-Type: multipart/alternative; boundary=XYZ\r\n" +
+		"\r\n" +
+		"--XYZ\r\n" +
+		"Content-Type: text/html; charset=utf-8\r\n" +
+		"\r\n" +
+		"<html><head><style>p{}</style></head><body><p>We need <b>CSV</b> export</p><script>alert(1)</script><p>Thanks &amp; bye</p></body></html>\r\n" +
+		"--XYZ--\r\n"
+)
+
+func Test_parseEmail_QuotedPrintableLatin1_Returns_Message(t *testing.T) {
+	email, err := parseEmail([]byte(plainEmail))
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "<abc@example.com>", email.MessageId)
+	assert.EqualValues(t, "Dark mode ✨", email.Subject)
+	assert.EqualValues(t, "jane@example.com", email.From)
+	assert.EqualValues(t, "Please add dark mode.\nIt would be gräat.", email.Body)
+}
+
+func Test_parseEmail_HtmlOnly_Returns_SanitisedText(t *testing.T) {
+	email, err := parseEmail([]byte(alternativeEmail))
+
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(email.MessageId, "sha256:"))
+	assert.EqualValues(t, "We need CSV export\nThanks & bye", email.Body)
+}
+
+func Test_splitMbox_Returns_Messages(t *testing.T) {
+	mbox := "From jane@example.com Mon Jan  1 00:00:00 2022\n" +
+		"Subject: One\n\nFirst\n>From the start\n\n" +
+		"From john@example.com Mon Jan  1 00:00:00 2022\n" +
+		"Subject: Two\n\nSecond\n"
+
+	messages, err := splitMbox(strings.NewReader(mbox))
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(messages))
+	assert.EqualValues(t, "Subject: One\n\nFirst\nFrom the start\n\n", string(messages[0]))
+	assert.EqualValues(t, "Subject: Two\n\nSecond\n", string(messages[1]))
+}
+
+func Test_textToNoteContent_Escapes_Html(t *testing.T) {
+	content := textToNoteContent("a < b\nc")
+
+	assert.EqualValues(t, "a &lt; b<br>c", content)
+}
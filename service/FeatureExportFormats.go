@@ -0,0 +1,137 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+const (
+	unassignedGroup = "Unassigned"
+)
+
+var (
+	exportCsvHeader = []string{"id", "name", "type", "status", "archived", "product", "component", "parentPath", "startDate", "endDate", "url"}
+	markdownCell    = strings.NewReplacer("|", "\\|", "\r\n", " ", "\n", " ")
+)
+
+type featureWriter interface {
+	Write(dto.ExportFeature) error
+	Close() error
+}
+
+type csvFeatureWriter struct {
+	csv           *csv.Writer
+	headerWritten bool
+}
+
+type jsonlFeatureWriter struct {
+	encoder *json.Encoder
+}
+
+// markdownFeatureWriter has to see all features before it can group them, so it only writes on Close
+type markdownFeatureWriter struct {
+	w      io.Writer
+	groups map[string][]dto.ExportFeature
+}
+
+func newFeatureWriter(w io.Writer, format string) (featureWriter, api_error.ApiErr) {
+	switch format {
+	case dto.ExportCsv:
+		return &csvFeatureWriter{csv: csv.NewWriter(w)}, nil
+	case dto.ExportJsonl:
+		return &jsonlFeatureWriter{encoder: json.NewEncoder(w)}, nil
+	case dto.ExportMarkdown:
+		return &markdownFeatureWriter{w: w, groups: make(map[string][]dto.ExportFeature)}, nil
+	}
+	msg := fmt.Sprintf("Unknown export format \"%v\"", format)
+	logger.Error(msg, nil)
+	return nil, api_error.NewBadRequestError(msg)
+}
+
+func (cw *csvFeatureWriter) Write(feature dto.ExportFeature) error {
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+	return cw.csv.Write([]string{
+		feature.ID, feature.Name, feature.Type, feature.Status, strconv.FormatBool(feature.Archived),
+		feature.Product, feature.Component, feature.ParentPath, feature.StartDate, feature.EndDate, feature.Url,
+	})
+}
+
+func (cw *csvFeatureWriter) Close() error {
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+	cw.csv.Flush()
+	return cw.csv.Error()
+}
+
+func (cw *csvFeatureWriter) writeHeader() error {
+	if cw.headerWritten {
+		return nil
+	}
+	cw.headerWritten = true
+	return cw.csv.Write(exportCsvHeader)
+}
+
+func (jw *jsonlFeatureWriter) Write(feature dto.ExportFeature) error {
+	return jw.encoder.Encode(feature)
+}
+
+func (jw *jsonlFeatureWriter) Close() error {
+	return nil
+}
+
+func (mw *markdownFeatureWriter) Write(feature dto.ExportFeature) error {
+	group := unassignedGroup
+	if feature.Product != "" {
+		group = feature.Product
+	}
+	if feature.Component != "" {
+		group = fmt.Sprintf("%v / %v", group, feature.Component)
+	}
+	mw.groups[group] = append(mw.groups[group], feature)
+	return nil
+}
+
+func (mw *markdownFeatureWriter) Close() error {
+	groups := make([]string, 0, len(mw.groups))
+	for group := range mw.groups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	var sb strings.Builder
+	sb.WriteString("# Features\n")
+	for _, group := range groups {
+		sb.WriteString(fmt.Sprintf("\n## %v\n\n", markdownCell.Replace(group)))
+		sb.WriteString("| Feature | Status | Start | End |\n")
+		sb.WriteString("| --- | --- | --- | --- |\n")
+		for _, feature := range mw.groups[group] {
+			sb.WriteString(fmt.Sprintf("| %v | %v | %v | %v |\n", markdownFeatureName(feature), markdownCell.Replace(feature.Status), feature.StartDate, feature.EndDate))
+		}
+	}
+	_, err := io.WriteString(mw.w, sb.String())
+	return err
+}
+
+// markdownFeatureName links the feature and puts the parent feature in front of subfeatures
+func markdownFeatureName(feature dto.ExportFeature) string {
+	name := markdownCell.Replace(feature.Name)
+	if feature.Url != "" {
+		name = fmt.Sprintf("[%v](%v)", strings.NewReplacer("[", "\\[", "]", "\\]").Replace(name), feature.Url)
+	}
+	if feature.Type == dto.NodeTypeSubfeature {
+		path := strings.Split(feature.ParentPath, " / ")
+		name = fmt.Sprintf("%v / %v", markdownCell.Replace(path[len(path)-1]), name)
+	}
+	return name
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+const (
+	maxParentDepth = 32
+)
+
+//go:generate mockgen -destination=../mocks/service/mockFeatureExportService.go -package=service github.com/johannes-kuhfuss/pbreact/service FeatureExportService
+type FeatureExportService interface {
+	ExportFeatures(io.Writer, string, dto.ExportFilter) api_error.ApiErr
+}
+
+type DefaultFeatureExportService struct {
+	repo domain.PbApiRepository
+	cfg  *config.AppConfig
+}
+
+type exportNode struct {
+	id       string
+	name     string
+	nodeType string
+	parentId string
+}
+
+// exportResolver turns parent ids into names. Products and components are loaded up front, parent features are looked up as needed.
+type exportResolver struct {
+	repo  domain.PbApiRepository
+	nodes map[string]exportNode
+}
+
+func NewFeatureExportService(c *config.AppConfig, r domain.PbApiRepository) DefaultFeatureExportService {
+	return DefaultFeatureExportService{
+		repo: r,
+		cfg:  c,
+	}
+}
+
+// ParseExportFilter builds a filter from comma separated status names, an optional archived flag and a parent name or id
+func ParseExportFilter(statuses []string, archived string, parent string) (*dto.ExportFilter, api_error.ApiErr) {
+	filter := dto.ExportFilter{
+		Statuses: splitList(statuses),
+		Parent:   strings.TrimSpace(parent),
+	}
+	if archived != "" {
+		value, err := strconv.ParseBool(archived)
+		if err != nil {
+			msg := fmt.Sprintf("Archived filter must be true or false, not \"%v\"", archived)
+			logger.Error(msg, nil)
+			return nil, api_error.NewBadRequestError(msg)
+		}
+		filter.Archived = &value
+	}
+	return &filter, nil
+}
+
+// ExportFeatures writes all features matching the filter in the given format, page by page as they are fetched
+func (fes DefaultFeatureExportService) ExportFeatures(w io.Writer, format string, filter dto.ExportFilter) api_error.ApiErr {
+	writer, err := newFeatureWriter(w, format)
+	if err != nil {
+		return err
+	}
+	resolver, err := fes.newResolver()
+	if err != nil {
+		return err
+	}
+	apiFilter := dto.FeatureFilter{Archived: filter.Archived}
+	if len(filter.Statuses) == 1 {
+		apiFilter.StatusName = filter.Statuses[0]
+	}
+	count := 0
+	err = fes.repo.GetFeaturePages(apiFilter, func(page []dto.Feature) api_error.ApiErr {
+		for _, feature := range page {
+			resolver.addFeature(feature)
+		}
+		for _, feature := range page {
+			if !containsFold(filter.Statuses, feature.Status.Name) {
+				continue
+			}
+			chain := resolver.ancestors(feature.Parent.ParentId())
+			if !exportParentMatches(chain, filter) {
+				continue
+			}
+			if err := writer.Write(exportFeature(feature, chain)); err != nil {
+				return exportWriteError(err)
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return exportWriteError(err)
+	}
+	logger.Info(fmt.Sprintf("Exported %v feature(s) as %v", count, format))
+	return nil
+}
+
+func (fes DefaultFeatureExportService) newResolver() (*exportResolver, api_error.ApiErr) {
+	products, err := fes.repo.GetProducts()
+	if err != nil {
+		return nil, err
+	}
+	components, err := fes.repo.GetComponents()
+	if err != nil {
+		return nil, err
+	}
+	resolver := exportResolver{
+		repo:  fes.repo,
+		nodes: make(map[string]exportNode),
+	}
+	for _, p := range products {
+		resolver.nodes[p.ID] = exportNode{id: p.ID, name: p.Name, nodeType: dto.NodeTypeProduct}
+	}
+	for _, c := range components {
+		resolver.nodes[c.ID] = exportNode{id: c.ID, name: c.Name, nodeType: dto.NodeTypeComponent, parentId: c.Parent.ParentId()}
+	}
+	return &resolver, nil
+}
+
+func (er *exportResolver) addFeature(feature dto.Feature) {
+	er.nodes[feature.ID] = exportNode{id: feature.ID, name: feature.Name, nodeType: feature.Type, parentId: feature.Parent.ParentId()}
+}
+
+// ancestors returns the chain of parents from the product down to parentId. Parents that cannot be found end the chain.
+func (er *exportResolver) ancestors(parentId string) []exportNode {
+	chain := []exportNode{}
+	for id := parentId; id != "" && len(chain) < maxParentDepth; {
+		node, found := er.nodes[id]
+		if !found {
+			feature, err := er.repo.GetFeature(id)
+			if err != nil {
+				logger.Warn(fmt.Sprintf("Could not resolve parent %v, exporting incomplete parent path", id))
+				break
+			}
+			er.addFeature(*feature)
+			node = er.nodes[id]
+		}
+		chain = append([]exportNode{node}, chain...)
+		id = node.parentId
+	}
+	return chain
+}
+
+func exportParentMatches(chain []exportNode, filter dto.ExportFilter) bool {
+	if filter.Parent == "" {
+		return true
+	}
+	for _, node := range chain {
+		if node.id == filter.Parent || strings.EqualFold(node.name, filter.Parent) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList flattens query values that may each hold a comma separated list
+func splitList(lists []string) []string {
+	values := []string{}
+	for _, list := range lists {
+		for _, value := range strings.Split(list, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+func exportFeature(feature dto.Feature, chain []exportNode) dto.ExportFeature {
+	export := dto.ExportFeature{
+		ID:        feature.ID,
+		Name:      feature.Name,
+		Type:      feature.Type,
+		Status:    feature.Status.Name,
+		Archived:  feature.Archived,
+		StartDate: exportDate(feature.Timeframe.StartDate),
+		EndDate:   exportDate(feature.Timeframe.EndDate),
+		Url:       feature.Links.Html,
+	}
+	names := []string{}
+	components := []string{}
+	for _, node := range chain {
+		names = append(names, node.name)
+		switch node.nodeType {
+		case dto.NodeTypeProduct:
+			export.Product = node.name
+		case dto.NodeTypeComponent:
+			components = append(components, node.name)
+		}
+	}
+	export.Component = strings.Join(components, " / ")
+	export.ParentPath = strings.Join(names, " / ")
+	return export
+}
+
+func exportDate(date string) string {
+	if date == noTimeframeDate {
+		return ""
+	}
+	return date
+}
+
+func exportWriteError(err error) api_error.ApiErr {
+	msg := "Could not write feature export"
+	logger.Error(msg, err)
+	return api_error.NewInternalServerError(msg, err)
+}
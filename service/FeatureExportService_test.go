@@ -0,0 +1,154 @@
+package service
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	fes DefaultFeatureExportService
+)
+
+func setupFeatureExport(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	fes = NewFeatureExportService(&cfg, mockPbApiRepo)
+	return func() {
+		pbApiCtrl.Finish()
+	}
+}
+
+func exportPages(pages ...[]dto.Feature) func(dto.FeatureFilter, func([]dto.Feature) api_error.ApiErr) api_error.ApiErr {
+	return func(_ dto.FeatureFilter, handlePage func([]dto.Feature) api_error.ApiErr) api_error.ApiErr {
+		for _, page := range pages {
+			if err := handlePage(page); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func expectExportHierarchy() {
+	mockPbApiRepo.EXPECT().GetProducts().Return([]dto.Product{{ID: "p1", Name: "Portal"}}, nil)
+	mockPbApiRepo.EXPECT().GetComponents().Return([]dto.Component{
+		{ID: "c1", Name: "Branding", Parent: dto.Parent{Product: &dto.ParentRef{ID: "p1"}}},
+		{ID: "c2", Name: "Logos", Parent: dto.Parent{Component: &dto.ParentRef{ID: "c1"}}},
+	}, nil)
+}
+
+func exportTestFeatures() []dto.Feature {
+	return []dto.Feature{
+		{ID: "f1", Name: "Dark mode", Type: "feature", Status: dto.FeatureStatus{Name: "In progress"}, Parent: dto.Parent{Product: &dto.ParentRef{ID: "p1"}},
+			Timeframe: dto.Timeframe{StartDate: "2024-01-01", EndDate: "none"}, Links: dto.EntityLinks{Html: "https://pb/f1"}},
+		{ID: "f2", Name: "SVG | PNG", Type: "subfeature", Status: dto.FeatureStatus{Name: "New idea"}, Parent: dto.Parent{Feature: &dto.ParentRef{ID: "f3"}}},
+	}
+}
+
+func Test_ExportFeatures_UnknownFormat_Returns_BadRequestError(t *testing.T) {
+	teardown := setupFeatureExport(t)
+	defer teardown()
+	var out bytes.Buffer
+
+	err := fes.ExportFeatures(&out, "xml", dto.ExportFilter{})
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Unknown export format \"xml\"", err.Message())
+}
+
+func Test_ExportFeatures_Csv_Resolves_ParentPath(t *testing.T) {
+	teardown := setupFeatureExport(t)
+	defer teardown()
+	var out bytes.Buffer
+
+	expectExportHierarchy()
+	mockPbApiRepo.EXPECT().GetFeaturePages(dto.FeatureFilter{}, gomock.Any()).DoAndReturn(exportPages(exportTestFeatures()))
+	mockPbApiRepo.EXPECT().GetFeature("f3").Return(&dto.Feature{ID: "f3", Name: "Custom logo", Type: "feature", Parent: dto.Parent{Component: &dto.ParentRef{ID: "c2"}}}, nil)
+
+	err := fes.ExportFeatures(&out, dto.ExportCsv, dto.ExportFilter{})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "id,name,type,status,archived,product,component,parentPath,startDate,endDate,url\n"+
+		"f1,Dark mode,feature,In progress,false,Portal,,Portal,2024-01-01,,https://pb/f1\n"+
+		"f2,SVG | PNG,subfeature,New idea,false,Portal,Branding / Logos,Portal / Branding / Logos / Custom logo,,,\n", out.String())
+}
+
+func Test_ExportFeatures_Filters_StatusAndParent(t *testing.T) {
+	teardown := setupFeatureExport(t)
+	defer teardown()
+	var out bytes.Buffer
+	features := append(exportTestFeatures(), dto.Feature{ID: "f3", Name: "Custom logo", Status: dto.FeatureStatus{Name: "new idea"}, Parent: dto.Parent{Component: &dto.ParentRef{ID: "c2"}}})
+	filter := dto.ExportFilter{Statuses: []string{"New idea", "Done"}, Parent: "branding"}
+
+	expectExportHierarchy()
+	mockPbApiRepo.EXPECT().GetFeaturePages(dto.FeatureFilter{}, gomock.Any()).DoAndReturn(exportPages(features))
+
+	err := fes.ExportFeatures(&out, dto.ExportJsonl, filter)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, `{"id":"f2","name":"SVG | PNG","type":"subfeature","status":"New idea","archived":false,"product":"Portal","component":"Branding / Logos","parentPath":"Portal / Branding / Logos / Custom logo","startDate":"","endDate":"","url":""}`+"\n"+
+		`{"id":"f3","name":"Custom logo","type":"","status":"new idea","archived":false,"product":"Portal","component":"Branding / Logos","parentPath":"Portal / Branding / Logos","startDate":"","endDate":"","url":""}`+"\n", out.String())
+}
+
+func Test_ExportFeatures_Markdown_Groups_ByProductAndComponent(t *testing.T) {
+	teardown := setupFeatureExport(t)
+	defer teardown()
+	var out bytes.Buffer
+	orphan := dto.Feature{ID: "f4", Name: "Lost", Status: dto.FeatureStatus{Name: "Done"}, Parent: dto.Parent{Product: &dto.ParentRef{ID: "p9"}}}
+
+	expectExportHierarchy()
+	mockPbApiRepo.EXPECT().GetFeaturePages(dto.FeatureFilter{}, gomock.Any()).DoAndReturn(exportPages([]dto.Feature{orphan}, exportTestFeatures()))
+	mockPbApiRepo.EXPECT().GetFeature("p9").Return(nil, api_error.NewNotFoundError("not found"))
+	mockPbApiRepo.EXPECT().GetFeature("f3").Return(&dto.Feature{ID: "f3", Name: "Custom logo", Parent: dto.Parent{Component: &dto.ParentRef{ID: "c2"}}}, nil)
+
+	err := fes.ExportFeatures(&out, dto.ExportMarkdown, dto.ExportFilter{})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "# Features\n"+
+		"\n## Portal\n\n| Feature | Status | Start | End |\n| --- | --- | --- | --- |\n"+
+		"| [Dark mode](https://pb/f1) | In progress | 2024-01-01 |  |\n"+
+		"\n## Portal / Branding / Logos\n\n| Feature | Status | Start | End |\n| --- | --- | --- | --- |\n"+
+		"| Custom logo / SVG \\| PNG | New idea |  |  |\n"+
+		"\n## Unassigned\n\n| Feature | Status | Start | End |\n| --- | --- | --- | --- |\n"+
+		"| Lost | Done |  |  |\n", out.String())
+}
+
+func Test_ExportFeatures_SingleStatus_Filters_InApi(t *testing.T) {
+	teardown := setupFeatureExport(t)
+	defer teardown()
+	var out bytes.Buffer
+	archived := false
+
+	expectExportHierarchy()
+	mockPbApiRepo.EXPECT().GetFeaturePages(dto.FeatureFilter{StatusName: "Done", Archived: &archived}, gomock.Any()).DoAndReturn(exportPages(exportTestFeatures()))
+
+	err := fes.ExportFeatures(&out, dto.ExportJsonl, dto.ExportFilter{Statuses: []string{"Done"}, Archived: &archived})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "", out.String())
+}
+
+func Test_ParseExportFilter_InvalidArchived_Returns_BadRequestError(t *testing.T) {
+	filter, err := ParseExportFilter(nil, "maybe", "")
+
+	assert.Nil(t, filter)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+}
+
+func Test_ParseExportFilter_Splits_Statuses(t *testing.T) {
+	filter, err := ParseExportFilter([]string{"New idea, Done", "", "Released"}, "true", " Portal ")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"New idea", "Done", "Released"}, filter.Statuses)
+	assert.True(t, *filter.Archived)
+	assert.EqualValues(t, "Portal", filter.Parent)
+}
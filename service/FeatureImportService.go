@@ -0,0 +1,484 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+	"github.com/johannes-kuhfuss/services_utils/misc"
+)
+
+const (
+	featureDateLayout = "2006-01-02"
+	noTimeframeDate   = "none"
+)
+
+var (
+	importHeader = strings.NewReplacer("_", "", "-", "", " ", "")
+)
+
+//go:generate mockgen -destination=../mocks/service/mockFeatureImportService.go -package=service github.com/johannes-kuhfuss/pbreact/service FeatureImportService
+type FeatureImportService interface {
+	PlanImport(string) (*dto.FeatureImportPlan, api_error.ApiErr)
+	RunImport(dto.FeatureImportPlan) (*dto.FeatureImportResult, api_error.ApiErr)
+}
+
+type DefaultFeatureImportService struct {
+	repo     domain.PbApiRepository
+	statuses PbStatusService
+	progress domain.ImportLedgerRepository
+	cfg      *config.AppConfig
+}
+
+// importCatalog holds the products, components and features the names in an import file are resolved against
+type importCatalog struct {
+	products       []dto.Product
+	components     []dto.Component
+	componentsById map[string]dto.Component
+	features       map[string][]dto.Feature
+}
+
+type importParent struct {
+	id   string
+	name string
+	ref  dto.ParentIdRef
+}
+
+func NewFeatureImportService(c *config.AppConfig, r domain.PbApiRepository, s PbStatusService, l domain.ImportLedgerRepository) DefaultFeatureImportService {
+	return DefaultFeatureImportService{
+		repo:     r,
+		statuses: s,
+		progress: l,
+		cfg:      c,
+	}
+}
+
+// PlanImport reads a CSV or JSON file, resolves all names and works out which features to create or update without changing anything
+func (fis DefaultFeatureImportService) PlanImport(path string) (*dto.FeatureImportPlan, api_error.ApiErr) {
+	rows, err := readImportRows(path)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := fis.loadCatalog()
+	if err != nil {
+		return nil, err
+	}
+	plan := dto.FeatureImportPlan{
+		File:    path,
+		Actions: []dto.FeatureImportAction{},
+	}
+	seen := make(map[string]int)
+	for i, row := range rows {
+		action, problems, err := fis.planRow(i+1, row, catalog, seen)
+		if err != nil {
+			return nil, err
+		}
+		for _, problem := range problems {
+			plan.Errors = append(plan.Errors, fmt.Sprintf("row %v: %v", i+1, problem))
+		}
+		if action != nil {
+			plan.Actions = append(plan.Actions, *action)
+		}
+	}
+	logger.Info(fmt.Sprintf("Planned import of %v: %v to create, %v to update, %v unchanged, %v already imported, %v error(s)", path,
+		plan.Count(dto.ImportCreate), plan.Count(dto.ImportUpdate), plan.Count(dto.ImportUnchanged), plan.Count(dto.ImportDone), len(plan.Errors)))
+	return &plan, nil
+}
+
+// RunImport executes a plan without errors, rate limited. Every row done is recorded so an interrupted import can be resumed.
+func (fis DefaultFeatureImportService) RunImport(plan dto.FeatureImportPlan) (*dto.FeatureImportResult, api_error.ApiErr) {
+	if len(plan.Errors) > 0 {
+		msg := fmt.Sprintf("Import plan for %v has %v error(s)", plan.File, len(plan.Errors))
+		logger.Error(msg, nil)
+		return nil, api_error.NewBadRequestError(msg)
+	}
+	var limit <-chan time.Time
+	if fis.cfg.FeatureImport.RateLimit > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(fis.cfg.FeatureImport.RateLimit))
+		defer ticker.Stop()
+		limit = ticker.C
+	}
+	result := dto.FeatureImportResult{File: plan.File}
+	for _, action := range plan.Actions {
+		if action.Action != dto.ImportCreate && action.Action != dto.ImportUpdate {
+			result.Skipped++
+			continue
+		}
+		if limit != nil {
+			<-limit
+		}
+		if err := fis.applyAction(action); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("row %v: %v", action.Row, err.Message()))
+			continue
+		}
+		if action.Action == dto.ImportCreate {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+		if err := fis.progress.MarkImported(action.Key); err != nil {
+			return nil, err
+		}
+	}
+	logger.Info(fmt.Sprintf("Imported %v: %v created, %v updated, %v skipped, %v failed", plan.File, result.Created, result.Updated, result.Skipped, result.Failed))
+	return &result, nil
+}
+
+func (fis DefaultFeatureImportService) applyAction(action dto.FeatureImportAction) api_error.ApiErr {
+	if action.Action == dto.ImportCreate {
+		_, err := fis.repo.CreateFeature(*action.Create)
+		return err
+	}
+	_, err := fis.repo.UpdateFeature(action.FeatureId, *action.Update)
+	return err
+}
+
+func (fis DefaultFeatureImportService) loadCatalog() (*importCatalog, api_error.ApiErr) {
+	products, err := fis.repo.GetProducts()
+	if err != nil {
+		return nil, err
+	}
+	components, err := fis.repo.GetComponents()
+	if err != nil {
+		return nil, err
+	}
+	features, err := fis.repo.GetFeatures(dto.FeatureFilter{})
+	if err != nil {
+		return nil, err
+	}
+	catalog := importCatalog{
+		products:       products,
+		components:     components,
+		componentsById: make(map[string]dto.Component),
+		features:       make(map[string][]dto.Feature),
+	}
+	for _, c := range components {
+		catalog.componentsById[c.ID] = c
+	}
+	for _, f := range features {
+		key := importFeatureKey(f.Parent.ParentId(), f.Name)
+		catalog.features[key] = append(catalog.features[key], f)
+	}
+	return &catalog, nil
+}
+
+// planRow validates a single row and decides what to do with it. Problems with the row are returned as messages, only failures to look up data as error.
+func (fis DefaultFeatureImportService) planRow(n int, row dto.FeatureImportRow, catalog *importCatalog, seen map[string]int) (*dto.FeatureImportAction, []string, api_error.ApiErr) {
+	problems := []string{}
+	name := strings.TrimSpace(row.Name)
+	if name == "" {
+		problems = append(problems, "name is missing")
+	} else if len([]rune(name)) > 255 {
+		problems = append(problems, "name is longer than 255 characters")
+	}
+	parent, problem := catalog.resolveParent(row.Product, row.Component)
+	if problem != "" {
+		problems = append(problems, problem)
+	}
+	statusId := ""
+	if status := strings.TrimSpace(row.Status); status != "" {
+		id, err := fis.statuses.GetStatusId(status)
+		if err != nil && err.StatusCode() >= 500 {
+			return nil, nil, err
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("unknown or ambiguous status \"%v\"", status))
+		}
+		statusId = id
+	}
+	timeframe, timeframeProblems := importTimeframe(row.StartDate, row.EndDate)
+	problems = append(problems, timeframeProblems...)
+	if len(problems) > 0 {
+		return nil, problems, nil
+	}
+	key := importFeatureKey(parent.id, name)
+	if first, found := seen[key]; found {
+		return nil, []string{fmt.Sprintf("feature \"%v\" already appears in row %v", name, first)}, nil
+	}
+	seen[key] = n
+	action := dto.FeatureImportAction{
+		Row:    n,
+		Key:    importRowKey(row),
+		Name:   name,
+		Parent: parent.name,
+	}
+	if fis.progress.IsImported(action.Key) {
+		action.Action = dto.ImportDone
+		return &action, nil, nil
+	}
+	description := importDescription(row.Description)
+	existing := catalog.features[key]
+	switch len(existing) {
+	case 0:
+		if statusId == "" {
+			return nil, []string{"status is required for new features"}, nil
+		}
+		action.Action = dto.ImportCreate
+		action.Create = &dto.FeatureCreate{
+			Name:        name,
+			Description: description,
+			Type:        dto.NodeTypeFeature,
+			Status:      dto.StatusRef{ID: statusId},
+			Parent:      parent.ref,
+			Timeframe:   timeframe,
+		}
+	case 1:
+		action.FeatureId = existing[0].ID
+		update, changes := featureChanges(existing[0], name, description, statusId, timeframe)
+		if len(changes) == 0 {
+			action.Action = dto.ImportUnchanged
+			break
+		}
+		action.Action = dto.ImportUpdate
+		action.Update = update
+		action.Changes = changes
+	default:
+		return nil, []string{fmt.Sprintf("%v features named \"%v\" already exist below %v", len(existing), name, parent.name)}, nil
+	}
+	return &action, nil, nil
+}
+
+// resolveParent finds the product or component a row belongs to. A product narrows down the search for a component of the same name.
+func (catalog *importCatalog) resolveParent(product string, component string) (*importParent, string) {
+	product = strings.TrimSpace(product)
+	component = strings.TrimSpace(component)
+	if product == "" && component == "" {
+		return nil, "product or component is missing"
+	}
+	productId := ""
+	if product != "" {
+		matches := []dto.Product{}
+		for _, p := range catalog.products {
+			if strings.EqualFold(p.Name, product) {
+				matches = append(matches, p)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Sprintf("unknown product \"%v\"", product)
+		case 1:
+			productId = matches[0].ID
+		default:
+			return nil, fmt.Sprintf("product name \"%v\" is ambiguous", product)
+		}
+		if component == "" {
+			return &importParent{
+				id:   productId,
+				name: fmt.Sprintf("product \"%v\"", matches[0].Name),
+				ref:  dto.ParentIdRef{Product: &dto.IdRef{ID: productId}},
+			}, ""
+		}
+	}
+	matches := []dto.Component{}
+	for _, c := range catalog.components {
+		if strings.EqualFold(c.Name, component) && (productId == "" || catalog.productOf(c) == productId) {
+			matches = append(matches, c)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		if product != "" {
+			return nil, fmt.Sprintf("unknown component \"%v\" in product \"%v\"", component, product)
+		}
+		return nil, fmt.Sprintf("unknown component \"%v\"", component)
+	case 1:
+		return &importParent{
+			id:   matches[0].ID,
+			name: fmt.Sprintf("component \"%v\"", matches[0].Name),
+			ref:  dto.ParentIdRef{Component: &dto.IdRef{ID: matches[0].ID}},
+		}, ""
+	default:
+		return nil, fmt.Sprintf("component name \"%v\" is ambiguous, add the product to tell them apart", component)
+	}
+}
+
+// productOf walks up nested components to the product they belong to
+func (catalog *importCatalog) productOf(c dto.Component) string {
+	for i := 0; i <= len(catalog.componentsById); i++ {
+		switch {
+		case c.Parent.Product != nil:
+			return c.Parent.Product.ID
+		case c.Parent.Component != nil:
+			parent, found := catalog.componentsById[c.Parent.Component.ID]
+			if !found {
+				return ""
+			}
+			c = parent
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+func featureChanges(feature dto.Feature, name string, description string, statusId string, timeframe *dto.Timeframe) (*dto.FeatureUpdate, []string) {
+	update := dto.FeatureUpdate{}
+	changes := []string{}
+	if name != feature.Name {
+		update.Name = name
+		changes = append(changes, "name")
+	}
+	if description != "" && description != feature.Description {
+		update.Description = description
+		changes = append(changes, "description")
+	}
+	if statusId != "" && statusId != feature.Status.ID {
+		update.Status = &dto.StatusRef{ID: statusId}
+		changes = append(changes, "status")
+	}
+	if timeframe != nil && *timeframe != feature.Timeframe {
+		update.Timeframe = timeframe
+		changes = append(changes, "timeframe")
+	}
+	return &update, changes
+}
+
+func importTimeframe(start string, end string) (*dto.Timeframe, []string) {
+	start = strings.TrimSpace(start)
+	end = strings.TrimSpace(end)
+	if start == "" && end == "" {
+		return nil, nil
+	}
+	problems := []string{}
+	timeframe := dto.Timeframe{StartDate: noTimeframeDate, EndDate: noTimeframeDate}
+	var startDate, endDate time.Time
+	if start != "" {
+		date, err := time.Parse(featureDateLayout, start)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("start date \"%v\" is not a YYYY-MM-DD date", start))
+		}
+		startDate = date
+		timeframe.StartDate = start
+	}
+	if end != "" {
+		date, err := time.Parse(featureDateLayout, end)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("end date \"%v\" is not a YYYY-MM-DD date", end))
+		}
+		endDate = date
+		timeframe.EndDate = end
+	}
+	if !startDate.IsZero() && !endDate.IsZero() && endDate.Before(startDate) {
+		problems = append(problems, "end date is before start date")
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	return &timeframe, nil
+}
+
+// importDescription passes HTML through and turns plain text into a paragraph
+func importDescription(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" || strings.HasPrefix(text, "<") {
+		return text
+	}
+	return fmt.Sprintf("<p>%v</p>", textToNoteContent(text))
+}
+
+func importFeatureKey(parentId string, name string) string {
+	return fmt.Sprintf("%v/%v", parentId, strings.ToLower(strings.TrimSpace(name)))
+}
+
+// importRowKey identifies a row by its content, so progress survives reordering the file
+func importRowKey(row dto.FeatureImportRow) string {
+	raw, _ := json.Marshal(row)
+	return fmt.Sprintf("%x", sha256.Sum256(raw))
+}
+
+func readImportRows(path string) ([]dto.FeatureImportRow, api_error.ApiErr) {
+	f, err := os.Open(path)
+	if err != nil {
+		msg := fmt.Sprintf("Could not read import file %v", path)
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, err)
+	}
+	defer f.Close()
+	rows := []dto.FeatureImportRow{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		if err := json.NewDecoder(f).Decode(&rows); err != nil {
+			msg := fmt.Sprintf("Could not parse import file %v", path)
+			logger.Error(msg, err)
+			return nil, api_error.NewBadRequestError(fmt.Sprintf("%v: %v", msg, err))
+		}
+		return rows, nil
+	case ".csv":
+		return readCsvRows(path, csv.NewReader(f))
+	default:
+		msg := fmt.Sprintf("Import file %v must be a .csv or .json file", path)
+		logger.Error(msg, nil)
+		return nil, api_error.NewBadRequestError(msg)
+	}
+}
+
+func readCsvRows(path string, reader *csv.Reader) ([]dto.FeatureImportRow, api_error.ApiErr) {
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		msg := fmt.Sprintf("Could not parse import file %v", path)
+		logger.Error(msg, err)
+		return nil, api_error.NewBadRequestError(fmt.Sprintf("%v: %v", msg, err))
+	}
+	if len(records) == 0 {
+		msg := fmt.Sprintf("Import file %v is empty", path)
+		logger.Error(msg, nil)
+		return nil, api_error.NewBadRequestError(msg)
+	}
+	columns := make([]string, len(records[0]))
+	for i, header := range records[0] {
+		column := strings.ToLower(importHeader.Replace(strings.TrimSpace(strings.TrimPrefix(header, "\ufeff"))))
+		if _, known := csvField(&dto.FeatureImportRow{}, column); !known {
+			msg := fmt.Sprintf("Unknown column \"%v\" in import file %v", header, path)
+			logger.Error(msg, nil)
+			return nil, api_error.NewBadRequestError(msg)
+		}
+		columns[i] = column
+	}
+	if !misc.SliceContainsString(columns, "name") {
+		msg := fmt.Sprintf("Import file %v has no name column", path)
+		logger.Error(msg, nil)
+		return nil, api_error.NewBadRequestError(msg)
+	}
+	rows := []dto.FeatureImportRow{}
+	for _, record := range records[1:] {
+		row := dto.FeatureImportRow{}
+		for i, value := range record {
+			field, _ := csvField(&row, columns[i])
+			*field = value
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func csvField(row *dto.FeatureImportRow, column string) (*string, bool) {
+	switch column {
+	case "name":
+		return &row.Name, true
+	case "description":
+		return &row.Description, true
+	case "product":
+		return &row.Product, true
+	case "component":
+		return &row.Component, true
+	case "status":
+		return &row.Status, true
+	case "startdate":
+		return &row.StartDate, true
+	case "enddate":
+		return &row.EndDate, true
+	}
+	return nil, false
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	fis DefaultFeatureImportService
+)
+
+func setupFeatureImport(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	mockLedgerRepo = domain.NewMockImportLedgerRepository(pbApiCtrl)
+	cfg.FeatureImport.RateLimit = 1000
+	fis = NewFeatureImportService(&cfg, mockPbApiRepo, NewPbStatusService(&cfg, mockPbApiRepo), mockLedgerRepo)
+	return func() {
+		pbApiCtrl.Finish()
+	}
+}
+
+func writeImportFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	os.WriteFile(path, []byte(content), 0644)
+	return path
+}
+
+func expectImportCatalog() {
+	mockPbApiRepo.EXPECT().GetProducts().Return([]dto.Product{{ID: "p1", Name: "Portal"}, {ID: "p2", Name: "Mobile"}}, nil)
+	mockPbApiRepo.EXPECT().GetComponents().Return([]dto.Component{
+		{ID: "c1", Name: "Branding", Parent: dto.Parent{Product: &dto.ParentRef{ID: "p1"}}},
+		{ID: "c2", Name: "Branding", Parent: dto.Parent{Product: &dto.ParentRef{ID: "p2"}}},
+		{ID: "c3", Name: "Logos", Parent: dto.Parent{Component: &dto.ParentRef{ID: "c1"}}},
+	}, nil)
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).Return([]dto.Feature{
+		{ID: "f1", Name: "Dark mode", Description: "<p>Dark</p>", Status: dto.FeatureStatus{ID: "s1"}, Parent: dto.Parent{Product: &dto.ParentRef{ID: "p1"}}},
+		{ID: "f2", Name: "Custom colors", Status: dto.FeatureStatus{ID: "s2"}, Parent: dto.Parent{Component: &dto.ParentRef{ID: "c1"}}},
+	}, nil)
+	mockPbApiRepo.EXPECT().GetFeatureStatuses().Return(testStatuses(), nil).AnyTimes()
+}
+
+func Test_PlanImport_UnsupportedFile_Returns_BadRequestError(t *testing.T) {
+	teardown := setupFeatureImport(t)
+	defer teardown()
+	path := writeImportFile(t, "features.txt", "name\nDark mode\n")
+
+	plan, err := fis.PlanImport(path)
+
+	assert.Nil(t, plan)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+}
+
+func Test_PlanImport_UnknownColumn_Returns_BadRequestError(t *testing.T) {
+	teardown := setupFeatureImport(t)
+	defer teardown()
+	path := writeImportFile(t, "features.csv", "name,owner\nDark mode,jane\n")
+
+	plan, err := fis.PlanImport(path)
+
+	assert.Nil(t, plan)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Unknown column \"owner\" in import file "+path, err.Message())
+}
+
+func Test_PlanImport_Csv_Returns_Plan(t *testing.T) {
+	teardown := setupFeatureImport(t)
+	defer teardown()
+	path := writeImportFile(t, "features.csv", "Name,Description,Product,Component,Status,Start Date,End Date\n"+
+		"Dark mode,,Portal,,In progress,2024-01-01,2024-03-31\n"+
+		"Custom colors,,,Logos,New idea,,\n"+
+		"Custom colors,,Portal,Branding,In progress,,\n"+
+		"Login page,Brand the login page,Mobile,Branding,New idea,,\n")
+
+	expectImportCatalog()
+	mockLedgerRepo.EXPECT().IsImported(gomock.Any()).Return(false).Times(4)
+
+	plan, err := fis.PlanImport(path)
+
+	assert.Nil(t, err)
+	assert.Empty(t, plan.Errors)
+	assert.EqualValues(t, 4, len(plan.Actions))
+	assert.EqualValues(t, dto.ImportUpdate, plan.Actions[0].Action)
+	assert.EqualValues(t, "f1", plan.Actions[0].FeatureId)
+	assert.EqualValues(t, []string{"status", "timeframe"}, plan.Actions[0].Changes)
+	assert.EqualValues(t, &dto.Timeframe{StartDate: "2024-01-01", EndDate: "2024-03-31"}, plan.Actions[0].Update.Timeframe)
+	assert.EqualValues(t, dto.ImportCreate, plan.Actions[1].Action)
+	assert.EqualValues(t, "c3", plan.Actions[1].Create.Parent.Component.ID)
+	assert.EqualValues(t, dto.ImportUnchanged, plan.Actions[2].Action)
+	assert.EqualValues(t, dto.ImportCreate, plan.Actions[3].Action)
+	assert.EqualValues(t, "c2", plan.Actions[3].Create.Parent.Component.ID)
+	assert.EqualValues(t, "<p>Brand the login page</p>", plan.Actions[3].Create.Description)
+}
+
+func Test_PlanImport_Json_AlreadyImported_Returns_Done(t *testing.T) {
+	teardown := setupFeatureImport(t)
+	defer teardown()
+	path := writeImportFile(t, "features.json", `[{"name": "Dark mode", "product": "Portal", "status": "In progress"}]`)
+	key := importRowKey(dto.FeatureImportRow{Name: "Dark mode", Product: "Portal", Status: "In progress"})
+
+	expectImportCatalog()
+	mockLedgerRepo.EXPECT().IsImported(key).Return(true)
+
+	plan, err := fis.PlanImport(path)
+
+	assert.Nil(t, err)
+	assert.Empty(t, plan.Errors)
+	assert.EqualValues(t, 1, len(plan.Actions))
+	assert.EqualValues(t, dto.ImportDone, plan.Actions[0].Action)
+}
+
+func Test_PlanImport_InvalidRows_Collects_AllErrors(t *testing.T) {
+	teardown := setupFeatureImport(t)
+	defer teardown()
+	path := writeImportFile(t, "features.csv", "name,product,component,status,startDate,endDate\n"+
+		",Portal,,New idea,,\n"+
+		"Widgets,Desktop,,New idea,,\n"+
+		"Widgets,,Branding,New idea,,\n"+
+		"Widgets,Portal,,,,\n"+
+		"Reports,Portal,,New idea,2024-05-01,2024-02-30\n"+
+		"Reports,Portal,,New idea,2024-05-01,2024-04-01\n"+
+		"Search,Portal,,New idea,2024-01-01,\n"+
+		"Search,Portal,,New idea,2024-01-01,\n")
+
+	expectImportCatalog()
+	mockLedgerRepo.EXPECT().IsImported(gomock.Any()).Return(false).Times(2)
+
+	plan, err := fis.PlanImport(path)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{
+		"row 1: name is missing",
+		"row 2: unknown product \"Desktop\"",
+		"row 3: component name \"Branding\" is ambiguous, add the product to tell them apart",
+		"row 4: status is required for new features",
+		"row 5: end date \"2024-02-30\" is not a YYYY-MM-DD date",
+		"row 6: end date is before start date",
+		"row 8: feature \"Search\" already appears in row 7",
+	}, plan.Errors)
+	assert.EqualValues(t, &dto.Timeframe{StartDate: "2024-01-01", EndDate: "none"}, plan.Actions[0].Create.Timeframe)
+}
+
+func Test_RunImport_PlanWithErrors_Returns_BadRequestError(t *testing.T) {
+	teardown := setupFeatureImport(t)
+	defer teardown()
+	plan := dto.FeatureImportPlan{File: "features.csv", Errors: []string{"row 1: name is missing"}}
+
+	result, err := fis.RunImport(plan)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Import plan for features.csv has 1 error(s)", err.Message())
+}
+
+func Test_RunImport_Applies_Actions_And_RecordsProgress(t *testing.T) {
+	teardown := setupFeatureImport(t)
+	defer teardown()
+	create := dto.FeatureCreate{Name: "Widgets", Status: dto.StatusRef{ID: "s1"}}
+	update := dto.FeatureUpdate{Status: &dto.StatusRef{ID: "s2"}}
+	plan := dto.FeatureImportPlan{
+		File: "features.csv",
+		Actions: []dto.FeatureImportAction{
+			{Row: 1, Key: "k1", Action: dto.ImportCreate, Create: &create},
+			{Row: 2, Key: "k2", Action: dto.ImportUpdate, FeatureId: "f1", Update: &update},
+			{Row: 3, Key: "k3", Action: dto.ImportUnchanged},
+			{Row: 4, Key: "k4", Action: dto.ImportDone},
+			{Row: 5, Key: "k5", Action: dto.ImportCreate, Create: &create},
+		},
+	}
+	apiError := api_error.NewInternalServerError("something went wrong", nil)
+
+	gomock.InOrder(
+		mockPbApiRepo.EXPECT().CreateFeature(create).Return(&dto.Feature{ID: "f9"}, nil),
+		mockLedgerRepo.EXPECT().MarkImported("k1").Return(nil),
+		mockPbApiRepo.EXPECT().UpdateFeature("f1", update).Return(&dto.Feature{ID: "f1"}, nil),
+		mockLedgerRepo.EXPECT().MarkImported("k2").Return(nil),
+		mockPbApiRepo.EXPECT().CreateFeature(create).Return(nil, apiError),
+	)
+
+	result, err := fis.RunImport(plan)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, result.Created)
+	assert.EqualValues(t, 1, result.Updated)
+	assert.EqualValues(t, 2, result.Skipped)
+	assert.EqualValues(t, 1, result.Failed)
+	assert.EqualValues(t, []string{"row 5: something went wrong"}, result.Errors)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	icalLineLength  = 75
+	icalDateLayout  = "20060102"
+	icalStampLayout = "20060102T150405Z"
+)
+
+var (
+	icalEscaper = strings.NewReplacer("\\", "\\\\", ";", "\\;", ",", "\\,", "\r\n", "\\n", "\n", "\\n")
+)
+
+// icalWriter builds an iCalendar document with CRLF line endings and folded long lines (RFC 5545)
+type icalWriter struct {
+	sb strings.Builder
+}
+
+func (iw *icalWriter) line(name string, value string) {
+	line := name + ":" + value
+	for len(line) > icalLineLength {
+		cut := icalLineLength
+		for cut > 1 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		iw.sb.WriteString(line[:cut] + "\r\n")
+		line = " " + line[cut:]
+	}
+	iw.sb.WriteString(line + "\r\n")
+}
+
+func (iw *icalWriter) text(name string, value string) {
+	iw.line(name, icalEscaper.Replace(value))
+}
+
+func (iw *icalWriter) date(name string, date time.Time) {
+	iw.line(name+";VALUE=DATE", date.Format(icalDateLayout))
+}
+
+func (iw *icalWriter) stamp(name string, t time.Time) {
+	iw.line(name, t.UTC().Format(icalStampLayout))
+}
+
+func (iw *icalWriter) String() string {
+	return iw.sb.String()
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
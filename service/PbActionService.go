@@ -0,0 +1,157 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+	"github.com/johannes-kuhfuss/services_utils/misc"
+)
+
+// ActionHandler is implemented by everything that reacts to a plugin integration button.
+// Asynchronous handlers are answered with "progress" right away and run in the background.
+type ActionHandler interface {
+	HandleAction(dto.ActionNotification) (*dto.Connection, api_error.ApiErr)
+	IsAsync() bool
+}
+
+// ActionFunc adapts a plain function to the ActionHandler interface
+type ActionFunc struct {
+	Handle func(dto.ActionNotification) (*dto.Connection, api_error.ApiErr)
+	Async  bool
+}
+
+func (f ActionFunc) HandleAction(action dto.ActionNotification) (*dto.Connection, api_error.ApiErr) {
+	return f.Handle(action)
+}
+
+func (f ActionFunc) IsAsync() bool {
+	return f.Async
+}
+
+//go:generate mockgen -destination=../mocks/service/mockPbActionService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbActionService
+type PbActionService interface {
+	AddHandler(string, ActionHandler)
+	HandleAction(dto.ActionNotification) (*dto.Connection, api_error.ApiErr)
+}
+
+type DefaultPbActionService struct {
+	cfg         *config.AppConfig
+	connections PbConnectionService
+	handlers    *actionHandlers
+}
+
+type actionHandlers struct {
+	sync.RWMutex
+	byTrigger map[string]ActionHandler
+}
+
+type actionResult struct {
+	conn *dto.Connection
+	err  api_error.ApiErr
+}
+
+func NewPbActionService(c *config.AppConfig, cs PbConnectionService) DefaultPbActionService {
+	return DefaultPbActionService{
+		cfg:         c,
+		connections: cs,
+		handlers: &actionHandlers{
+			byTrigger: make(map[string]ActionHandler),
+		},
+	}
+}
+
+func (acs DefaultPbActionService) AddHandler(trigger string, h ActionHandler) {
+	acs.handlers.Lock()
+	defer acs.handlers.Unlock()
+	acs.handlers.byTrigger[trigger] = h
+}
+
+// HandleAction dispatches a button trigger to its handler. A failing handler is answered with the connection
+// in "error" state, which Productboard shows to the user. Productboard expects an answer within 5 seconds,
+// so synchronous handlers that exceed the configured timeout are answered with "progress" as well.
+// The connection is updated in Productboard once such background work completes.
+func (acs DefaultPbActionService) HandleAction(action dto.ActionNotification) (*dto.Connection, api_error.ApiErr) {
+	if !misc.SliceContainsString(dto.ActionTriggers, action.Trigger) {
+		msg := fmt.Sprintf("Unknown action trigger \"%v\"", action.Trigger)
+		logger.Error(msg, nil)
+		return nil, api_error.NewBadRequestError(msg)
+	}
+	acs.handlers.RLock()
+	h, found := acs.handlers.byTrigger[action.Trigger]
+	acs.handlers.RUnlock()
+	if !found {
+		msg := fmt.Sprintf("No handler registered for action trigger %v", action.Trigger)
+		logger.Error(msg, nil)
+		return nil, api_error.NewBadRequestError(msg)
+	}
+	results := make(chan actionResult, 1)
+	go func() {
+		conn, err := h.HandleAction(action)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Could not handle %v for feature %v", action.Trigger, action.Feature.ID), err)
+		}
+		results <- actionResult{conn: conn, err: err}
+	}()
+	if h.IsAsync() {
+		acs.connections.TrackConnection(action.Feature.ID, *progressConnection())
+		go acs.completeAction(action, results)
+		return progressConnection(), nil
+	}
+	select {
+	case result := <-results:
+		switch {
+		case result.err != nil:
+			result.conn = errorConnection(result.err)
+		case result.conn == nil:
+			// like on the async path, a handler without a connection resets the button
+			result.conn = initialConnection()
+		}
+		acs.connections.TrackConnection(action.Feature.ID, *result.conn)
+		return result.conn, nil
+	case <-time.After(time.Duration(acs.cfg.PluginIntegration.ActionTimeout) * time.Second):
+		logger.Warn(fmt.Sprintf("Handler for %v on feature %v is still running, answering with progress", action.Trigger, action.Feature.ID))
+		acs.connections.TrackConnection(action.Feature.ID, *progressConnection())
+		go acs.completeAction(action, results)
+		return progressConnection(), nil
+	}
+}
+
+func (acs DefaultPbActionService) completeAction(action dto.ActionNotification, results chan actionResult) {
+	result := <-results
+	var err api_error.ApiErr
+	switch {
+	case result.err != nil:
+		err = acs.connections.SetError(action.Feature.ID, result.err.Message())
+	case result.conn != nil:
+		err = acs.connections.UpdateConnection(action.Feature.ID, *result.conn)
+	default:
+		err = acs.connections.ResetConnection(action.Feature.ID)
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("Could not update connection of feature %v after %v", action.Feature.ID, action.Trigger), err)
+	}
+}
+
+func errorConnection(err api_error.ApiErr) *dto.Connection {
+	return &dto.Connection{
+		State:   dto.ConnectionError,
+		Message: err.Message(),
+	}
+}
+
+func initialConnection() *dto.Connection {
+	return &dto.Connection{
+		State: dto.ConnectionInitial,
+	}
+}
+
+func progressConnection() *dto.Connection {
+	return &dto.Connection{
+		State: dto.ConnectionProgress,
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/pbreact/repository"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	acs DefaultPbActionService
+)
+
+type stubActionHandler struct {
+	async   bool
+	delay   time.Duration
+	conn    *dto.Connection
+	err     api_error.ApiErr
+	handled chan dto.ActionNotification
+}
+
+func (h *stubActionHandler) HandleAction(action dto.ActionNotification) (*dto.Connection, api_error.ApiErr) {
+	time.Sleep(h.delay)
+	h.handled <- action
+	return h.conn, h.err
+}
+
+func (h *stubActionHandler) IsAsync() bool {
+	return h.async
+}
+
+func setupActions(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	cfg.PluginIntegration.ActionTimeout = 1
+	cfg.RunTime.PluginIntegrationId = "i1"
+	cs, _ = NewPbConnectionService(&cfg, mockPbApiRepo, repository.NewConnectionRepository(filepath.Join(t.TempDir(), "connections.json")))
+	acs = NewPbActionService(&cfg, cs)
+	return func() {
+		pbApiCtrl.Finish()
+	}
+}
+
+func pushAction() dto.ActionNotification {
+	return dto.ActionNotification{
+		Trigger: dto.TriggerPush,
+		Feature: dto.ActionFeature{ID: "f1"},
+	}
+}
+
+func Test_HandleAction_UnknownTrigger_Returns_BadRequestError(t *testing.T) {
+	teardown := setupActions(t)
+	defer teardown()
+
+	conn, err := acs.HandleAction(dto.ActionNotification{Trigger: "button.unknown"})
+
+	assert.Nil(t, conn)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Unknown action trigger \"button.unknown\"", err.Message())
+}
+
+func Test_HandleAction_NoHandler_Returns_BadRequestError(t *testing.T) {
+	teardown := setupActions(t)
+	defer teardown()
+
+	conn, err := acs.HandleAction(pushAction())
+
+	assert.Nil(t, conn)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "No handler registered for action trigger button.push", err.Message())
+}
+
+func Test_HandleAction_SyncHandler_Returns_HandlerConnection(t *testing.T) {
+	teardown := setupActions(t)
+	defer teardown()
+	h := stubActionHandler{conn: &dto.Connection{State: dto.ConnectionConnected, Label: "PB-1"}, handled: make(chan dto.ActionNotification, 1)}
+	acs.AddHandler(dto.TriggerPush, &h)
+
+	conn, err := acs.HandleAction(pushAction())
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "PB-1", conn.Label)
+	assert.EqualValues(t, "f1", (<-h.handled).Feature.ID)
+	assert.EqualValues(t, *conn, cs.GetConnection("f1"))
+}
+
+func Test_HandleAction_SyncHandlerNoConnection_Returns_InitialConnection(t *testing.T) {
+	teardown := setupActions(t)
+	defer teardown()
+	h := stubActionHandler{handled: make(chan dto.ActionNotification, 1)}
+	acs.AddHandler(dto.TriggerPush, &h)
+
+	conn, err := acs.HandleAction(pushAction())
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, dto.Connection{State: dto.ConnectionInitial}, *conn)
+	assert.EqualValues(t, *conn, cs.GetConnection("f1"))
+}
+
+func Test_HandleAction_SyncHandlerError_Returns_ErrorConnection(t *testing.T) {
+	teardown := setupActions(t)
+	defer teardown()
+	apiError := api_error.NewInternalServerError("something went wrong", nil)
+	h := stubActionHandler{err: apiError, handled: make(chan dto.ActionNotification, 1)}
+	acs.AddHandler(dto.TriggerPush, &h)
+
+	conn, err := acs.HandleAction(pushAction())
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, dto.Connection{State: dto.ConnectionError, Message: "something went wrong"}, *conn)
+	assert.EqualValues(t, *conn, cs.GetConnection("f1"))
+}
+
+func Test_HandleAction_AsyncHandler_Returns_Progress_And_UpdatesConnection(t *testing.T) {
+	teardown := setupActions(t)
+	defer teardown()
+	connected := dto.Connection{State: dto.ConnectionConnected, Label: "PB-1"}
+	h := stubActionHandler{async: true, conn: &connected, handled: make(chan dto.ActionNotification, 1)}
+	acs.AddHandler(dto.TriggerPush, &h)
+	updated := make(chan bool)
+	mockPbApiRepo.EXPECT().UpdatePluginConnection("i1", "f1", connected).DoAndReturn(func(string, string, dto.Connection) (*dto.ConnectionData, api_error.ApiErr) {
+		updated <- true
+		return &dto.ConnectionData{Connection: connected}, nil
+	})
+
+	conn, err := acs.HandleAction(pushAction())
+	<-updated
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, dto.ConnectionProgress, conn.State)
+	assert.EqualValues(t, "f1", (<-h.handled).Feature.ID)
+}
+
+func Test_HandleAction_AsyncHandlerError_Sets_ErrorConnection(t *testing.T) {
+	teardown := setupActions(t)
+	defer teardown()
+	h := stubActionHandler{async: true, err: api_error.NewInternalServerError("tracker unavailable", nil), handled: make(chan dto.ActionNotification, 1)}
+	acs.AddHandler(dto.TriggerPush, &h)
+	updated := make(chan bool)
+	expected := dto.Connection{State: dto.ConnectionError, Message: "tracker unavailable"}
+	mockPbApiRepo.EXPECT().UpdatePluginConnection("i1", "f1", expected).DoAndReturn(func(string, string, dto.Connection) (*dto.ConnectionData, api_error.ApiErr) {
+		updated <- true
+		return &dto.ConnectionData{Connection: expected}, nil
+	})
+
+	conn, err := acs.HandleAction(pushAction())
+	<-updated
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, dto.ConnectionProgress, conn.State)
+}
+
+func Test_HandleAction_SlowSyncHandler_Returns_Progress(t *testing.T) {
+	teardown := setupActions(t)
+	defer teardown()
+	h := stubActionHandler{delay: 1500 * time.Millisecond, handled: make(chan dto.ActionNotification, 1)}
+	acs.AddHandler(dto.TriggerPush, &h)
+	updated := make(chan bool)
+	mockPbApiRepo.EXPECT().DeletePluginConnection("i1", "f1").DoAndReturn(func(string, string) api_error.ApiErr {
+		updated <- true
+		return nil
+	})
+
+	conn, err := acs.HandleAction(pushAction())
+	<-updated
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, dto.ConnectionProgress, conn.State)
+}
+
+func Test_HandleAction_SlowSyncHandlerError_Sets_ErrorConnection(t *testing.T) {
+	teardown := setupActions(t)
+	defer teardown()
+	h := stubActionHandler{delay: 1500 * time.Millisecond, err: api_error.NewBadRequestError("feature has no title"), handled: make(chan dto.ActionNotification, 1)}
+	acs.AddHandler(dto.TriggerPush, &h)
+	updated := make(chan bool)
+	expected := dto.Connection{State: dto.ConnectionError, Message: "feature has no title"}
+	mockPbApiRepo.EXPECT().UpdatePluginConnection("i1", "f1", expected).DoAndReturn(func(string, string, dto.Connection) (*dto.ConnectionData, api_error.ApiErr) {
+		updated <- true
+		return &dto.ConnectionData{Connection: expected}, nil
+	})
+
+	conn, err := acs.HandleAction(pushAction())
+	<-updated
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, dto.ConnectionProgress, conn.State)
+}
@@ -1,9 +1,12 @@
 package service
 
 import (
+	"fmt"
+
 	"github.com/gofrs/uuid"
 	"github.com/johannes-kuhfuss/pbreact/config"
 	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
 	"github.com/johannes-kuhfuss/services_utils/api_error"
 	"github.com/johannes-kuhfuss/services_utils/logger"
 )
@@ -13,6 +16,7 @@ type PbApiService interface {
 	RegisterForNotifications() api_error.ApiErr
 	UnregisterForNotifications() api_error.ApiErr
 	GenerateSessionApiToken() api_error.ApiErr
+	RegisterPluginIntegration() (*dto.PluginIntegration, api_error.ApiErr)
 }
 
 type DefaultPbApiService struct {
@@ -61,3 +65,55 @@ func (as DefaultPbApiService) UnregisterForNotifications() api_error.ApiErr {
 	}
 	return nil
 }
+
+// RegisterPluginIntegration creates the configured plugin integration or updates an existing one of the same type
+func (as DefaultPbApiService) RegisterPluginIntegration() (*dto.PluginIntegration, api_error.ApiErr) {
+	pluginCfg := as.cfg.PluginIntegration
+	if pluginCfg.Type == "" || pluginCfg.ActionUrl == "" {
+		msg := "Plugin integration type and action url must be configured"
+		logger.Error(msg, nil)
+		return nil, api_error.NewBadRequestError(msg)
+	}
+	integrations, err := as.repo.GetPluginIntegrations()
+	if err != nil {
+		return nil, err
+	}
+	data := dto.PluginIntegrationData{
+		IntegrationStatus: dto.IntegrationEnabled,
+		Name:              pluginCfg.Name,
+		InitialState: &dto.InitialState{
+			Label: pluginCfg.ButtonLabel,
+		},
+		Action: &dto.PluginAction{
+			URL:     pluginCfg.ActionUrl,
+			Version: 1,
+			Headers: &dto.Headers{
+				Authorization: as.cfg.RunTime.CallbackAuthToken,
+			},
+		},
+	}
+	var integration *dto.PluginIntegration
+	existing := findPluginIntegration(integrations, pluginCfg.Type)
+	if existing != nil {
+		logger.Info(fmt.Sprintf("Updating plugin integration %v", existing.ID))
+		integration, err = as.repo.UpdatePluginIntegration(existing.ID, data)
+	} else {
+		logger.Info(fmt.Sprintf("Creating plugin integration of type %v", pluginCfg.Type))
+		data.Type = pluginCfg.Type
+		integration, err = as.repo.CreatePluginIntegration(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	as.cfg.RunTime.PluginIntegrationId = integration.ID
+	return integration, nil
+}
+
+func findPluginIntegration(integrations []dto.PluginIntegration, integrationType string) *dto.PluginIntegration {
+	for i := range integrations {
+		if integrations[i].Type == integrationType {
+			return &integrations[i]
+		}
+	}
+	return nil
+}
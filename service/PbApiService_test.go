@@ -118,3 +118,53 @@ func Test_UnregisterForNotifications_Returns_NoError(t *testing.T) {
 
 	assert.Nil(t, err)
 }
+
+func Test_RegisterPluginIntegration_NotConfigured_Returns_BadRequestError(t *testing.T) {
+	teardown := setupApi(t)
+	defer teardown()
+	cfg.PluginIntegration.Type = ""
+
+	integration, err := as.RegisterPluginIntegration()
+
+	assert.Nil(t, integration)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Plugin integration type and action url must be configured", err.Message())
+}
+
+func Test_RegisterPluginIntegration_NoneExisting_Creates_Integration(t *testing.T) {
+	teardown := setupApi(t)
+	defer teardown()
+	cfg.PluginIntegration.Type = "com.example.pbreact"
+	cfg.PluginIntegration.ActionUrl = "https://example.com/pbplugin"
+	cfg.RunTime.CallbackAuthToken = "token"
+
+	mockPbApiRepo.EXPECT().GetPluginIntegrations().Return([]dto.PluginIntegration{{ID: "other", Type: "com.example.other"}}, nil)
+	mockPbApiRepo.EXPECT().CreatePluginIntegration(gomock.Any()).DoAndReturn(func(data dto.PluginIntegrationData) (*dto.PluginIntegration, api_error.ApiErr) {
+		assert.EqualValues(t, "com.example.pbreact", data.Type)
+		assert.EqualValues(t, "token", data.Action.Headers.Authorization)
+		return &dto.PluginIntegration{ID: "i1"}, nil
+	})
+
+	integration, err := as.RegisterPluginIntegration()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "i1", integration.ID)
+	assert.EqualValues(t, "i1", cfg.RunTime.PluginIntegrationId)
+}
+
+func Test_RegisterPluginIntegration_Existing_Updates_Integration(t *testing.T) {
+	teardown := setupApi(t)
+	defer teardown()
+	cfg.PluginIntegration.Type = "com.example.pbreact"
+	cfg.PluginIntegration.ActionUrl = "https://example.com/pbplugin"
+	apiError := api_error.NewInternalServerError("something went wrong", nil)
+
+	mockPbApiRepo.EXPECT().GetPluginIntegrations().Return([]dto.PluginIntegration{{ID: "i1", Type: "com.example.pbreact"}}, nil)
+	mockPbApiRepo.EXPECT().UpdatePluginIntegration("i1", gomock.Any()).Return(nil, apiError)
+
+	integration, err := as.RegisterPluginIntegration()
+
+	assert.Nil(t, integration)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, apiError.Message(), err.Message())
+}
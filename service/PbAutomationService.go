@@ -0,0 +1,355 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+const (
+	automationWriteWindow = time.Hour
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbAutomationService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbAutomationService
+type PbAutomationService interface {
+	HandleFeatureEvent(dto.FeatureEvent)
+	CheckAutomations() api_error.ApiErr
+	StatusNames() []string
+	ScheduleChecks()
+	StopChecks()
+}
+
+// DefaultPbAutomationService applies automations to features. Every update causes another feature.updated webhook,
+// so three guards keep automations from looping: the notification of an own write is recognized and ignored, updates
+// that would not change anything are not sent, and every feature is only written a limited number of times per hour.
+type DefaultPbAutomationService struct {
+	repo        domain.PbApiRepository
+	journal     domain.FeatureJournalRepository
+	flow        PbFlowService
+	hierarchy   PbHierarchyService
+	statuses    PbStatusService
+	cfg         *config.AppConfig
+	automations []dto.Automation
+	seen        *seenFeatures
+	echoes      *echoGuard
+	writes      *automationWrites
+	done        chan bool
+	stop        *sync.Once
+}
+
+type automationWrites struct {
+	sync.Mutex
+	times map[string][]time.Time
+}
+
+// NewPbAutomationService loads and validates the automations and the feature states seen before the last restart.
+// Without a rules file no feature is changed.
+func NewPbAutomationService(c *config.AppConfig, r domain.PbApiRepository, j domain.FeatureJournalRepository, fl PbFlowService, h PbHierarchyService, s PbStatusService, f domain.FeatureCacheRepository) (DefaultPbAutomationService, api_error.ApiErr) {
+	as := DefaultPbAutomationService{
+		repo:      r,
+		journal:   j,
+		flow:      fl,
+		hierarchy: h,
+		statuses:  s,
+		cfg:       c,
+		seen:      newSeenFeatures(),
+		echoes:    newEchoGuard(time.Duration(c.Automation.EchoWindow) * time.Second),
+		writes: &automationWrites{
+			times: make(map[string][]time.Time),
+		},
+		done: make(chan bool),
+		stop: &sync.Once{},
+	}
+	if c.Automation.RulesFile == "" {
+		return as, nil
+	}
+	automations, err := loadAutomations(c.Automation.RulesFile)
+	if err != nil {
+		msg := fmt.Sprintf("Could not load automations from %v", c.Automation.RulesFile)
+		logger.Error(msg, err)
+		return as, api_error.NewInternalServerError(msg, err)
+	}
+	as.automations = automations
+	logger.Info(fmt.Sprintf("Loaded %v automation(s)", len(automations)))
+	seen, loadErr := f.Load()
+	if loadErr != nil {
+		return as, loadErr
+	}
+	as.seen.features = seen
+	as.seen.cache = f
+	return as, nil
+}
+
+func loadAutomations(file string) ([]dto.Automation, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var automations []dto.Automation
+	if err := json.Unmarshal(content, &automations); err != nil {
+		return nil, err
+	}
+	for i := range automations {
+		a := &automations[i]
+		if a.Name == "" {
+			a.Name = fmt.Sprintf("automation %v", i+1)
+		}
+		if a.MoveTo != "" {
+			return nil, fmt.Errorf("%v uses moveTo, but features cannot be moved through the Productboard API", a.Name)
+		}
+		if a.SetStatus == "" && !a.Archive && a.Timeframe == nil && a.ShiftTimeframeDays == 0 && a.NamePrefix == "" {
+			return nil, fmt.Errorf("%v has no action", a.Name)
+		}
+		if a.AfterDays < 0 {
+			return nil, fmt.Errorf("%v has negative afterDays", a.Name)
+		}
+		if a.AfterDays > 0 && len(a.Statuses) == 0 {
+			return nil, fmt.Errorf("%v needs statuses to count the days in", a.Name)
+		}
+	}
+	return automations, nil
+}
+
+// StatusNames returns the status names used by automations, so they can be validated on startup
+func (as DefaultPbAutomationService) StatusNames() []string {
+	names := []string{}
+	seen := make(map[string]bool)
+	for _, a := range as.automations {
+		for _, name := range append([]string{a.SetStatus}, a.Statuses...) {
+			if key := strings.ToLower(name); name != "" && !seen[key] {
+				seen[key] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// HandleFeatureEvent runs the event automations matching the event, combined into a single update
+func (as DefaultPbAutomationService) HandleFeatureEvent(event dto.FeatureEvent) {
+	if len(as.automations) == 0 || event.Feature == nil {
+		return
+	}
+	data, found := as.seen.observe(event)
+	if !found {
+		return
+	}
+	if as.echoes.isEcho(event.ID, automationState(*event.Feature)) {
+		logger.Info(fmt.Sprintf("Ignoring echo of automation update to feature %v", event.ID))
+		return
+	}
+	path := hierarchyPath(as.hierarchy, event.Feature.Parent.ParentId())
+	matching := []dto.Automation{}
+	for _, a := range as.automations {
+		if a.AfterDays == 0 && filterMatches(a.EventFilter, *data, path) {
+			matching = append(matching, a)
+		}
+	}
+	if err := as.apply(*event.Feature, matching); err != nil {
+		logger.Error(fmt.Sprintf("Could not run automations on feature %v", event.ID), err)
+	}
+}
+
+// CheckAutomations runs the automations with AfterDays on all features that have been in a matching status long enough.
+// Features without a status in the journal have it recorded first, so their days are counted from the first check.
+func (as DefaultPbAutomationService) CheckAutomations() api_error.ApiErr {
+	timed := []dto.Automation{}
+	for _, a := range as.automations {
+		if a.AfterDays > 0 {
+			timed = append(timed, a)
+		}
+	}
+	if len(timed) == 0 {
+		return nil
+	}
+	features, err := as.repo.GetFeatures(dto.FeatureFilter{})
+	if err != nil {
+		return err
+	}
+	if err := as.flow.RecordStatuses(features); err != nil {
+		return err
+	}
+	entries, err := as.journal.Read(time.Time{})
+	if err != nil {
+		return err
+	}
+	since := make(map[string]dto.JournalEntry)
+	for _, entry := range entries {
+		if entry.Kind == dto.JournalStatus {
+			since[entry.FeatureId] = entry
+		}
+	}
+	now := date.GetNowUtc()
+	var firstErr api_error.ApiErr
+	for _, feature := range features {
+		entry, found := since[feature.ID]
+		if !found || !strings.EqualFold(entry.Status, feature.Status.Name) {
+			continue
+		}
+		data := dto.NotifyData{Feature: feature}
+		path := hierarchyPath(as.hierarchy, feature.Parent.ParentId())
+		matching := []dto.Automation{}
+		for _, a := range timed {
+			filter := dto.EventFilter{Statuses: a.Statuses, Products: a.Products}
+			if now.Sub(entry.Time) >= time.Duration(a.AfterDays)*24*time.Hour && filterMatches(filter, data, path) {
+				matching = append(matching, a)
+			}
+		}
+		if err := as.apply(feature, matching); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (as DefaultPbAutomationService) ScheduleChecks() {
+	interval := time.Duration(as.cfg.Automation.CheckInterval) * time.Minute
+	logger.Info(fmt.Sprintf("Checking timed automations every %v", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := as.CheckAutomations(); err != nil {
+			logger.Error("Could not check timed automations", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-as.done:
+			logger.Info("Stopped checking timed automations")
+			return
+		}
+	}
+}
+
+func (as DefaultPbAutomationService) StopChecks() {
+	as.stop.Do(func() {
+		close(as.done)
+	})
+}
+
+// apply plans the actions of all automations on the feature and sends them as one update, unless nothing changes
+func (as DefaultPbAutomationService) apply(feature dto.Feature, automations []dto.Automation) api_error.ApiErr {
+	target := feature
+	update := dto.FeatureUpdate{}
+	names := []string{}
+	for _, a := range automations {
+		changed, err := as.plan(a, &target, &update)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Skipping automation \"%v\" on feature %v", a.Name, feature.ID), err)
+			continue
+		}
+		if changed {
+			names = append(names, a.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	if !as.allowWrite(feature.ID) {
+		msg := fmt.Sprintf("Feature %v was already updated %v time(s) by automations within %v. Not running %v", feature.ID, as.cfg.Automation.MaxWrites, automationWriteWindow, strings.Join(names, ", "))
+		logger.Error(msg, nil)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	if as.cfg.Automation.DryRun {
+		planned, _ := json.Marshal(update)
+		logger.Info(fmt.Sprintf("Dry run: automations %v would update feature %v with %v", strings.Join(names, ", "), feature.ID, string(planned)))
+		return nil
+	}
+	as.echoes.expect(feature.ID, automationState(target))
+	if _, err := as.repo.UpdateFeature(feature.ID, update); err != nil {
+		as.echoes.forget(feature.ID)
+		return err
+	}
+	logger.Info(fmt.Sprintf("Automations %v updated feature %v", strings.Join(names, ", "), feature.ID))
+	return nil
+}
+
+// plan adds the actions of an automation that would change the target to the update and applies them to the target
+func (as DefaultPbAutomationService) plan(a dto.Automation, target *dto.Feature, update *dto.FeatureUpdate) (bool, api_error.ApiErr) {
+	changed := false
+	if a.SetStatus != "" && !strings.EqualFold(target.Status.Name, a.SetStatus) {
+		id, err := as.statuses.GetStatusId(a.SetStatus)
+		if err != nil {
+			return false, err
+		}
+		update.Status = &dto.StatusRef{ID: id}
+		target.Status = dto.FeatureStatus{ID: id, Name: a.SetStatus}
+		changed = true
+	}
+	if a.Archive && !target.Archived {
+		archived := true
+		update.Archived = &archived
+		target.Archived = true
+		changed = true
+	}
+	timeframe := target.Timeframe
+	if a.Timeframe != nil {
+		timeframe = *a.Timeframe
+	}
+	if a.ShiftTimeframeDays != 0 {
+		timeframe = dto.Timeframe{
+			StartDate: shiftDate(timeframe.StartDate, a.ShiftTimeframeDays),
+			EndDate:   shiftDate(timeframe.EndDate, a.ShiftTimeframeDays),
+		}
+	}
+	if timeframe != target.Timeframe {
+		update.Timeframe = &timeframe
+		target.Timeframe = timeframe
+		changed = true
+	}
+	if a.NamePrefix != "" && !strings.HasPrefix(target.Name, a.NamePrefix) {
+		update.Name = a.NamePrefix + target.Name
+		target.Name = update.Name
+		changed = true
+	}
+	return changed, nil
+}
+
+// allowWrite records a write to the feature unless the feature already had the maximum number of writes in the window
+func (as DefaultPbAutomationService) allowWrite(featureId string) bool {
+	as.writes.Lock()
+	defer as.writes.Unlock()
+	now := date.GetNowUtc()
+	recent := []time.Time{}
+	for _, t := range as.writes.times[featureId] {
+		if now.Sub(t) < automationWriteWindow {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= as.cfg.Automation.MaxWrites {
+		as.writes.times[featureId] = recent
+		return false
+	}
+	as.writes.times[featureId] = append(recent, now)
+	return true
+}
+
+// automationState covers the fields automations write, to recognize the notification caused by an update
+func automationState(feature dto.Feature) string {
+	return strings.Join([]string{
+		feature.Name,
+		strings.ToLower(feature.Status.Name),
+		strconv.FormatBool(feature.Archived),
+		exportDate(feature.Timeframe.StartDate),
+		exportDate(feature.Timeframe.EndDate),
+	}, "\n")
+}
+
+// shiftDate moves a timeframe date by days. Dates that are not set stay unset.
+func shiftDate(value string, days int) string {
+	d, err := time.Parse(featureDateLayout, value)
+	if err != nil {
+		return value
+	}
+	return d.AddDate(0, 0, days).Format(featureDateLayout)
+}
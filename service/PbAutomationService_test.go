@@ -0,0 +1,253 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/pbreact/repository"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	aus         DefaultPbAutomationService
+	automations = `[
+	{"name": "Release prefix", "events": ["updated"], "statuses": ["Released"], "changed": ["status"], "namePrefix": "[GA] "},
+	{"name": "Close released", "statuses": ["Released"], "changed": ["status"], "setStatus": "Done"},
+	{"name": "Postpone", "events": ["updated"], "statuses": ["Later"], "shiftTimeframeDays": 7},
+	{"name": "Archive won't do", "statuses": ["Won't do"], "afterDays": 30, "archive": true}
+]`
+)
+
+func setupAutomation(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	mockJournalRepo = domain.NewMockFeatureJournalRepository(pbApiCtrl)
+	cfg.Automation.RulesFile = filepath.Join(t.TempDir(), "automations.json")
+	cfg.Automation.EchoWindow = 60
+	cfg.Automation.MaxWrites = 3
+	cfg.Automation.DryRun = false
+	os.WriteFile(cfg.Automation.RulesFile, []byte(automations), 0644)
+	aus, _ = newAutomationService(t.TempDir())
+	return func() {
+		cfg.Automation.RulesFile = ""
+		pbApiCtrl.Finish()
+	}
+}
+
+func newAutomationService(dir string) (DefaultPbAutomationService, api_error.ApiErr) {
+	hierarchy := NewPbHierarchyService(&cfg, mockPbApiRepo)
+	return NewPbAutomationService(&cfg, mockPbApiRepo, mockJournalRepo, NewPbFlowService(&cfg, mockJournalRepo, hierarchy), hierarchy, NewPbStatusService(&cfg, mockPbApiRepo), repository.NewFeatureCacheRepository(filepath.Join(dir, "seen.json")))
+}
+
+func expectAutomationHierarchy(features []dto.Feature) {
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).Return(features, nil).AnyTimes()
+	mockPbApiRepo.EXPECT().GetProducts().Return([]dto.Product{{ID: "p1", Name: "Portal"}}, nil)
+	mockPbApiRepo.EXPECT().GetComponents().Return([]dto.Component{
+		{ID: "c1", Name: "Branding", Parent: dto.Parent{Product: &dto.ParentRef{ID: "p1"}}},
+		{ID: "c2", Name: "Shipped", Parent: dto.Parent{Product: &dto.ParentRef{ID: "p1"}}},
+	}, nil)
+}
+
+func automationFeature(status string, timeframe dto.Timeframe) dto.Feature {
+	return dto.Feature{ID: "f1", Name: "Dark mode", Status: dto.FeatureStatus{Name: status},
+		Parent: dto.Parent{Component: &dto.ParentRef{ID: "c1"}}, Timeframe: timeframe}
+}
+
+func Test_HandleFeatureEvent_Combines_Actions_And_Ignores_Echo(t *testing.T) {
+	teardown := setupAutomation(t)
+	defer teardown()
+	before, after := automationFeature("In progress", dto.Timeframe{}), automationFeature("Released", dto.Timeframe{})
+	echo := after
+	echo.Name = "[GA] Dark mode"
+	echo.Status = dto.FeatureStatus{ID: "s3", Name: "Done"}
+
+	expectAutomationHierarchy([]dto.Feature{})
+	mockPbApiRepo.EXPECT().GetFeatureStatuses().Return([]dto.FeatureStatus{{ID: "s2", Name: "Released"}, {ID: "s3", Name: "Done"}}, nil)
+	mockPbApiRepo.EXPECT().UpdateFeature("f1", dto.FeatureUpdate{
+		Name:   "[GA] Dark mode",
+		Status: &dto.StatusRef{ID: "s3"},
+	}).Return(&echo, nil)
+
+	aus.HandleFeatureEvent(notifyEvent("featureCreate", &before))
+	aus.HandleFeatureEvent(notifyEvent("featureUpdate", &after))
+	aus.HandleFeatureEvent(notifyEvent("featureUpdate", &echo))
+}
+
+func Test_HandleFeatureEvent_NothingToChange_Sends_NoUpdate(t *testing.T) {
+	teardown := setupAutomation(t)
+	defer teardown()
+	before, after := automationFeature("In progress", dto.Timeframe{}), automationFeature("Released", dto.Timeframe{})
+	after.Name = "[GA] Dark mode"
+	cfg.Automation.RulesFile = filepath.Join(t.TempDir(), "prefix.json")
+	os.WriteFile(cfg.Automation.RulesFile, []byte(`[{"statuses": ["Released"], "namePrefix": "[GA] "}]`), 0644)
+	aus, _ = newAutomationService(t.TempDir())
+
+	expectAutomationHierarchy([]dto.Feature{})
+
+	aus.HandleFeatureEvent(notifyEvent("featureCreate", &before))
+	aus.HandleFeatureEvent(notifyEvent("featureUpdate", &after))
+}
+
+func Test_HandleFeatureEvent_ShiftEcho_And_WriteLimit_Stop_Loop(t *testing.T) {
+	teardown := setupAutomation(t)
+	defer teardown()
+	cfg.Automation.MaxWrites = 2
+	feature := automationFeature("Later", dto.Timeframe{StartDate: "2024-01-01", EndDate: "none"})
+	echo := automationFeature("Later", dto.Timeframe{StartDate: "2024-01-08", EndDate: "none"})
+	renamed := automationFeature("Later", dto.Timeframe{StartDate: "2024-01-10", EndDate: "none"})
+	renamed.Name = "Dark theme"
+	renamedAgain := automationFeature("Later", dto.Timeframe{StartDate: "2024-01-15", EndDate: "none"})
+
+	expectAutomationHierarchy([]dto.Feature{})
+	first := mockPbApiRepo.EXPECT().UpdateFeature("f1", dto.FeatureUpdate{Timeframe: &dto.Timeframe{StartDate: "2024-01-08", EndDate: "none"}}).Return(&echo, nil)
+	mockPbApiRepo.EXPECT().UpdateFeature("f1", dto.FeatureUpdate{Timeframe: &dto.Timeframe{StartDate: "2024-01-17", EndDate: "none"}}).Return(nil, nil).After(first)
+
+	aus.HandleFeatureEvent(notifyEvent("featureUpdate", &feature))
+	aus.HandleFeatureEvent(notifyEvent("featureUpdate", &echo))
+	aus.HandleFeatureEvent(notifyEvent("featureUpdate", &renamed))
+	aus.HandleFeatureEvent(notifyEvent("featureUpdate", &renamedAgain))
+}
+
+func Test_CheckAutomations_Archives_After_Days_In_Status(t *testing.T) {
+	teardown := setupAutomation(t)
+	defer teardown()
+	now := date.GetNowUtc()
+	stale, recent, reopened := automationFeature("Won't do", dto.Timeframe{}), automationFeature("Won't do", dto.Timeframe{}), automationFeature("In progress", dto.Timeframe{})
+	recent.ID, reopened.ID = "f2", "f3"
+	archived := true
+
+	expectAutomationHierarchy([]dto.Feature{stale, recent, reopened})
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return([]dto.JournalEntry{
+		{Time: now.AddDate(0, 0, -31), FeatureId: "f1", Kind: dto.JournalStatus, Status: "Won't do"},
+		{Time: now.AddDate(0, 0, -40), FeatureId: "f2", Kind: dto.JournalStatus, Status: "Won't do"},
+		{Time: now.AddDate(0, 0, -10), FeatureId: "f2", Kind: dto.JournalStatus, Status: "Won't do", OldStatus: "New idea"},
+		{Time: now.AddDate(0, 0, -40), FeatureId: "f3", Kind: dto.JournalStatus, Status: "Won't do"},
+	}, nil).Times(2)
+	mockJournalRepo.EXPECT().Append(gomock.Any()).DoAndReturn(func(entry dto.JournalEntry) api_error.ApiErr {
+		assert.EqualValues(t, "f3", entry.FeatureId)
+		assert.EqualValues(t, "In progress", entry.Status)
+		assert.EqualValues(t, "Won't do", entry.OldStatus)
+		return nil
+	})
+	mockPbApiRepo.EXPECT().UpdateFeature("f1", dto.FeatureUpdate{Archived: &archived}).Return(nil, nil)
+
+	err := aus.CheckAutomations()
+
+	assert.Nil(t, err)
+}
+
+func Test_CheckAutomations_DryRun_Sends_NoUpdate(t *testing.T) {
+	teardown := setupAutomation(t)
+	defer teardown()
+	cfg.Automation.DryRun = true
+	stale := automationFeature("Won't do", dto.Timeframe{})
+
+	expectAutomationHierarchy([]dto.Feature{stale})
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return([]dto.JournalEntry{
+		{Time: date.GetNowUtc().AddDate(0, 0, -31), FeatureId: "f1", Kind: dto.JournalStatus, Status: "Won't do"},
+	}, nil).Times(2)
+
+	err := aus.CheckAutomations()
+
+	assert.Nil(t, err)
+}
+
+func Test_CheckAutomations_UnknownFeature_Seeds_Journal(t *testing.T) {
+	teardown := setupAutomation(t)
+	defer teardown()
+	stale := automationFeature("Won't do", dto.Timeframe{})
+	var seeded dto.JournalEntry
+
+	expectAutomationHierarchy([]dto.Feature{stale})
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return([]dto.JournalEntry{}, nil)
+	mockJournalRepo.EXPECT().Append(gomock.Any()).DoAndReturn(func(entry dto.JournalEntry) api_error.ApiErr {
+		seeded = entry
+		return nil
+	})
+	mockJournalRepo.EXPECT().Read(time.Time{}).DoAndReturn(func(time.Time) ([]dto.JournalEntry, api_error.ApiErr) {
+		return []dto.JournalEntry{seeded}, nil
+	})
+
+	err := aus.CheckAutomations()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "f1", seeded.FeatureId)
+	assert.EqualValues(t, dto.JournalStatus, seeded.Kind)
+	assert.EqualValues(t, "Won't do", seeded.Status)
+	assert.EqualValues(t, "", seeded.OldStatus)
+	assert.WithinDuration(t, date.GetNowUtc(), seeded.Time, time.Minute)
+}
+
+func Test_HandleFeatureEvent_AfterRestart_Knows_SeenFeatures_Automation(t *testing.T) {
+	teardown := setupAutomation(t)
+	defer teardown()
+	dir := t.TempDir()
+	before, after := automationFeature("In progress", dto.Timeframe{}), automationFeature("Released", dto.Timeframe{})
+
+	expectAutomationHierarchy([]dto.Feature{})
+	mockPbApiRepo.EXPECT().GetFeatureStatuses().Return([]dto.FeatureStatus{{ID: "s3", Name: "Done"}}, nil)
+	mockPbApiRepo.EXPECT().UpdateFeature("f1", dto.FeatureUpdate{Name: "[GA] Dark mode", Status: &dto.StatusRef{ID: "s3"}}).Return(nil, nil)
+
+	first, _ := newAutomationService(dir)
+	first.HandleFeatureEvent(notifyEvent("featureUpdate", &before))
+	expectAutomationHierarchy([]dto.Feature{})
+	restarted, err := newAutomationService(dir)
+	restarted.HandleFeatureEvent(notifyEvent("featureUpdate", &after))
+
+	assert.Nil(t, err)
+}
+
+func Test_NewPbAutomationService_NoAction_Returns_Error(t *testing.T) {
+	teardown := setupAutomation(t)
+	defer teardown()
+	os.WriteFile(cfg.Automation.RulesFile, []byte(`[{"name": "Nothing", "statuses": ["Released"]}]`), 0644)
+
+	_, err := newAutomationService(t.TempDir())
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Could not load automations from "+cfg.Automation.RulesFile, err.Message())
+}
+
+func Test_NewPbAutomationService_MoveTo_Returns_Error(t *testing.T) {
+	teardown := setupAutomation(t)
+	defer teardown()
+	os.WriteFile(cfg.Automation.RulesFile, []byte(`[{"name": "Ship", "statuses": ["Released"], "moveTo": "c2"}]`), 0644)
+
+	_, err := newAutomationService(t.TempDir())
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Could not load automations from "+cfg.Automation.RulesFile, err.Message())
+}
+
+func Test_HandleFeatureEvent_UnappliedAction_DoesNot_Refire(t *testing.T) {
+	teardown := setupAutomation(t)
+	defer teardown()
+	cfg.Automation.RulesFile = filepath.Join(t.TempDir(), "unknown.json")
+	os.WriteFile(cfg.Automation.RulesFile, []byte(`[{"statuses": ["Released"], "setStatus": "Shipped"}]`), 0644)
+	cfg.Statuses.MissRefreshInterval = 300
+	defer func() { cfg.Statuses.MissRefreshInterval = 0 }()
+	aus, _ = newAutomationService(t.TempDir())
+	feature := automationFeature("Released", dto.Timeframe{})
+	renamed := feature
+	renamed.Name = "Dark theme"
+
+	expectAutomationHierarchy([]dto.Feature{})
+	mockPbApiRepo.EXPECT().GetFeatureStatuses().Return([]dto.FeatureStatus{{ID: "s2", Name: "Released"}}, nil).Times(2)
+
+	aus.HandleFeatureEvent(notifyEvent("featureUpdate", &feature))
+	aus.HandleFeatureEvent(notifyEvent("featureUpdate", &renamed))
+}
+
+func Test_StatusNames_Returns_DistinctNames(t *testing.T) {
+	teardown := setupAutomation(t)
+	defer teardown()
+
+	assert.EqualValues(t, []string{"Released", "Done", "Later", "Won't do"}, aus.StatusNames())
+}
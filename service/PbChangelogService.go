@@ -0,0 +1,356 @@
+package service
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+const (
+	// maxCatchUpWindows limits how many windows missed during downtime are published at once
+	maxCatchUpWindows = 12
+)
+
+var (
+	markdownText = strings.NewReplacer("\\", "\\\\", "*", "\\*", "_", "\\_", "`", "\\`", "[", "\\[", "]", "\\]", "<", "\\<", ">", "\\>", "#", "\\#", "|", "\\|")
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbChangelogService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbChangelogService
+type PbChangelogService interface {
+	GetChangelog(time.Time, time.Time, string) (string, api_error.ApiErr)
+	PublishClosedWindow() api_error.ApiErr
+	SchedulePublishing()
+	StopPublishing()
+}
+
+type DefaultPbChangelogService struct {
+	repo      domain.PbApiRepository
+	journal   domain.FeatureJournalRepository
+	hierarchy PbHierarchyService
+	published domain.ImportLedgerRepository
+	webhooks  domain.WebhookRepository
+	cfg       *config.AppConfig
+	done      chan bool
+	stop      *sync.Once
+}
+
+func NewPbChangelogService(c *config.AppConfig, r domain.PbApiRepository, j domain.FeatureJournalRepository, h PbHierarchyService, p domain.ImportLedgerRepository, w domain.WebhookRepository) DefaultPbChangelogService {
+	return DefaultPbChangelogService{
+		repo:      r,
+		journal:   j,
+		hierarchy: h,
+		published: p,
+		webhooks:  w,
+		cfg:       c,
+		done:      make(chan bool),
+		stop:      &sync.Once{},
+	}
+}
+
+// ParseChangelogRange parses an inclusive date range (2006-01-02) and returns it with an exclusive end.
+// Without dates it covers the last seven days up to and including today.
+func ParseChangelogRange(from string, to string) (time.Time, time.Time, api_error.ApiErr) {
+	now := date.GetNowUtc()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if to != "" {
+		parsed, err := time.Parse(featureDateLayout, to)
+		if err != nil {
+			return changelogRangeError("to", to, err)
+		}
+		end = parsed
+	}
+	end = end.AddDate(0, 0, 1)
+	start := end.AddDate(0, 0, -7)
+	if from != "" {
+		parsed, err := time.Parse(featureDateLayout, from)
+		if err != nil {
+			return changelogRangeError("from", from, err)
+		}
+		start = parsed
+	}
+	if !start.Before(end) {
+		msg := fmt.Sprintf("Changelog range %v to %v is empty", from, to)
+		logger.Error(msg, nil)
+		return time.Time{}, time.Time{}, api_error.NewBadRequestError(msg)
+	}
+	return start, end, nil
+}
+
+// GetChangelog lists the features that moved into a release status between from and to (exclusive), grouped by product and component
+func (cs DefaultPbChangelogService) GetChangelog(from time.Time, to time.Time, format string) (string, api_error.ApiErr) {
+	content, _, err := cs.render(from, to, format)
+	return content, err
+}
+
+// render returns the changelog and the number of features it lists
+func (cs DefaultPbChangelogService) render(from time.Time, to time.Time, format string) (string, int, api_error.ApiErr) {
+	if _, known := dto.ChangelogContentTypes[format]; !known {
+		msg := fmt.Sprintf("Unknown changelog format \"%v\"", format)
+		logger.Error(msg, nil)
+		return "", 0, api_error.NewBadRequestError(msg)
+	}
+	entries, err := cs.releasedFeatures(from, to)
+	if err != nil {
+		return "", 0, err
+	}
+	title := fmt.Sprintf("%v: %v to %v", cs.cfg.Changelog.Title, from.Format(featureDateLayout), to.AddDate(0, 0, -1).Format(featureDateLayout))
+	groups, names := groupChangelog(entries)
+	if format == dto.ChangelogHtml {
+		return htmlChangelog(title, groups, names), len(entries), nil
+	}
+	return markdownChangelog(title, groups, names), len(entries), nil
+}
+
+// PublishClosedWindow writes the changelog of the release window that closed last, unless it was published before.
+// Windows that closed while pbreact was not running are published as well, oldest first, as long as an earlier
+// published window is found within maxCatchUpWindows. Otherwise the older windows are only logged as missed.
+func (cs DefaultPbChangelogService) PublishClosedWindow() api_error.ApiErr {
+	from, to, found := closedWindow(date.GetNowUtc(), cs.cfg.Changelog.Window)
+	if !found {
+		msg := fmt.Sprintf("Unknown changelog window \"%v\"", cs.cfg.Changelog.Window)
+		logger.Error(msg, nil)
+		return api_error.NewBadRequestError(msg)
+	}
+	missed := [][2]time.Time{}
+	anchored := false
+	for len(missed) < maxCatchUpWindows {
+		if cs.published.IsImported(windowKey(from, to)) {
+			anchored = true
+			break
+		}
+		missed = append(missed, [2]time.Time{from, to})
+		from, to, _ = closedWindow(from, cs.cfg.Changelog.Window)
+	}
+	if len(missed) == 0 {
+		return nil
+	}
+	if !anchored && len(missed) > 1 {
+		oldest := missed[len(missed)-1]
+		logger.Warn(fmt.Sprintf("No published changelog found back to %v. Only the last window is published, earlier ones can be written with the changelog command",
+			oldest[0].Format(featureDateLayout)))
+		missed = missed[:1]
+	}
+	for i := len(missed) - 1; i >= 0; i-- {
+		if err := cs.publishWindow(missed[i][0], missed[i][1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cs DefaultPbChangelogService) publishWindow(from time.Time, to time.Time) api_error.ApiErr {
+	key := windowKey(from, to)
+	format := cs.cfg.Changelog.Format
+	content, count, err := cs.render(from, to, format)
+	if err != nil {
+		return err
+	}
+	if cs.cfg.Changelog.OutputDir != "" {
+		file := filepath.Join(cs.cfg.Changelog.OutputDir, fmt.Sprintf("changelog-%v-%v.%v", from.Format(featureDateLayout), to.AddDate(0, 0, -1).Format(featureDateLayout), dto.ChangelogFileExtensions[format]))
+		if err := cs.writeFile(file, content); err != nil {
+			return err
+		}
+		logger.Info(fmt.Sprintf("Wrote changelog for %v to %v", key, file))
+	}
+	if cs.cfg.Changelog.WebhookUrl != "" {
+		document := dto.ChangelogDocument{
+			Title:    cs.cfg.Changelog.Title,
+			From:     from,
+			To:       to,
+			Format:   format,
+			Features: count,
+			Content:  content,
+		}
+		if err := cs.webhooks.PostJson(cs.cfg.Changelog.WebhookUrl, document); err != nil {
+			return err
+		}
+		logger.Info(fmt.Sprintf("Posted changelog for %v to %v", key, cs.cfg.Changelog.WebhookUrl))
+	}
+	return cs.published.MarkImported(key)
+}
+
+func (cs DefaultPbChangelogService) SchedulePublishing() {
+	logger.Info(fmt.Sprintf("Publishing %v changelogs", cs.cfg.Changelog.Window))
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		if err := cs.PublishClosedWindow(); err != nil {
+			logger.Error("Could not publish changelog", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-cs.done:
+			logger.Info("Stopped publishing changelogs")
+			return
+		}
+	}
+}
+
+func (cs DefaultPbChangelogService) StopPublishing() {
+	cs.stop.Do(func() {
+		close(cs.done)
+	})
+}
+
+// releasedFeatures finds status changes into a release status in the journal and looks up the current feature for its description
+func (cs DefaultPbChangelogService) releasedFeatures(from time.Time, to time.Time) ([]dto.ChangelogEntry, api_error.ApiErr) {
+	journal, err := cs.journal.Read(from)
+	if err != nil {
+		return nil, err
+	}
+	released := make(map[string]dto.JournalEntry)
+	for _, entry := range journal {
+		if entry.Kind != dto.JournalStatus || !entry.Time.Before(to) {
+			continue
+		}
+		// the first status seen of a feature has no old status and could have been released long before
+		if !cs.isRelease(entry.Status) || entry.OldStatus == "" || cs.isRelease(entry.OldStatus) {
+			continue
+		}
+		released[entry.FeatureId] = entry
+	}
+	entries := []dto.ChangelogEntry{}
+	for id, change := range released {
+		entry := dto.ChangelogEntry{FeatureId: id, Name: change.FeatureName, ReleasedAt: change.Time}
+		parentId := change.ParentId
+		feature, err := cs.repo.GetFeature(id)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Could not get feature %v for changelog. Listing it without description", id))
+		} else {
+			entry.Name = feature.Name
+			entry.Description = htmlToText(feature.Description)
+			entry.Url = feature.Links.Html
+			parentId = feature.Parent.ParentId()
+		}
+		entry.Product, entry.Component = productAndComponent(hierarchyPath(cs.hierarchy, parentId))
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (cs DefaultPbChangelogService) isRelease(status string) bool {
+	return len(cs.cfg.Changelog.ReleaseStatuses) > 0 && containsFold(cs.cfg.Changelog.ReleaseStatuses, status)
+}
+
+func (cs DefaultPbChangelogService) writeFile(file string, content string) api_error.ApiErr {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		msg := fmt.Sprintf("Could not create changelog directory for %v", file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		msg := fmt.Sprintf("Could not write changelog %v", file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	return nil
+}
+
+func windowKey(from time.Time, to time.Time) string {
+	return fmt.Sprintf("%v/%v", from.Format(featureDateLayout), to.Format(featureDateLayout))
+}
+
+// closedWindow returns the last weekly (Monday to Sunday) or monthly release window that ended before now
+func closedWindow(now time.Time, window string) (time.Time, time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch window {
+	case dto.ChangelogWeekly:
+		end := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		return end.AddDate(0, 0, -7), end, true
+	case dto.ChangelogMonthly:
+		end := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return end.AddDate(0, -1, 0), end, true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// groupChangelog groups entries by "Product / Component" and sorts the groups and the entries within by name
+func groupChangelog(entries []dto.ChangelogEntry) (map[string][]dto.ChangelogEntry, []string) {
+	groups := make(map[string][]dto.ChangelogEntry)
+	for _, entry := range entries {
+		names := []string{}
+		for _, name := range []string{entry.Product, entry.Component} {
+			if name != "" {
+				names = append(names, name)
+			}
+		}
+		group := strings.Join(names, " / ")
+		if group == "" {
+			group = unassignedGroup
+		}
+		groups[group] = append(groups[group], entry)
+	}
+	names := make([]string, 0, len(groups))
+	for name, group := range groups {
+		names = append(names, name)
+		sort.Slice(group, func(i, j int) bool {
+			return strings.ToLower(group[i].Name) < strings.ToLower(group[j].Name)
+		})
+	}
+	sort.Strings(names)
+	return groups, names
+}
+
+func markdownChangelog(title string, groups map[string][]dto.ChangelogEntry, names []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %v\n", markdownText.Replace(title))
+	if len(names) == 0 {
+		sb.WriteString("\nNo features were released in this period.\n")
+	}
+	for _, name := range names {
+		fmt.Fprintf(&sb, "\n## %v\n", markdownText.Replace(name))
+		for _, entry := range groups[name] {
+			if entry.Url != "" {
+				fmt.Fprintf(&sb, "\n### [%v](%v)\n", markdownText.Replace(entry.Name), entry.Url)
+			} else {
+				fmt.Fprintf(&sb, "\n### %v\n", markdownText.Replace(entry.Name))
+			}
+			if entry.Description != "" {
+				fmt.Fprintf(&sb, "\n%v\n", markdownText.Replace(entry.Description))
+			}
+		}
+	}
+	return sb.String()
+}
+
+func htmlChangelog(title string, groups map[string][]dto.ChangelogEntry, names []string) string {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&sb, "<title>%v</title>\n</head>\n<body>\n<h1>%v</h1>\n", html.EscapeString(title), html.EscapeString(title))
+	if len(names) == 0 {
+		sb.WriteString("<p>No features were released in this period.</p>\n")
+	}
+	for _, name := range names {
+		fmt.Fprintf(&sb, "<h2>%v</h2>\n", html.EscapeString(name))
+		for _, entry := range groups[name] {
+			if entry.Url != "" {
+				fmt.Fprintf(&sb, "<h3><a href=\"%v\">%v</a></h3>\n", html.EscapeString(entry.Url), html.EscapeString(entry.Name))
+			} else {
+				fmt.Fprintf(&sb, "<h3>%v</h3>\n", html.EscapeString(entry.Name))
+			}
+			if entry.Description != "" {
+				fmt.Fprintf(&sb, "<p>%v</p>\n", textToNoteContent(entry.Description))
+			}
+		}
+	}
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
+
+func changelogRangeError(name string, value string, err error) (time.Time, time.Time, api_error.ApiErr) {
+	msg := fmt.Sprintf("Could not parse %v date \"%v\". Use YYYY-MM-DD", name, value)
+	logger.Error(msg, err)
+	return time.Time{}, time.Time{}, api_error.NewBadRequestError(msg)
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	cls                  DefaultPbChangelogService
+	mockPublishedRepo    *domain.MockImportLedgerRepository
+	changelogFrom        = time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	changelogTo          = time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	changelogDescription = "<p>Supports *dark* themes &amp; more</p><script>alert(1)</script>"
+)
+
+func setupChangelog(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	mockJournalRepo = domain.NewMockFeatureJournalRepository(pbApiCtrl)
+	mockWebhookRepo = domain.NewMockWebhookRepository(pbApiCtrl)
+	mockPublishedRepo = domain.NewMockImportLedgerRepository(pbApiCtrl)
+	cfg.Changelog.ReleaseStatuses = []string{"Released"}
+	cfg.Changelog.Title = "Release notes"
+	cfg.Changelog.Format = dto.ChangelogMarkdown
+	cfg.Changelog.Window = dto.ChangelogWeekly
+	cfg.Changelog.OutputDir = ""
+	cfg.Changelog.WebhookUrl = ""
+	cls = NewPbChangelogService(&cfg, mockPbApiRepo, mockJournalRepo, NewPbHierarchyService(&cfg, mockPbApiRepo), mockPublishedRepo, mockWebhookRepo)
+	return func() {
+		pbApiCtrl.Finish()
+	}
+}
+
+func changelogJournal() []dto.JournalEntry {
+	released := func(day int, id string, name string, old string, parentId string) dto.JournalEntry {
+		return dto.JournalEntry{Time: time.Date(2024, 1, day, 12, 0, 0, 0, time.UTC), FeatureId: id, FeatureName: name, Kind: dto.JournalStatus, Status: "Released", OldStatus: old, ParentId: parentId}
+	}
+	return []dto.JournalEntry{
+		released(9, "f1", "Dark mode", "In progress", "c1"),
+		released(9, "f2", "Old feature", "", "c1"),
+		released(10, "f3", "Audit log", "In progress", "p2"),
+		released(11, "f4", "Deleted feature", "In progress", ""),
+		released(15, "f5", "Next week", "In progress", "c1"),
+		{Time: time.Date(2024, 1, 12, 12, 0, 0, 0, time.UTC), FeatureId: "f6", FeatureName: "Search", Kind: dto.JournalStatus, Status: "In progress", OldStatus: "Released", ParentId: "c1"},
+	}
+}
+
+func expectChangelogFeatures() {
+	mockPbApiRepo.EXPECT().GetFeature("f1").Return(&dto.Feature{ID: "f1", Name: "Dark mode", Description: changelogDescription,
+		Parent: dto.Parent{Component: &dto.ParentRef{ID: "c1"}}, Links: dto.EntityLinks{Html: "https://pb/f1"}}, nil)
+	mockPbApiRepo.EXPECT().GetFeature("f3").Return(&dto.Feature{ID: "f3", Name: "Audit log", Parent: dto.Parent{Product: &dto.ParentRef{ID: "p2"}}}, nil)
+	mockPbApiRepo.EXPECT().GetFeature("f4").Return(nil, api_error.NewNotFoundError("Feature not found"))
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).Return([]dto.Feature{}, nil)
+	mockPbApiRepo.EXPECT().GetProducts().Return([]dto.Product{{ID: "p1", Name: "Portal"}, {ID: "p2", Name: "Analytics"}}, nil)
+	mockPbApiRepo.EXPECT().GetComponents().Return([]dto.Component{{ID: "c1", Name: "Branding", Parent: dto.Parent{Product: &dto.ParentRef{ID: "p1"}}}}, nil)
+}
+
+func Test_GetChangelog_Markdown_Groups_ReleasedFeatures(t *testing.T) {
+	teardown := setupChangelog(t)
+	defer teardown()
+
+	mockJournalRepo.EXPECT().Read(changelogFrom).Return(changelogJournal(), nil)
+	expectChangelogFeatures()
+
+	changelog, err := cls.GetChangelog(changelogFrom, changelogTo, dto.ChangelogMarkdown)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "# Release notes: 2024-01-08 to 2024-01-14\n"+
+		"\n## Analytics\n"+
+		"\n### Audit log\n"+
+		"\n## Portal / Branding\n"+
+		"\n### [Dark mode](https://pb/f1)\n"+
+		"\nSupports \\*dark\\* themes & more\n"+
+		"\n## Unassigned\n"+
+		"\n### Deleted feature\n", changelog)
+}
+
+func Test_GetChangelog_Html_Escapes_Content(t *testing.T) {
+	teardown := setupChangelog(t)
+	defer teardown()
+
+	mockJournalRepo.EXPECT().Read(changelogFrom).Return(changelogJournal(), nil)
+	expectChangelogFeatures()
+
+	changelog, err := cls.GetChangelog(changelogFrom, changelogTo, dto.ChangelogHtml)
+
+	assert.Nil(t, err)
+	assert.Contains(t, changelog, "<h2>Portal / Branding</h2>\n<h3><a href=\"https://pb/f1\">Dark mode</a></h3>\n<p>Supports *dark* themes &amp; more</p>\n")
+	assert.Contains(t, changelog, "<h2>Unassigned</h2>\n<h3>Deleted feature</h3>\n")
+	assert.NotContains(t, changelog, "script")
+	assert.NotContains(t, changelog, "Old feature")
+	assert.NotContains(t, changelog, "Next week")
+	assert.NotContains(t, changelog, "Search")
+}
+
+func Test_GetChangelog_UnknownFormat_Returns_BadRequestError(t *testing.T) {
+	teardown := setupChangelog(t)
+	defer teardown()
+
+	changelog, err := cls.GetChangelog(changelogFrom, changelogTo, "pdf")
+
+	assert.EqualValues(t, "", changelog)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Unknown changelog format \"pdf\"", err.Message())
+}
+
+func Test_PublishClosedWindow_Writes_FileAndPostsWebhook(t *testing.T) {
+	teardown := setupChangelog(t)
+	defer teardown()
+	cfg.Changelog.OutputDir = filepath.Join(t.TempDir(), "changelogs")
+	cfg.Changelog.WebhookUrl = "http://hooks/changelog"
+	from, to, _ := closedWindow(date.GetNowUtc(), dto.ChangelogWeekly)
+	key := from.Format(featureDateLayout) + "/" + to.Format(featureDateLayout)
+	var document dto.ChangelogDocument
+
+	previousFrom, previousTo, _ := closedWindow(from, dto.ChangelogWeekly)
+
+	mockPublishedRepo.EXPECT().IsImported(key).Return(false)
+	mockPublishedRepo.EXPECT().IsImported(windowKey(previousFrom, previousTo)).Return(true)
+	mockJournalRepo.EXPECT().Read(from).Return([]dto.JournalEntry{}, nil)
+	mockWebhookRepo.EXPECT().PostJson("http://hooks/changelog", gomock.Any()).DoAndReturn(func(_ string, payload interface{}) api_error.ApiErr {
+		document = payload.(dto.ChangelogDocument)
+		return nil
+	})
+	mockPublishedRepo.EXPECT().MarkImported(key).Return(nil)
+
+	err := cls.PublishClosedWindow()
+
+	content, readErr := os.ReadFile(filepath.Join(cfg.Changelog.OutputDir, "changelog-"+from.Format(featureDateLayout)+"-"+to.AddDate(0, 0, -1).Format(featureDateLayout)+".md"))
+	assert.Nil(t, err)
+	assert.Nil(t, readErr)
+	assert.Contains(t, string(content), "No features were released in this period.")
+	assert.EqualValues(t, string(content), document.Content)
+	assert.EqualValues(t, 0, document.Features)
+	assert.EqualValues(t, from, document.From)
+}
+
+func Test_PublishClosedWindow_AlreadyPublished_DoesNothing(t *testing.T) {
+	teardown := setupChangelog(t)
+	defer teardown()
+
+	mockPublishedRepo.EXPECT().IsImported(gomock.Any()).Return(true)
+
+	err := cls.PublishClosedWindow()
+
+	assert.Nil(t, err)
+}
+
+func Test_PublishClosedWindow_Publishes_MissedWindows_OldestFirst(t *testing.T) {
+	teardown := setupChangelog(t)
+	defer teardown()
+	lastFrom, lastTo, _ := closedWindow(date.GetNowUtc(), dto.ChangelogWeekly)
+	missedFrom, missedTo, _ := closedWindow(lastFrom, dto.ChangelogWeekly)
+	publishedFrom, publishedTo, _ := closedWindow(missedFrom, dto.ChangelogWeekly)
+
+	mockPublishedRepo.EXPECT().IsImported(windowKey(lastFrom, lastTo)).Return(false)
+	mockPublishedRepo.EXPECT().IsImported(windowKey(missedFrom, missedTo)).Return(false)
+	mockPublishedRepo.EXPECT().IsImported(windowKey(publishedFrom, publishedTo)).Return(true)
+	gomock.InOrder(
+		mockJournalRepo.EXPECT().Read(missedFrom).Return([]dto.JournalEntry{}, nil),
+		mockPublishedRepo.EXPECT().MarkImported(windowKey(missedFrom, missedTo)).Return(nil),
+		mockJournalRepo.EXPECT().Read(lastFrom).Return([]dto.JournalEntry{}, nil),
+		mockPublishedRepo.EXPECT().MarkImported(windowKey(lastFrom, lastTo)).Return(nil),
+	)
+
+	err := cls.PublishClosedWindow()
+
+	assert.Nil(t, err)
+}
+
+func Test_PublishClosedWindow_NothingPublishedYet_Publishes_LastWindowOnly(t *testing.T) {
+	teardown := setupChangelog(t)
+	defer teardown()
+	lastFrom, lastTo, _ := closedWindow(date.GetNowUtc(), dto.ChangelogWeekly)
+
+	mockPublishedRepo.EXPECT().IsImported(gomock.Any()).Return(false).Times(maxCatchUpWindows)
+	mockJournalRepo.EXPECT().Read(lastFrom).Return([]dto.JournalEntry{}, nil)
+	mockPublishedRepo.EXPECT().MarkImported(windowKey(lastFrom, lastTo)).Return(nil)
+
+	err := cls.PublishClosedWindow()
+
+	assert.Nil(t, err)
+}
+
+func Test_closedWindow_Returns_LastClosedWindow(t *testing.T) {
+	from, to, found := closedWindow(time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC), dto.ChangelogWeekly)
+	assert.True(t, found)
+	assert.EqualValues(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.EqualValues(t, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, _ = closedWindow(time.Date(2024, 1, 8, 0, 30, 0, 0, time.UTC), dto.ChangelogWeekly)
+	assert.EqualValues(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.EqualValues(t, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, _ = closedWindow(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), dto.ChangelogMonthly)
+	assert.EqualValues(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.EqualValues(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), to)
+
+	_, _, found = closedWindow(time.Now(), "daily")
+	assert.False(t, found)
+}
+
+func Test_ParseChangelogRange_Returns_ExclusiveEnd(t *testing.T) {
+	from, to, err := ParseChangelogRange("2024-01-08", "2024-01-14")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, changelogFrom, from)
+	assert.EqualValues(t, changelogTo, to)
+
+	_, _, err = ParseChangelogRange("2024-01-15", "2024-01-14")
+	assert.NotNil(t, err)
+	assert.EqualValues(t, 400, err.StatusCode())
+
+	_, _, err = ParseChangelogRange("", "14.01.2024")
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Could not parse to date \"14.01.2024\". Use YYYY-MM-DD", err.Message())
+}
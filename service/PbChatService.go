@@ -0,0 +1,492 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+const (
+	chatThreadNameLength = 100
+)
+
+var (
+	chatColors = map[string]string{
+		"created": "2EB67D",
+		"updated": "1D9BF0",
+		"deleted": "E01E5A",
+	}
+	slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbChatService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbChatService
+type PbChatService interface {
+	HandleFeatureEvent(dto.FeatureEvent)
+	ProcessPosts()
+	StopProcessing()
+}
+
+type DefaultPbChatService struct {
+	hierarchy PbHierarchyService
+	webhooks  domain.WebhookRepository
+	cfg       *config.AppConfig
+	channels  []dto.ChatChannel
+	seen      *seenFeatures
+	threads   *chatThreads
+	queue     chan chatPost
+	done      chan bool
+	stop      *sync.Once
+}
+
+type chatPost struct {
+	channel   dto.ChatChannel
+	content   chatContent
+	featureId string
+	deleted   bool
+	attempts  int
+}
+
+// chatThreads maps channel and feature to the thread the feature's messages are posted to. With a cache, the threads are
+// saved after every change, so a feature's messages stay in its thread after a restart.
+type chatThreads struct {
+	sync.Mutex
+	ids   map[string]string
+	cache domain.ChatThreadRepository
+}
+
+// chatContent is a feature event, formatted for chat messages
+type chatContent struct {
+	title    string
+	link     string
+	action   string
+	status   string
+	location string
+	owner    string
+	at       time.Time
+	color    string
+	changes  []string
+}
+
+// NewPbChatService loads and validates the chat channels, the feature states seen and the threads from before the last
+// restart. Without a channels file nothing is posted.
+func NewPbChatService(c *config.AppConfig, h PbHierarchyService, w domain.WebhookRepository, f domain.FeatureCacheRepository, t domain.ChatThreadRepository) (DefaultPbChatService, api_error.ApiErr) {
+	cs := DefaultPbChatService{
+		hierarchy: h,
+		webhooks:  w,
+		cfg:       c,
+		seen:      newSeenFeatures(),
+		threads: &chatThreads{
+			ids: make(map[string]string),
+		},
+		queue: make(chan chatPost, c.Chat.QueueSize),
+		done:  make(chan bool),
+		stop:  &sync.Once{},
+	}
+	if c.Chat.ChannelsFile == "" {
+		return cs, nil
+	}
+	channels, err := loadChatChannels(c.Chat.ChannelsFile)
+	if err != nil {
+		msg := fmt.Sprintf("Could not load chat channels from %v", c.Chat.ChannelsFile)
+		logger.Error(msg, err)
+		return cs, api_error.NewInternalServerError(msg, err)
+	}
+	cs.channels = channels
+	logger.Info(fmt.Sprintf("Loaded %v chat channel(s)", len(channels)))
+	seen, loadErr := f.Load()
+	if loadErr != nil {
+		return cs, loadErr
+	}
+	cs.seen.features = seen
+	cs.seen.cache = f
+	threads, loadErr := t.Load()
+	if loadErr != nil {
+		return cs, loadErr
+	}
+	cs.threads.ids = threads
+	cs.threads.cache = t
+	return cs, nil
+}
+
+func loadChatChannels(file string) ([]dto.ChatChannel, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var channels []dto.ChatChannel
+	if err := json.Unmarshal(content, &channels); err != nil {
+		return nil, err
+	}
+	for i := range channels {
+		channel := &channels[i]
+		if channel.Name == "" {
+			channel.Name = fmt.Sprintf("channel %v", i+1)
+		}
+		channel.Format = strings.ToLower(channel.Format)
+		switch {
+		case channel.Format == dto.ChatSlack && channel.Token != "":
+			if channel.Channel == "" {
+				return nil, fmt.Errorf("%v posts with a Slack token but has no channel", channel.Name)
+			}
+		case channel.Format != dto.ChatSlack && channel.Format != dto.ChatMattermost && channel.Format != dto.ChatTeams &&
+			channel.Format != dto.ChatGoogleChat && channel.Format != dto.ChatDiscord:
+			return nil, fmt.Errorf("%v has unknown format \"%v\"", channel.Name, channel.Format)
+		default:
+			if _, err := url.ParseRequestURI(channel.Url); err != nil {
+				return nil, fmt.Errorf("%v has no valid url", channel.Name)
+			}
+		}
+		if channel.Thread && (channel.Format == dto.ChatMattermost || channel.Format == dto.ChatTeams || (channel.Format == dto.ChatSlack && channel.Token == "")) {
+			logger.Warn(fmt.Sprintf("Chat channel %v cannot thread messages with a %v incoming webhook", channel.Name, channel.Format))
+			channel.Thread = false
+		}
+	}
+	return channels, nil
+}
+
+// HandleFeatureEvent queues a post for every channel whose filter the event matches. It never blocks; if the queue is full,
+// the post is dropped.
+func (cs DefaultPbChatService) HandleFeatureEvent(event dto.FeatureEvent) {
+	if len(cs.channels) == 0 {
+		return
+	}
+	data, found := cs.seen.observe(event)
+	if !found {
+		return
+	}
+	path := hierarchyPath(cs.hierarchy, data.Feature.Parent.ParentId())
+	data.Product, data.Component = productAndComponent(path)
+	content := newChatContent(*data)
+	for _, channel := range cs.channels {
+		if !filterMatches(channel.EventFilter, *data, path) {
+			continue
+		}
+		select {
+		case cs.queue <- chatPost{channel: channel, content: content, featureId: event.ID, deleted: event.EventType == dto.PbEventTypes["featureDelete"]}:
+		default:
+			logger.Error(fmt.Sprintf("Could not queue feature %v for chat channel %v", event.ID, channel.Name), api_error.NewInternalServerError("Chat queue is full", nil))
+		}
+	}
+}
+
+// ProcessPosts posts the queued messages one at a time, so the messages of a feature reach each channel in order.
+// A failed post is retried before the next one is taken from the queue.
+func (cs DefaultPbChatService) ProcessPosts() {
+	logger.Info("Start processing chat posts")
+	for {
+		select {
+		case p := <-cs.queue:
+			cs.processPost(p)
+		case <-cs.done:
+			logger.Info("Stopped processing chat posts")
+			return
+		}
+	}
+}
+
+func (cs DefaultPbChatService) StopProcessing() {
+	cs.stop.Do(func() {
+		close(cs.done)
+	})
+}
+
+// processPost posts the message, waiting with an increasing delay between attempts. It returns early if processing
+// is stopped while waiting.
+func (cs DefaultPbChatService) processPost(p chatPost) {
+	for {
+		p.attempts++
+		err := cs.post(p.channel, p.content, p.featureId)
+		if err == nil || p.attempts > cs.cfg.Chat.MaxRetries {
+			if err != nil {
+				logger.Error(fmt.Sprintf("Giving up on posting feature %v to chat channel %v after %v attempt(s)", p.featureId, p.channel.Name, p.attempts), err)
+			}
+			if p.deleted {
+				cs.deleteThread(p.channel.Name + "/" + p.featureId)
+			}
+			return
+		}
+		delay := time.Duration(cs.cfg.Chat.RetryDelay) * time.Second * time.Duration(1<<uint(p.attempts-1))
+		logger.Info(fmt.Sprintf("Retrying post of feature %v to chat channel %v in %v", p.featureId, p.channel.Name, delay))
+		select {
+		case <-time.After(delay):
+		case <-cs.done:
+			logger.Error(fmt.Sprintf("Could not retry post of feature %v to chat channel %v", p.featureId, p.channel.Name), api_error.NewInternalServerError("Chat processing stopped", nil))
+			return
+		}
+	}
+}
+
+func (cs DefaultPbChatService) post(channel dto.ChatChannel, content chatContent, featureId string) api_error.ApiErr {
+	threadKey := channel.Name + "/" + featureId
+	switch channel.Format {
+	case dto.ChatTeams:
+		return cs.webhooks.PostJson(channel.Url, teamsMessage(content))
+	case dto.ChatGoogleChat:
+		target := channel.Url
+		if channel.Thread {
+			target = withQuery(target, url.Values{"threadKey": {featureId}, "messageReplyOption": {"REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD"}})
+		}
+		return cs.webhooks.PostJson(target, googleChatMessage(content))
+	case dto.ChatDiscord:
+		message := discordMessage(content)
+		if !channel.Thread {
+			return cs.webhooks.PostJson(channel.Url, message)
+		}
+		threadId := cs.thread(threadKey)
+		query := url.Values{"wait": {"true"}}
+		if threadId != "" {
+			query.Set("thread_id", threadId)
+		} else {
+			message.ThreadName = truncateRunes(content.title, chatThreadNameLength)
+		}
+		var reply dto.DiscordReply
+		if err := cs.webhooks.PostJsonReply(withQuery(channel.Url, query), "", message, &reply); err != nil {
+			return err
+		}
+		if threadId == "" {
+			cs.setThread(threadKey, reply.ChannelId)
+		}
+		return nil
+	}
+	message := slackMessage(content)
+	if channel.Token == "" {
+		return cs.webhooks.PostJson(channel.Url, message)
+	}
+	message.Channel = channel.Channel
+	if channel.Thread {
+		message.ThreadTs = cs.thread(threadKey)
+	}
+	var reply dto.SlackReply
+	if err := cs.webhooks.PostJsonReply(cs.cfg.Chat.SlackApiUrl, channel.Token, message, &reply); err != nil {
+		return err
+	}
+	if !reply.Ok {
+		msg := fmt.Sprintf("Slack rejected message to %v: %v", channel.Channel, reply.Error)
+		logger.Error(msg, nil)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	if channel.Thread && message.ThreadTs == "" {
+		cs.setThread(threadKey, reply.Ts)
+	}
+	return nil
+}
+
+func (cs DefaultPbChatService) thread(key string) string {
+	cs.threads.Lock()
+	defer cs.threads.Unlock()
+	return cs.threads.ids[key]
+}
+
+func (cs DefaultPbChatService) setThread(key string, id string) {
+	if id == "" {
+		return
+	}
+	cs.threads.Lock()
+	defer cs.threads.Unlock()
+	cs.threads.ids[key] = id
+	cs.threads.save()
+}
+
+func (cs DefaultPbChatService) deleteThread(key string) {
+	cs.threads.Lock()
+	defer cs.threads.Unlock()
+	if _, found := cs.threads.ids[key]; !found {
+		return
+	}
+	delete(cs.threads.ids, key)
+	cs.threads.save()
+}
+
+// save persists the threads if there is a cache; the lock has to be held
+func (t *chatThreads) save() {
+	if t.cache == nil {
+		return
+	}
+	if err := t.cache.Save(t.ids); err != nil {
+		logger.Error("Could not save the chat threads", err)
+	}
+}
+
+func newChatContent(data dto.NotifyData) chatContent {
+	content := chatContent{
+		title:   data.Feature.Name,
+		link:    data.Feature.Links.Html,
+		action:  data.Action,
+		status:  data.Feature.Status.Name,
+		owner:   ownerEmail(data.Feature),
+		at:      data.Event.ReceivedAt.UTC(),
+		color:   chatColors[data.Action],
+		changes: []string{},
+	}
+	if content.title == "" {
+		content.title = data.Feature.ID
+	}
+	if content.color == "" {
+		content.color = chatColors["updated"]
+	}
+	if data.Product != "" {
+		content.location = data.Product
+		if data.Component != "" {
+			content.location += " / " + data.Component
+		}
+	}
+	for _, change := range data.Changes {
+		switch change.Field {
+		case dto.NotifyFieldStatus:
+			content.status = fmt.Sprintf("%v → %v", change.Old, change.New)
+		case dto.NotifyFieldDescription:
+			content.changes = append(content.changes, "description changed")
+		default:
+			content.changes = append(content.changes, fmt.Sprintf("%v: %v → %v", change.Field, chatValue(change.Old), chatValue(change.New)))
+		}
+	}
+	return content
+}
+
+func (c chatContent) summary() string {
+	return fmt.Sprintf("Feature %v: %v", c.action, c.title)
+}
+
+func (c chatContent) byline() string {
+	when := c.at.Format(digestTimeLayout) + " UTC"
+	if c.owner == "" {
+		return when
+	}
+	return fmt.Sprintf("Owner %v · %v", c.owner, when)
+}
+
+func slackMessage(c chatContent) dto.SlackMessage {
+	fields := []dto.SlackField{{Title: "Status", Value: slackEscaper.Replace(c.status), Short: true}}
+	if c.location != "" {
+		fields = append(fields, dto.SlackField{Title: "Product", Value: slackEscaper.Replace(c.location), Short: true})
+	}
+	text := []string{}
+	for _, change := range c.changes {
+		text = append(text, "• "+slackEscaper.Replace(change))
+	}
+	return dto.SlackMessage{
+		Text: slackEscaper.Replace(c.summary()),
+		Attachments: []dto.SlackAttachment{{
+			Fallback:  c.summary(),
+			Color:     "#" + c.color,
+			Title:     c.title,
+			TitleLink: c.link,
+			Text:      strings.Join(text, "\n"),
+			Fields:    fields,
+			Footer:    c.byline(),
+			Ts:        c.at.Unix(),
+		}},
+	}
+}
+
+func teamsMessage(c chatContent) dto.TeamsMessage {
+	facts := []dto.TeamsFact{{Name: "Status", Value: c.status}}
+	if c.location != "" {
+		facts = append(facts, dto.TeamsFact{Name: "Product", Value: c.location})
+	}
+	message := dto.TeamsMessage{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		Summary:    c.summary(),
+		ThemeColor: c.color,
+		Title:      c.title,
+		Sections: []dto.TeamsSection{{
+			ActivityTitle:    "Feature " + c.action,
+			ActivitySubtitle: c.byline(),
+			Facts:            facts,
+			Text:             strings.Join(c.changes, "<br>"),
+		}},
+	}
+	if c.link != "" {
+		message.PotentialAction = []dto.TeamsAction{{
+			Type:    "OpenUri",
+			Name:    "Open in Productboard",
+			Targets: []dto.TeamsTarget{{Os: "default", Uri: c.link}},
+		}}
+	}
+	return message
+}
+
+func googleChatMessage(c chatContent) dto.GoogleChatMessage {
+	widgets := []dto.GoogleChatWidget{{DecoratedText: &dto.GoogleChatText{TopLabel: "Status", Text: c.status}}}
+	if c.location != "" {
+		widgets = append(widgets, dto.GoogleChatWidget{DecoratedText: &dto.GoogleChatText{TopLabel: "Product", Text: c.location}})
+	}
+	if len(c.changes) > 0 {
+		widgets = append(widgets, dto.GoogleChatWidget{DecoratedText: &dto.GoogleChatText{TopLabel: "Changes", Text: strings.Join(c.changes, "<br>")}})
+	}
+	if c.link != "" {
+		widgets = append(widgets, dto.GoogleChatWidget{ButtonList: &dto.GoogleChatButtons{Buttons: []dto.GoogleChatButton{{
+			Text:    "Open in Productboard",
+			OnClick: dto.GoogleChatOnClick{OpenLink: dto.GoogleChatLink{Url: c.link}},
+		}}}})
+	}
+	return dto.GoogleChatMessage{
+		Text: c.summary(),
+		CardsV2: []dto.GoogleChatCard{{
+			CardId: "feature",
+			Card: dto.GoogleChatCardBody{
+				Header:   dto.GoogleChatHeader{Title: c.title, Subtitle: fmt.Sprintf("Feature %v · %v", c.action, c.byline())},
+				Sections: []dto.GoogleChatSection{{Widgets: widgets}},
+			},
+		}},
+	}
+}
+
+func discordMessage(c chatContent) dto.DiscordMessage {
+	var color int
+	fmt.Sscanf(c.color, "%x", &color)
+	fields := []dto.DiscordField{{Name: "Status", Value: c.status, Inline: true}}
+	if c.location != "" {
+		fields = append(fields, dto.DiscordField{Name: "Product", Value: c.location, Inline: true})
+	}
+	embed := dto.DiscordEmbed{
+		Title:       c.title,
+		Url:         c.link,
+		Description: strings.Join(c.changes, "\n"),
+		Color:       color,
+		Fields:      fields,
+		Timestamp:   c.at.Format(time.RFC3339),
+	}
+	if c.owner != "" {
+		embed.Footer = &dto.DiscordEmbedFooter{Text: "Owner " + c.owner}
+	}
+	return dto.DiscordMessage{Embeds: []dto.DiscordEmbed{embed}}
+}
+
+func chatValue(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
+func withQuery(target string, query url.Values) string {
+	u, err := url.Parse(target)
+	if err != nil {
+		return target
+	}
+	values := u.Query()
+	for key, value := range query {
+		values[key] = value
+	}
+	u.RawQuery = values.Encode()
+	return u.String()
+}
+
+func truncateRunes(value string, length int) string {
+	if runes := []rune(value); len(runes) > length {
+		return string(runes[:length])
+	}
+	return value
+}
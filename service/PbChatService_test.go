@@ -0,0 +1,269 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/pbreact/repository"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	chs          DefaultPbChatService
+	chatAt       = time.Date(2024, 1, 9, 10, 30, 0, 0, time.UTC)
+	chatChannels = `[
+	{"name": "releases", "format": "slack", "url": "http://hooks/slack", "statuses": ["Released"], "changed": ["status"]},
+	{"name": "portal", "format": "teams", "url": "http://hooks/teams", "products": ["Portal"], "events": ["updated"]},
+	{"name": "threads", "format": "slack", "token": "xoxb-token", "channel": "#features", "thread": true},
+	{"name": "forum", "format": "discord", "url": "http://hooks/discord?x=1", "thread": true},
+	{"name": "spaces", "format": "googlechat", "url": "http://hooks/chat?key=k", "thread": true, "events": ["deleted"]}
+]`
+)
+
+func setupChat(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	mockWebhookRepo = domain.NewMockWebhookRepository(pbApiCtrl)
+	cfg.Chat.ChannelsFile = filepath.Join(t.TempDir(), "channels.json")
+	cfg.Chat.SlackApiUrl = "http://slack/api/chat.postMessage"
+	cfg.Chat.QueueSize = 20
+	cfg.Chat.MaxRetries = 1
+	cfg.Chat.RetryDelay = 0
+	os.WriteFile(cfg.Chat.ChannelsFile, []byte(chatChannels), 0644)
+	chs, _ = newChatService(t.TempDir(), NewPbHierarchyService(&cfg, mockPbApiRepo))
+	return func() {
+		cfg.Chat.ChannelsFile = ""
+		pbApiCtrl.Finish()
+	}
+}
+
+func newChatService(dir string, h PbHierarchyService) (DefaultPbChatService, api_error.ApiErr) {
+	return NewPbChatService(&cfg, h, mockWebhookRepo, repository.NewFeatureCacheRepository(filepath.Join(dir, "seen.json")), repository.NewChatThreadRepository(filepath.Join(dir, "threads.json")))
+}
+
+func chatEvent(eventType string, feature *dto.Feature) dto.FeatureEvent {
+	event := notifyEvent(eventType, feature)
+	event.ReceivedAt = chatAt
+	return event
+}
+
+// processChatPosts posts the queued messages the way ProcessPosts does, without starting the worker
+func processChatPosts() {
+	for {
+		select {
+		case p := <-chs.queue:
+			chs.processPost(p)
+		default:
+			return
+		}
+	}
+}
+
+func Test_HandleFeatureEvent_StatusChange_Posts_FormattedMessages(t *testing.T) {
+	teardown := setupChat(t)
+	defer teardown()
+	before, after := notifyFeature("In progress", "c1"), notifyFeature("Released", "c1")
+	after.Owner = &dto.Owner{Email: "pm@example.com"}
+	var slack dto.SlackMessage
+	var teams dto.TeamsMessage
+
+	expectNotifyHierarchy()
+	mockWebhookRepo.EXPECT().PostJsonReply(cfg.Chat.SlackApiUrl, "xoxb-token", gomock.Any(), gomock.Any()).DoAndReturn(func(_ string, _ string, _ interface{}, reply interface{}) api_error.ApiErr {
+		reply.(*dto.SlackReply).Ok = true
+		return nil
+	}).Times(2)
+	mockWebhookRepo.EXPECT().PostJsonReply("http://hooks/discord?wait=true&x=1", "", gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	mockWebhookRepo.EXPECT().PostJson("http://hooks/slack", gomock.Any()).DoAndReturn(func(_ string, payload interface{}) api_error.ApiErr {
+		slack = payload.(dto.SlackMessage)
+		return nil
+	})
+	mockWebhookRepo.EXPECT().PostJson("http://hooks/teams", gomock.Any()).DoAndReturn(func(_ string, payload interface{}) api_error.ApiErr {
+		teams = payload.(dto.TeamsMessage)
+		return nil
+	})
+
+	chs.HandleFeatureEvent(chatEvent("featureCreate", &before))
+	chs.HandleFeatureEvent(chatEvent("featureUpdate", &after))
+	processChatPosts()
+
+	assert.EqualValues(t, "Feature updated: Dark mode", slack.Text)
+	assert.EqualValues(t, dto.SlackAttachment{
+		Fallback:  "Feature updated: Dark mode",
+		Color:     "#1D9BF0",
+		Title:     "Dark mode",
+		TitleLink: "https://pb/f1",
+		Text:      "• owner: none → pm@example.com",
+		Fields:    []dto.SlackField{{Title: "Status", Value: "In progress → Released", Short: true}, {Title: "Product", Value: "Portal / Branding", Short: true}},
+		Footer:    "Owner pm@example.com · 2024-01-09 10:30 UTC",
+		Ts:        chatAt.Unix(),
+	}, slack.Attachments[0])
+	assert.EqualValues(t, "MessageCard", teams.Type)
+	assert.EqualValues(t, []dto.TeamsFact{{Name: "Status", Value: "In progress → Released"}, {Name: "Product", Value: "Portal / Branding"}}, teams.Sections[0].Facts)
+	assert.EqualValues(t, "https://pb/f1", teams.PotentialAction[0].Targets[0].Uri)
+}
+
+func Test_HandleFeatureEvent_Thread_Keeps_FeatureTogether(t *testing.T) {
+	teardown := setupChat(t)
+	defer teardown()
+	feature := notifyFeature("In progress", "c1")
+	slackThreads := []string{}
+	discordThreads := []string{}
+	var discordUrls []string
+
+	expectNotifyHierarchy()
+	mockWebhookRepo.EXPECT().PostJsonReply(cfg.Chat.SlackApiUrl, "xoxb-token", gomock.Any(), gomock.Any()).DoAndReturn(func(_ string, _ string, payload interface{}, reply interface{}) api_error.ApiErr {
+		slackThreads = append(slackThreads, payload.(dto.SlackMessage).ThreadTs)
+		*reply.(*dto.SlackReply) = dto.SlackReply{Ok: true, Ts: "1704796200.000100"}
+		return nil
+	}).Times(3)
+	mockWebhookRepo.EXPECT().PostJsonReply(gomock.Any(), "", gomock.Any(), gomock.Any()).DoAndReturn(func(url string, _ string, payload interface{}, reply interface{}) api_error.ApiErr {
+		discordUrls = append(discordUrls, url)
+		discordThreads = append(discordThreads, payload.(dto.DiscordMessage).ThreadName)
+		*reply.(*dto.DiscordReply) = dto.DiscordReply{ID: "m1", ChannelId: "t1"}
+		return nil
+	}).Times(3)
+	mockWebhookRepo.EXPECT().PostJson("http://hooks/teams", gomock.Any()).Return(nil)
+	mockWebhookRepo.EXPECT().PostJson("http://hooks/chat?key=k&messageReplyOption=REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD&threadKey=f1", gomock.Any()).Return(nil)
+
+	chs.HandleFeatureEvent(chatEvent("featureCreate", &feature))
+	chs.HandleFeatureEvent(chatEvent("featureUpdate", &feature))
+	chs.HandleFeatureEvent(chatEvent("featureDelete", nil))
+	processChatPosts()
+
+	assert.EqualValues(t, []string{"", "1704796200.000100", "1704796200.000100"}, slackThreads)
+	assert.EqualValues(t, []string{"Dark mode", "", ""}, discordThreads)
+	assert.EqualValues(t, []string{"http://hooks/discord?wait=true&x=1", "http://hooks/discord?thread_id=t1&wait=true&x=1", "http://hooks/discord?thread_id=t1&wait=true&x=1"}, discordUrls)
+	assert.EqualValues(t, 0, len(chs.threads.ids))
+}
+
+func Test_HandleFeatureEvent_SlackRejects_Continues_WithOtherChannels(t *testing.T) {
+	teardown := setupChat(t)
+	defer teardown()
+	feature := notifyFeature("In progress", "c1")
+
+	expectNotifyHierarchy()
+	mockWebhookRepo.EXPECT().PostJsonReply(cfg.Chat.SlackApiUrl, "xoxb-token", gomock.Any(), gomock.Any()).DoAndReturn(func(_ string, _ string, _ interface{}, reply interface{}) api_error.ApiErr {
+		*reply.(*dto.SlackReply) = dto.SlackReply{Ok: false, Error: "channel_not_found"}
+		return nil
+	})
+	mockWebhookRepo.EXPECT().PostJsonReply(gomock.Any(), "", gomock.Any(), gomock.Any()).Return(api_error.NewInternalServerError("down", nil))
+	cfg.Chat.MaxRetries = 0
+
+	chs.HandleFeatureEvent(chatEvent("featureCreate", &feature))
+	processChatPosts()
+
+	assert.EqualValues(t, 0, len(chs.threads.ids))
+}
+
+func Test_NewPbChatService_InvalidChannel_Returns_Error(t *testing.T) {
+	teardown := setupChat(t)
+	defer teardown()
+	os.WriteFile(cfg.Chat.ChannelsFile, []byte(`[{"name": "irc", "format": "irc", "url": "http://hooks/irc"}]`), 0644)
+
+	_, err := newChatService(t.TempDir(), NewPbHierarchyService(&cfg, mockPbApiRepo))
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Could not load chat channels from "+cfg.Chat.ChannelsFile, err.Message())
+}
+
+func Test_HandleFeatureEvent_Queues_Posts_Without_Posting(t *testing.T) {
+	teardown := setupChat(t)
+	defer teardown()
+	feature := notifyFeature("In progress", "c1")
+
+	expectNotifyHierarchy()
+	chs.HandleFeatureEvent(chatEvent("featureCreate", &feature))
+
+	assert.EqualValues(t, 2, len(chs.queue))
+}
+
+func Test_processPost_Error_Retries_ThenGivesUp(t *testing.T) {
+	teardown := setupChat(t)
+	defer teardown()
+	channel := dto.ChatChannel{Name: "portal", Format: dto.ChatTeams, Url: "http://hooks/teams"}
+	mockWebhookRepo.EXPECT().PostJson("http://hooks/teams", gomock.Any()).Return(api_error.NewInternalServerError("down", nil)).Times(2)
+
+	chs.processPost(chatPost{channel: channel, featureId: "f1"})
+
+	assert.EqualValues(t, 0, len(chs.queue))
+}
+
+func Test_processPost_Retry_Keeps_Order(t *testing.T) {
+	teardown := setupChat(t)
+	defer teardown()
+	channel := dto.ChatChannel{Name: "portal", Format: dto.ChatTeams, Url: "http://hooks/teams"}
+	titles := []string{}
+	post := func(title string, err api_error.ApiErr) *gomock.Call {
+		return mockWebhookRepo.EXPECT().PostJson("http://hooks/teams", gomock.Any()).DoAndReturn(func(_ string, payload interface{}) api_error.ApiErr {
+			titles = append(titles, payload.(dto.TeamsMessage).Title)
+			return err
+		})
+	}
+	gomock.InOrder(
+		post("Created", api_error.NewInternalServerError("down", nil)),
+		post("Created", nil),
+		post("Released", nil),
+	)
+
+	chs.queue <- chatPost{channel: channel, content: chatContent{title: "Created"}, featureId: "f1"}
+	chs.queue <- chatPost{channel: channel, content: chatContent{title: "Released"}, featureId: "f1"}
+	processChatPosts()
+
+	assert.EqualValues(t, []string{"Created", "Created", "Released"}, titles)
+}
+
+func Test_processPost_Stopped_While_Waiting_Returns(t *testing.T) {
+	teardown := setupChat(t)
+	defer teardown()
+	channel := dto.ChatChannel{Name: "portal", Format: dto.ChatTeams, Url: "http://hooks/teams"}
+	cfg.Chat.RetryDelay = 60
+	defer func() { cfg.Chat.RetryDelay = 0 }()
+	mockWebhookRepo.EXPECT().PostJson("http://hooks/teams", gomock.Any()).Return(api_error.NewInternalServerError("down", nil))
+
+	chs.StopProcessing()
+	chs.processPost(chatPost{channel: channel, featureId: "f1"})
+}
+
+func Test_HandleFeatureEvent_AfterRestart_Keeps_Thread_And_SeenFeatures(t *testing.T) {
+	teardown := setupChat(t)
+	defer teardown()
+	os.WriteFile(cfg.Chat.ChannelsFile, []byte(`[{"name": "threads", "format": "slack", "token": "xoxb-token", "channel": "#features", "thread": true}]`), 0644)
+	dir := t.TempDir()
+	before, after := notifyFeature("In progress", "c1"), notifyFeature("Released", "c1")
+	var threadTs []string
+	var statuses []string
+
+	expectNotifyHierarchy()
+	mockWebhookRepo.EXPECT().PostJsonReply(cfg.Chat.SlackApiUrl, "xoxb-token", gomock.Any(), gomock.Any()).DoAndReturn(func(_ string, _ string, payload interface{}, reply interface{}) api_error.ApiErr {
+		message := payload.(dto.SlackMessage)
+		threadTs = append(threadTs, message.ThreadTs)
+		statuses = append(statuses, message.Attachments[0].Fields[0].Value)
+		*reply.(*dto.SlackReply) = dto.SlackReply{Ok: true, Ts: "1704796200.000100"}
+		return nil
+	}).Times(2)
+
+	first, _ := newChatService(dir, NewPbHierarchyService(&cfg, mockPbApiRepo))
+	first.HandleFeatureEvent(chatEvent("featureCreate", &before))
+	first.processPost(<-first.queue)
+	restarted, err := newChatService(dir, first.hierarchy)
+	restarted.HandleFeatureEvent(chatEvent("featureUpdate", &after))
+	restarted.processPost(<-restarted.queue)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"", "1704796200.000100"}, threadTs)
+	assert.EqualValues(t, []string{"In progress", "In progress → Released"}, statuses)
+}
+
+func Test_StopProcessing_Chat_CalledTwice_DoesNotPanic(t *testing.T) {
+	teardown := setupChat(t)
+	defer teardown()
+
+	chs.StopProcessing()
+	chs.StopProcessing()
+}
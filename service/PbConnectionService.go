@@ -0,0 +1,176 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbConnectionService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbConnectionService
+type PbConnectionService interface {
+	GetConnection(string) dto.Connection
+	TrackConnection(string, dto.Connection)
+	UpdateConnection(string, dto.Connection) api_error.ApiErr
+	SetError(string, string) api_error.ApiErr
+	ResetConnection(string) api_error.ApiErr
+	SyncConnections() api_error.ApiErr
+}
+
+type DefaultPbConnectionService struct {
+	repo  domain.PbApiRepository
+	cfg   *config.AppConfig
+	store domain.ConnectionRepository
+	cache *connectionCache
+}
+
+type connectionCache struct {
+	sync.RWMutex
+	connections map[string]dto.Connection
+}
+
+// NewPbConnectionService loads the connections saved before the last shutdown, so SyncConnections can correct
+// connections that changed in Productboard in the meantime
+func NewPbConnectionService(c *config.AppConfig, r domain.PbApiRepository, s domain.ConnectionRepository) (DefaultPbConnectionService, api_error.ApiErr) {
+	cs := DefaultPbConnectionService{
+		repo:  r,
+		cfg:   c,
+		store: s,
+		cache: &connectionCache{
+			connections: make(map[string]dto.Connection),
+		},
+	}
+	saved, err := s.Load()
+	if err != nil {
+		return cs, err
+	}
+	for featureId, conn := range saved {
+		cs.cache.connections[featureId] = conn
+	}
+	return cs, nil
+}
+
+// GetConnection returns the locally known connection of a feature. Unknown features are in the initial state.
+func (cs DefaultPbConnectionService) GetConnection(featureId string) dto.Connection {
+	cs.cache.RLock()
+	defer cs.cache.RUnlock()
+	conn, found := cs.cache.connections[featureId]
+	if !found {
+		return dto.Connection{State: dto.ConnectionInitial}
+	}
+	return conn
+}
+
+// TrackConnection records a connection Productboard already knows about, e.g. one returned in an action response
+func (cs DefaultPbConnectionService) TrackConnection(featureId string, conn dto.Connection) {
+	cs.cache.Lock()
+	defer cs.cache.Unlock()
+	if conn.State == dto.ConnectionInitial {
+		delete(cs.cache.connections, featureId)
+	} else {
+		cs.cache.connections[featureId] = conn
+	}
+	cs.save()
+}
+
+func (cs DefaultPbConnectionService) UpdateConnection(featureId string, conn dto.Connection) api_error.ApiErr {
+	integrationId, err := cs.integrationId()
+	if err != nil {
+		return err
+	}
+	if conn.State == dto.ConnectionInitial {
+		return cs.ResetConnection(featureId)
+	}
+	if _, err := cs.repo.UpdatePluginConnection(integrationId, featureId, conn); err != nil {
+		return err
+	}
+	cs.TrackConnection(featureId, conn)
+	return nil
+}
+
+func (cs DefaultPbConnectionService) SetError(featureId string, message string) api_error.ApiErr {
+	return cs.UpdateConnection(featureId, dto.Connection{
+		State:   dto.ConnectionError,
+		Message: message,
+	})
+}
+
+func (cs DefaultPbConnectionService) ResetConnection(featureId string) api_error.ApiErr {
+	integrationId, err := cs.integrationId()
+	if err != nil {
+		return err
+	}
+	if err := cs.repo.DeletePluginConnection(integrationId, featureId); err != nil {
+		return err
+	}
+	cs.TrackConnection(featureId, dto.Connection{State: dto.ConnectionInitial})
+	return nil
+}
+
+// SyncConnections pushes every locally tracked connection that differs from Productboard and adopts
+// connections only Productboard knows about, e.g. after a restart
+func (cs DefaultPbConnectionService) SyncConnections() api_error.ApiErr {
+	integrationId, err := cs.integrationId()
+	if err != nil {
+		return err
+	}
+	remote, err := cs.repo.GetPluginConnections(integrationId)
+	if err != nil {
+		return err
+	}
+	remoteConns := make(map[string]dto.Connection)
+	for _, data := range remote {
+		remoteConns[data.FeatureId] = data.Connection
+	}
+	cs.cache.Lock()
+	local := make(map[string]dto.Connection)
+	for featureId, conn := range cs.cache.connections {
+		local[featureId] = conn
+	}
+	for featureId, conn := range remoteConns {
+		if _, found := local[featureId]; !found && conn.State != dto.ConnectionInitial {
+			cs.cache.connections[featureId] = conn
+		}
+	}
+	cs.save()
+	cs.cache.Unlock()
+
+	updated, failed := 0, 0
+	for featureId, conn := range local {
+		if remoteConn, found := remoteConns[featureId]; found && remoteConn == conn {
+			continue
+		}
+		if _, err := cs.repo.UpdatePluginConnection(integrationId, featureId, conn); err != nil {
+			logger.Error(fmt.Sprintf("Could not sync connection of feature %v", featureId), err)
+			failed++
+			continue
+		}
+		updated++
+	}
+	logger.Info(fmt.Sprintf("Synced plugin integration connections. %v updated, %v failed", updated, failed))
+	if failed > 0 {
+		msg := fmt.Sprintf("Could not sync %v plugin integration connection(s)", failed)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	return nil
+}
+
+// save persists the connections; the lock has to be held. A failed save is logged only, Productboard keeps the connection anyway.
+func (cs DefaultPbConnectionService) save() {
+	if err := cs.store.Save(cs.cache.connections); err != nil {
+		logger.Error("Could not save plugin integration connections", err)
+	}
+}
+
+func (cs DefaultPbConnectionService) integrationId() (string, api_error.ApiErr) {
+	if cs.cfg.RunTime.PluginIntegrationId == "" {
+		msg := "No plugin integration registered"
+		logger.Error(msg, nil)
+		return "", api_error.NewInternalServerError(msg, nil)
+	}
+	return cs.cfg.RunTime.PluginIntegrationId, nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/pbreact/repository"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	cs DefaultPbConnectionService
+)
+
+func setupConnections(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	cfg.RunTime.PluginIntegrationId = "i1"
+	cs, _ = NewPbConnectionService(&cfg, mockPbApiRepo, repository.NewConnectionRepository(filepath.Join(t.TempDir(), "connections.json")))
+	return func() {
+		pbApiCtrl.Finish()
+	}
+}
+
+func Test_GetConnection_Unknown_Returns_Initial(t *testing.T) {
+	teardown := setupConnections(t)
+	defer teardown()
+
+	conn := cs.GetConnection("f1")
+
+	assert.EqualValues(t, dto.ConnectionInitial, conn.State)
+}
+
+func Test_UpdateConnection_NoIntegration_Returns_Error(t *testing.T) {
+	teardown := setupConnections(t)
+	defer teardown()
+	cfg.RunTime.PluginIntegrationId = ""
+
+	err := cs.UpdateConnection("f1", dto.Connection{State: dto.ConnectionConnected})
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "No plugin integration registered", err.Message())
+}
+
+func Test_UpdateConnection_Updates_And_Tracks_Connection(t *testing.T) {
+	teardown := setupConnections(t)
+	defer teardown()
+	conn := dto.Connection{State: dto.ConnectionConnected, Label: "PB-1", TargetUrl: "https://tracker/PB-1"}
+	mockPbApiRepo.EXPECT().UpdatePluginConnection("i1", "f1", conn).Return(&dto.ConnectionData{Connection: conn}, nil)
+
+	err := cs.UpdateConnection("f1", conn)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, conn, cs.GetConnection("f1"))
+}
+
+func Test_UpdateConnection_RepoError_Returns_Error(t *testing.T) {
+	teardown := setupConnections(t)
+	defer teardown()
+	apiError := api_error.NewInternalServerError("something went wrong", nil)
+	mockPbApiRepo.EXPECT().UpdatePluginConnection("i1", "f1", gomock.Any()).Return(nil, apiError)
+
+	err := cs.SetError("f1", "failed")
+
+	assert.EqualValues(t, apiError, err)
+	assert.EqualValues(t, dto.ConnectionInitial, cs.GetConnection("f1").State)
+}
+
+func Test_ResetConnection_Deletes_Connection(t *testing.T) {
+	teardown := setupConnections(t)
+	defer teardown()
+	cs.TrackConnection("f1", dto.Connection{State: dto.ConnectionConnected})
+	mockPbApiRepo.EXPECT().DeletePluginConnection("i1", "f1").Return(nil)
+
+	err := cs.ResetConnection("f1")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, dto.ConnectionInitial, cs.GetConnection("f1").State)
+}
+
+func Test_SyncConnections_Pushes_Drifted_And_Adopts_Remote(t *testing.T) {
+	teardown := setupConnections(t)
+	defer teardown()
+	inSync := dto.Connection{State: dto.ConnectionConnected, Label: "PB-1"}
+	drifted := dto.Connection{State: dto.ConnectionConnected, Label: "PB-2"}
+	remoteOnly := dto.Connection{State: dto.ConnectionError, Message: "failed"}
+	cs.TrackConnection("f1", inSync)
+	cs.TrackConnection("f2", drifted)
+	mockPbApiRepo.EXPECT().GetPluginConnections("i1").Return([]dto.ConnectionData{
+		{FeatureId: "f1", Connection: inSync},
+		{FeatureId: "f2", Connection: dto.Connection{State: dto.ConnectionInitial}},
+		{FeatureId: "f3", Connection: remoteOnly},
+	}, nil)
+	mockPbApiRepo.EXPECT().UpdatePluginConnection("i1", "f2", drifted).Return(&dto.ConnectionData{Connection: drifted}, nil)
+
+	err := cs.SyncConnections()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, remoteOnly, cs.GetConnection("f3"))
+}
+
+func Test_SyncConnections_UpdateFails_Returns_Error(t *testing.T) {
+	teardown := setupConnections(t)
+	defer teardown()
+	cs.TrackConnection("f1", dto.Connection{State: dto.ConnectionConnected})
+	mockPbApiRepo.EXPECT().GetPluginConnections("i1").Return([]dto.ConnectionData{}, nil)
+	mockPbApiRepo.EXPECT().UpdatePluginConnection("i1", "f1", gomock.Any()).Return(nil, api_error.NewInternalServerError("something went wrong", nil))
+
+	err := cs.SyncConnections()
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Could not sync 1 plugin integration connection(s)", err.Message())
+}
+
+func Test_SyncConnections_AfterRestart_Corrects_Remote(t *testing.T) {
+	teardown := setupConnections(t)
+	defer teardown()
+	store := repository.NewConnectionRepository(filepath.Join(t.TempDir(), "connections.json"))
+	local := dto.Connection{State: dto.ConnectionConnected, Label: "PB-1"}
+	before, _ := NewPbConnectionService(&cfg, mockPbApiRepo, store)
+	before.TrackConnection("f1", local)
+	mockPbApiRepo.EXPECT().GetPluginConnections("i1").Return([]dto.ConnectionData{
+		{FeatureId: "f1", Connection: dto.Connection{State: dto.ConnectionError, Message: "failed"}},
+	}, nil)
+	mockPbApiRepo.EXPECT().UpdatePluginConnection("i1", "f1", local).Return(&dto.ConnectionData{Connection: local}, nil)
+
+	restarted, loadErr := NewPbConnectionService(&cfg, mockPbApiRepo, store)
+	err := restarted.SyncConnections()
+
+	assert.Nil(t, loadErr)
+	assert.Nil(t, err)
+	assert.EqualValues(t, local, restarted.GetConnection("f1"))
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+const (
+	digestTimeLayout  = "2006-01-02 15:04"
+	defaultDigestText = `{{.Subject}}
+{{date .From}} to {{date .To}} (UTC)
+{{if .Empty}}
+No feature activity in this period.
+{{end}}{{with .Created}}
+Created ({{len .}})
+{{range .}}- {{.FeatureName}}
+{{end}}{{end}}{{with .Deleted}}
+Deleted ({{len .}})
+{{range .}}- {{.FeatureName}}
+{{end}}{{end}}{{with .StatusChanges}}
+Status changes ({{len .}})
+{{range .}}- {{.FeatureName}}: {{.Old}} -> {{.New}}
+{{end}}{{end}}{{with .TimeframeChanges}}
+Timeframe changes ({{len .}})
+{{range .}}- {{.FeatureName}}: {{.Old}} -> {{.New}}
+{{end}}{{end}}{{with .Slips}}
+Slips ({{len .}})
+{{range .}}- {{.FeatureName}}: end date moved {{.Days}} day(s) from {{.Old}} to {{.New}}
+{{end}}{{end}}`
+)
+
+var (
+	digestFuncs = map[string]interface{}{
+		"date": func(t time.Time) string {
+			return t.UTC().Format(digestTimeLayout)
+		},
+	}
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbDigestService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbDigestService
+type PbDigestService interface {
+	HandleFeatureEvent(dto.FeatureEvent)
+	BuildDigest(time.Time, time.Time) (*dto.Digest, api_error.ApiErr)
+	SendDigest(time.Time, time.Time) api_error.ApiErr
+	ScheduleDigests()
+	StopDigests()
+}
+
+type DefaultPbDigestService struct {
+	journal  domain.FeatureJournalRepository
+	mail     domain.MailRepository
+	webhooks domain.WebhookRepository
+	cfg      *config.AppConfig
+	schedule *cronSchedule
+	text     *texttemplate.Template
+	html     *htmltemplate.Template
+	done     chan bool
+	stop     *sync.Once
+}
+
+// NewPbDigestService parses the digest schedule and templates. A template file ending in .html or .htm renders the html part,
+// any other replaces the default text template.
+func NewPbDigestService(c *config.AppConfig, j domain.FeatureJournalRepository, m domain.MailRepository, w domain.WebhookRepository) (DefaultPbDigestService, api_error.ApiErr) {
+	ds := DefaultPbDigestService{
+		journal:  j,
+		mail:     m,
+		webhooks: w,
+		cfg:      c,
+		text:     texttemplate.Must(texttemplate.New("digest").Funcs(digestFuncs).Parse(defaultDigestText)),
+		done:     make(chan bool),
+		stop:     &sync.Once{},
+	}
+	if c.Digest.Schedule != "" {
+		schedule, err := parseCron(c.Digest.Schedule)
+		if err != nil {
+			msg := "Invalid digest schedule"
+			logger.Error(msg, err)
+			return ds, api_error.NewInternalServerError(msg, err)
+		}
+		ds.schedule = schedule
+	}
+	if c.Digest.TemplateFile == "" {
+		return ds, nil
+	}
+	content, err := os.ReadFile(c.Digest.TemplateFile)
+	if err == nil {
+		switch strings.ToLower(filepath.Ext(c.Digest.TemplateFile)) {
+		case ".html", ".htm":
+			ds.html, err = htmltemplate.New("digest").Funcs(digestFuncs).Parse(string(content))
+		default:
+			ds.text, err = texttemplate.New("digest").Funcs(digestFuncs).Parse(string(content))
+		}
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Could not load digest template %v", c.Digest.TemplateFile)
+		logger.Error(msg, err)
+		return ds, api_error.NewInternalServerError(msg, err)
+	}
+	return ds, nil
+}
+
+// HandleFeatureEvent records created and deleted features. Status and timeframe changes are recorded by the flow and slip services.
+func (ds DefaultPbDigestService) HandleFeatureEvent(event dto.FeatureEvent) {
+	entry := dto.JournalEntry{Time: event.ReceivedAt, FeatureId: event.ID}
+	switch {
+	case event.EventType == dto.PbEventTypes["featureCreate"] && event.Feature != nil:
+		entry.Kind = dto.JournalCreated
+		entry.FeatureName = event.Feature.Name
+		entry.Status = event.Feature.Status.Name
+		entry.ParentId = event.Feature.Parent.ParentId()
+	case event.EventType == dto.PbEventTypes["featureDelete"]:
+		entry.Kind = dto.JournalDeleted
+	default:
+		return
+	}
+	ds.journal.Append(entry)
+}
+
+// BuildDigest collects the changes recorded in the journal between from and to (exclusive)
+func (ds DefaultPbDigestService) BuildDigest(from time.Time, to time.Time) (*dto.Digest, api_error.ApiErr) {
+	entries, err := ds.journal.Read(time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	digest := dto.Digest{
+		Subject:          fmt.Sprintf("%v %v", ds.cfg.Digest.Subject, to.Format(featureDateLayout)),
+		From:             from,
+		To:               to,
+		Created:          []dto.DigestItem{},
+		Deleted:          []dto.DigestItem{},
+		StatusChanges:    []dto.DigestItem{},
+		TimeframeChanges: []dto.DigestItem{},
+		Slips:            []dto.DigestItem{},
+	}
+	// deleted features have no name in their event, so remember the last name seen before
+	names := make(map[string]string)
+	for _, entry := range entries {
+		if entry.FeatureName != "" {
+			names[entry.FeatureId] = entry.FeatureName
+		}
+		if entry.Time.Before(from) || !entry.Time.Before(to) {
+			continue
+		}
+		item := dto.DigestItem{FeatureId: entry.FeatureId, FeatureName: names[entry.FeatureId], Time: entry.Time}
+		if item.FeatureName == "" {
+			item.FeatureName = entry.FeatureId
+		}
+		switch entry.Kind {
+		case dto.JournalCreated:
+			digest.Created = append(digest.Created, item)
+		case dto.JournalDeleted:
+			digest.Deleted = append(digest.Deleted, item)
+		case dto.JournalStatus:
+			if entry.OldStatus != "" {
+				item.Old, item.New = entry.OldStatus, entry.Status
+				digest.StatusChanges = append(digest.StatusChanges, item)
+			}
+		case dto.JournalTimeframe:
+			if entry.OldTimeframe == nil || entry.Timeframe == nil {
+				continue
+			}
+			item.Old, item.New = digestTimeframe(*entry.OldTimeframe), digestTimeframe(*entry.Timeframe)
+			digest.TimeframeChanges = append(digest.TimeframeChanges, item)
+			if entry.DaysSlipped > 0 {
+				item.Old, item.New, item.Days = exportDate(entry.OldTimeframe.EndDate), exportDate(entry.Timeframe.EndDate), entry.DaysSlipped
+				digest.Slips = append(digest.Slips, item)
+			}
+		}
+	}
+	return &digest, nil
+}
+
+// SendDigest renders the digest and delivers it by mail, webhook and file, as configured. It tries every channel
+// and returns the first error.
+func (ds DefaultPbDigestService) SendDigest(from time.Time, to time.Time) api_error.ApiErr {
+	digest, err := ds.BuildDigest(from, to)
+	if err != nil {
+		return err
+	}
+	if digest.Empty() && ds.cfg.Digest.SkipEmpty {
+		logger.Info(fmt.Sprintf("No feature activity from %v to %v. Skipping digest", from.Format(digestTimeLayout), to.Format(digestTimeLayout)))
+		return nil
+	}
+	text, html, err := ds.render(*digest)
+	if err != nil {
+		return err
+	}
+	var firstErr api_error.ApiErr
+	keep := func(err api_error.ApiErr) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if len(ds.cfg.Digest.MailTo) > 0 {
+		keep(ds.mail.Send(dto.MailMessage{To: ds.cfg.Digest.MailTo, Subject: digest.Subject, Text: text, Html: html}))
+	}
+	if ds.cfg.Digest.WebhookUrl != "" {
+		keep(ds.webhooks.PostJson(ds.cfg.Digest.WebhookUrl, dto.DigestDocument{Digest: *digest, Text: text, Html: html}))
+	}
+	if ds.cfg.Digest.OutputDir != "" {
+		name := filepath.Join(ds.cfg.Digest.OutputDir, fmt.Sprintf("digest-%v", to.Format("2006-01-02-1504")))
+		keep(ds.writeFile(name+".txt", text))
+		if html != "" {
+			keep(ds.writeFile(name+".html", html))
+		}
+	}
+	if firstErr == nil {
+		logger.Info(fmt.Sprintf("Sent digest \"%v\"", digest.Subject))
+	}
+	return firstErr
+}
+
+// ScheduleDigests sends a digest every time the schedule fires, covering the time since it fired before
+func (ds DefaultPbDigestService) ScheduleDigests() {
+	if ds.schedule == nil {
+		return
+	}
+	logger.Info(fmt.Sprintf("Sending digests on schedule \"%v\"", ds.cfg.Digest.Schedule))
+	for {
+		now := date.GetNowUtc()
+		next := ds.schedule.next(now)
+		if next.IsZero() {
+			logger.Warn(fmt.Sprintf("Digest schedule \"%v\" never fires", ds.cfg.Digest.Schedule))
+			return
+		}
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-timer.C:
+		case <-ds.done:
+			timer.Stop()
+			logger.Info("Stopped sending digests")
+			return
+		}
+		if err := ds.SendDigest(ds.schedule.prev(next), next); err != nil {
+			logger.Error("Could not send digest", err)
+		}
+	}
+}
+
+func (ds DefaultPbDigestService) StopDigests() {
+	ds.stop.Do(func() {
+		close(ds.done)
+	})
+}
+
+func (ds DefaultPbDigestService) render(digest dto.Digest) (string, string, api_error.ApiErr) {
+	var text, html bytes.Buffer
+	err := ds.text.Execute(&text, digest)
+	if err == nil && ds.html != nil {
+		err = ds.html.Execute(&html, digest)
+	}
+	if err != nil {
+		msg := "Could not render digest"
+		logger.Error(msg, err)
+		return "", "", api_error.NewInternalServerError(msg, err)
+	}
+	return text.String(), html.String(), nil
+}
+
+func (ds DefaultPbDigestService) writeFile(file string, content string) api_error.ApiErr {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		msg := fmt.Sprintf("Could not create digest directory for %v", file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		msg := fmt.Sprintf("Could not write digest %v", file)
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, err)
+	}
+	return nil
+}
+
+func digestTimeframe(timeframe dto.Timeframe) string {
+	start, end := exportDate(timeframe.StartDate), exportDate(timeframe.EndDate)
+	if start == "" && end == "" {
+		return "none"
+	}
+	return fmt.Sprintf("%v - %v", start, end)
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	dgs          DefaultPbDigestService
+	mockMailRepo *domain.MockMailRepository
+	digestFrom   = time.Date(2024, 1, 9, 8, 0, 0, 0, time.UTC)
+	digestTo     = time.Date(2024, 1, 10, 8, 0, 0, 0, time.UTC)
+)
+
+func setupDigest(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockJournalRepo = domain.NewMockFeatureJournalRepository(pbApiCtrl)
+	mockWebhookRepo = domain.NewMockWebhookRepository(pbApiCtrl)
+	mockMailRepo = domain.NewMockMailRepository(pbApiCtrl)
+	cfg.Digest.Schedule = "0 8 * * *"
+	cfg.Digest.Subject = "Feature digest"
+	cfg.Digest.TemplateFile = ""
+	cfg.Digest.SkipEmpty = true
+	cfg.Digest.MailTo = []string{"team@example.com"}
+	cfg.Digest.WebhookUrl = ""
+	cfg.Digest.OutputDir = ""
+	dgs, _ = NewPbDigestService(&cfg, mockJournalRepo, mockMailRepo, mockWebhookRepo)
+	return func() {
+		cfg.Digest.Schedule = ""
+		pbApiCtrl.Finish()
+	}
+}
+
+func digestJournal() []dto.JournalEntry {
+	at := func(hour int) time.Time {
+		return time.Date(2024, 1, 9, hour, 0, 0, 0, time.UTC)
+	}
+	return []dto.JournalEntry{
+		{Time: at(7), FeatureId: "f1", FeatureName: "Dark mode", Kind: dto.JournalCreated},
+		{Time: at(7), FeatureId: "f2", FeatureName: "Old reports", Kind: dto.JournalStatus, Status: "New idea"},
+		{Time: at(9), FeatureId: "f3", FeatureName: "Search", Kind: dto.JournalCreated, Status: "New idea"},
+		{Time: at(9), FeatureId: "f3", FeatureName: "Search", Kind: dto.JournalStatus, Status: "New idea"},
+		{Time: at(10), FeatureId: "f1", FeatureName: "Dark mode", Kind: dto.JournalStatus, Status: "In progress", OldStatus: "New idea"},
+		{Time: at(11), FeatureId: "f1", FeatureName: "Dark mode", Kind: dto.JournalTimeframe, Timeframe: &dto.Timeframe{StartDate: "2024-01-01", EndDate: "2024-03-31"}},
+		{Time: at(12), FeatureId: "f1", FeatureName: "Dark mode", Kind: dto.JournalTimeframe, Timeframe: &dto.Timeframe{StartDate: "2024-01-01", EndDate: "2024-04-15"},
+			OldTimeframe: &dto.Timeframe{StartDate: "2024-01-01", EndDate: "2024-03-31"}, DaysSlipped: 15},
+		{Time: at(13), FeatureId: "f2", Kind: dto.JournalDeleted},
+		{Time: at(14), FeatureId: "f9", Kind: dto.JournalDeleted},
+		{Time: digestTo, FeatureId: "f4", FeatureName: "Tomorrow", Kind: dto.JournalCreated},
+	}
+}
+
+func Test_HandleFeatureEvent_CreateAndDelete_Appends_Journal(t *testing.T) {
+	teardown := setupDigest(t)
+	defer teardown()
+	feature := dto.Feature{ID: "f1", Name: "Dark mode", Status: dto.FeatureStatus{Name: "New idea"}, Parent: dto.Parent{Product: &dto.ParentRef{ID: "p1"}}}
+
+	mockJournalRepo.EXPECT().Append(dto.JournalEntry{Time: digestFrom, FeatureId: "f1", FeatureName: "Dark mode", Kind: dto.JournalCreated, Status: "New idea", ParentId: "p1"}).Return(nil)
+	mockJournalRepo.EXPECT().Append(dto.JournalEntry{Time: digestTo, FeatureId: "f1", Kind: dto.JournalDeleted}).Return(nil)
+
+	dgs.HandleFeatureEvent(dto.FeatureEvent{ID: "f1", EventType: dto.PbEventTypes["featureCreate"], Feature: &feature, ReceivedAt: digestFrom})
+	dgs.HandleFeatureEvent(dto.FeatureEvent{ID: "f1", EventType: dto.PbEventTypes["featureUpdate"], Feature: &feature, ReceivedAt: digestFrom})
+	dgs.HandleFeatureEvent(dto.FeatureEvent{ID: "f1", EventType: dto.PbEventTypes["featureDelete"], ReceivedAt: digestTo})
+}
+
+func Test_BuildDigest_Collects_ChangesInPeriod(t *testing.T) {
+	teardown := setupDigest(t)
+	defer teardown()
+
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return(digestJournal(), nil)
+
+	digest, err := dgs.BuildDigest(digestFrom, digestTo)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Feature digest 2024-01-10", digest.Subject)
+	assert.EqualValues(t, 1, len(digest.Created))
+	assert.EqualValues(t, "Search", digest.Created[0].FeatureName)
+	assert.EqualValues(t, []string{"Old reports", "f9"}, []string{digest.Deleted[0].FeatureName, digest.Deleted[1].FeatureName})
+	assert.EqualValues(t, 1, len(digest.StatusChanges))
+	assert.EqualValues(t, "New idea", digest.StatusChanges[0].Old)
+	assert.EqualValues(t, "In progress", digest.StatusChanges[0].New)
+	assert.EqualValues(t, 1, len(digest.TimeframeChanges))
+	assert.EqualValues(t, "2024-01-01 - 2024-03-31", digest.TimeframeChanges[0].Old)
+	assert.EqualValues(t, dto.DigestItem{FeatureId: "f1", FeatureName: "Dark mode", Time: digestFrom.Add(4 * time.Hour), Old: "2024-03-31", New: "2024-04-15", Days: 15}, digest.Slips[0])
+}
+
+func Test_SendDigest_Delivers_MailWebhookAndFile(t *testing.T) {
+	teardown := setupDigest(t)
+	defer teardown()
+	cfg.Digest.WebhookUrl = "http://hooks/digest"
+	cfg.Digest.OutputDir = t.TempDir()
+	var mail dto.MailMessage
+	var document dto.DigestDocument
+
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return(digestJournal(), nil)
+	mockMailRepo.EXPECT().Send(gomock.Any()).DoAndReturn(func(message dto.MailMessage) api_error.ApiErr {
+		mail = message
+		return nil
+	})
+	mockWebhookRepo.EXPECT().PostJson("http://hooks/digest", gomock.Any()).DoAndReturn(func(_ string, payload interface{}) api_error.ApiErr {
+		document = payload.(dto.DigestDocument)
+		return nil
+	})
+
+	err := dgs.SendDigest(digestFrom, digestTo)
+
+	file, readErr := os.ReadFile(filepath.Join(cfg.Digest.OutputDir, "digest-2024-01-10-0800.txt"))
+	assert.Nil(t, err)
+	assert.Nil(t, readErr)
+	assert.EqualValues(t, []string{"team@example.com"}, mail.To)
+	assert.EqualValues(t, "Feature digest 2024-01-10", mail.Subject)
+	assert.EqualValues(t, "", mail.Html)
+	assert.EqualValues(t, "Feature digest 2024-01-10\n"+
+		"2024-01-09 08:00 to 2024-01-10 08:00 (UTC)\n"+
+		"\nCreated (1)\n- Search\n"+
+		"\nDeleted (2)\n- Old reports\n- f9\n"+
+		"\nStatus changes (1)\n- Dark mode: New idea -> In progress\n"+
+		"\nTimeframe changes (1)\n- Dark mode: 2024-01-01 - 2024-03-31 -> 2024-01-01 - 2024-04-15\n"+
+		"\nSlips (1)\n- Dark mode: end date moved 15 day(s) from 2024-03-31 to 2024-04-15\n", mail.Text)
+	assert.EqualValues(t, mail.Text, document.Text)
+	assert.EqualValues(t, 2, len(document.Deleted))
+	assert.EqualValues(t, mail.Text, string(file))
+}
+
+func Test_SendDigest_Empty_Skips_Delivery(t *testing.T) {
+	teardown := setupDigest(t)
+	defer teardown()
+
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return(digestJournal(), nil)
+
+	err := dgs.SendDigest(digestTo.Add(time.Minute), digestTo.Add(time.Hour))
+
+	assert.Nil(t, err)
+}
+
+func Test_SendDigest_HtmlTemplate_Sends_Alternatives(t *testing.T) {
+	teardown := setupDigest(t)
+	defer teardown()
+	cfg.Digest.TemplateFile = filepath.Join(t.TempDir(), "digest.html")
+	os.WriteFile(cfg.Digest.TemplateFile, []byte("<h1>{{.Subject}}</h1>{{range .Created}}<p>{{.FeatureName}}</p>{{end}}"), 0644)
+	dgs, _ = NewPbDigestService(&cfg, mockJournalRepo, mockMailRepo, mockWebhookRepo)
+	var mail dto.MailMessage
+
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return([]dto.JournalEntry{{Time: digestFrom, FeatureId: "f1", FeatureName: "<b>Bold</b>", Kind: dto.JournalCreated}}, nil)
+	mockMailRepo.EXPECT().Send(gomock.Any()).DoAndReturn(func(message dto.MailMessage) api_error.ApiErr {
+		mail = message
+		return nil
+	})
+
+	err := dgs.SendDigest(digestFrom, digestTo)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "<h1>Feature digest 2024-01-10</h1><p>&lt;b&gt;Bold&lt;/b&gt;</p>", mail.Html)
+	assert.Contains(t, mail.Text, "Created (1)\n- <b>Bold</b>\n")
+}
+
+func Test_NewPbDigestService_InvalidSchedule_Returns_Error(t *testing.T) {
+	teardown := setupDigest(t)
+	defer teardown()
+	cfg.Digest.Schedule = "every day"
+
+	_, err := NewPbDigestService(&cfg, mockJournalRepo, mockMailRepo, mockWebhookRepo)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Invalid digest schedule", err.Message())
+}
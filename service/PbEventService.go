@@ -0,0 +1,141 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+// FeatureEventHandler is implemented by everything that wants to react to feature events
+type FeatureEventHandler interface {
+	HandleFeatureEvent(dto.FeatureEvent)
+}
+
+// FeatureEventEnricher adds data to a feature event before it is handed to the handlers
+type FeatureEventEnricher interface {
+	EnrichFeatureEvent(*dto.FeatureEvent)
+}
+
+//go:generate mockgen -destination=../mocks/service/mockPbEventService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbEventService
+type PbEventService interface {
+	QueueEvent(dto.PbEventNotification) api_error.ApiErr
+	QueueReconciledEvent(dto.PbEventNotification) api_error.ApiErr
+	AddHandler(FeatureEventHandler)
+	AddEnricher(FeatureEventEnricher)
+	ProcessEvents()
+	StopProcessing()
+}
+
+type queuedEvent struct {
+	notif      dto.PbEventNotification
+	reconciled bool
+}
+
+type DefaultPbEventService struct {
+	repo      domain.PbApiRepository
+	cfg       *config.AppConfig
+	queue     chan queuedEvent
+	done      chan bool
+	stop      *sync.Once
+	handlers  *[]FeatureEventHandler
+	enrichers *[]FeatureEventEnricher
+}
+
+func NewPbEventService(c *config.AppConfig, r domain.PbApiRepository) DefaultPbEventService {
+	return DefaultPbEventService{
+		repo:      r,
+		cfg:       c,
+		queue:     make(chan queuedEvent, c.Events.QueueSize),
+		done:      make(chan bool),
+		stop:      &sync.Once{},
+		handlers:  &[]FeatureEventHandler{},
+		enrichers: &[]FeatureEventEnricher{},
+	}
+}
+
+func (es DefaultPbEventService) AddHandler(h FeatureEventHandler) {
+	*es.handlers = append(*es.handlers, h)
+}
+
+func (es DefaultPbEventService) AddEnricher(e FeatureEventEnricher) {
+	*es.enrichers = append(*es.enrichers, e)
+}
+
+func (es DefaultPbEventService) QueueEvent(notif dto.PbEventNotification) api_error.ApiErr {
+	return es.queueEvent(queuedEvent{notif: notif})
+}
+
+// QueueReconciledEvent queues an event pbreact synthesised itself for a change no webhook was received for
+func (es DefaultPbEventService) QueueReconciledEvent(notif dto.PbEventNotification) api_error.ApiErr {
+	return es.queueEvent(queuedEvent{notif: notif, reconciled: true})
+}
+
+func (es DefaultPbEventService) queueEvent(item queuedEvent) api_error.ApiErr {
+	select {
+	case es.queue <- item:
+		return nil
+	default:
+		msg := "Event queue is full"
+		logger.Error(msg, nil)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+}
+
+func (es DefaultPbEventService) ProcessEvents() {
+	logger.Info("Start processing events")
+	for {
+		select {
+		case item := <-es.queue:
+			es.processEvent(item.notif, item.reconciled)
+		case <-es.done:
+			logger.Info("Stopped processing events")
+			return
+		}
+	}
+}
+
+// StopProcessing can be called more than once, only the first call closes the done channel
+func (es DefaultPbEventService) StopProcessing() {
+	es.stop.Do(func() {
+		close(es.done)
+	})
+}
+
+func (es DefaultPbEventService) processEvent(notif dto.PbEventNotification, reconciled bool) {
+	event, err := es.buildEvent(notif)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Dropping event %v for feature %v", notif.Data.EventType, notif.Data.ID), err)
+		return
+	}
+	event.Reconciled = reconciled
+	for _, e := range *es.enrichers {
+		e.EnrichFeatureEvent(event)
+	}
+	for _, h := range *es.handlers {
+		h.HandleFeatureEvent(*event)
+	}
+}
+
+func (es DefaultPbEventService) buildEvent(notif dto.PbEventNotification) (*dto.FeatureEvent, api_error.ApiErr) {
+	event := dto.FeatureEvent{
+		ID:         notif.Data.ID,
+		EventType:  notif.Data.EventType,
+		Target:     notif.Data.Links.Target,
+		ReceivedAt: date.GetNowUtc(),
+	}
+	if event.EventType == dto.PbEventTypes["featureDelete"] {
+		return &event, nil
+	}
+	feature, err := es.repo.GetFeature(event.ID)
+	if err != nil {
+		return nil, err
+	}
+	event.Feature = feature
+	return &event, nil
+}
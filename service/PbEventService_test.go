@@ -0,0 +1,138 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	es DefaultPbEventService
+)
+
+type recordingHandler struct {
+	events []dto.FeatureEvent
+}
+
+func (h *recordingHandler) HandleFeatureEvent(event dto.FeatureEvent) {
+	h.events = append(h.events, event)
+}
+
+func setupEvents(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	cfg.Events.QueueSize = 1
+	es = NewPbEventService(&cfg, mockPbApiRepo)
+	return func() {
+		pbApiCtrl.Finish()
+	}
+}
+
+func eventNotification(id string, eventType string) dto.PbEventNotification {
+	return dto.PbEventNotification{
+		Data: dto.EventData{
+			ID:        id,
+			EventType: eventType,
+		},
+	}
+}
+
+func Test_QueueEvent_QueueFull_Returns_Error(t *testing.T) {
+	teardown := setupEvents(t)
+	defer teardown()
+
+	err1 := es.QueueEvent(eventNotification("f1", dto.PbEventTypes["featureUpdate"]))
+	err2 := es.QueueEvent(eventNotification("f2", dto.PbEventTypes["featureUpdate"]))
+
+	assert.Nil(t, err1)
+	assert.NotNil(t, err2)
+	assert.EqualValues(t, "Event queue is full", err2.Message())
+}
+
+func Test_processEvent_Update_Dispatches_EventWithFeature(t *testing.T) {
+	teardown := setupEvents(t)
+	defer teardown()
+	h := recordingHandler{}
+	es.AddHandler(&h)
+	feature := dto.Feature{ID: "f1", Name: "Feature 1"}
+
+	mockPbApiRepo.EXPECT().GetFeature("f1").Return(&feature, nil)
+
+	es.processEvent(eventNotification("f1", dto.PbEventTypes["featureUpdate"]), false)
+
+	assert.EqualValues(t, 1, len(h.events))
+	assert.EqualValues(t, "f1", h.events[0].ID)
+	assert.EqualValues(t, feature, *h.events[0].Feature)
+}
+
+func Test_processEvent_Enrichers_Run_BeforeHandlers(t *testing.T) {
+	teardown := setupEvents(t)
+	defer teardown()
+	h := recordingHandler{}
+	es.AddHandler(&h)
+	es.AddEnricher(NewPbJiraService(&cfg, mockPbApiRepo))
+	feature := dto.Feature{ID: "f1", Name: "Feature 1"}
+
+	mockPbApiRepo.EXPECT().GetFeature("f1").Return(&feature, nil)
+	mockPbApiRepo.EXPECT().GetJiraIntegrations().Return([]dto.JiraIntegration{{ID: "j1", IntegrationStatus: dto.IntegrationEnabled}}, nil)
+	mockPbApiRepo.EXPECT().GetJiraIntegrationConnection("j1", "f1").Return(&dto.JiraConnectionData{FeatureId: "f1", Connection: dto.JiraConnection{IssueKey: "PROJ-123"}}, nil)
+
+	es.processEvent(eventNotification("f1", dto.PbEventTypes["featureUpdate"]), false)
+
+	assert.EqualValues(t, 1, len(h.events))
+	assert.EqualValues(t, "PROJ-123", h.events[0].JiraIssues[0].IssueKey)
+}
+
+func Test_processEvent_Delete_DoesNotFetchFeature(t *testing.T) {
+	teardown := setupEvents(t)
+	defer teardown()
+	h := recordingHandler{}
+	es.AddHandler(&h)
+
+	es.processEvent(eventNotification("f1", dto.PbEventTypes["featureDelete"]), false)
+
+	assert.EqualValues(t, 1, len(h.events))
+	assert.Nil(t, h.events[0].Feature)
+}
+
+func Test_processEvent_FetchFails_DropsEvent(t *testing.T) {
+	teardown := setupEvents(t)
+	defer teardown()
+	h := recordingHandler{}
+	es.AddHandler(&h)
+	apiError := api_error.NewInternalServerError("something went wrong", nil)
+
+	mockPbApiRepo.EXPECT().GetFeature("f1").Return(nil, apiError)
+
+	es.processEvent(eventNotification("f1", dto.PbEventTypes["featureCreate"]), false)
+
+	assert.EqualValues(t, 0, len(h.events))
+}
+
+func Test_ProcessEvents_Stops_OnStopProcessing(t *testing.T) {
+	teardown := setupEvents(t)
+	defer teardown()
+	finished := make(chan bool)
+
+	go func() {
+		es.ProcessEvents()
+		finished <- true
+	}()
+	es.StopProcessing()
+
+	assert.True(t, <-finished)
+}
+
+func Test_StopProcessing_CalledTwice_DoesNotPanic(t *testing.T) {
+	teardown := setupEvents(t)
+	defer teardown()
+
+	assert.NotPanics(t, func() {
+		es.StopProcessing()
+		es.StopProcessing()
+	})
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbFeedbackService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbFeedbackService
+type PbFeedbackService interface {
+	SubmitFeedback(dto.FeedbackRequest) (*dto.FeedbackStatus, api_error.ApiErr)
+	GetFeedbackStatus(string) (*dto.FeedbackStatus, api_error.ApiErr)
+	ProcessFeedback()
+	StopProcessing()
+}
+
+type DefaultPbFeedbackService struct {
+	repo  domain.PbApiRepository
+	cfg   *config.AppConfig
+	queue chan string
+	done  chan bool
+	stop  *sync.Once
+	store *feedbackStore
+}
+
+type feedbackStore struct {
+	sync.Mutex
+	notes    map[string]dto.Note
+	statuses map[string]*dto.FeedbackStatus
+	sources  map[string]string
+}
+
+func NewPbFeedbackService(c *config.AppConfig, r domain.PbApiRepository) DefaultPbFeedbackService {
+	return DefaultPbFeedbackService{
+		repo:  r,
+		cfg:   c,
+		queue: make(chan string, c.Feedback.QueueSize),
+		done:  make(chan bool),
+		stop:  &sync.Once{},
+		store: &feedbackStore{
+			notes:    make(map[string]dto.Note),
+			statuses: make(map[string]*dto.FeedbackStatus),
+			sources:  make(map[string]string),
+		},
+	}
+}
+
+// SubmitFeedback queues feedback for note creation. Feedback from a source record that was submitted before is not queued again,
+// unless the earlier submission failed.
+func (fs DefaultPbFeedbackService) SubmitFeedback(req dto.FeedbackRequest) (*dto.FeedbackStatus, api_error.ApiErr) {
+	note, err := fs.normalizeFeedback(req)
+	if err != nil {
+		return nil, err
+	}
+	fs.store.Lock()
+	defer fs.store.Unlock()
+	fs.evictFinished()
+	sourceKey := ""
+	if note.Source != nil {
+		sourceKey = fmt.Sprintf("%v/%v", note.Source.Origin, note.Source.RecordId)
+		if id, found := fs.store.sources[sourceKey]; found && fs.store.statuses[id].Status != dto.FeedbackFailed {
+			logger.Info(fmt.Sprintf("Feedback from source %v was already submitted as %v", sourceKey, id))
+			status := *fs.store.statuses[id]
+			return &status, nil
+		}
+	}
+	trackingId, uuidErr := uuid.NewV4()
+	if uuidErr != nil {
+		msg := "Could not generate feedback tracking id"
+		logger.Error(msg, uuidErr)
+		return nil, api_error.NewInternalServerError(msg, uuidErr)
+	}
+	id := trackingId.String()
+	select {
+	case fs.queue <- id:
+	default:
+		msg := "Feedback queue is full"
+		logger.Error(msg, nil)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	now := date.GetNowUtc()
+	status := dto.FeedbackStatus{
+		TrackingId: id,
+		Status:     dto.FeedbackQueued,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	fs.store.notes[id] = *note
+	fs.store.statuses[id] = &status
+	if sourceKey != "" {
+		fs.store.sources[sourceKey] = id
+	}
+	result := status
+	return &result, nil
+}
+
+func (fs DefaultPbFeedbackService) GetFeedbackStatus(id string) (*dto.FeedbackStatus, api_error.ApiErr) {
+	fs.store.Lock()
+	defer fs.store.Unlock()
+	status, found := fs.store.statuses[id]
+	if !found {
+		msg := fmt.Sprintf("No feedback with tracking id %v found", id)
+		logger.Error(msg, nil)
+		return nil, api_error.NewNotFoundError(msg)
+	}
+	result := *status
+	return &result, nil
+}
+
+func (fs DefaultPbFeedbackService) ProcessFeedback() {
+	logger.Info("Start processing feedback")
+	for {
+		select {
+		case id := <-fs.queue:
+			fs.processFeedback(id)
+		case <-fs.done:
+			logger.Info("Stopped processing feedback")
+			return
+		}
+	}
+}
+
+func (fs DefaultPbFeedbackService) StopProcessing() {
+	fs.stop.Do(func() {
+		close(fs.done)
+	})
+}
+
+// evictFinished forgets feedback that was created or failed longer ago than the retention. The lock has to be held.
+func (fs DefaultPbFeedbackService) evictFinished() {
+	if fs.cfg.Feedback.Retention <= 0 {
+		return
+	}
+	cutoff := date.GetNowUtc().Add(-time.Duration(fs.cfg.Feedback.Retention) * time.Hour)
+	for id, status := range fs.store.statuses {
+		finished := status.Status != dto.FeedbackQueued && status.Status != dto.FeedbackRetrying
+		if finished && status.UpdatedAt.Before(cutoff) {
+			delete(fs.store.statuses, id)
+		}
+	}
+	for key, id := range fs.store.sources {
+		if _, found := fs.store.statuses[id]; !found {
+			delete(fs.store.sources, key)
+		}
+	}
+}
+
+func (fs DefaultPbFeedbackService) processFeedback(id string) {
+	fs.store.Lock()
+	note := fs.store.notes[id]
+	fs.store.statuses[id].Attempts++
+	attempts := fs.store.statuses[id].Attempts
+	fs.store.Unlock()
+
+	result, err := fs.repo.CreateNote(note)
+
+	fs.store.Lock()
+	defer fs.store.Unlock()
+	status := fs.store.statuses[id]
+	status.UpdatedAt = date.GetNowUtc()
+	if err == nil {
+		status.Error = ""
+		status.NoteId = result.ID
+		status.NoteUrl = result.Url
+		status.Status = dto.FeedbackCreated
+		if result.AlreadyExists {
+			status.Status = dto.FeedbackDuplicate
+		}
+		delete(fs.store.notes, id)
+		return
+	}
+	status.Error = err.Message()
+	if err.StatusCode() < 500 || attempts > fs.cfg.Feedback.MaxRetries {
+		logger.Error(fmt.Sprintf("Giving up on feedback %v after %v attempt(s)", id, attempts), err)
+		status.Status = dto.FeedbackFailed
+		delete(fs.store.notes, id)
+		return
+	}
+	status.Status = dto.FeedbackRetrying
+	delay := time.Duration(fs.cfg.Feedback.RetryDelay) * time.Second * time.Duration(1<<uint(attempts-1))
+	logger.Info(fmt.Sprintf("Retrying feedback %v in %v", id, delay))
+	time.AfterFunc(delay, func() {
+		fs.requeue(id)
+	})
+}
+
+func (fs DefaultPbFeedbackService) requeue(id string) {
+	select {
+	case fs.queue <- id:
+	default:
+		fs.store.Lock()
+		defer fs.store.Unlock()
+		msg := "Feedback queue is full"
+		logger.Error(fmt.Sprintf("Could not retry feedback %v", id), api_error.NewInternalServerError(msg, nil))
+		fs.store.statuses[id].Status = dto.FeedbackFailed
+		fs.store.statuses[id].Error = msg
+		delete(fs.store.notes, id)
+	}
+}
+
+func (fs DefaultPbFeedbackService) normalizeFeedback(req dto.FeedbackRequest) (*dto.Note, api_error.ApiErr) {
+	note := dto.Note{
+		Title:         strings.TrimSpace(req.Title),
+		Content:       strings.TrimSpace(req.Content),
+		CustomerEmail: strings.ToLower(strings.TrimSpace(req.CustomerEmail)),
+		DisplayUrl:    strings.TrimSpace(req.DisplayUrl),
+		Tags:          normalizeTags(req.Tags),
+	}
+	if note.Content == "" {
+		return nil, feedbackValidationError("Feedback content cannot be empty")
+	}
+	if note.Title == "" {
+		note.Title = strings.TrimSpace(strings.SplitN(note.Content, "\n", 2)[0])
+	}
+	if title := []rune(note.Title); len(title) > 255 {
+		note.Title = string(title[:255])
+	}
+	if note.CustomerEmail != "" {
+		address, err := mail.ParseAddress(note.CustomerEmail)
+		if err != nil {
+			return nil, feedbackValidationError(fmt.Sprintf("Invalid customer email \"%v\"", req.CustomerEmail))
+		}
+		note.CustomerEmail = address.Address
+	}
+	recordId := strings.TrimSpace(req.Source.RecordId)
+	if recordId != "" {
+		origin := strings.TrimSpace(req.Source.Origin)
+		if origin == "" {
+			origin = fs.cfg.Feedback.Origin
+		}
+		note.Source = &dto.NoteSource{
+			Origin:   origin,
+			RecordId: recordId,
+		}
+	}
+	return &note, nil
+}
+
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+	return normalized
+}
+
+func feedbackValidationError(msg string) api_error.ApiErr {
+	logger.Error(msg, nil)
+	return api_error.NewBadRequestError(msg)
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	fs DefaultPbFeedbackService
+)
+
+func setupFeedback(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	cfg.Feedback.QueueSize = 10
+	cfg.Feedback.MaxRetries = 1
+	cfg.Feedback.RetryDelay = 0
+	cfg.Feedback.Origin = "pbreact"
+	cfg.Feedback.Retention = 24
+	fs = NewPbFeedbackService(&cfg, mockPbApiRepo)
+	return func() {
+		pbApiCtrl.Finish()
+	}
+}
+
+func Test_SubmitFeedback_NoContent_Returns_BadRequestError(t *testing.T) {
+	teardown := setupFeedback(t)
+	defer teardown()
+
+	status, err := fs.SubmitFeedback(dto.FeedbackRequest{Title: "Title", Content: "  "})
+
+	assert.Nil(t, status)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Feedback content cannot be empty", err.Message())
+}
+
+func Test_SubmitFeedback_InvalidEmail_Returns_BadRequestError(t *testing.T) {
+	teardown := setupFeedback(t)
+	defer teardown()
+
+	status, err := fs.SubmitFeedback(dto.FeedbackRequest{Content: "Content", CustomerEmail: "not an email"})
+
+	assert.Nil(t, status)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Invalid customer email \"not an email\"", err.Message())
+}
+
+func Test_SubmitFeedback_Normalizes_Note(t *testing.T) {
+	teardown := setupFeedback(t)
+	defer teardown()
+	req := dto.FeedbackRequest{
+		Content:       " Please add dark mode\nIt hurts my eyes ",
+		CustomerEmail: " Jane@Example.com",
+		Source:        dto.FeedbackSource{RecordId: "42"},
+		Tags:          []string{"ui", " UI ", "", "wish"},
+	}
+
+	status, err := fs.SubmitFeedback(req)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, dto.FeedbackQueued, status.Status)
+	note := fs.store.notes[status.TrackingId]
+	assert.EqualValues(t, dto.Note{
+		Title:         "Please add dark mode",
+		Content:       "Please add dark mode\nIt hurts my eyes",
+		CustomerEmail: "jane@example.com",
+		Source:        &dto.NoteSource{Origin: "pbreact", RecordId: "42"},
+		Tags:          []string{"ui", "wish"},
+	}, note)
+}
+
+func Test_SubmitFeedback_SameSource_Returns_SameTrackingId(t *testing.T) {
+	teardown := setupFeedback(t)
+	defer teardown()
+	req := dto.FeedbackRequest{Content: "Content", Source: dto.FeedbackSource{Origin: "helpdesk", RecordId: "42"}}
+
+	first, err1 := fs.SubmitFeedback(req)
+	second, err2 := fs.SubmitFeedback(req)
+
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.EqualValues(t, first.TrackingId, second.TrackingId)
+	assert.EqualValues(t, 1, len(fs.queue))
+}
+
+func Test_SubmitFeedback_SameSourceAfterFailure_Queues_Again(t *testing.T) {
+	teardown := setupFeedback(t)
+	defer teardown()
+	req := dto.FeedbackRequest{Content: "Content", Source: dto.FeedbackSource{Origin: "helpdesk", RecordId: "42"}}
+	first, _ := fs.SubmitFeedback(req)
+	<-fs.queue
+
+	mockPbApiRepo.EXPECT().CreateNote(gomock.Any()).Return(nil, api_error.NewBadRequestError("Productboard rejected note"))
+
+	fs.processFeedback(first.TrackingId)
+	second, err := fs.SubmitFeedback(req)
+
+	assert.Nil(t, err)
+	assert.NotEqualValues(t, first.TrackingId, second.TrackingId)
+	assert.EqualValues(t, dto.FeedbackQueued, second.Status)
+	assert.EqualValues(t, 1, len(fs.queue))
+}
+
+func Test_SubmitFeedback_Evicts_FinishedFeedback_AfterRetention(t *testing.T) {
+	teardown := setupFeedback(t)
+	defer teardown()
+	req := dto.FeedbackRequest{Content: "Content", Source: dto.FeedbackSource{Origin: "helpdesk", RecordId: "42"}}
+	old, _ := fs.SubmitFeedback(req)
+	pending, _ := fs.SubmitFeedback(dto.FeedbackRequest{Content: "Other"})
+	fs.store.statuses[old.TrackingId].Status = dto.FeedbackCreated
+	fs.store.statuses[old.TrackingId].UpdatedAt = time.Now().Add(-25 * time.Hour)
+	fs.store.statuses[pending.TrackingId].UpdatedAt = time.Now().Add(-25 * time.Hour)
+
+	fs.SubmitFeedback(dto.FeedbackRequest{Content: "New"})
+
+	_, oldErr := fs.GetFeedbackStatus(old.TrackingId)
+	_, pendingErr := fs.GetFeedbackStatus(pending.TrackingId)
+	assert.EqualValues(t, http.StatusNotFound, oldErr.StatusCode())
+	assert.Nil(t, pendingErr)
+	assert.EqualValues(t, 0, len(fs.store.sources))
+}
+
+func Test_GetFeedbackStatus_Unknown_Returns_NotFoundError(t *testing.T) {
+	teardown := setupFeedback(t)
+	defer teardown()
+
+	status, err := fs.GetFeedbackStatus("unknown")
+
+	assert.Nil(t, status)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+}
+
+func Test_processFeedback_Success_Sets_Created(t *testing.T) {
+	teardown := setupFeedback(t)
+	defer teardown()
+	queued, _ := fs.SubmitFeedback(dto.FeedbackRequest{Content: "Content"})
+	<-fs.queue
+
+	mockPbApiRepo.EXPECT().CreateNote(gomock.Any()).Return(&dto.NoteResult{ID: "n1", Url: "https://pb/notes/n1"}, nil)
+
+	fs.processFeedback(queued.TrackingId)
+
+	status, _ := fs.GetFeedbackStatus(queued.TrackingId)
+	assert.EqualValues(t, dto.FeedbackCreated, status.Status)
+	assert.EqualValues(t, 1, status.Attempts)
+	assert.EqualValues(t, "https://pb/notes/n1", status.NoteUrl)
+}
+
+func Test_processFeedback_ServerError_Retries_ThenFails(t *testing.T) {
+	teardown := setupFeedback(t)
+	defer teardown()
+	apiError := api_error.NewInternalServerError("something went wrong", nil)
+	queued, _ := fs.SubmitFeedback(dto.FeedbackRequest{Content: "Content"})
+	<-fs.queue
+
+	mockPbApiRepo.EXPECT().CreateNote(gomock.Any()).Return(nil, apiError).Times(2)
+
+	fs.processFeedback(queued.TrackingId)
+	retrying, _ := fs.GetFeedbackStatus(queued.TrackingId)
+	fs.processFeedback(<-fs.queue)
+	failed, _ := fs.GetFeedbackStatus(queued.TrackingId)
+
+	assert.EqualValues(t, dto.FeedbackRetrying, retrying.Status)
+	assert.EqualValues(t, dto.FeedbackFailed, failed.Status)
+	assert.EqualValues(t, 2, failed.Attempts)
+	assert.EqualValues(t, "something went wrong", failed.Error)
+}
+
+func Test_processFeedback_ClientError_Fails_WithoutRetry(t *testing.T) {
+	teardown := setupFeedback(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("Productboard rejected note")
+	queued, _ := fs.SubmitFeedback(dto.FeedbackRequest{Content: "Content"})
+	<-fs.queue
+
+	mockPbApiRepo.EXPECT().CreateNote(gomock.Any()).Return(nil, apiError)
+
+	fs.processFeedback(queued.TrackingId)
+
+	status, _ := fs.GetFeedbackStatus(queued.TrackingId)
+	assert.EqualValues(t, dto.FeedbackFailed, status.Status)
+	assert.EqualValues(t, 0, len(fs.queue))
+}
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+var (
+	flowThroughputCsvHeader = []string{"week", "product", "component", "count"}
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbFlowService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbFlowService
+type PbFlowService interface {
+	HandleFeatureEvent(dto.FeatureEvent)
+	RecordStatuses([]dto.Feature) api_error.ApiErr
+	GetReport(dto.FlowFilter) (*dto.FlowReport, api_error.ApiErr)
+	WriteCsv(io.Writer, string, dto.FlowFilter) api_error.ApiErr
+}
+
+type DefaultPbFlowService struct {
+	journal   domain.FeatureJournalRepository
+	hierarchy PbHierarchyService
+	cfg       *config.AppConfig
+	flow      *flowState
+}
+
+// flowState is the last status seen per feature, so only actual status changes end up in the journal
+type flowState struct {
+	sync.Mutex
+	loaded   bool
+	statuses map[string]string
+}
+
+func NewPbFlowService(c *config.AppConfig, j domain.FeatureJournalRepository, h PbHierarchyService) DefaultPbFlowService {
+	return DefaultPbFlowService{
+		journal:   j,
+		hierarchy: h,
+		cfg:       c,
+		flow:      &flowState{},
+	}
+}
+
+// ParseFlowFilter splits comma separated lists and parses since as date (2006-01-02) or RFC 3339 time
+func ParseFlowFilter(products []string, components []string, since string) (*dto.FlowFilter, api_error.ApiErr) {
+	filter := dto.FlowFilter{
+		Products:   splitList(products),
+		Components: splitList(components),
+	}
+	if since == "" {
+		return &filter, nil
+	}
+	sinceTime, err := time.Parse(featureDateLayout, since)
+	if err != nil {
+		sinceTime, err = time.Parse(time.RFC3339, since)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Could not parse since \"%v\". Use YYYY-MM-DD or RFC 3339", since)
+		logger.Error(msg, err)
+		return nil, api_error.NewBadRequestError(msg)
+	}
+	filter.Since = sinceTime.UTC()
+	return &filter, nil
+}
+
+// HandleFeatureEvent records the feature's status in the journal whenever it differs from the last one seen
+func (fs DefaultPbFlowService) HandleFeatureEvent(event dto.FeatureEvent) {
+	if event.Feature == nil {
+		return
+	}
+	if err := fs.ensureLoaded(); err != nil {
+		logger.Error(fmt.Sprintf("Could not record status of feature %v", event.ID), err)
+		return
+	}
+	fs.flow.Lock()
+	defer fs.flow.Unlock()
+	fs.record(*event.Feature, event.ReceivedAt)
+}
+
+// RecordStatuses records the current status of every feature whose status is missing from the journal or differs from
+// the last one seen. History then also starts for features that were already in their status when pbreact first saw them,
+// counted from now, and a missed status change is caught up on.
+func (fs DefaultPbFlowService) RecordStatuses(features []dto.Feature) api_error.ApiErr {
+	if err := fs.ensureLoaded(); err != nil {
+		return err
+	}
+	fs.flow.Lock()
+	defer fs.flow.Unlock()
+	now := date.GetNowUtc()
+	for _, feature := range features {
+		fs.record(feature, now)
+	}
+	return nil
+}
+
+// record appends the feature's status to the journal if it changed; the lock has to be held
+func (fs DefaultPbFlowService) record(feature dto.Feature, at time.Time) {
+	old, found := fs.flow.statuses[feature.ID]
+	if found && old == feature.Status.Name {
+		return
+	}
+	entry := dto.JournalEntry{
+		Time:        at,
+		FeatureId:   feature.ID,
+		FeatureName: feature.Name,
+		Kind:        dto.JournalStatus,
+		Status:      feature.Status.Name,
+		OldStatus:   old,
+		ParentId:    feature.Parent.ParentId(),
+	}
+	if err := fs.journal.Append(entry); err != nil {
+		return
+	}
+	fs.flow.statuses[feature.ID] = feature.Status.Name
+	if found {
+		logger.Info(fmt.Sprintf("Status of feature %v changed from \"%v\" to \"%v\"", feature.ID, old, feature.Status.Name))
+	}
+}
+
+// GetReport computes time-in-status, lead and cycle time and weekly throughput from the status changes in the journal.
+// Lead time counts from the first status seen, cycle time from the first start status, both up to reaching an end status.
+func (fs DefaultPbFlowService) GetReport(filter dto.FlowFilter) (*dto.FlowReport, api_error.ApiErr) {
+	entries, err := fs.journal.Read(time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	now := date.GetNowUtc()
+	report := dto.FlowReport{
+		GeneratedAt:   now,
+		StartStatuses: fs.cfg.Flow.StartStatuses,
+		EndStatuses:   fs.endStatuses(),
+		Features:      []dto.FeatureFlow{},
+		Throughput:    []dto.WeeklyThroughput{},
+	}
+	histories := make(map[string][]dto.JournalEntry)
+	ids := []string{}
+	for _, entry := range entries {
+		if entry.Kind != dto.JournalStatus {
+			continue
+		}
+		if _, found := histories[entry.FeatureId]; !found {
+			ids = append(ids, entry.FeatureId)
+		}
+		histories[entry.FeatureId] = append(histories[entry.FeatureId], entry)
+	}
+	sort.Strings(ids)
+	throughput := make(map[dto.WeeklyThroughput]int)
+	var leadTimes, cycleTimes []float64
+	for _, id := range ids {
+		flow, path := fs.featureFlow(histories[id], now)
+		if !pathMatches(path, dto.NodeTypeProduct, filter.Products) || !pathMatches(path, dto.NodeTypeComponent, filter.Components) {
+			continue
+		}
+		if flow.FinishedAt != nil && flow.FinishedAt.Before(filter.Since) {
+			continue
+		}
+		report.Features = append(report.Features, flow)
+		if flow.FinishedAt == nil {
+			continue
+		}
+		year, week := flow.FinishedAt.ISOWeek()
+		throughput[dto.WeeklyThroughput{Week: fmt.Sprintf("%04d-W%02d", year, week), Product: flow.Product, Component: flow.Component}]++
+		leadTimes = append(leadTimes, *flow.LeadTimeDays)
+		if flow.CycleTimeDays != nil {
+			cycleTimes = append(cycleTimes, *flow.CycleTimeDays)
+		}
+	}
+	for key, count := range throughput {
+		key.Count = count
+		report.Throughput = append(report.Throughput, key)
+	}
+	sort.Slice(report.Throughput, func(i, j int) bool {
+		a, b := report.Throughput[i], report.Throughput[j]
+		if a.Week != b.Week {
+			return a.Week < b.Week
+		}
+		if a.Product != b.Product {
+			return a.Product < b.Product
+		}
+		return a.Component < b.Component
+	})
+	report.Finished = len(leadTimes)
+	report.AvgLeadTimeDays = average(leadTimes)
+	report.AvgCycleTimeDays = average(cycleTimes)
+	return &report, nil
+}
+
+// WriteCsv writes either the features or the throughput table of the flow report as CSV
+func (fs DefaultPbFlowService) WriteCsv(w io.Writer, table string, filter dto.FlowFilter) api_error.ApiErr {
+	if table != dto.FlowTableFeatures && table != dto.FlowTableThroughput {
+		msg := fmt.Sprintf("Unknown flow table \"%v\"", table)
+		logger.Error(msg, nil)
+		return api_error.NewBadRequestError(msg)
+	}
+	report, err := fs.GetReport(filter)
+	if err != nil {
+		return err
+	}
+	out := csv.NewWriter(w)
+	if table == dto.FlowTableThroughput {
+		out.Write(flowThroughputCsvHeader)
+		for _, t := range report.Throughput {
+			out.Write([]string{t.Week, t.Product, t.Component, strconv.Itoa(t.Count)})
+		}
+	} else {
+		writeFlowFeatures(out, report.Features)
+	}
+	out.Flush()
+	if writeErr := out.Error(); writeErr != nil {
+		msg := "Could not write flow report"
+		logger.Error(msg, writeErr)
+		return api_error.NewInternalServerError(msg, writeErr)
+	}
+	return nil
+}
+
+// featureFlow walks the status history of a feature. Time in an end status only counts if the feature left it again.
+func (fs DefaultPbFlowService) featureFlow(history []dto.JournalEntry, now time.Time) (dto.FeatureFlow, []dto.HierarchyNode) {
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Time.Before(history[j].Time)
+	})
+	last := history[len(history)-1]
+	flow := dto.FeatureFlow{
+		FeatureId:    last.FeatureId,
+		Name:         last.FeatureName,
+		Status:       last.Status,
+		FirstSeenAt:  history[0].Time,
+		TimeInStatus: []dto.StatusTime{},
+	}
+	durations := make(map[string]time.Duration)
+	parentId := ""
+	for i, entry := range history {
+		at := entry.Time
+		if entry.ParentId != "" {
+			parentId = entry.ParentId
+		}
+		if _, seen := durations[entry.Status]; !seen {
+			durations[entry.Status] = 0
+			flow.TimeInStatus = append(flow.TimeInStatus, dto.StatusTime{Status: entry.Status})
+		}
+		if flow.StartedAt == nil && len(fs.cfg.Flow.StartStatuses) > 0 && containsFold(fs.cfg.Flow.StartStatuses, entry.Status) {
+			flow.StartedAt = &at
+		}
+		if fs.isEnd(entry.Status) {
+			if flow.FinishedAt == nil {
+				flow.FinishedAt = &at
+			}
+		} else {
+			flow.FinishedAt = nil
+		}
+		switch {
+		case i+1 < len(history):
+			durations[entry.Status] += history[i+1].Time.Sub(at)
+		case !fs.isEnd(entry.Status):
+			durations[entry.Status] += now.Sub(at)
+		}
+	}
+	for i := range flow.TimeInStatus {
+		flow.TimeInStatus[i].Days = days(durations[flow.TimeInStatus[i].Status])
+	}
+	if flow.FinishedAt != nil {
+		lead := days(flow.FinishedAt.Sub(flow.FirstSeenAt))
+		flow.LeadTimeDays = &lead
+		if flow.StartedAt != nil && !flow.StartedAt.After(*flow.FinishedAt) {
+			cycle := days(flow.FinishedAt.Sub(*flow.StartedAt))
+			flow.CycleTimeDays = &cycle
+		}
+	}
+	path := hierarchyPath(fs.hierarchy, parentId)
+	flow.Product, flow.Component = productAndComponent(path)
+	return flow, path
+}
+
+func (fs DefaultPbFlowService) ensureLoaded() api_error.ApiErr {
+	fs.flow.Lock()
+	defer fs.flow.Unlock()
+	if fs.flow.loaded {
+		return nil
+	}
+	entries, err := fs.journal.Read(time.Time{})
+	if err != nil {
+		return err
+	}
+	statuses := make(map[string]string)
+	for _, entry := range entries {
+		if entry.Kind == dto.JournalStatus {
+			statuses[entry.FeatureId] = entry.Status
+		}
+	}
+	fs.flow.statuses = statuses
+	fs.flow.loaded = true
+	return nil
+}
+
+// endStatuses falls back to the done statuses if no end statuses are configured
+func (fs DefaultPbFlowService) endStatuses() []string {
+	if len(fs.cfg.Flow.EndStatuses) > 0 {
+		return fs.cfg.Flow.EndStatuses
+	}
+	return fs.cfg.Statuses.Done
+}
+
+func (fs DefaultPbFlowService) isEnd(status string) bool {
+	ends := fs.endStatuses()
+	return len(ends) > 0 && containsFold(ends, status)
+}
+
+// writeFlowFeatures writes one row per feature with a "days:<status>" column for every status any feature was in
+func writeFlowFeatures(out *csv.Writer, features []dto.FeatureFlow) {
+	statuses := []string{}
+	columns := make(map[string]int)
+	for _, flow := range features {
+		for _, st := range flow.TimeInStatus {
+			if _, found := columns[st.Status]; !found {
+				columns[st.Status] = len(statuses)
+				statuses = append(statuses, st.Status)
+			}
+		}
+	}
+	header := []string{"featureId", "name", "product", "component", "status", "firstSeenAt", "startedAt", "finishedAt", "leadTimeDays", "cycleTimeDays"}
+	for _, status := range statuses {
+		header = append(header, "days:"+status)
+	}
+	out.Write(header)
+	for _, flow := range features {
+		row := []string{flow.FeatureId, flow.Name, flow.Product, flow.Component, flow.Status, flow.FirstSeenAt.Format(time.RFC3339),
+			csvTime(flow.StartedAt), csvTime(flow.FinishedAt), csvDays(flow.LeadTimeDays), csvDays(flow.CycleTimeDays)}
+		statusDays := make([]string, len(statuses))
+		for _, st := range flow.TimeInStatus {
+			statusDays[columns[st.Status]] = strconv.FormatFloat(st.Days, 'f', -1, 64)
+		}
+		out.Write(append(row, statusDays...))
+	}
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func csvDays(d *float64) string {
+	if d == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*d, 'f', -1, 64)
+}
+
+// days converts a duration to days, rounded to one decimal
+func days(d time.Duration) float64 {
+	return math.Round(d.Hours()/24*10) / 10
+}
+
+func average(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	avg := math.Round(sum/float64(len(values))*10) / 10
+	return &avg
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	fls DefaultPbFlowService
+)
+
+func setupFlow(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	mockJournalRepo = domain.NewMockFeatureJournalRepository(pbApiCtrl)
+	cfg.Flow.StartStatuses = []string{"In progress"}
+	cfg.Statuses.Done = []string{"Released"}
+	fls = NewPbFlowService(&cfg, mockJournalRepo, NewPbHierarchyService(&cfg, mockPbApiRepo))
+	return func() {
+		cfg.Statuses.Done = nil
+		pbApiCtrl.Finish()
+	}
+}
+
+func flowDay(day int) time.Time {
+	return time.Date(2024, 1, day, 10, 0, 0, 0, time.UTC)
+}
+
+func flowJournal() []dto.JournalEntry {
+	return []dto.JournalEntry{
+		{Time: flowDay(1), FeatureId: "f1", FeatureName: "Dark mode", Kind: dto.JournalStatus, Status: "New idea", ParentId: "c1"},
+		{Time: flowDay(2), FeatureId: "f2", FeatureName: "Reports", Kind: dto.JournalStatus, Status: "In progress", ParentId: "p2"},
+		{Time: flowDay(3), FeatureId: "f1", FeatureName: "Dark mode", Kind: dto.JournalStatus, Status: "In progress", OldStatus: "New idea", ParentId: "c1"},
+		{Time: flowDay(3), FeatureId: "f1", FeatureName: "Dark mode", Kind: dto.JournalTimeframe, Timeframe: &dto.Timeframe{EndDate: "2024-03-31"}},
+		{Time: flowDay(4), FeatureId: "f2", FeatureName: "Reports", Kind: dto.JournalStatus, Status: "Released", OldStatus: "In progress", ParentId: "p2"},
+		{Time: flowDay(5), FeatureId: "f2", FeatureName: "Reports", Kind: dto.JournalStatus, Status: "In progress", OldStatus: "Released", ParentId: "p2"},
+		{Time: flowDay(5), FeatureId: "f3", FeatureName: "Search", Kind: dto.JournalStatus, Status: "In progress", ParentId: "c1"},
+		{Time: flowDay(8), FeatureId: "f1", FeatureName: "Dark mode", Kind: dto.JournalStatus, Status: "Released", OldStatus: "In progress", ParentId: "c1"},
+		{Time: flowDay(9), FeatureId: "f2", FeatureName: "Reports", Kind: dto.JournalStatus, Status: "Released", OldStatus: "In progress", ParentId: "p2"},
+	}
+}
+
+func expectFlowHierarchy() {
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).Return([]dto.Feature{}, nil)
+	mockPbApiRepo.EXPECT().GetProducts().Return([]dto.Product{{ID: "p1", Name: "Portal"}, {ID: "p2", Name: "Analytics"}}, nil)
+	mockPbApiRepo.EXPECT().GetComponents().Return([]dto.Component{{ID: "c1", Name: "Branding", Parent: dto.Parent{Product: &dto.ParentRef{ID: "p1"}}}}, nil)
+}
+
+func Test_HandleFeatureEvent_StatusChange_Appends_Journal(t *testing.T) {
+	teardown := setupFlow(t)
+	defer teardown()
+	feature := dto.Feature{ID: "f1", Name: "Dark mode", Status: dto.FeatureStatus{Name: "New idea"}, Parent: dto.Parent{Component: &dto.ParentRef{ID: "c1"}}}
+	moved := feature
+	moved.Status.Name = "In progress"
+	created := dto.Feature{ID: "f9", Name: "Exports", Status: dto.FeatureStatus{Name: "New idea"}}
+
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return(flowJournal()[:1], nil)
+	mockJournalRepo.EXPECT().Append(dto.JournalEntry{Time: flowDay(3), FeatureId: "f1", FeatureName: "Dark mode", Kind: dto.JournalStatus, Status: "In progress", OldStatus: "New idea", ParentId: "c1"}).Return(nil)
+	mockJournalRepo.EXPECT().Append(dto.JournalEntry{Time: flowDay(4), FeatureId: "f9", FeatureName: "Exports", Kind: dto.JournalStatus, Status: "New idea"}).Return(nil)
+
+	fls.HandleFeatureEvent(dto.FeatureEvent{ID: "f1", EventType: dto.PbEventTypes["featureUpdate"], Feature: &feature, ReceivedAt: flowDay(2)})
+	fls.HandleFeatureEvent(dto.FeatureEvent{ID: "f1", EventType: dto.PbEventTypes["featureUpdate"], Feature: &moved, ReceivedAt: flowDay(3)})
+	fls.HandleFeatureEvent(dto.FeatureEvent{ID: "f9", EventType: dto.PbEventTypes["featureCreate"], Feature: &created, ReceivedAt: flowDay(4)})
+	fls.HandleFeatureEvent(dto.FeatureEvent{ID: "f9", EventType: dto.PbEventTypes["featureDelete"]})
+
+	assert.EqualValues(t, "In progress", fls.flow.statuses["f1"])
+	assert.EqualValues(t, "New idea", fls.flow.statuses["f9"])
+}
+
+func Test_RecordStatuses_Records_Unknown_And_Changed_Features(t *testing.T) {
+	teardown := setupFlow(t)
+	defer teardown()
+	features := []dto.Feature{
+		{ID: "f1", Name: "Dark mode", Status: dto.FeatureStatus{Name: "New idea"}},
+		{ID: "f2", Name: "Reports", Status: dto.FeatureStatus{Name: "Released"}},
+		{ID: "f9", Name: "Exports", Status: dto.FeatureStatus{Name: "Won't do"}},
+	}
+	appended := []dto.JournalEntry{}
+
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return(flowJournal()[:2], nil)
+	mockJournalRepo.EXPECT().Append(gomock.Any()).DoAndReturn(func(entry dto.JournalEntry) api_error.ApiErr {
+		appended = append(appended, entry)
+		return nil
+	}).Times(2)
+
+	err := fls.RecordStatuses(features)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "f2", appended[0].FeatureId)
+	assert.EqualValues(t, "Released", appended[0].Status)
+	assert.EqualValues(t, "In progress", appended[0].OldStatus)
+	assert.EqualValues(t, "f9", appended[1].FeatureId)
+	assert.EqualValues(t, "Won't do", appended[1].Status)
+	assert.EqualValues(t, "", appended[1].OldStatus)
+	assert.EqualValues(t, "Won't do", fls.flow.statuses["f9"])
+}
+
+func Test_GetReport_Computes_CycleTimeAndThroughput(t *testing.T) {
+	teardown := setupFlow(t)
+	defer teardown()
+
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return(flowJournal(), nil)
+	expectFlowHierarchy()
+
+	report, err := fls.GetReport(dto.FlowFilter{})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"Released"}, report.EndStatuses)
+	assert.EqualValues(t, 3, len(report.Features))
+	f1 := report.Features[0]
+	assert.EqualValues(t, "Portal", f1.Product)
+	assert.EqualValues(t, "Branding", f1.Component)
+	assert.EqualValues(t, flowDay(3), *f1.StartedAt)
+	assert.EqualValues(t, flowDay(8), *f1.FinishedAt)
+	assert.EqualValues(t, 7, *f1.LeadTimeDays)
+	assert.EqualValues(t, 5, *f1.CycleTimeDays)
+	assert.EqualValues(t, []dto.StatusTime{{Status: "New idea", Days: 2}, {Status: "In progress", Days: 5}, {Status: "Released", Days: 0}}, f1.TimeInStatus)
+	f2 := report.Features[1]
+	assert.EqualValues(t, "Analytics", f2.Product)
+	assert.EqualValues(t, flowDay(9), *f2.FinishedAt)
+	assert.EqualValues(t, 7, *f2.CycleTimeDays)
+	assert.EqualValues(t, []dto.StatusTime{{Status: "In progress", Days: 6}, {Status: "Released", Days: 1}}, f2.TimeInStatus)
+	f3 := report.Features[2]
+	assert.Nil(t, f3.FinishedAt)
+	assert.Nil(t, f3.LeadTimeDays)
+	assert.EqualValues(t, 2, report.Finished)
+	assert.EqualValues(t, 7, *report.AvgLeadTimeDays)
+	assert.EqualValues(t, 6, *report.AvgCycleTimeDays)
+	assert.EqualValues(t, []dto.WeeklyThroughput{
+		{Week: "2024-W02", Product: "Analytics", Count: 1},
+		{Week: "2024-W02", Product: "Portal", Component: "Branding", Count: 1},
+	}, report.Throughput)
+}
+
+func Test_GetReport_Filters_ComponentAndSince(t *testing.T) {
+	teardown := setupFlow(t)
+	defer teardown()
+
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return(flowJournal(), nil)
+	expectFlowHierarchy()
+
+	filter, _ := ParseFlowFilter([]string{}, []string{"branding"}, "2024-01-09")
+	report, err := fls.GetReport(*filter)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(report.Features))
+	assert.EqualValues(t, "f3", report.Features[0].FeatureId)
+	assert.EqualValues(t, 0, report.Finished)
+	assert.Nil(t, report.AvgLeadTimeDays)
+	assert.EqualValues(t, 0, len(report.Throughput))
+}
+
+func Test_WriteCsv_Writes_FeaturesTable(t *testing.T) {
+	teardown := setupFlow(t)
+	defer teardown()
+	var out bytes.Buffer
+
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return(flowJournal()[:8], nil)
+	expectFlowHierarchy()
+
+	filter, _ := ParseFlowFilter([]string{"Portal"}, []string{}, "")
+	err := fls.WriteCsv(&out, dto.FlowTableFeatures, *filter)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(lines))
+	assert.EqualValues(t, "featureId,name,product,component,status,firstSeenAt,startedAt,finishedAt,leadTimeDays,cycleTimeDays,days:New idea,days:In progress,days:Released", lines[0])
+	assert.EqualValues(t, "f1,Dark mode,Portal,Branding,Released,2024-01-01T10:00:00Z,2024-01-03T10:00:00Z,2024-01-08T10:00:00Z,7,5,2,5,0", lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], "f3,Search,Portal,Branding,In progress,2024-01-05T10:00:00Z,2024-01-05T10:00:00Z,,,,,"))
+}
+
+func Test_WriteCsv_UnknownTable_Returns_BadRequestError(t *testing.T) {
+	teardown := setupFlow(t)
+	defer teardown()
+	var out bytes.Buffer
+
+	err := fls.WriteCsv(&out, "statuses", dto.FlowFilter{})
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, 400, err.StatusCode())
+	assert.EqualValues(t, "Unknown flow table \"statuses\"", err.Message())
+}
+
+func Test_ParseFlowFilter_InvalidSince_Returns_BadRequestError(t *testing.T) {
+	filter, err := ParseFlowFilter([]string{}, []string{}, "last week")
+
+	assert.Nil(t, filter)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, 400, err.StatusCode())
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbHierarchyService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbHierarchyService
+type PbHierarchyService interface {
+	RefreshTree() api_error.ApiErr
+	GetTree() (*dto.HierarchyTree, api_error.ApiErr)
+	GetNode(string) (*dto.HierarchyNode, api_error.ApiErr)
+	GetFeaturesBelow(string) ([]dto.Feature, api_error.ApiErr)
+	HandleFeatureEvent(dto.FeatureEvent)
+}
+
+// DefaultPbHierarchyService caches the product hierarchy. The Productboard API documents the parent only for subfeatures:
+// features and components are placed below a product or component only if the API returns that parent. All others are
+// unassigned, they are not found below any product or component and have no product or component in filters.
+type DefaultPbHierarchyService struct {
+	repo  domain.PbApiRepository
+	cfg   *config.AppConfig
+	cache *hierarchyCache
+}
+
+// hierarchyCache keeps the lists the tree was built from, so a feature event only rebuilds it without calling the API
+type hierarchyCache struct {
+	sync.RWMutex
+	tree       *dto.HierarchyTree
+	nodes      map[string]*dto.HierarchyNode
+	products   []dto.Product
+	components []dto.Component
+	features   []dto.Feature
+}
+
+func NewPbHierarchyService(c *config.AppConfig, r domain.PbApiRepository) DefaultPbHierarchyService {
+	return DefaultPbHierarchyService{
+		repo:  r,
+		cfg:   c,
+		cache: &hierarchyCache{},
+	}
+}
+
+func (hs DefaultPbHierarchyService) RefreshTree() api_error.ApiErr {
+	products, err := hs.repo.GetProducts()
+	if err != nil {
+		return err
+	}
+	components, err := hs.repo.GetComponents()
+	if err != nil {
+		return err
+	}
+	features, err := hs.repo.GetFeatures(dto.FeatureFilter{})
+	if err != nil {
+		return err
+	}
+	tree, nodes := buildTree(products, components, features)
+	hs.cache.Lock()
+	hs.cache.tree = tree
+	hs.cache.nodes = nodes
+	hs.cache.products = products
+	hs.cache.components = components
+	hs.cache.features = features
+	hs.cache.Unlock()
+	logger.Info(fmt.Sprintf("Refreshed hierarchy with %v products, %v components and %v features", len(products), len(components), len(features)))
+	if len(tree.Unassigned) > 0 {
+		logger.Warn(fmt.Sprintf("%v components and features have no product or component, the Productboard API does not return it", len(tree.Unassigned)))
+	}
+	return nil
+}
+
+func (hs DefaultPbHierarchyService) GetTree() (*dto.HierarchyTree, api_error.ApiErr) {
+	hs.cache.RLock()
+	tree := hs.cache.tree
+	hs.cache.RUnlock()
+	if tree != nil {
+		return tree, nil
+	}
+	err := hs.RefreshTree()
+	if err != nil {
+		return nil, err
+	}
+	hs.cache.RLock()
+	defer hs.cache.RUnlock()
+	return hs.cache.tree, nil
+}
+
+func (hs DefaultPbHierarchyService) GetNode(id string) (*dto.HierarchyNode, api_error.ApiErr) {
+	_, err := hs.GetTree()
+	if err != nil {
+		return nil, err
+	}
+	hs.cache.RLock()
+	defer hs.cache.RUnlock()
+	node, found := hs.cache.nodes[id]
+	if !found {
+		msg := fmt.Sprintf("No product, component or feature with id %v found", id)
+		logger.Error(msg, nil)
+		return nil, api_error.NewNotFoundError(msg)
+	}
+	return node, nil
+}
+
+// GetFeaturesBelow returns all features and subfeatures located anywhere underneath the given node
+func (hs DefaultPbHierarchyService) GetFeaturesBelow(id string) ([]dto.Feature, api_error.ApiErr) {
+	node, err := hs.GetNode(id)
+	if err != nil {
+		return nil, err
+	}
+	hs.cache.RLock()
+	defer hs.cache.RUnlock()
+	features := []dto.Feature{}
+	collectFeatures(node, &features)
+	return features, nil
+}
+
+// HandleFeatureEvent replaces or removes the feature in the cached tree. Only a feature whose parent is not in the tree,
+// like a component added since the last refresh, causes a refresh from the API.
+func (hs DefaultPbHierarchyService) HandleFeatureEvent(event dto.FeatureEvent) {
+	deleted := event.EventType == dto.PbEventTypes["featureDelete"]
+	if event.Feature == nil && !deleted {
+		return
+	}
+	hs.cache.Lock()
+	if hs.cache.tree == nil {
+		hs.cache.Unlock()
+		return
+	}
+	if !deleted {
+		if parentId := event.Feature.Parent.ParentId(); parentId != "" && hs.cache.nodes[parentId] == nil {
+			hs.cache.Unlock()
+			if err := hs.RefreshTree(); err != nil {
+				logger.Error(fmt.Sprintf("Could not refresh hierarchy after event %v for feature %v", event.EventType, event.ID), err)
+			}
+			return
+		}
+	}
+	features := make([]dto.Feature, 0, len(hs.cache.features)+1)
+	replaced := false
+	for _, f := range hs.cache.features {
+		switch {
+		case f.ID != event.ID:
+			features = append(features, f)
+		case !deleted:
+			features = append(features, *event.Feature)
+			replaced = true
+		}
+	}
+	if !deleted && !replaced {
+		features = append(features, *event.Feature)
+	}
+	hs.cache.tree, hs.cache.nodes = buildTree(hs.cache.products, hs.cache.components, features)
+	hs.cache.features = features
+	hs.cache.Unlock()
+}
+
+func collectFeatures(node *dto.HierarchyNode, features *[]dto.Feature) {
+	for _, child := range node.Children {
+		if child.Feature != nil {
+			*features = append(*features, *child.Feature)
+		}
+		collectFeatures(child, features)
+	}
+}
+
+func buildTree(products []dto.Product, components []dto.Component, features []dto.Feature) (*dto.HierarchyTree, map[string]*dto.HierarchyNode) {
+	tree := dto.HierarchyTree{
+		Products:    []*dto.HierarchyNode{},
+		RefreshedAt: date.GetNowUtc(),
+	}
+	nodes := make(map[string]*dto.HierarchyNode)
+	for _, p := range products {
+		node := dto.HierarchyNode{ID: p.ID, Name: p.Name, Type: dto.NodeTypeProduct}
+		nodes[p.ID] = &node
+		tree.Products = append(tree.Products, &node)
+	}
+	children := []*dto.HierarchyNode{}
+	for _, c := range components {
+		node := dto.HierarchyNode{ID: c.ID, Name: c.Name, Type: dto.NodeTypeComponent, ParentId: c.Parent.ParentId()}
+		nodes[c.ID] = &node
+		children = append(children, &node)
+	}
+	for i := range features {
+		f := features[i]
+		nodeType := dto.NodeTypeFeature
+		if f.Type == dto.NodeTypeSubfeature {
+			nodeType = dto.NodeTypeSubfeature
+		}
+		node := dto.HierarchyNode{ID: f.ID, Name: f.Name, Type: nodeType, ParentId: f.Parent.ParentId(), Feature: &f}
+		nodes[f.ID] = &node
+		children = append(children, &node)
+	}
+	for _, node := range children {
+		if node.ParentId == "" {
+			tree.Unassigned = append(tree.Unassigned, node)
+			continue
+		}
+		parent, found := nodes[node.ParentId]
+		if !found {
+			tree.Orphans = append(tree.Orphans, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+	return &tree, nodes
+}
+
+// hierarchyPath returns the hierarchy nodes above a feature, starting at the product
+func hierarchyPath(hierarchy PbHierarchyService, parentId string) []dto.HierarchyNode {
+	path := []dto.HierarchyNode{}
+	for id := parentId; id != "" && len(path) < maxParentDepth; {
+		node, err := hierarchy.GetNode(id)
+		if err != nil {
+			break
+		}
+		path = append([]dto.HierarchyNode{*node}, path...)
+		id = node.ParentId
+	}
+	return path
+}
+
+// productAndComponent returns the names of the product and the nearest component in a hierarchy path
+func productAndComponent(path []dto.HierarchyNode) (string, string) {
+	product, component := "", ""
+	for _, node := range path {
+		switch node.Type {
+		case dto.NodeTypeProduct:
+			product = node.Name
+		case dto.NodeTypeComponent:
+			component = node.Name
+		}
+	}
+	return product, component
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	hs DefaultPbHierarchyService
+)
+
+func setupHierarchy(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	hs = NewPbHierarchyService(&cfg, mockPbApiRepo)
+	return func() {
+		pbApiCtrl.Finish()
+	}
+}
+
+func expectHierarchy() {
+	products := []dto.Product{{ID: "p1", Name: "Product 1"}}
+	components := []dto.Component{
+		{ID: "c1", Name: "Component 1", Parent: dto.Parent{Product: &dto.ParentRef{ID: "p1"}}},
+		{ID: "c2", Name: "Component 2", Parent: dto.Parent{Component: &dto.ParentRef{ID: "c1"}}},
+	}
+	features := []dto.Feature{
+		{ID: "f1", Name: "Feature 1", Type: "feature", Parent: dto.Parent{Component: &dto.ParentRef{ID: "c2"}}},
+		{ID: "f2", Name: "Subfeature 2", Type: "subfeature", Parent: dto.Parent{Feature: &dto.ParentRef{ID: "f1"}}},
+		{ID: "f3", Name: "Feature 3", Type: "feature", Parent: dto.Parent{Product: &dto.ParentRef{ID: "p1"}}},
+		{ID: "f4", Name: "Lost feature", Type: "feature", Parent: dto.Parent{Component: &dto.ParentRef{ID: "gone"}}},
+		{ID: "f5", Name: "Feature 5", Type: "feature"},
+	}
+	mockPbApiRepo.EXPECT().GetProducts().Return(products, nil)
+	mockPbApiRepo.EXPECT().GetComponents().Return(components, nil)
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).Return(features, nil)
+}
+
+func Test_RefreshTree_GetProductsFails_Returns_Error(t *testing.T) {
+	teardown := setupHierarchy(t)
+	defer teardown()
+	apiError := api_error.NewInternalServerError("something went wrong", nil)
+
+	mockPbApiRepo.EXPECT().GetProducts().Return(nil, apiError)
+
+	err := hs.RefreshTree()
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, apiError.Message(), err.Message())
+}
+
+func Test_GetTree_BuildsTreeOnce_Returns_Tree(t *testing.T) {
+	teardown := setupHierarchy(t)
+	defer teardown()
+
+	expectHierarchy()
+
+	tree, err := hs.GetTree()
+	cached, cachedErr := hs.GetTree()
+
+	assert.Nil(t, err)
+	assert.Nil(t, cachedErr)
+	assert.Same(t, tree, cached)
+	assert.EqualValues(t, 1, len(tree.Products))
+	assert.EqualValues(t, "c1", tree.Products[0].Children[0].ID)
+	assert.EqualValues(t, "f3", tree.Products[0].Children[1].ID)
+	assert.EqualValues(t, "f4", tree.Orphans[0].ID)
+	assert.EqualValues(t, "f5", tree.Unassigned[0].ID)
+}
+
+func Test_GetNode_Unknown_Returns_NotFoundError(t *testing.T) {
+	teardown := setupHierarchy(t)
+	defer teardown()
+
+	expectHierarchy()
+
+	node, err := hs.GetNode("unknown")
+
+	assert.Nil(t, node)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+}
+
+func Test_GetFeaturesBelow_Component_Returns_AllFeatures(t *testing.T) {
+	teardown := setupHierarchy(t)
+	defer teardown()
+
+	expectHierarchy()
+
+	features, err := hs.GetFeaturesBelow("c1")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(features))
+	assert.EqualValues(t, "f1", features[0].ID)
+	assert.EqualValues(t, "f2", features[1].ID)
+}
+
+func Test_HandleFeatureEvent_Updates_Tree_Without_Refetching(t *testing.T) {
+	teardown := setupHierarchy(t)
+	defer teardown()
+	moved := dto.Feature{ID: "f5", Name: "Feature 5 renamed", Type: "feature", Parent: dto.Parent{Component: &dto.ParentRef{ID: "c1"}}}
+
+	expectHierarchy()
+
+	tree, _ := hs.GetTree()
+	hs.HandleFeatureEvent(dto.FeatureEvent{ID: "f5", EventType: dto.PbEventTypes["featureUpdate"], Feature: &moved})
+	hs.HandleFeatureEvent(dto.FeatureEvent{ID: "f4", EventType: dto.PbEventTypes["featureDelete"]})
+	updated, _ := hs.GetTree()
+	node, _ := hs.GetNode("f5")
+	features, _ := hs.GetFeaturesBelow("c1")
+
+	assert.NotSame(t, tree, updated)
+	assert.EqualValues(t, "Feature 5 renamed", node.Name)
+	assert.EqualValues(t, 3, len(features))
+	assert.EqualValues(t, 0, len(updated.Unassigned))
+	assert.EqualValues(t, 0, len(updated.Orphans))
+}
+
+func Test_HandleFeatureEvent_UnknownParent_Refreshes_Tree(t *testing.T) {
+	teardown := setupHierarchy(t)
+	defer teardown()
+	added := dto.Feature{ID: "f6", Name: "Feature 6", Type: "feature", Parent: dto.Parent{Component: &dto.ParentRef{ID: "c3"}}}
+
+	expectHierarchy()
+	expectHierarchy()
+
+	hs.GetTree()
+	hs.HandleFeatureEvent(dto.FeatureEvent{ID: "f6", EventType: dto.PbEventTypes["featureCreate"], Feature: &added})
+}
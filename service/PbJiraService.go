@@ -0,0 +1,140 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbJiraService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbJiraService
+type PbJiraService interface {
+	GetIntegrations() ([]dto.JiraIntegration, api_error.ApiErr)
+	GetLinkedIssues(string) ([]dto.JiraIssue, api_error.ApiErr)
+	FindFeatureByIssueKey(string) (string, api_error.ApiErr)
+	EnrichFeatureEvent(*dto.FeatureEvent)
+}
+
+type DefaultPbJiraService struct {
+	repo  domain.PbApiRepository
+	cfg   *config.AppConfig
+	cache *jiraIntegrationCache
+}
+
+type jiraIntegrationCache struct {
+	sync.RWMutex
+	loaded       bool
+	integrations []dto.JiraIntegration
+}
+
+func NewPbJiraService(c *config.AppConfig, r domain.PbApiRepository) DefaultPbJiraService {
+	return DefaultPbJiraService{
+		repo:  r,
+		cfg:   c,
+		cache: &jiraIntegrationCache{},
+	}
+}
+
+// GetIntegrations returns the enabled Jira integrations. The list is loaded once and then cached.
+func (js DefaultPbJiraService) GetIntegrations() ([]dto.JiraIntegration, api_error.ApiErr) {
+	js.cache.RLock()
+	if js.cache.loaded {
+		defer js.cache.RUnlock()
+		return js.cache.integrations, nil
+	}
+	js.cache.RUnlock()
+	integrations, err := js.repo.GetJiraIntegrations()
+	if err != nil {
+		return nil, err
+	}
+	enabled := []dto.JiraIntegration{}
+	for _, integration := range integrations {
+		if integration.IntegrationStatus == dto.IntegrationEnabled {
+			enabled = append(enabled, integration)
+		}
+	}
+	js.cache.Lock()
+	defer js.cache.Unlock()
+	js.cache.integrations = enabled
+	js.cache.loaded = true
+	return enabled, nil
+}
+
+// GetLinkedIssues returns the Jira issues a feature is linked to across all Jira integrations
+func (js DefaultPbJiraService) GetLinkedIssues(featureId string) ([]dto.JiraIssue, api_error.ApiErr) {
+	integrations, err := js.GetIntegrations()
+	if err != nil {
+		return nil, err
+	}
+	issues := []dto.JiraIssue{}
+	for _, integration := range integrations {
+		conn, err := js.repo.GetJiraIntegrationConnection(integration.ID, featureId)
+		if err != nil && err.StatusCode() == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, js.toIssue(integration.ID, *conn))
+	}
+	return issues, nil
+}
+
+func (js DefaultPbJiraService) FindFeatureByIssueKey(issueKey string) (string, api_error.ApiErr) {
+	integrations, err := js.GetIntegrations()
+	if err != nil {
+		return "", err
+	}
+	for _, integration := range integrations {
+		conns, err := js.repo.GetJiraIntegrationConnections(integration.ID, dto.JiraConnectionFilter{IssueKey: issueKey})
+		if err != nil {
+			return "", err
+		}
+		if len(conns) > 0 {
+			return conns[0].FeatureId, nil
+		}
+	}
+	msg := fmt.Sprintf("No feature linked to Jira issue %v", issueKey)
+	logger.Error(msg, nil)
+	return "", api_error.NewNotFoundError(msg)
+}
+
+// EnrichFeatureEvent attaches the linked Jira issues to a feature event. Failures are logged and leave the event as is.
+func (js DefaultPbJiraService) EnrichFeatureEvent(event *dto.FeatureEvent) {
+	if event.Feature == nil {
+		return
+	}
+	issues, err := js.GetLinkedIssues(event.ID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Could not look up Jira issues of feature %v", event.ID), err)
+		return
+	}
+	if len(issues) > 0 {
+		keys := []string{}
+		for _, issue := range issues {
+			keys = append(keys, issue.IssueKey)
+		}
+		logger.Info(fmt.Sprintf("Feature %v linked to %v is now %v", event.ID, strings.Join(keys, ", "), event.Feature.Status.Name))
+	}
+	event.JiraIssues = issues
+}
+
+// toIssue links the issue only with a configured browse url, the API only returns a link to the connection in Productboard
+func (js DefaultPbJiraService) toIssue(integrationId string, conn dto.JiraConnectionData) dto.JiraIssue {
+	issueUrl := ""
+	if js.cfg.Jira.BrowseUrl != "" {
+		issueUrl = strings.TrimSuffix(js.cfg.Jira.BrowseUrl, "/") + "/" + conn.Connection.IssueKey
+	}
+	return dto.JiraIssue{
+		IntegrationId: integrationId,
+		IssueKey:      conn.Connection.IssueKey,
+		IssueId:       conn.Connection.IssueId,
+		Url:           issueUrl,
+	}
+}
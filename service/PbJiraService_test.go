@@ -0,0 +1,109 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	js DefaultPbJiraService
+)
+
+func setupJira(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	cfg.Jira.BrowseUrl = "https://example.atlassian.net/browse/"
+	js = NewPbJiraService(&cfg, mockPbApiRepo)
+	return func() {
+		cfg.Jira.BrowseUrl = ""
+		pbApiCtrl.Finish()
+	}
+}
+
+func jiraIntegrations() []dto.JiraIntegration {
+	return []dto.JiraIntegration{
+		{ID: "j1", IntegrationStatus: dto.IntegrationEnabled},
+		{ID: "j2", IntegrationStatus: dto.IntegrationDisabled},
+		{ID: "j3", IntegrationStatus: dto.IntegrationEnabled},
+	}
+}
+
+func Test_GetIntegrations_Returns_EnabledOnly_And_Caches(t *testing.T) {
+	teardown := setupJira(t)
+	defer teardown()
+	mockPbApiRepo.EXPECT().GetJiraIntegrations().Return(jiraIntegrations(), nil).Times(1)
+
+	js.GetIntegrations()
+	integrations, err := js.GetIntegrations()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(integrations))
+	assert.EqualValues(t, "j3", integrations[1].ID)
+}
+
+func Test_GetLinkedIssues_Skips_UnlinkedIntegrations(t *testing.T) {
+	teardown := setupJira(t)
+	defer teardown()
+	mockPbApiRepo.EXPECT().GetJiraIntegrations().Return(jiraIntegrations(), nil)
+	mockPbApiRepo.EXPECT().GetJiraIntegrationConnection("j1", "f1").Return(nil, api_error.NewNotFoundError("not linked"))
+	mockPbApiRepo.EXPECT().GetJiraIntegrationConnection("j3", "f1").Return(&dto.JiraConnectionData{
+		FeatureId:  "f1",
+		Connection: dto.JiraConnection{IssueKey: "PROJ-123", IssueId: "10001"},
+	}, nil)
+
+	issues, err := js.GetLinkedIssues("f1")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, []dto.JiraIssue{{
+		IntegrationId: "j3",
+		IssueKey:      "PROJ-123",
+		IssueId:       "10001",
+		Url:           "https://example.atlassian.net/browse/PROJ-123",
+	}}, issues)
+}
+
+func Test_GetLinkedIssues_NoBrowseUrl_Returns_NoUrl(t *testing.T) {
+	teardown := setupJira(t)
+	defer teardown()
+	cfg.Jira.BrowseUrl = ""
+	mockPbApiRepo.EXPECT().GetJiraIntegrations().Return(jiraIntegrations()[:1], nil)
+	mockPbApiRepo.EXPECT().GetJiraIntegrationConnection("j1", "f1").Return(&dto.JiraConnectionData{
+		FeatureId:  "f1",
+		Connection: dto.JiraConnection{IssueKey: "PROJ-123", IssueId: "10001"},
+		Links:      dto.SelfLinks{Self: "https://api.productboard.com/jira-integrations/j1/connections/f1"},
+	}, nil)
+
+	issues, err := js.GetLinkedIssues("f1")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "", issues[0].Url)
+}
+
+func Test_FindFeatureByIssueKey_NotLinked_Returns_NotFoundError(t *testing.T) {
+	teardown := setupJira(t)
+	defer teardown()
+	mockPbApiRepo.EXPECT().GetJiraIntegrations().Return(jiraIntegrations(), nil)
+	mockPbApiRepo.EXPECT().GetJiraIntegrationConnections(gomock.Any(), dto.JiraConnectionFilter{IssueKey: "PROJ-9"}).Return([]dto.JiraConnectionData{}, nil).Times(2)
+
+	featureId, err := js.FindFeatureByIssueKey("PROJ-9")
+
+	assert.EqualValues(t, "", featureId)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "No feature linked to Jira issue PROJ-9", err.Message())
+}
+
+func Test_EnrichFeatureEvent_LookupFails_Leaves_Event(t *testing.T) {
+	teardown := setupJira(t)
+	defer teardown()
+	event := dto.FeatureEvent{ID: "f1", Feature: &dto.Feature{ID: "f1"}}
+	mockPbApiRepo.EXPECT().GetJiraIntegrations().Return(nil, api_error.NewInternalServerError("something went wrong", nil))
+
+	js.EnrichFeatureEvent(&event)
+
+	assert.Nil(t, event.JiraIssues)
+}
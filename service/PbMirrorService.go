@@ -0,0 +1,447 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	mirrorUnassigned = "unassigned"
+	mirrorSlugLength = 60
+	mirrorDelimiter  = "---\n"
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbMirrorService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbMirrorService
+type PbMirrorService interface {
+	HandleFeatureEvent(dto.FeatureEvent)
+	Sync() (*dto.MirrorResult, api_error.ApiErr)
+	ScheduleSync()
+	StopSync()
+}
+
+// DefaultPbMirrorService keeps every feature as a Markdown file with YAML front matter in a git repository, placed
+// in a directory per product, component and parent feature. Each change is committed on its own.
+type DefaultPbMirrorService struct {
+	cfg       *config.AppConfig
+	repo      domain.PbApiRepository
+	hierarchy PbHierarchyService
+	git       domain.GitRepository
+	files     *mirrorFiles
+	done      chan bool
+	stop      *sync.Once
+}
+
+// mirrorFiles maps feature ids to their file, relative to the mirror directory. It is read from the front matter of
+// the existing files on first use, so the mirror survives restarts. The lock also keeps writes and commits in order.
+type mirrorFiles struct {
+	sync.Mutex
+	paths  map[string]string
+	loaded bool
+}
+
+type mirrorFile struct {
+	meta        dto.MirrorFrontMatter
+	description string
+}
+
+func NewPbMirrorService(c *config.AppConfig, r domain.PbApiRepository, h PbHierarchyService, g domain.GitRepository) (DefaultPbMirrorService, api_error.ApiErr) {
+	ms := DefaultPbMirrorService{
+		cfg:       c,
+		repo:      r,
+		hierarchy: h,
+		git:       g,
+		files: &mirrorFiles{
+			paths: make(map[string]string),
+		},
+		done: make(chan bool),
+		stop: &sync.Once{},
+	}
+	if err := g.Init(); err != nil {
+		return ms, err
+	}
+	return ms, nil
+}
+
+// HandleFeatureEvent writes the feature's file, or removes it when the feature was deleted, and commits the change
+func (ms DefaultPbMirrorService) HandleFeatureEvent(event dto.FeatureEvent) {
+	ms.files.Lock()
+	defer ms.files.Unlock()
+	if err := ms.loadFiles(); err != nil {
+		logger.Error(fmt.Sprintf("Could not read mirror directory %v", ms.cfg.Mirror.Dir), err)
+		return
+	}
+	var subject string
+	var changes []dto.FieldChange
+	var err error
+	switch {
+	case event.Feature != nil:
+		subject, changes, err = ms.write(*event.Feature)
+	case event.EventType == dto.PbEventTypes["featureDelete"]:
+		subject, err = ms.remove(event.ID)
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("Could not mirror feature %v", event.ID), err)
+		return
+	}
+	if subject == "" {
+		return
+	}
+	if err := ms.git.Commit(mirrorCommitMessage(subject, event, changes)); err != nil {
+		logger.Error(fmt.Sprintf("Could not commit mirror of feature %v", event.ID), err)
+	}
+}
+
+// Sync writes all features and removes the files of features that no longer exist, all in one commit. The hierarchy is
+// refreshed first, so features below a renamed product or component are moved to its new directory.
+func (ms DefaultPbMirrorService) Sync() (*dto.MirrorResult, api_error.ApiErr) {
+	if err := ms.hierarchy.RefreshTree(); err != nil {
+		return nil, err
+	}
+	features, err := ms.repo.GetFeatures(dto.FeatureFilter{})
+	if err != nil {
+		return nil, err
+	}
+	ms.files.Lock()
+	defer ms.files.Unlock()
+	if err := ms.loadFiles(); err != nil {
+		msg := fmt.Sprintf("Could not read mirror directory %v", ms.cfg.Mirror.Dir)
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, err)
+	}
+	result := dto.MirrorResult{}
+	found := make(map[string]bool)
+	for _, feature := range features {
+		found[feature.ID] = true
+		subject, _, err := ms.write(feature)
+		if err != nil {
+			msg := fmt.Sprintf("Could not mirror feature %v", feature.ID)
+			logger.Error(msg, err)
+			return nil, api_error.NewInternalServerError(msg, err)
+		}
+		if subject != "" {
+			result.Written++
+		}
+	}
+	for id := range ms.files.paths {
+		if found[id] {
+			continue
+		}
+		if _, err := ms.remove(id); err != nil {
+			msg := fmt.Sprintf("Could not remove feature %v from mirror", id)
+			logger.Error(msg, err)
+			return nil, api_error.NewInternalServerError(msg, err)
+		}
+		result.Removed++
+	}
+	if result.Written+result.Removed > 0 {
+		if err := ms.git.Commit(fmt.Sprintf("Sync features: %v written, %v removed", result.Written, result.Removed)); err != nil {
+			return nil, err
+		}
+	}
+	logger.Info(fmt.Sprintf("Synced mirror with %v features: %v written, %v removed", len(features), result.Written, result.Removed))
+	return &result, nil
+}
+
+func (ms DefaultPbMirrorService) ScheduleSync() {
+	interval := time.Duration(ms.cfg.Mirror.SyncInterval) * time.Minute
+	logger.Info(fmt.Sprintf("Syncing the mirror every %v", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := ms.Sync(); err != nil {
+			logger.Error("Could not sync the mirror", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ms.done:
+			logger.Info("Stopped syncing the mirror")
+			return
+		}
+	}
+}
+
+func (ms DefaultPbMirrorService) StopSync() {
+	ms.stop.Do(func() {
+		close(ms.done)
+	})
+}
+
+func (ms DefaultPbMirrorService) loadFiles() error {
+	if ms.files.loaded {
+		return nil
+	}
+	root := ms.cfg.Mirror.Dir
+	err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".md" {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		file, err := parseMirrorFile(content)
+		if err != nil || file.meta.ID == "" {
+			logger.Warn(fmt.Sprintf("Ignoring %v in mirror, it has no feature front matter", path))
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		ms.files.paths[file.meta.ID] = rel
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	ms.files.loaded = true
+	return nil
+}
+
+// write returns the commit subject and the changes for the feature's file, or an empty subject if it did not change.
+// A feature moved or renamed gets a new file; the old one is removed.
+func (ms DefaultPbMirrorService) write(feature dto.Feature) (string, []dto.FieldChange, error) {
+	path := hierarchyPath(ms.hierarchy, feature.Parent.ParentId())
+	file := newMirrorFile(feature, path)
+	content, err := file.render()
+	if err != nil {
+		return "", nil, err
+	}
+	target := mirrorFilePath(feature, path)
+	subject := "Add feature: " + feature.Name
+	changes := []dto.FieldChange{}
+	current, known := ms.files.paths[feature.ID]
+	if known {
+		if old, err := os.ReadFile(filepath.Join(ms.cfg.Mirror.Dir, current)); err == nil {
+			if current == target && bytes.Equal(old, content) {
+				return "", nil, nil
+			}
+			if previous, err := parseMirrorFile(old); err == nil {
+				changes = previous.diff(file)
+			}
+			subject = "Update feature: " + feature.Name
+			if len(changes) > 0 {
+				fields := []string{}
+				for _, change := range changes {
+					fields = append(fields, change.Field)
+				}
+				subject = fmt.Sprintf("%v (%v)", subject, strings.Join(fields, ", "))
+			}
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(ms.cfg.Mirror.Dir, filepath.Dir(target)), 0755); err != nil {
+		return "", nil, err
+	}
+	if err := os.WriteFile(filepath.Join(ms.cfg.Mirror.Dir, target), content, 0644); err != nil {
+		return "", nil, err
+	}
+	if known && current != target {
+		if err := ms.removeFile(current); err != nil {
+			return "", nil, err
+		}
+	}
+	ms.files.paths[feature.ID] = target
+	return subject, changes, nil
+}
+
+// remove returns the commit subject for the removed file, or an empty subject if the feature was not mirrored
+func (ms DefaultPbMirrorService) remove(id string) (string, error) {
+	current, known := ms.files.paths[id]
+	if !known {
+		return "", nil
+	}
+	name := id
+	if content, err := os.ReadFile(filepath.Join(ms.cfg.Mirror.Dir, current)); err == nil {
+		if file, err := parseMirrorFile(content); err == nil && file.meta.Name != "" {
+			name = file.meta.Name
+		}
+	}
+	if err := ms.removeFile(current); err != nil {
+		return "", err
+	}
+	delete(ms.files.paths, id)
+	return "Remove feature: " + name, nil
+}
+
+// removeFile removes a file and the directories above it that became empty
+func (ms DefaultPbMirrorService) removeFile(path string) error {
+	if err := os.Remove(filepath.Join(ms.cfg.Mirror.Dir, path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for dir := filepath.Dir(path); dir != "."; dir = filepath.Dir(dir) {
+		if os.Remove(filepath.Join(ms.cfg.Mirror.Dir, dir)) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+func newMirrorFile(feature dto.Feature, path []dto.HierarchyNode) mirrorFile {
+	file := mirrorFile{
+		meta: dto.MirrorFrontMatter{
+			ID:     feature.ID,
+			Name:   feature.Name,
+			Status: feature.Status.Name,
+			Parent: dto.MirrorParent{
+				ID: feature.Parent.ParentId(),
+			},
+			Timeframe: dto.MirrorTimeframe{
+				Start: exportDate(feature.Timeframe.StartDate),
+				End:   exportDate(feature.Timeframe.EndDate),
+			},
+			Archived: feature.Archived,
+			Owner:    ownerEmail(feature),
+			Link:     feature.Links.Html,
+		},
+		description: strings.TrimSpace(htmlToText(feature.Description)),
+	}
+	if len(path) > 0 {
+		parent := path[len(path)-1]
+		file.meta.Parent.Type = parent.Type
+		file.meta.Parent.Name = parent.Name
+	}
+	file.meta.Product, file.meta.Component = productAndComponent(path)
+	return file
+}
+
+func (f mirrorFile) render() ([]byte, error) {
+	meta, err := yaml.Marshal(f.meta)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(mirrorDelimiter)
+	buf.Write(meta)
+	buf.WriteString(mirrorDelimiter)
+	fmt.Fprintf(&buf, "\n# %v\n", f.meta.Name)
+	if f.description != "" {
+		fmt.Fprintf(&buf, "\n%v\n", f.description)
+	}
+	return buf.Bytes(), nil
+}
+
+func parseMirrorFile(content []byte) (*mirrorFile, error) {
+	text := string(content)
+	if !strings.HasPrefix(text, mirrorDelimiter) {
+		return nil, errors.New("file does not start with front matter")
+	}
+	text = strings.TrimPrefix(text, mirrorDelimiter)
+	end := strings.Index(text, "\n"+mirrorDelimiter)
+	if end < 0 {
+		return nil, errors.New("front matter is not closed")
+	}
+	var file mirrorFile
+	if err := yaml.Unmarshal([]byte(text[:end+1]), &file.meta); err != nil {
+		return nil, err
+	}
+	body := strings.TrimLeft(text[end+1+len(mirrorDelimiter):], "\n")
+	if strings.HasPrefix(body, "# ") {
+		if eol := strings.Index(body, "\n"); eol >= 0 {
+			body = body[eol+1:]
+		} else {
+			body = ""
+		}
+	}
+	file.description = strings.TrimSpace(body)
+	return &file, nil
+}
+
+func (f mirrorFile) diff(new mirrorFile) []dto.FieldChange {
+	changes := []dto.FieldChange{}
+	add := func(field string, o string, n string) {
+		if o != n {
+			changes = append(changes, dto.FieldChange{Field: field, Old: o, New: n})
+		}
+	}
+	parent := func(p dto.MirrorParent) string {
+		if p.Name != "" {
+			return p.Name
+		}
+		return p.ID
+	}
+	timeframe := func(t dto.MirrorTimeframe) string {
+		return digestTimeframe(dto.Timeframe{StartDate: t.Start, EndDate: t.End})
+	}
+	add(dto.NotifyFieldName, f.meta.Name, new.meta.Name)
+	add(dto.NotifyFieldStatus, f.meta.Status, new.meta.Status)
+	add(dto.NotifyFieldDescription, f.description, new.description)
+	add(dto.NotifyFieldTimeframe, timeframe(f.meta.Timeframe), timeframe(new.meta.Timeframe))
+	if f.meta.Parent.ID != new.meta.Parent.ID {
+		add(dto.NotifyFieldParent, parent(f.meta.Parent), parent(new.meta.Parent))
+	}
+	add(dto.NotifyFieldArchived, strconv.FormatBool(f.meta.Archived), strconv.FormatBool(new.meta.Archived))
+	add(dto.NotifyFieldOwner, f.meta.Owner, new.meta.Owner)
+	return changes
+}
+
+// mirrorFilePath places a feature in a directory per product, component and parent feature. The id in the file name
+// keeps features with the same name apart.
+func mirrorFilePath(feature dto.Feature, path []dto.HierarchyNode) string {
+	parts := []string{}
+	for _, node := range path {
+		parts = append(parts, mirrorSlug(node.Name, node.ID))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, mirrorUnassigned)
+	}
+	parts = append(parts, fmt.Sprintf("%v-%v.md", mirrorSlug(feature.Name, dto.NodeTypeFeature), feature.ID))
+	return filepath.Join(parts...)
+}
+
+func mirrorSlug(name string, fallback string) string {
+	var slug strings.Builder
+	gap := false
+	for _, r := range strings.ToLower(name) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			gap = true
+			continue
+		}
+		if gap && slug.Len() > 0 {
+			slug.WriteRune('-')
+		}
+		gap = false
+		slug.WriteRune(r)
+	}
+	if slug.Len() == 0 {
+		return fallback
+	}
+	return strings.TrimRight(truncateRunes(slug.String(), mirrorSlugLength), "-")
+}
+
+// mirrorCommitMessage lists the changes below the subject. Descriptions are only reported as changed, they can be long.
+func mirrorCommitMessage(subject string, event dto.FeatureEvent, changes []dto.FieldChange) string {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "%v\n\nEvent: %v\nFeature: %v\n", subject, event.EventType, event.ID)
+	if len(changes) > 0 {
+		msg.WriteString("\n")
+	}
+	for _, change := range changes {
+		if change.Field == dto.NotifyFieldDescription {
+			fmt.Fprintf(&msg, "- %v changed\n", change.Field)
+			continue
+		}
+		fmt.Fprintf(&msg, "- %v: %v -> %v\n", change.Field, chatValue(change.Old), chatValue(change.New))
+	}
+	return msg.String()
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	mis         DefaultPbMirrorService
+	mockGitRepo *domain.MockGitRepository
+)
+
+func setupMirror(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	mockGitRepo = domain.NewMockGitRepository(pbApiCtrl)
+	cfg.Mirror.Dir = t.TempDir()
+	mockGitRepo.EXPECT().Init().Return(nil)
+	mis, _ = NewPbMirrorService(&cfg, mockPbApiRepo, NewPbHierarchyService(&cfg, mockPbApiRepo), mockGitRepo)
+	return func() {
+		cfg.Mirror.Dir = ""
+		pbApiCtrl.Finish()
+	}
+}
+
+// expectMirrorSync expects the hierarchy refresh and the feature list of a sync, with the component c1 named as given
+func expectMirrorSync(features []dto.Feature, component string) {
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).Return(features, nil).Times(2)
+	mockPbApiRepo.EXPECT().GetProducts().Return([]dto.Product{{ID: "p1", Name: "Portal"}}, nil)
+	mockPbApiRepo.EXPECT().GetComponents().Return([]dto.Component{{ID: "c1", Name: component, Parent: dto.Parent{Product: &dto.ParentRef{ID: "p1"}}}}, nil)
+}
+
+func readMirrorFile(path string) string {
+	content, _ := os.ReadFile(filepath.Join(cfg.Mirror.Dir, path))
+	return string(content)
+}
+
+func Test_HandleFeatureEvent_Writes_And_Commits_FeatureFiles(t *testing.T) {
+	teardown := setupMirror(t)
+	defer teardown()
+	created := notifyFeature("In progress", "c1")
+	updated := notifyFeature("Released", "c1")
+	updated.Description = "<p>Follow the OS theme</p>"
+	updated.Timeframe = dto.Timeframe{StartDate: "2024-01-01", EndDate: "none"}
+	moved := updated
+	moved.Parent = dto.Parent{Product: &dto.ParentRef{ID: "p2"}}
+
+	expectNotifyHierarchy()
+	gomock.InOrder(
+		mockGitRepo.EXPECT().Commit("Add feature: Dark mode\n\nEvent: feature.created\nFeature: f1\n").Return(nil),
+		mockGitRepo.EXPECT().Commit("Update feature: Dark mode (status, description, timeframe)\n\nEvent: feature.updated\nFeature: f1\n\n"+
+			"- status: In progress -> Released\n- description changed\n- timeframe: none -> 2024-01-01 - \n").Return(nil),
+	)
+
+	mis.HandleFeatureEvent(notifyEvent("featureCreate", &created))
+	mis.HandleFeatureEvent(notifyEvent("featureUpdate", &updated))
+
+	assert.EqualValues(t, `---
+id: f1
+name: Dark mode
+status: Released
+parent:
+  id: c1
+  type: component
+  name: Branding
+product: Portal
+component: Branding
+timeframe:
+  start: "2024-01-01"
+archived: false
+link: https://pb/f1
+---
+
+# Dark mode
+
+Follow the OS theme
+`, readMirrorFile(filepath.Join("portal", "branding", "dark-mode-f1.md")))
+
+	mockGitRepo.EXPECT().Commit("Update feature: Dark mode (parent)\n\nEvent: feature.updated\nFeature: f1\n\n- parent: Branding -> Analytics\n").Return(nil)
+
+	mis.HandleFeatureEvent(notifyEvent("featureUpdate", &moved))
+
+	assert.NoDirExists(t, filepath.Join(cfg.Mirror.Dir, "portal"))
+	assert.FileExists(t, filepath.Join(cfg.Mirror.Dir, "analytics", "dark-mode-f1.md"))
+
+	mockGitRepo.EXPECT().Commit("Remove feature: Dark mode\n\nEvent: feature.deleted\nFeature: f1\n").Return(nil)
+
+	mis.HandleFeatureEvent(notifyEvent("featureDelete", nil))
+
+	assert.NoDirExists(t, filepath.Join(cfg.Mirror.Dir, "analytics"))
+	assert.EqualValues(t, 0, len(mis.files.paths))
+}
+
+func Test_HandleFeatureEvent_Unchanged_Commits_Nothing(t *testing.T) {
+	teardown := setupMirror(t)
+	defer teardown()
+	feature := notifyFeature("In progress", "")
+	feature.Parent = dto.Parent{}
+
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).Return([]dto.Feature{}, nil).AnyTimes()
+	mockPbApiRepo.EXPECT().GetProducts().Return([]dto.Product{}, nil).AnyTimes()
+	mockPbApiRepo.EXPECT().GetComponents().Return([]dto.Component{}, nil).AnyTimes()
+	mockGitRepo.EXPECT().Commit("Add feature: Dark mode\n\nEvent: feature.created\nFeature: f1\n").Return(nil)
+
+	mis.HandleFeatureEvent(notifyEvent("featureCreate", &feature))
+	mis.HandleFeatureEvent(notifyEvent("featureUpdate", &feature))
+	mis.HandleFeatureEvent(dto.FeatureEvent{ID: "f2", EventType: dto.PbEventTypes["featureDelete"]})
+
+	assert.FileExists(t, filepath.Join(cfg.Mirror.Dir, mirrorUnassigned, "dark-mode-f1.md"))
+}
+
+func Test_Sync_Reads_ExistingFiles_And_Removes_Stale(t *testing.T) {
+	teardown := setupMirror(t)
+	defer teardown()
+	current, stale := notifyFeature("Released", "c1"), notifyFeature("Won't do", "c1")
+	stale.ID, stale.Name = "f9", "Light mode"
+	for _, feature := range []dto.Feature{current, stale} {
+		content, _ := newMirrorFile(feature, []dto.HierarchyNode{{ID: "c1", Name: "Branding", Type: dto.NodeTypeComponent}}).render()
+		os.MkdirAll(filepath.Join(cfg.Mirror.Dir, "old"), 0755)
+		os.WriteFile(filepath.Join(cfg.Mirror.Dir, "old", feature.ID+".md"), content, 0644)
+	}
+	os.WriteFile(filepath.Join(cfg.Mirror.Dir, "README.md"), []byte("# Features\n"), 0644)
+
+	expectMirrorSync([]dto.Feature{current}, "Branding")
+	mockGitRepo.EXPECT().Commit("Sync features: 1 written, 1 removed").Return(nil)
+
+	result, err := mis.Sync()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, dto.MirrorResult{Written: 1, Removed: 1}, *result)
+	assert.NoDirExists(t, filepath.Join(cfg.Mirror.Dir, "old"))
+	assert.FileExists(t, filepath.Join(cfg.Mirror.Dir, "README.md"))
+	assert.FileExists(t, filepath.Join(cfg.Mirror.Dir, "portal", "branding", "dark-mode-f1.md"))
+}
+
+func Test_Sync_RenamedComponent_Moves_Files(t *testing.T) {
+	teardown := setupMirror(t)
+	defer teardown()
+	feature := notifyFeature("Released", "c1")
+
+	expectMirrorSync([]dto.Feature{feature}, "Branding")
+	mockGitRepo.EXPECT().Commit("Sync features: 1 written, 0 removed").Return(nil)
+	mis.Sync()
+	expectMirrorSync([]dto.Feature{feature}, "Theming")
+	mockGitRepo.EXPECT().Commit("Sync features: 1 written, 0 removed").Return(nil)
+
+	result, err := mis.Sync()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, dto.MirrorResult{Written: 1}, *result)
+	assert.NoDirExists(t, filepath.Join(cfg.Mirror.Dir, "portal", "branding"))
+	assert.Contains(t, readMirrorFile(filepath.Join("portal", "theming", "dark-mode-f1.md")), "component: Theming")
+}
+
+func Test_HandleFeatureEvent_CommitError_Keeps_File(t *testing.T) {
+	teardown := setupMirror(t)
+	defer teardown()
+	feature := notifyFeature("Released", "c1")
+
+	expectNotifyHierarchy()
+	mockGitRepo.EXPECT().Commit(gomock.Any()).Return(api_error.NewInternalServerError("Could not commit changes", nil))
+
+	mis.HandleFeatureEvent(notifyEvent("featureCreate", &feature))
+
+	assert.FileExists(t, filepath.Join(cfg.Mirror.Dir, "portal", "branding", "dark-mode-f1.md"))
+}
+
+func Test_StopSync_CalledTwice_DoesNotPanic(t *testing.T) {
+	teardown := setupMirror(t)
+	defer teardown()
+
+	mis.StopSync()
+	mis.StopSync()
+}
+
+func Test_mirrorSlug_Normalizes_Names(t *testing.T) {
+	assert.EqualValues(t, "single-sign-on-saml", mirrorSlug("  Single Sign-On (SAML)!", "feature"))
+	assert.EqualValues(t, "größe-ändern", mirrorSlug("Größe ändern", "feature"))
+	assert.EqualValues(t, "feature", mirrorSlug("🚀", "feature"))
+}
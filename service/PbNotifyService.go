@@ -0,0 +1,368 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"net/mail"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+const (
+	defaultNotifySubject = `[{{.Rule}}] {{.Feature.Name}} {{.Action}}`
+	defaultNotifyText    = `Feature "{{.Feature.Name}}" was {{.Action}}.
+{{with .Product}}
+Product: {{.}}{{end}}{{with .Component}}
+Component: {{.}}{{end}}
+Status: {{.Feature.Status.Name}}{{with .Feature.Links.Html}}
+Link: {{.}}{{end}}
+{{with .Changes}}
+Changes:
+{{range .}}- {{.Field}}: {{.Old}} -> {{.New}}
+{{end}}{{end}}`
+)
+
+var (
+	notifyFuncs = map[string]interface{}{
+		"date": digestFuncs["date"],
+		"text": htmlToText,
+	}
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbNotifyService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbNotifyService
+type PbNotifyService interface {
+	HandleFeatureEvent(dto.FeatureEvent)
+	ProcessMails()
+	StopProcessing()
+}
+
+type DefaultPbNotifyService struct {
+	hierarchy PbHierarchyService
+	mail      domain.MailRepository
+	cfg       *config.AppConfig
+	rules     []notifyRule
+	queue     chan notifyMail
+	done      chan bool
+	stop      *sync.Once
+	seen      *seenFeatures
+}
+
+type notifyRule struct {
+	dto.NotifyRule
+	to      []*texttemplate.Template
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+type notifyMail struct {
+	message  dto.MailMessage
+	attempts int
+}
+
+// seenFeatures is the last state seen of every feature, used to compute the changes of the next event.
+// With a cache, the states are saved after every event, so changes and deletions are still recognized after a restart.
+type seenFeatures struct {
+	sync.Mutex
+	features map[string]dto.Feature
+	cache    domain.FeatureCacheRepository
+}
+
+// NewPbNotifyService loads and parses the notification rules and the feature states seen before the last restart.
+// Without a rules file no mails are sent.
+func NewPbNotifyService(c *config.AppConfig, h PbHierarchyService, m domain.MailRepository, f domain.FeatureCacheRepository) (DefaultPbNotifyService, api_error.ApiErr) {
+	ns := DefaultPbNotifyService{
+		hierarchy: h,
+		mail:      m,
+		cfg:       c,
+		queue:     make(chan notifyMail, c.Notify.QueueSize),
+		done:      make(chan bool),
+		stop:      &sync.Once{},
+		seen:      newSeenFeatures(),
+	}
+	if c.Notify.RulesFile == "" {
+		return ns, nil
+	}
+	rules, err := loadNotifyRules(c.Notify.RulesFile)
+	if err != nil {
+		msg := fmt.Sprintf("Could not load notification rules from %v", c.Notify.RulesFile)
+		logger.Error(msg, err)
+		return ns, api_error.NewInternalServerError(msg, err)
+	}
+	ns.rules = rules
+	logger.Info(fmt.Sprintf("Loaded %v notification rule(s)", len(rules)))
+	seen, loadErr := f.Load()
+	if loadErr != nil {
+		return ns, loadErr
+	}
+	ns.seen.features = seen
+	ns.seen.cache = f
+	return ns, nil
+}
+
+func loadNotifyRules(file string) ([]notifyRule, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var configured []dto.NotifyRule
+	if err := json.Unmarshal(content, &configured); err != nil {
+		return nil, err
+	}
+	rules := []notifyRule{}
+	for i, c := range configured {
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("rule %v", i+1)
+		}
+		if len(c.To) == 0 {
+			return nil, fmt.Errorf("%v has no recipients", c.Name)
+		}
+		if c.Subject == "" {
+			c.Subject = defaultNotifySubject
+		}
+		if c.Text == "" {
+			c.Text = defaultNotifyText
+		}
+		rule := notifyRule{NotifyRule: c}
+		for j, to := range c.To {
+			t, err := texttemplate.New("to" + strconv.Itoa(j)).Funcs(notifyFuncs).Parse(to)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %v", c.Name, err)
+			}
+			rule.to = append(rule.to, t)
+		}
+		if rule.subject, err = texttemplate.New("subject").Funcs(notifyFuncs).Parse(c.Subject); err == nil {
+			rule.text, err = texttemplate.New("text").Funcs(notifyFuncs).Parse(c.Text)
+		}
+		if err == nil && c.Html != "" {
+			rule.html, err = htmltemplate.New("html").Funcs(notifyFuncs).Parse(c.Html)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", c.Name, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// HandleFeatureEvent queues a mail for every rule the event matches. It never blocks; if the queue is full, the mail is dropped.
+func (ns DefaultPbNotifyService) HandleFeatureEvent(event dto.FeatureEvent) {
+	if len(ns.rules) == 0 {
+		return
+	}
+	data, found := ns.seen.observe(event)
+	if !found {
+		return
+	}
+	path := hierarchyPath(ns.hierarchy, data.Feature.Parent.ParentId())
+	data.Product, data.Component = productAndComponent(path)
+	for _, rule := range ns.rules {
+		if !filterMatches(rule.EventFilter, *data, path) {
+			continue
+		}
+		data.Rule = rule.Name
+		message, err := renderNotification(rule, *data)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Could not render notification \"%v\" for feature %v", rule.Name, event.ID), err)
+			continue
+		}
+		if len(message.To) == 0 {
+			logger.Warn(fmt.Sprintf("Notification \"%v\" for feature %v has no valid recipients", rule.Name, event.ID))
+			continue
+		}
+		select {
+		case ns.queue <- notifyMail{message: *message}:
+		default:
+			logger.Error(fmt.Sprintf("Could not queue notification \"%v\" for feature %v", rule.Name, event.ID), api_error.NewInternalServerError("Notification queue is full", nil))
+		}
+	}
+}
+
+func (ns DefaultPbNotifyService) ProcessMails() {
+	logger.Info("Start processing notifications")
+	for {
+		select {
+		case m := <-ns.queue:
+			ns.processMail(m)
+		case <-ns.done:
+			logger.Info("Stopped processing notifications")
+			return
+		}
+	}
+}
+
+func (ns DefaultPbNotifyService) StopProcessing() {
+	ns.stop.Do(func() {
+		close(ns.done)
+	})
+}
+
+func (ns DefaultPbNotifyService) processMail(m notifyMail) {
+	m.attempts++
+	err := ns.mail.Send(m.message)
+	if err == nil {
+		logger.Info(fmt.Sprintf("Sent notification \"%v\" to %v", m.message.Subject, strings.Join(m.message.To, ", ")))
+		return
+	}
+	if m.attempts > ns.cfg.Notify.MaxRetries {
+		logger.Error(fmt.Sprintf("Giving up on notification \"%v\" after %v attempt(s)", m.message.Subject, m.attempts), err)
+		return
+	}
+	delay := time.Duration(ns.cfg.Notify.RetryDelay) * time.Second * time.Duration(1<<uint(m.attempts-1))
+	logger.Info(fmt.Sprintf("Retrying notification \"%v\" in %v", m.message.Subject, delay))
+	time.AfterFunc(delay, func() {
+		ns.requeue(m)
+	})
+}
+
+func (ns DefaultPbNotifyService) requeue(m notifyMail) {
+	select {
+	case ns.queue <- m:
+	default:
+		logger.Error(fmt.Sprintf("Could not retry notification \"%v\"", m.message.Subject), api_error.NewInternalServerError("Notification queue is full", nil))
+	}
+}
+
+func newSeenFeatures() *seenFeatures {
+	return &seenFeatures{
+		features: make(map[string]dto.Feature),
+	}
+}
+
+// observe records the feature's state and returns the event data with the changes since the previous event.
+// Deleted features are reported with their last state seen, or not at all if they were never seen.
+func (s *seenFeatures) observe(event dto.FeatureEvent) (*dto.NotifyData, bool) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.save()
+	data := dto.NotifyData{
+		Action:  strings.TrimPrefix(event.EventType, "feature."),
+		Event:   event,
+		Changes: []dto.FieldChange{},
+	}
+	old, seen := s.features[event.ID]
+	if event.Feature == nil {
+		if event.EventType != dto.PbEventTypes["featureDelete"] || !seen {
+			return nil, false
+		}
+		delete(s.features, event.ID)
+		data.Feature = old
+		return &data, true
+	}
+	data.Feature = *event.Feature
+	s.features[event.ID] = *event.Feature
+	if seen {
+		data.Changes = featureDiff(old, *event.Feature)
+	}
+	return &data, true
+}
+
+// save persists the states if there is a cache; the lock has to be held
+func (s *seenFeatures) save() {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Save(s.features); err != nil {
+		logger.Error("Could not save the feature states seen", err)
+	}
+}
+
+func filterMatches(filter dto.EventFilter, data dto.NotifyData, path []dto.HierarchyNode) bool {
+	if len(filter.Events) > 0 && !containsFold(filter.Events, data.Event.EventType) && !containsFold(filter.Events, data.Action) {
+		return false
+	}
+	if !containsFold(filter.Statuses, data.Feature.Status.Name) || !pathMatches(path, dto.NodeTypeProduct, filter.Products) {
+		return false
+	}
+	if len(filter.Changed) == 0 {
+		return true
+	}
+	for _, change := range data.Changes {
+		if containsFold(filter.Changed, change.Field) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderNotification renders the mail of a rule. Every recipient template may yield a comma separated list of
+// addresses; invalid and duplicate addresses are skipped.
+func renderNotification(rule notifyRule, data dto.NotifyData) (*dto.MailMessage, error) {
+	render := func(t *texttemplate.Template) (string, error) {
+		var buf bytes.Buffer
+		err := t.Execute(&buf, data)
+		return buf.String(), err
+	}
+	message := dto.MailMessage{To: []string{}}
+	seen := make(map[string]bool)
+	for _, t := range rule.to {
+		to, err := render(t)
+		if err != nil {
+			return nil, err
+		}
+		for _, recipient := range splitList([]string{to}) {
+			address, err := mail.ParseAddress(recipient)
+			if err != nil {
+				logger.Warn(fmt.Sprintf("Skipping invalid recipient \"%v\" of notification \"%v\"", recipient, rule.Name))
+				continue
+			}
+			key := strings.ToLower(address.Address)
+			if !seen[key] {
+				seen[key] = true
+				message.To = append(message.To, address.Address)
+			}
+		}
+	}
+	subject, err := render(rule.subject)
+	if err != nil {
+		return nil, err
+	}
+	message.Subject = strings.Join(strings.Fields(subject), " ")
+	if message.Text, err = render(rule.text); err != nil {
+		return nil, err
+	}
+	if rule.html != nil {
+		var buf bytes.Buffer
+		if err := rule.html.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		message.Html = buf.String()
+	}
+	return &message, nil
+}
+
+func featureDiff(old dto.Feature, new dto.Feature) []dto.FieldChange {
+	changes := []dto.FieldChange{}
+	add := func(field string, o string, n string) {
+		if o != n {
+			changes = append(changes, dto.FieldChange{Field: field, Old: o, New: n})
+		}
+	}
+	add(dto.NotifyFieldName, old.Name, new.Name)
+	add(dto.NotifyFieldStatus, old.Status.Name, new.Status.Name)
+	add(dto.NotifyFieldDescription, htmlToText(old.Description), htmlToText(new.Description))
+	add(dto.NotifyFieldTimeframe, digestTimeframe(old.Timeframe), digestTimeframe(new.Timeframe))
+	add(dto.NotifyFieldParent, old.Parent.ParentId(), new.Parent.ParentId())
+	add(dto.NotifyFieldArchived, strconv.FormatBool(old.Archived), strconv.FormatBool(new.Archived))
+	add(dto.NotifyFieldOwner, ownerEmail(old), ownerEmail(new))
+	return changes
+}
+
+func ownerEmail(feature dto.Feature) string {
+	if feature.Owner == nil {
+		return ""
+	}
+	return feature.Owner.Email
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/pbreact/repository"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	nos         DefaultPbNotifyService
+	notifyRules = `[
+	{
+		"name": "Portal releases",
+		"events": ["updated"],
+		"statuses": ["Released"],
+		"products": ["Portal"],
+		"changed": ["status"],
+		"to": ["releases@example.com, {{.Product}}-team@example.com", "releases@example.com", "not an address"],
+		"html": "<p>{{.Feature.Name}} is {{.Feature.Status.Name}}</p>"
+	},
+	{
+		"name": "Deletions",
+		"events": ["feature.deleted"],
+		"to": ["audit@example.com"],
+		"subject": "Deleted: {{.Feature.Name}}",
+		"text": "{{.Feature.Name}} ({{.Feature.ID}}) was deleted from {{.Product}}"
+	}
+]`
+)
+
+func setupNotify(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	mockMailRepo = domain.NewMockMailRepository(pbApiCtrl)
+	cfg.Notify.RulesFile = filepath.Join(t.TempDir(), "rules.json")
+	cfg.Notify.QueueSize = 10
+	cfg.Notify.MaxRetries = 1
+	cfg.Notify.RetryDelay = 0
+	os.WriteFile(cfg.Notify.RulesFile, []byte(notifyRules), 0644)
+	nos, _ = NewPbNotifyService(&cfg, NewPbHierarchyService(&cfg, mockPbApiRepo), mockMailRepo, repository.NewFeatureCacheRepository(filepath.Join(t.TempDir(), "seen.json")))
+	return func() {
+		cfg.Notify.RulesFile = ""
+		pbApiCtrl.Finish()
+	}
+}
+
+func expectNotifyHierarchy() {
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).Return([]dto.Feature{}, nil)
+	mockPbApiRepo.EXPECT().GetProducts().Return([]dto.Product{{ID: "p1", Name: "Portal"}, {ID: "p2", Name: "Analytics"}}, nil)
+	mockPbApiRepo.EXPECT().GetComponents().Return([]dto.Component{{ID: "c1", Name: "Branding", Parent: dto.Parent{Product: &dto.ParentRef{ID: "p1"}}}}, nil)
+}
+
+func notifyFeature(status string, componentId string) dto.Feature {
+	return dto.Feature{ID: "f1", Name: "Dark mode", Status: dto.FeatureStatus{Name: status},
+		Parent: dto.Parent{Component: &dto.ParentRef{ID: componentId}}, Links: dto.EntityLinks{Html: "https://pb/f1"}}
+}
+
+func notifyEvent(eventType string, feature *dto.Feature) dto.FeatureEvent {
+	return dto.FeatureEvent{ID: "f1", EventType: dto.PbEventTypes[eventType], Feature: feature}
+}
+
+func Test_HandleFeatureEvent_StatusChange_Queues_RenderedMail(t *testing.T) {
+	teardown := setupNotify(t)
+	defer teardown()
+	before, after := notifyFeature("In progress", "c1"), notifyFeature("Released", "c1")
+
+	expectNotifyHierarchy()
+
+	nos.HandleFeatureEvent(notifyEvent("featureCreate", &before))
+	nos.HandleFeatureEvent(notifyEvent("featureUpdate", &after))
+
+	assert.EqualValues(t, 1, len(nos.queue))
+	m := <-nos.queue
+	assert.EqualValues(t, []string{"releases@example.com", "Portal-team@example.com"}, m.message.To)
+	assert.EqualValues(t, "[Portal releases] Dark mode updated", m.message.Subject)
+	assert.EqualValues(t, "Feature \"Dark mode\" was updated.\n"+
+		"\nProduct: Portal"+
+		"\nComponent: Branding"+
+		"\nStatus: Released"+
+		"\nLink: https://pb/f1\n"+
+		"\nChanges:\n- status: In progress -> Released\n", m.message.Text)
+	assert.EqualValues(t, "<p>Dark mode is Released</p>", m.message.Html)
+}
+
+func Test_HandleFeatureEvent_NoMatch_Queues_Nothing(t *testing.T) {
+	teardown := setupNotify(t)
+	defer teardown()
+	released, renamed, other := notifyFeature("Released", "c1"), notifyFeature("Released", "c1"), notifyFeature("Released", "p2")
+	renamed.Name = "Dark theme"
+
+	expectNotifyHierarchy()
+
+	nos.HandleFeatureEvent(notifyEvent("featureUpdate", &released))
+	nos.HandleFeatureEvent(notifyEvent("featureUpdate", &renamed))
+	nos.HandleFeatureEvent(notifyEvent("featureUpdate", &other))
+
+	assert.EqualValues(t, 0, len(nos.queue))
+}
+
+func Test_HandleFeatureEvent_Delete_Uses_LastSeenFeature(t *testing.T) {
+	teardown := setupNotify(t)
+	defer teardown()
+	feature := notifyFeature("In progress", "c1")
+
+	expectNotifyHierarchy()
+
+	nos.HandleFeatureEvent(notifyEvent("featureDelete", nil))
+	nos.HandleFeatureEvent(notifyEvent("featureCreate", &feature))
+	nos.HandleFeatureEvent(notifyEvent("featureDelete", nil))
+
+	assert.EqualValues(t, 1, len(nos.queue))
+	m := <-nos.queue
+	assert.EqualValues(t, []string{"audit@example.com"}, m.message.To)
+	assert.EqualValues(t, "Deleted: Dark mode", m.message.Subject)
+	assert.EqualValues(t, "Dark mode (f1) was deleted from Portal", m.message.Text)
+	assert.EqualValues(t, "", m.message.Html)
+}
+
+func Test_HandleFeatureEvent_AfterRestart_Knows_SeenFeatures(t *testing.T) {
+	teardown := setupNotify(t)
+	defer teardown()
+	cache := repository.NewFeatureCacheRepository(filepath.Join(t.TempDir(), "seen.json"))
+	before, after := notifyFeature("In progress", "c1"), notifyFeature("Released", "c1")
+	first, _ := NewPbNotifyService(&cfg, NewPbHierarchyService(&cfg, mockPbApiRepo), mockMailRepo, cache)
+
+	expectNotifyHierarchy()
+
+	first.HandleFeatureEvent(notifyEvent("featureCreate", &before))
+	restarted, err := NewPbNotifyService(&cfg, first.hierarchy, mockMailRepo, cache)
+	restarted.HandleFeatureEvent(notifyEvent("featureUpdate", &after))
+	restarted.HandleFeatureEvent(notifyEvent("featureDelete", nil))
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(restarted.queue))
+	assert.EqualValues(t, "[Portal releases] Dark mode updated", (<-restarted.queue).message.Subject)
+	assert.EqualValues(t, "Deleted: Dark mode", (<-restarted.queue).message.Subject)
+}
+
+func Test_processMail_Error_Retries_ThenGivesUp(t *testing.T) {
+	teardown := setupNotify(t)
+	defer teardown()
+	message := dto.MailMessage{To: []string{"audit@example.com"}, Subject: "Deleted: Dark mode"}
+
+	mockMailRepo.EXPECT().Send(message).Return(api_error.NewInternalServerError("Could not send mail", nil)).Times(2)
+
+	nos.processMail(notifyMail{message: message})
+	var retried notifyMail
+	select {
+	case retried = <-nos.queue:
+	case <-time.After(time.Second):
+		t.Fatal("notification was not requeued")
+	}
+	assert.EqualValues(t, 1, retried.attempts)
+	nos.processMail(retried)
+	time.Sleep(10 * time.Millisecond)
+
+	assert.EqualValues(t, 0, len(nos.queue))
+}
+
+func Test_NewPbNotifyService_InvalidRules_Returns_Error(t *testing.T) {
+	teardown := setupNotify(t)
+	defer teardown()
+	os.WriteFile(cfg.Notify.RulesFile, []byte(`[{"name": "Nobody", "events": ["updated"]}]`), 0644)
+
+	_, err := NewPbNotifyService(&cfg, NewPbHierarchyService(&cfg, mockPbApiRepo), mockMailRepo, repository.NewFeatureCacheRepository(filepath.Join(t.TempDir(), "seen.json")))
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Could not load notification rules from "+cfg.Notify.RulesFile, err.Message())
+}
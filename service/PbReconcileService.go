@@ -0,0 +1,243 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+const (
+	reconcileQueueRetryDelay    = 100 * time.Millisecond
+	maxReconcileQueueRetryDelay = 5 * time.Second
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbReconcileService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbReconcileService
+type PbReconcileService interface {
+	Reconcile() (*dto.ReconcileResult, api_error.ApiErr)
+	HandleFeatureEvent(dto.FeatureEvent)
+	ScheduleReconcile()
+	StopReconcile()
+}
+
+type DefaultPbReconcileService struct {
+	repo   domain.PbApiRepository
+	states domain.FeatureStateRepository
+	events PbEventService
+	cfg    *config.AppConfig
+	done   chan bool
+	stop   *sync.Once
+	known  *knownFeatures
+}
+
+type knownFeatures struct {
+	sync.Mutex
+	loaded   bool
+	baseline bool
+	byId     map[string]dto.FeatureSnapshot
+}
+
+func NewPbReconcileService(c *config.AppConfig, r domain.PbApiRepository, s domain.FeatureStateRepository, e PbEventService) DefaultPbReconcileService {
+	return DefaultPbReconcileService{
+		repo:   r,
+		states: s,
+		events: e,
+		cfg:    c,
+		done:   make(chan bool),
+		stop:   &sync.Once{},
+		known: &knownFeatures{
+			byId: make(map[string]dto.FeatureSnapshot),
+		},
+	}
+}
+
+// Reconcile compares all features with their last known state and queues reconciled events for every difference.
+// In polling mode this is the only source of changes, so the events are queued like webhook notifications.
+// Without a persisted state the current features are recorded as baseline and no events are queued.
+func (rs DefaultPbReconcileService) Reconcile() (*dto.ReconcileResult, api_error.ApiErr) {
+	if err := rs.ensureLoaded(); err != nil {
+		return nil, err
+	}
+	features, err := rs.repo.GetFeatures(dto.FeatureFilter{})
+	if err != nil {
+		return nil, err
+	}
+	rs.known.Lock()
+	if !rs.known.baseline {
+		defer rs.known.Unlock()
+		for _, feature := range features {
+			rs.known.byId[feature.ID] = featureSnapshot(feature)
+		}
+		rs.known.baseline = true
+		logger.Info(fmt.Sprintf("Recorded baseline state of %v feature(s)", len(features)))
+		return &dto.ReconcileResult{}, rs.states.Save(rs.known.byId)
+	}
+	known := make(map[string]dto.FeatureSnapshot)
+	for id, snapshot := range rs.known.byId {
+		known[id] = snapshot
+	}
+	rs.known.Unlock()
+
+	// the state is recorded as soon as an event is queued, so the next pass does not queue it again while it is pending
+	result := dto.ReconcileResult{}
+	defer rs.saveKnown()
+	for _, feature := range features {
+		old, found := known[feature.ID]
+		delete(known, feature.ID)
+		snapshot := featureSnapshot(feature)
+		switch {
+		case !found:
+			err = rs.queue(feature.ID, dto.PbEventTypes["featureCreate"], feature.Links.Self)
+			result.Created++
+		case old.Fingerprint != snapshot.Fingerprint:
+			err = rs.queue(feature.ID, dto.PbEventTypes["featureUpdate"], feature.Links.Self)
+			result.Updated++
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		rs.remember(feature.ID, &snapshot)
+	}
+	for id := range known {
+		if err := rs.queue(id, dto.PbEventTypes["featureDelete"], ""); err != nil {
+			return nil, err
+		}
+		rs.remember(id, nil)
+		result.Deleted++
+	}
+	logger.Info(fmt.Sprintf("Reconciled features. %v created, %v updated and %v deleted since last known state", result.Created, result.Updated, result.Deleted))
+	return &result, nil
+}
+
+// remember records the state of a feature, nil for a deleted one
+func (rs DefaultPbReconcileService) remember(id string, snapshot *dto.FeatureSnapshot) {
+	rs.known.Lock()
+	defer rs.known.Unlock()
+	if snapshot == nil {
+		delete(rs.known.byId, id)
+		return
+	}
+	rs.known.byId[id] = *snapshot
+}
+
+func (rs DefaultPbReconcileService) saveKnown() {
+	rs.known.Lock()
+	defer rs.known.Unlock()
+	if err := rs.states.Save(rs.known.byId); err != nil {
+		logger.Error("Could not save feature states", err)
+	}
+}
+
+// HandleFeatureEvent records the state a feature event left a feature in
+func (rs DefaultPbReconcileService) HandleFeatureEvent(event dto.FeatureEvent) {
+	if err := rs.ensureLoaded(); err != nil {
+		return
+	}
+	rs.known.Lock()
+	defer rs.known.Unlock()
+	if !rs.known.baseline {
+		return
+	}
+	if event.EventType == dto.PbEventTypes["featureDelete"] {
+		delete(rs.known.byId, event.ID)
+	} else if event.Feature != nil {
+		rs.known.byId[event.ID] = featureSnapshot(*event.Feature)
+	}
+	rs.states.Save(rs.known.byId)
+}
+
+func (rs DefaultPbReconcileService) ScheduleReconcile() {
+	interval := time.Duration(rs.cfg.Reconcile.Interval) * time.Minute
+	if rs.cfg.Polling.Enabled {
+		interval = time.Duration(rs.cfg.Polling.Interval) * time.Second
+	}
+	logger.Info(fmt.Sprintf("Reconciling features every %v", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := rs.Reconcile(); err != nil {
+			logger.Error("Could not reconcile features", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-rs.done:
+			logger.Info("Stopped reconciling features")
+			return
+		}
+	}
+}
+
+func (rs DefaultPbReconcileService) StopReconcile() {
+	rs.stop.Do(func() {
+		close(rs.done)
+	})
+}
+
+func (rs DefaultPbReconcileService) ensureLoaded() api_error.ApiErr {
+	rs.known.Lock()
+	defer rs.known.Unlock()
+	if rs.known.loaded {
+		return nil
+	}
+	states, err := rs.states.Load()
+	if err != nil {
+		return err
+	}
+	if states != nil {
+		rs.known.byId = states
+		rs.known.baseline = true
+	}
+	rs.known.loaded = true
+	return nil
+}
+
+// queue waits while the event queue is full, backing off up to maxReconcileQueueRetryDelay, so a pass with more
+// changes than the queue holds is not cut short. It only gives up when reconciling is stopped.
+func (rs DefaultPbReconcileService) queue(id string, eventType string, target string) api_error.ApiErr {
+	notif := dto.PbEventNotification{
+		Data: dto.EventData{
+			ID:        id,
+			EventType: eventType,
+			Links:     dto.EventLinks{Target: target},
+		},
+	}
+	delay := reconcileQueueRetryDelay
+	for {
+		var err api_error.ApiErr
+		if rs.cfg.Polling.Enabled {
+			err = rs.events.QueueEvent(notif)
+		} else {
+			err = rs.events.QueueReconciledEvent(notif)
+		}
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-time.After(delay):
+		case <-rs.done:
+			return err
+		}
+		if delay *= 2; delay > maxReconcileQueueRetryDelay {
+			delay = maxReconcileQueueRetryDelay
+		}
+	}
+}
+
+func featureSnapshot(feature dto.Feature) dto.FeatureSnapshot {
+	raw, _ := json.Marshal(feature)
+	return dto.FeatureSnapshot{
+		ID:          feature.ID,
+		Name:        feature.Name,
+		Status:      feature.Status.Name,
+		Fingerprint: fmt.Sprintf("%x", sha256.Sum256(raw)),
+	}
+}
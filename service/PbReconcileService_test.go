@@ -0,0 +1,162 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	rs             DefaultPbReconcileService
+	mockStateRepo  *domain.MockFeatureStateRepository
+	reconcileQueue DefaultPbEventService
+)
+
+func setupReconcile(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	mockStateRepo = domain.NewMockFeatureStateRepository(pbApiCtrl)
+	cfg.Events.QueueSize = 10
+	reconcileQueue = NewPbEventService(&cfg, mockPbApiRepo)
+	rs = NewPbReconcileService(&cfg, mockPbApiRepo, mockStateRepo, reconcileQueue)
+	return func() {
+		pbApiCtrl.Finish()
+	}
+}
+
+func queuedEvents() []queuedEvent {
+	items := []queuedEvent{}
+	for len(reconcileQueue.queue) > 0 {
+		items = append(items, <-reconcileQueue.queue)
+	}
+	return items
+}
+
+func Test_Reconcile_NoBaseline_Records_Baseline(t *testing.T) {
+	teardown := setupReconcile(t)
+	defer teardown()
+	features := []dto.Feature{{ID: "f1"}, {ID: "f2"}}
+	mockStateRepo.EXPECT().Load().Return(nil, nil)
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).Return(features, nil)
+	mockStateRepo.EXPECT().Save(gomock.Len(2)).Return(nil)
+
+	result, err := rs.Reconcile()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, dto.ReconcileResult{}, *result)
+	assert.EqualValues(t, 0, len(queuedEvents()))
+}
+
+func Test_Reconcile_Queues_ReconciledEvents_For_Differences(t *testing.T) {
+	teardown := setupReconcile(t)
+	defer teardown()
+	unchanged := dto.Feature{ID: "f1", Name: "Unchanged"}
+	changed := dto.Feature{ID: "f2", Name: "Before"}
+	mockStateRepo.EXPECT().Load().Return(map[string]dto.FeatureSnapshot{
+		"f1": featureSnapshot(unchanged),
+		"f2": featureSnapshot(changed),
+		"f3": featureSnapshot(dto.Feature{ID: "f3"}),
+	}, nil)
+	changed.Name = "After"
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).Return([]dto.Feature{unchanged, changed, {ID: "f4"}}, nil)
+	mockStateRepo.EXPECT().Save(gomock.Len(3)).Return(nil)
+
+	result, err := rs.Reconcile()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, dto.ReconcileResult{Created: 1, Updated: 1, Deleted: 1}, *result)
+	events := make(map[string]string)
+	for _, item := range queuedEvents() {
+		assert.True(t, item.reconciled)
+		events[item.notif.Data.ID] = item.notif.Data.EventType
+	}
+	assert.EqualValues(t, map[string]string{
+		"f2": dto.PbEventTypes["featureUpdate"],
+		"f3": dto.PbEventTypes["featureDelete"],
+		"f4": dto.PbEventTypes["featureCreate"],
+	}, events)
+}
+
+func Test_HandleFeatureEvent_Records_State(t *testing.T) {
+	teardown := setupReconcile(t)
+	defer teardown()
+	created := dto.Feature{ID: "f2", Name: "New"}
+	mockStateRepo.EXPECT().Load().Return(map[string]dto.FeatureSnapshot{"f1": {ID: "f1"}}, nil)
+	gomock.InOrder(
+		mockStateRepo.EXPECT().Save(map[string]dto.FeatureSnapshot{}).Return(nil),
+		mockStateRepo.EXPECT().Save(map[string]dto.FeatureSnapshot{"f2": featureSnapshot(created)}).Return(nil),
+	)
+
+	rs.HandleFeatureEvent(dto.FeatureEvent{ID: "f1", EventType: dto.PbEventTypes["featureDelete"]})
+	rs.HandleFeatureEvent(dto.FeatureEvent{ID: "f2", EventType: dto.PbEventTypes["featureCreate"], Feature: &created})
+}
+
+func Test_HandleFeatureEvent_NoBaseline_DoesNotSave(t *testing.T) {
+	teardown := setupReconcile(t)
+	defer teardown()
+	mockStateRepo.EXPECT().Load().Return(nil, nil)
+
+	rs.HandleFeatureEvent(dto.FeatureEvent{ID: "f2", EventType: dto.PbEventTypes["featureCreate"], Feature: &dto.Feature{ID: "f2"}})
+}
+
+func Test_processEvent_Marks_Reconciled(t *testing.T) {
+	teardown := setupEvents(t)
+	defer teardown()
+	h := recordingHandler{}
+	es.AddHandler(&h)
+
+	es.processEvent(eventNotification("f1", dto.PbEventTypes["featureDelete"]), true)
+
+	assert.True(t, h.events[0].Reconciled)
+}
+
+func Test_Reconcile_PollingMode_Queues_RegularEvents(t *testing.T) {
+	teardown := setupReconcile(t)
+	defer teardown()
+	cfg.Polling.Enabled = true
+	defer func() { cfg.Polling.Enabled = false }()
+	mockStateRepo.EXPECT().Load().Return(map[string]dto.FeatureSnapshot{}, nil)
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).Return([]dto.Feature{{ID: "f1"}}, nil)
+	mockStateRepo.EXPECT().Save(gomock.Len(1)).Return(nil)
+
+	result, err := rs.Reconcile()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, result.Created)
+	events := queuedEvents()
+	assert.EqualValues(t, 1, len(events))
+	assert.False(t, events[0].reconciled)
+}
+
+func Test_Reconcile_FullQueue_Waits_And_DoesNot_Queue_Pending_Again(t *testing.T) {
+	teardown := setupReconcile(t)
+	defer teardown()
+	cfg.Events.QueueSize = 2
+	reconcileQueue = NewPbEventService(&cfg, mockPbApiRepo)
+	rs = NewPbReconcileService(&cfg, mockPbApiRepo, mockStateRepo, reconcileQueue)
+	features := []dto.Feature{{ID: "f1"}, {ID: "f2"}, {ID: "f3"}, {ID: "f4"}, {ID: "f5"}}
+	mockStateRepo.EXPECT().Load().Return(map[string]dto.FeatureSnapshot{}, nil)
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).Return(features, nil).Times(2)
+	mockStateRepo.EXPECT().Save(gomock.Len(5)).Return(nil).Times(2)
+	received := make(chan string, len(features))
+	go func() {
+		for range features {
+			received <- (<-reconcileQueue.queue).notif.Data.ID
+		}
+	}()
+
+	result, err := rs.Reconcile()
+	again, againErr := rs.Reconcile()
+
+	assert.Nil(t, err)
+	assert.Nil(t, againErr)
+	assert.EqualValues(t, 5, result.Created)
+	assert.EqualValues(t, dto.ReconcileResult{}, *again)
+	for _, feature := range features {
+		assert.EqualValues(t, feature.ID, <-received)
+	}
+	assert.EqualValues(t, 0, len(reconcileQueue.queue))
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbRoadmapService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbRoadmapService
+type PbRoadmapService interface {
+	GetCalendar(dto.RoadmapFilter) (string, api_error.ApiErr)
+	HandleFeatureEvent(dto.FeatureEvent)
+}
+
+type DefaultPbRoadmapService struct {
+	repo      domain.PbApiRepository
+	hierarchy PbHierarchyService
+	cfg       *config.AppConfig
+	roadmap   *roadmapEntries
+}
+
+// roadmapSequenceEpoch is the start of the SEQUENCE count. SEQUENCE is the number of seconds from it to the last timeframe
+// change, which keeps growing across restarts without storing anything, so calendar apps pick up every change.
+var roadmapSequenceEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// roadmapEntries holds every feature with a timeframe. Events arriving while the roadmap is loaded are kept in
+// pending and applied once loading is done, as the loaded features may predate them.
+type roadmapEntries struct {
+	sync.RWMutex
+	loaded  bool
+	loading bool
+	pending []dto.FeatureEvent
+	entries map[string]*roadmapEntry
+}
+
+type roadmapEntry struct {
+	feature     dto.Feature
+	rescheduled time.Time
+	modified    time.Time
+}
+
+func NewPbRoadmapService(c *config.AppConfig, r domain.PbApiRepository, h PbHierarchyService) DefaultPbRoadmapService {
+	return DefaultPbRoadmapService{
+		repo:      r,
+		hierarchy: h,
+		cfg:       c,
+		roadmap:   &roadmapEntries{},
+	}
+}
+
+func ParseRoadmapFilter(products []string, components []string, statuses []string) dto.RoadmapFilter {
+	return dto.RoadmapFilter{
+		Products:   splitList(products),
+		Components: splitList(components),
+		Statuses:   splitList(statuses),
+	}
+}
+
+// GetCalendar renders all features with a timeframe that match the filter as iCalendar feed, one all-day event per feature
+func (rs DefaultPbRoadmapService) GetCalendar(filter dto.RoadmapFilter) (string, api_error.ApiErr) {
+	err := rs.ensureLoaded()
+	if err != nil {
+		return "", err
+	}
+	rs.roadmap.RLock()
+	entries := make([]roadmapEntry, 0, len(rs.roadmap.entries))
+	for _, entry := range rs.roadmap.entries {
+		entries = append(entries, *entry)
+	}
+	rs.roadmap.RUnlock()
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].feature.ID < entries[j].feature.ID
+	})
+	cal := icalWriter{}
+	cal.line("BEGIN", "VCALENDAR")
+	cal.line("VERSION", "2.0")
+	cal.line("PRODID", "-//pbreact//roadmap//EN")
+	cal.line("CALSCALE", "GREGORIAN")
+	cal.line("METHOD", "PUBLISH")
+	cal.text("X-WR-CALNAME", rs.cfg.Roadmap.CalendarName)
+	now := date.GetNowUtc()
+	for _, entry := range entries {
+		if !containsFold(filter.Statuses, entry.feature.Status.Name) {
+			continue
+		}
+		path := hierarchyPath(rs.hierarchy, entry.feature.Parent.ParentId())
+		if !pathMatches(path, dto.NodeTypeProduct, filter.Products) || !pathMatches(path, dto.NodeTypeComponent, filter.Components) {
+			continue
+		}
+		rs.writeEvent(&cal, entry, path, now)
+	}
+	cal.line("END", "VCALENDAR")
+	return cal.String(), nil
+}
+
+// HandleFeatureEvent keeps the roadmap current, so the next calendar refresh sees changed timeframes
+func (rs DefaultPbRoadmapService) HandleFeatureEvent(event dto.FeatureEvent) {
+	rs.roadmap.Lock()
+	defer rs.roadmap.Unlock()
+	if !rs.roadmap.loaded {
+		if rs.roadmap.loading {
+			rs.roadmap.pending = append(rs.roadmap.pending, event)
+		}
+		return
+	}
+	rs.applyEvent(event)
+}
+
+// applyEvent updates the entry of the event's feature; the lock has to be held
+func (rs DefaultPbRoadmapService) applyEvent(event dto.FeatureEvent) {
+	if event.EventType == dto.PbEventTypes["featureDelete"] || event.Feature == nil || !hasTimeframe(event.Feature.Timeframe) {
+		delete(rs.roadmap.entries, event.ID)
+		return
+	}
+	feature := *event.Feature
+	entry, found := rs.roadmap.entries[feature.ID]
+	if !found {
+		rs.roadmap.entries[feature.ID] = &roadmapEntry{feature: feature, rescheduled: event.ReceivedAt, modified: event.ReceivedAt}
+		return
+	}
+	if entry.feature.Timeframe != feature.Timeframe {
+		logger.Info(fmt.Sprintf("Timeframe of feature %v changed from %v - %v to %v - %v", feature.ID,
+			entry.feature.Timeframe.StartDate, entry.feature.Timeframe.EndDate, feature.Timeframe.StartDate, feature.Timeframe.EndDate))
+		previous := entry.rescheduled
+		entry.rescheduled = event.ReceivedAt
+		if !entry.rescheduled.After(previous.Add(time.Second)) {
+			entry.rescheduled = previous.Add(time.Second)
+		}
+		entry.modified = event.ReceivedAt
+	} else if entry.feature.Name != feature.Name || entry.feature.Status != feature.Status {
+		entry.modified = event.ReceivedAt
+	}
+	entry.feature = feature
+}
+
+func (rs DefaultPbRoadmapService) ensureLoaded() api_error.ApiErr {
+	rs.roadmap.Lock()
+	loaded := rs.roadmap.loaded
+	rs.roadmap.loading = !loaded
+	rs.roadmap.Unlock()
+	if loaded {
+		return nil
+	}
+	features, err := rs.repo.GetFeatures(dto.FeatureFilter{})
+	if err != nil {
+		rs.roadmap.Lock()
+		rs.roadmap.loading = false
+		rs.roadmap.pending = nil
+		rs.roadmap.Unlock()
+		return err
+	}
+	now := date.GetNowUtc()
+	entries := make(map[string]*roadmapEntry)
+	for _, feature := range features {
+		if hasTimeframe(feature.Timeframe) {
+			entries[feature.ID] = &roadmapEntry{feature: feature, rescheduled: now, modified: now}
+		}
+	}
+	rs.roadmap.Lock()
+	if !rs.roadmap.loaded {
+		rs.roadmap.entries = entries
+		rs.roadmap.loaded = true
+		for _, event := range rs.roadmap.pending {
+			rs.applyEvent(event)
+		}
+	}
+	rs.roadmap.loading = false
+	rs.roadmap.pending = nil
+	rs.roadmap.Unlock()
+	logger.Info(fmt.Sprintf("Loaded roadmap with %v feature(s) with timeframe", len(entries)))
+	return nil
+}
+
+func (rs DefaultPbRoadmapService) writeEvent(cal *icalWriter, entry roadmapEntry, path []dto.HierarchyNode, now time.Time) {
+	start, end, valid := timeframeDates(entry.feature.Timeframe)
+	if !valid {
+		logger.Warn(fmt.Sprintf("Skipping feature %v with invalid timeframe %v - %v", entry.feature.ID, entry.feature.Timeframe.StartDate, entry.feature.Timeframe.EndDate))
+		return
+	}
+	names := []string{}
+	for _, node := range path {
+		names = append(names, node.Name)
+	}
+	description := fmt.Sprintf("Status: %v", entry.feature.Status.Name)
+	if len(names) > 0 {
+		description = fmt.Sprintf("%v\n%v", strings.Join(names, " / "), description)
+	}
+	cal.line("BEGIN", "VEVENT")
+	cal.text("UID", fmt.Sprintf("%v@%v", entry.feature.ID, rs.cfg.Roadmap.UidDomain))
+	cal.stamp("DTSTAMP", now)
+	cal.stamp("LAST-MODIFIED", entry.modified)
+	cal.line("SEQUENCE", fmt.Sprint(entry.sequence()))
+	cal.date("DTSTART", start)
+	cal.date("DTEND", end.AddDate(0, 0, 1))
+	cal.text("SUMMARY", entry.feature.Name)
+	cal.text("DESCRIPTION", description)
+	if entry.feature.Links.Html != "" {
+		cal.text("URL", entry.feature.Links.Html)
+	}
+	cal.line("TRANSP", "TRANSPARENT")
+	cal.line("END", "VEVENT")
+}
+
+func (entry roadmapEntry) sequence() int64 {
+	return int64(entry.rescheduled.Sub(roadmapSequenceEpoch) / time.Second)
+}
+
+func hasTimeframe(timeframe dto.Timeframe) bool {
+	return exportDate(timeframe.StartDate) != "" || exportDate(timeframe.EndDate) != ""
+}
+
+// timeframeDates returns the first and last day of a timeframe. A timeframe with only one date lasts that day.
+func timeframeDates(timeframe dto.Timeframe) (time.Time, time.Time, bool) {
+	start, startErr := time.Parse(featureDateLayout, exportDate(timeframe.StartDate))
+	end, endErr := time.Parse(featureDateLayout, exportDate(timeframe.EndDate))
+	switch {
+	case startErr == nil && endErr == nil:
+		return start, end, !end.Before(start)
+	case startErr == nil && exportDate(timeframe.EndDate) == "":
+		return start, start, true
+	case endErr == nil && exportDate(timeframe.StartDate) == "":
+		return end, end, true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+func pathMatches(path []dto.HierarchyNode, nodeType string, wanted []string) bool {
+	if len(wanted) == 0 {
+		return true
+	}
+	for _, node := range path {
+		if node.Type != nodeType {
+			continue
+		}
+		for _, w := range wanted {
+			if node.ID == w || strings.EqualFold(node.Name, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsFold reports whether value is in values, ignoring case. An empty list matches everything.
+func containsFold(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
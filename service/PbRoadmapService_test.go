@@ -0,0 +1,141 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	rms DefaultPbRoadmapService
+)
+
+func setupRoadmap(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	cfg.Roadmap.CalendarName = "Roadmap"
+	cfg.Roadmap.UidDomain = "pbreact"
+	rms = NewPbRoadmapService(&cfg, mockPbApiRepo, NewPbHierarchyService(&cfg, mockPbApiRepo))
+	return func() {
+		pbApiCtrl.Finish()
+	}
+}
+
+func roadmapFeatures() []dto.Feature {
+	return []dto.Feature{
+		{ID: "f1", Name: "Dark mode, finally", Status: dto.FeatureStatus{Name: "In progress"}, Parent: dto.Parent{Component: &dto.ParentRef{ID: "c1"}},
+			Timeframe: dto.Timeframe{StartDate: "2024-01-01", EndDate: "2024-03-31"}, Links: dto.EntityLinks{Html: "https://pb/f1"}},
+		{ID: "f2", Name: "Reports", Status: dto.FeatureStatus{Name: "New idea"}, Parent: dto.Parent{Product: &dto.ParentRef{ID: "p2"}},
+			Timeframe: dto.Timeframe{StartDate: "none", EndDate: "2024-06-30"}},
+		{ID: "f3", Name: "Someday", Status: dto.FeatureStatus{Name: "New idea"}, Parent: dto.Parent{Product: &dto.ParentRef{ID: "p2"}},
+			Timeframe: dto.Timeframe{StartDate: "none", EndDate: "none"}},
+	}
+}
+
+func expectRoadmapHierarchy(features []dto.Feature) {
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).Return(features, nil).Times(2)
+	mockPbApiRepo.EXPECT().GetProducts().Return([]dto.Product{{ID: "p1", Name: "Portal"}, {ID: "p2", Name: "Analytics"}}, nil)
+	mockPbApiRepo.EXPECT().GetComponents().Return([]dto.Component{{ID: "c1", Name: "Branding", Parent: dto.Parent{Product: &dto.ParentRef{ID: "p1"}}}}, nil)
+}
+
+func Test_GetCalendar_Renders_FeaturesWithTimeframe(t *testing.T) {
+	teardown := setupRoadmap(t)
+	defer teardown()
+
+	expectRoadmapHierarchy(roadmapFeatures())
+
+	cal, err := rms.GetCalendar(dto.RoadmapFilter{})
+
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(cal, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(cal, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.EqualValues(t, 2, strings.Count(cal, "BEGIN:VEVENT"))
+	assert.Contains(t, cal, "UID:f1@pbreact\r\n")
+	assert.Contains(t, cal, "DTSTART;VALUE=DATE:20240101\r\nDTEND;VALUE=DATE:20240401\r\nSUMMARY:Dark mode\\, finally\r\n")
+	assert.Contains(t, cal, "DESCRIPTION:Portal / Branding\\nStatus: In progress\r\n")
+	assert.Contains(t, cal, "UID:f2@pbreact\r\n")
+	assert.Contains(t, cal, "DTSTART;VALUE=DATE:20240630\r\nDTEND;VALUE=DATE:20240701\r\n")
+	assert.NotContains(t, cal, "f3@pbreact")
+}
+
+func Test_GetCalendar_Filters_ProductAndStatus(t *testing.T) {
+	teardown := setupRoadmap(t)
+	defer teardown()
+
+	expectRoadmapHierarchy(roadmapFeatures())
+
+	cal, err := rms.GetCalendar(ParseRoadmapFilter([]string{"portal,analytics"}, []string{"c1"}, []string{"In progress"}))
+
+	assert.Nil(t, err)
+	assert.Contains(t, cal, "UID:f1@pbreact")
+	assert.NotContains(t, cal, "UID:f2@pbreact")
+}
+
+func Test_HandleFeatureEvent_TimeframeChange_Increments_Sequence(t *testing.T) {
+	teardown := setupRoadmap(t)
+	defer teardown()
+	features := roadmapFeatures()
+	moved := features[0]
+	moved.Timeframe.EndDate = "2024-04-30"
+	receivedAt := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).Return(features, nil)
+
+	rms.ensureLoaded()
+	loaded := rms.roadmap.entries["f1"].sequence()
+	rms.HandleFeatureEvent(dto.FeatureEvent{ID: "f1", EventType: dto.PbEventTypes["featureUpdate"], Feature: &moved, ReceivedAt: receivedAt})
+	rms.HandleFeatureEvent(dto.FeatureEvent{ID: "f2", EventType: dto.PbEventTypes["featureDelete"]})
+
+	assert.EqualValues(t, loaded+1, rms.roadmap.entries["f1"].sequence())
+	assert.EqualValues(t, receivedAt, rms.roadmap.entries["f1"].modified)
+	assert.EqualValues(t, "2024-04-30", rms.roadmap.entries["f1"].feature.Timeframe.EndDate)
+	assert.NotContains(t, rms.roadmap.entries, "f2")
+}
+
+func Test_roadmapEntry_Sequence_Counts_Seconds_Since_Epoch(t *testing.T) {
+	before := roadmapEntry{rescheduled: time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)}
+	after := roadmapEntry{rescheduled: time.Date(2024, 2, 1, 10, 0, 5, 0, time.UTC)}
+
+	assert.EqualValues(t, 128944800, before.sequence())
+	assert.EqualValues(t, 5, after.sequence()-before.sequence())
+}
+
+func Test_HandleFeatureEvent_WhileLoading_Is_Applied_AfterLoad(t *testing.T) {
+	teardown := setupRoadmap(t)
+	defer teardown()
+	features := roadmapFeatures()
+	moved := features[1]
+	moved.Timeframe.EndDate = "2024-07-31"
+
+	rms.HandleFeatureEvent(dto.FeatureEvent{ID: "f1", EventType: dto.PbEventTypes["featureDelete"]})
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).DoAndReturn(func(dto.FeatureFilter) ([]dto.Feature, api_error.ApiErr) {
+		rms.HandleFeatureEvent(dto.FeatureEvent{ID: "f2", EventType: dto.PbEventTypes["featureUpdate"], Feature: &moved, ReceivedAt: date.GetNowUtc()})
+		return features, nil
+	})
+
+	err := rms.ensureLoaded()
+
+	assert.Nil(t, err)
+	assert.Contains(t, rms.roadmap.entries, "f1")
+	assert.EqualValues(t, "2024-07-31", rms.roadmap.entries["f2"].feature.Timeframe.EndDate)
+	assert.EqualValues(t, 0, len(rms.roadmap.pending))
+}
+
+func Test_icalWriter_Folds_LongLines(t *testing.T) {
+	cal := icalWriter{}
+
+	cal.text("SUMMARY", strings.Repeat("ä", 50))
+
+	lines := strings.Split(strings.TrimSuffix(cal.String(), "\r\n"), "\r\n")
+	assert.EqualValues(t, 2, len(lines))
+	assert.True(t, len(lines[0]) <= 75)
+	assert.True(t, strings.HasPrefix(lines[1], " "))
+	assert.EqualValues(t, "SUMMARY:"+strings.Repeat("ä", 50), lines[0]+lines[1][1:])
+}
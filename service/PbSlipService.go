@@ -0,0 +1,329 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbSlipService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbSlipService
+type PbSlipService interface {
+	HandleFeatureEvent(dto.FeatureEvent)
+	CheckOverdue() api_error.ApiErr
+	GetReport() (*dto.SlipReport, api_error.ApiErr)
+	ScheduleChecks()
+	StopChecks()
+}
+
+type DefaultPbSlipService struct {
+	repo     domain.PbApiRepository
+	journal  domain.FeatureJournalRepository
+	webhooks domain.WebhookRepository
+	cfg      *config.AppConfig
+	done     chan bool
+	stop     *sync.Once
+	slips    *slipState
+}
+
+// slipState is the timeframe history of all features, rebuilt from the journal on first use
+type slipState struct {
+	sync.Mutex
+	loaded   bool
+	features map[string]*slipRecord
+}
+
+type slipRecord struct {
+	dto.SlipRecord
+	timeframe       dto.Timeframe
+	overdueNotified string
+}
+
+func NewPbSlipService(c *config.AppConfig, r domain.PbApiRepository, j domain.FeatureJournalRepository, w domain.WebhookRepository) DefaultPbSlipService {
+	return DefaultPbSlipService{
+		repo:     r,
+		journal:  j,
+		webhooks: w,
+		cfg:      c,
+		done:     make(chan bool),
+		stop:     &sync.Once{},
+		slips:    &slipState{},
+	}
+}
+
+// HandleFeatureEvent records the feature's timeframe and sends a notification if its end date moved later
+func (ss DefaultPbSlipService) HandleFeatureEvent(event dto.FeatureEvent) {
+	if err := ss.ensureLoaded(); err != nil {
+		logger.Error(fmt.Sprintf("Could not check feature %v for timeframe slips", event.ID), err)
+		return
+	}
+	if event.Feature == nil || event.Feature.Archived {
+		if event.EventType == dto.PbEventTypes["featureDelete"] || event.Feature != nil {
+			ss.slips.Lock()
+			delete(ss.slips.features, event.ID)
+			ss.slips.Unlock()
+		}
+		return
+	}
+	if notification := ss.observe(*event.Feature, event.ReceivedAt); notification != nil {
+		ss.notify(*notification)
+	}
+}
+
+// CheckOverdue fetches all features, records their timeframes and notifies once about every end date that passed
+// while the feature is not in a done status. Features that were deleted or archived are forgotten.
+func (ss DefaultPbSlipService) CheckOverdue() api_error.ApiErr {
+	if err := ss.ensureLoaded(); err != nil {
+		return err
+	}
+	features, err := ss.repo.GetFeatures(dto.FeatureFilter{})
+	if err != nil {
+		return err
+	}
+	now := date.GetNowUtc()
+	notifications := []dto.SlipNotification{}
+	active := make(map[string]bool)
+	for _, feature := range features {
+		if feature.Archived {
+			continue
+		}
+		active[feature.ID] = true
+		if notification := ss.observe(feature, now); notification != nil {
+			notifications = append(notifications, *notification)
+		}
+	}
+	ss.slips.Lock()
+	for id := range ss.slips.features {
+		if !active[id] {
+			delete(ss.slips.features, id)
+		}
+	}
+	for _, record := range ss.slips.features {
+		days := ss.daysOverdue(record, now)
+		if days == 0 || record.overdueNotified == record.EndDate {
+			continue
+		}
+		timeframe := record.timeframe
+		if err := ss.journal.Append(dto.JournalEntry{Time: now, FeatureId: record.FeatureId, FeatureName: record.Name, Kind: dto.JournalOverdue, Status: record.Status, Timeframe: &timeframe}); err != nil {
+			continue
+		}
+		record.overdueNotified = record.EndDate
+		feature := record.SlipRecord
+		feature.DaysOverdue = days
+		notifications = append(notifications, dto.SlipNotification{Event: dto.SlipOverdue, Days: days, Feature: feature})
+	}
+	ss.slips.Unlock()
+	for _, notification := range notifications {
+		ss.notify(notification)
+	}
+	return nil
+}
+
+// GetReport lists features that are overdue and features that slipped at least the configured number of times
+func (ss DefaultPbSlipService) GetReport() (*dto.SlipReport, api_error.ApiErr) {
+	if err := ss.ensureLoaded(); err != nil {
+		return nil, err
+	}
+	now := date.GetNowUtc()
+	report := dto.SlipReport{
+		GeneratedAt: now,
+		Overdue:     []dto.SlipRecord{},
+		Slipped:     []dto.SlipRecord{},
+	}
+	ss.slips.Lock()
+	for _, record := range ss.slips.features {
+		feature := record.SlipRecord
+		feature.DaysOverdue = ss.daysOverdue(record, now)
+		if feature.DaysOverdue > 0 {
+			report.Overdue = append(report.Overdue, feature)
+		}
+		if feature.SlipCount >= ss.cfg.Slips.RepeatThreshold && feature.SlipCount > 0 {
+			report.Slipped = append(report.Slipped, feature)
+		}
+	}
+	ss.slips.Unlock()
+	sort.Slice(report.Overdue, func(i, j int) bool {
+		return report.Overdue[i].DaysOverdue > report.Overdue[j].DaysOverdue
+	})
+	sort.Slice(report.Slipped, func(i, j int) bool {
+		if report.Slipped[i].SlipCount != report.Slipped[j].SlipCount {
+			return report.Slipped[i].SlipCount > report.Slipped[j].SlipCount
+		}
+		return report.Slipped[i].DaysSlipped > report.Slipped[j].DaysSlipped
+	})
+	return &report, nil
+}
+
+func (ss DefaultPbSlipService) ScheduleChecks() {
+	interval := time.Duration(ss.cfg.Slips.CheckInterval) * time.Minute
+	logger.Info(fmt.Sprintf("Checking for overdue features every %v", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := ss.CheckOverdue(); err != nil {
+			logger.Error("Could not check for overdue features", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ss.done:
+			logger.Info("Stopped checking for overdue features")
+			return
+		}
+	}
+}
+
+func (ss DefaultPbSlipService) StopChecks() {
+	ss.stop.Do(func() {
+		close(ss.done)
+	})
+}
+
+// observe records a change of the feature's timeframe in the journal. It returns a notification if the end date moved later.
+func (ss DefaultPbSlipService) observe(feature dto.Feature, at time.Time) *dto.SlipNotification {
+	ss.slips.Lock()
+	defer ss.slips.Unlock()
+	record, found := ss.slips.features[feature.ID]
+	if !found {
+		record = &slipRecord{}
+		record.FeatureId = feature.ID
+		ss.slips.features[feature.ID] = record
+	}
+	record.Name = feature.Name
+	record.Status = feature.Status.Name
+	record.Url = feature.Links.Html
+	if found && record.timeframe == feature.Timeframe {
+		return nil
+	}
+	if !found && !hasTimeframe(feature.Timeframe) {
+		record.timeframe = feature.Timeframe
+		return nil
+	}
+	old := record.timeframe
+	entry := dto.JournalEntry{
+		Time:        at,
+		FeatureId:   feature.ID,
+		FeatureName: feature.Name,
+		Kind:        dto.JournalTimeframe,
+		Status:      feature.Status.Name,
+		Timeframe:   &feature.Timeframe,
+		DaysSlipped: slipDays(old.EndDate, feature.Timeframe.EndDate),
+	}
+	if found {
+		entry.OldTimeframe = &old
+	}
+	if err := ss.journal.Append(entry); err != nil {
+		return nil
+	}
+	record.apply(entry)
+	if entry.DaysSlipped == 0 {
+		return nil
+	}
+	logger.Info(fmt.Sprintf("End date of feature %v slipped by %v day(s) from %v to %v", feature.ID, entry.DaysSlipped, old.EndDate, feature.Timeframe.EndDate))
+	return &dto.SlipNotification{
+		Event:      dto.SlipSlipped,
+		Days:       entry.DaysSlipped,
+		OldEndDate: old.EndDate,
+		Feature:    record.SlipRecord,
+	}
+}
+
+// apply updates the record with a journal entry, both when observing a change and when replaying the journal
+func (record *slipRecord) apply(entry dto.JournalEntry) {
+	record.FeatureId = entry.FeatureId
+	record.Name = entry.FeatureName
+	if entry.Status != "" {
+		record.Status = entry.Status
+	}
+	switch entry.Kind {
+	case dto.JournalTimeframe:
+		record.timeframe = *entry.Timeframe
+		record.StartDate = exportDate(entry.Timeframe.StartDate)
+		record.EndDate = exportDate(entry.Timeframe.EndDate)
+		if record.OriginalEndDate == "" {
+			record.OriginalEndDate = record.EndDate
+		}
+		if entry.DaysSlipped > 0 {
+			at := entry.Time
+			record.SlipCount++
+			record.DaysSlipped += entry.DaysSlipped
+			record.LastSlipAt = &at
+		}
+	case dto.JournalOverdue:
+		record.overdueNotified = exportDate(entry.Timeframe.EndDate)
+	}
+}
+
+func (ss DefaultPbSlipService) ensureLoaded() api_error.ApiErr {
+	ss.slips.Lock()
+	defer ss.slips.Unlock()
+	if ss.slips.loaded {
+		return nil
+	}
+	entries, err := ss.journal.Read(time.Time{})
+	if err != nil {
+		return err
+	}
+	features := make(map[string]*slipRecord)
+	for _, entry := range entries {
+		if entry.Timeframe == nil || (entry.Kind != dto.JournalTimeframe && entry.Kind != dto.JournalOverdue) {
+			continue
+		}
+		record, found := features[entry.FeatureId]
+		if !found {
+			record = &slipRecord{}
+			features[entry.FeatureId] = record
+		}
+		record.apply(entry)
+	}
+	ss.slips.features = features
+	ss.slips.loaded = true
+	return nil
+}
+
+// daysOverdue returns how many days ago the end date passed, or 0 if it has not passed or the feature is done
+func (ss DefaultPbSlipService) daysOverdue(record *slipRecord, now time.Time) int {
+	end, err := time.Parse(featureDateLayout, record.EndDate)
+	if err != nil || record.Status == "" || ss.isDone(record.Status) {
+		return 0
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if !end.Before(today) {
+		return 0
+	}
+	return int(today.Sub(end).Hours() / 24)
+}
+
+func (ss DefaultPbSlipService) isDone(status string) bool {
+	for _, done := range ss.cfg.Statuses.Done {
+		if strings.EqualFold(done, status) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ss DefaultPbSlipService) notify(notification dto.SlipNotification) {
+	for _, url := range ss.cfg.Slips.WebhookUrls {
+		if err := ss.webhooks.PostJson(url, notification); err != nil {
+			logger.Error(fmt.Sprintf("Could not send %v notification for feature %v", notification.Event, notification.Feature.FeatureId), err)
+		}
+	}
+}
+
+// slipDays returns by how many days the end date moved later. Earlier, added or removed end dates are no slip.
+func slipDays(oldEnd string, newEnd string) int {
+	oldDate, oldErr := time.Parse(featureDateLayout, oldEnd)
+	newDate, newErr := time.Parse(featureDateLayout, newEnd)
+	if oldErr != nil || newErr != nil || !newDate.After(oldDate) {
+		return 0
+	}
+	return int(newDate.Sub(oldDate).Hours() / 24)
+}
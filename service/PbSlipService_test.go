@@ -0,0 +1,181 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	sls             DefaultPbSlipService
+	mockJournalRepo *domain.MockFeatureJournalRepository
+	mockWebhookRepo *domain.MockWebhookRepository
+)
+
+func setupSlips(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	mockJournalRepo = domain.NewMockFeatureJournalRepository(pbApiCtrl)
+	mockWebhookRepo = domain.NewMockWebhookRepository(pbApiCtrl)
+	cfg.Slips.WebhookUrls = []string{"http://hooks/slips"}
+	cfg.Slips.RepeatThreshold = 2
+	cfg.Statuses.Done = []string{"Released"}
+	sls = NewPbSlipService(&cfg, mockPbApiRepo, mockJournalRepo, mockWebhookRepo)
+	return func() {
+		cfg.Statuses.Done = nil
+		pbApiCtrl.Finish()
+	}
+}
+
+func slipFeature(endDate string, status string) dto.Feature {
+	return dto.Feature{
+		ID:        "f1",
+		Name:      "Dark mode",
+		Status:    dto.FeatureStatus{Name: status},
+		Timeframe: dto.Timeframe{StartDate: "2024-01-01", EndDate: endDate},
+	}
+}
+
+func slipEvent(feature dto.Feature, receivedAt time.Time) dto.FeatureEvent {
+	return dto.FeatureEvent{ID: feature.ID, EventType: dto.PbEventTypes["featureUpdate"], Feature: &feature, ReceivedAt: receivedAt}
+}
+
+func Test_HandleFeatureEvent_EndDateLater_Notifies_Slip(t *testing.T) {
+	teardown := setupSlips(t)
+	defer teardown()
+	receivedAt := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+	var notification dto.SlipNotification
+
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return([]dto.JournalEntry{
+		{FeatureId: "f1", FeatureName: "Dark mode", Kind: dto.JournalTimeframe, Timeframe: &dto.Timeframe{StartDate: "2024-01-01", EndDate: "2024-03-01"}},
+		{FeatureId: "f1", FeatureName: "Dark mode", Kind: dto.JournalTimeframe, Timeframe: &dto.Timeframe{StartDate: "2024-01-01", EndDate: "2024-03-31"}, DaysSlipped: 30},
+	}, nil)
+	mockJournalRepo.EXPECT().Append(gomock.Any()).DoAndReturn(func(entry dto.JournalEntry) error {
+		assert.EqualValues(t, 15, entry.DaysSlipped)
+		assert.EqualValues(t, "2024-03-31", entry.OldTimeframe.EndDate)
+		return nil
+	})
+	mockWebhookRepo.EXPECT().PostJson("http://hooks/slips", gomock.Any()).DoAndReturn(func(_ string, payload interface{}) error {
+		notification = payload.(dto.SlipNotification)
+		return nil
+	})
+
+	sls.HandleFeatureEvent(slipEvent(slipFeature("2024-04-15", "In progress"), receivedAt))
+
+	assert.EqualValues(t, dto.SlipSlipped, notification.Event)
+	assert.EqualValues(t, 15, notification.Days)
+	assert.EqualValues(t, "2024-03-31", notification.OldEndDate)
+	assert.EqualValues(t, 2, notification.Feature.SlipCount)
+	assert.EqualValues(t, 45, notification.Feature.DaysSlipped)
+	assert.EqualValues(t, "2024-03-01", notification.Feature.OriginalEndDate)
+	assert.EqualValues(t, receivedAt, *notification.Feature.LastSlipAt)
+}
+
+func Test_HandleFeatureEvent_EndDateEarlier_RecordsWithoutNotification(t *testing.T) {
+	teardown := setupSlips(t)
+	defer teardown()
+
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return([]dto.JournalEntry{}, nil)
+	mockJournalRepo.EXPECT().Append(gomock.Any()).Return(nil).Times(2)
+
+	sls.HandleFeatureEvent(slipEvent(slipFeature("2024-03-31", "In progress"), time.Now()))
+	sls.HandleFeatureEvent(slipEvent(slipFeature("2024-03-15", "In progress"), time.Now()))
+	sls.HandleFeatureEvent(slipEvent(slipFeature("2024-03-15", "Planned"), time.Now()))
+
+	assert.EqualValues(t, 0, sls.slips.features["f1"].SlipCount)
+	assert.EqualValues(t, "2024-03-15", sls.slips.features["f1"].EndDate)
+	assert.EqualValues(t, "Planned", sls.slips.features["f1"].Status)
+}
+
+func Test_CheckOverdue_Notifies_OncePerEndDate(t *testing.T) {
+	teardown := setupSlips(t)
+	defer teardown()
+	overdue := slipFeature(time.Now().UTC().AddDate(0, 0, -3).Format("2006-01-02"), "In progress")
+	done := slipFeature("2020-01-01", "released")
+	done.ID = "f2"
+	future := slipFeature(time.Now().UTC().AddDate(0, 0, 3).Format("2006-01-02"), "In progress")
+	future.ID = "f3"
+	var notification dto.SlipNotification
+
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return([]dto.JournalEntry{}, nil)
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).Return([]dto.Feature{overdue, done, future}, nil).Times(2)
+	mockJournalRepo.EXPECT().Append(gomock.Any()).Return(nil).Times(4)
+	mockWebhookRepo.EXPECT().PostJson("http://hooks/slips", gomock.Any()).DoAndReturn(func(_ string, payload interface{}) error {
+		notification = payload.(dto.SlipNotification)
+		return nil
+	})
+
+	err1 := sls.CheckOverdue()
+	err2 := sls.CheckOverdue()
+
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.EqualValues(t, dto.SlipOverdue, notification.Event)
+	assert.EqualValues(t, 3, notification.Days)
+	assert.EqualValues(t, "f1", notification.Feature.FeatureId)
+}
+
+func Test_CheckOverdue_Forgets_DeletedAndArchivedFeatures(t *testing.T) {
+	teardown := setupSlips(t)
+	defer teardown()
+	kept := slipFeature("2099-01-01", "In progress")
+	archived := slipFeature("2099-01-01", "In progress")
+	archived.ID = "f2"
+	archived.Archived = true
+	deleted := dto.JournalEntry{FeatureId: "f3", FeatureName: "Gone", Kind: dto.JournalTimeframe, Timeframe: &dto.Timeframe{StartDate: "2024-01-01", EndDate: "2024-02-01"}}
+
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return([]dto.JournalEntry{deleted}, nil)
+	mockJournalRepo.EXPECT().Append(gomock.Any()).Return(nil)
+	mockPbApiRepo.EXPECT().GetFeatures(dto.FeatureFilter{}).Return([]dto.Feature{kept, archived}, nil)
+
+	err := sls.CheckOverdue()
+	report, _ := sls.GetReport()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(sls.slips.features))
+	assert.Contains(t, sls.slips.features, "f1")
+	assert.EqualValues(t, 0, len(report.Overdue))
+}
+
+func Test_GetReport_Lists_OverdueAndRepeatedlySlipped(t *testing.T) {
+	teardown := setupSlips(t)
+	defer teardown()
+	pastEnd := time.Now().UTC().AddDate(0, 0, -10).Format("2006-01-02")
+
+	mockJournalRepo.EXPECT().Read(time.Time{}).Return([]dto.JournalEntry{
+		{FeatureId: "f1", FeatureName: "Dark mode", Kind: dto.JournalTimeframe, Status: "In progress", Timeframe: &dto.Timeframe{EndDate: pastEnd}},
+		{FeatureId: "f2", FeatureName: "Reports", Kind: dto.JournalTimeframe, Status: "Released", Timeframe: &dto.Timeframe{EndDate: "2024-01-31"}},
+		{FeatureId: "f2", FeatureName: "Reports", Kind: dto.JournalTimeframe, Timeframe: &dto.Timeframe{EndDate: "2024-02-29"}, DaysSlipped: 29},
+		{FeatureId: "f2", FeatureName: "Reports", Kind: dto.JournalTimeframe, Timeframe: &dto.Timeframe{EndDate: "2024-03-31"}, DaysSlipped: 31},
+		{FeatureId: "f3", FeatureName: "Search", Kind: dto.JournalTimeframe, Status: "Planned", Timeframe: &dto.Timeframe{EndDate: "2099-01-31"}},
+		{FeatureId: "f3", FeatureName: "Search", Kind: dto.JournalTimeframe, Timeframe: &dto.Timeframe{EndDate: "2099-02-28"}, DaysSlipped: 28},
+	}, nil)
+
+	report, err := sls.GetReport()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(report.Overdue))
+	assert.EqualValues(t, "f1", report.Overdue[0].FeatureId)
+	assert.EqualValues(t, 10, report.Overdue[0].DaysOverdue)
+	assert.EqualValues(t, 1, len(report.Slipped))
+	assert.EqualValues(t, "f2", report.Slipped[0].FeatureId)
+	assert.EqualValues(t, 60, report.Slipped[0].DaysSlipped)
+}
+
+func Test_slipDays_Returns_DaysMovedLater(t *testing.T) {
+	assert.EqualValues(t, 31, slipDays("2024-03-01", "2024-04-01"))
+	assert.EqualValues(t, 0, slipDays("2024-04-01", "2024-03-01"))
+	assert.EqualValues(t, 0, slipDays("none", "2024-03-01"))
+}
+
+func Test_StopChecks_CalledTwice_DoesNotPanic(t *testing.T) {
+	teardown := setupSlips(t)
+	defer teardown()
+
+	sls.StopChecks()
+	sls.StopChecks()
+}
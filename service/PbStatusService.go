@@ -0,0 +1,252 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbStatusService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbStatusService
+type PbStatusService interface {
+	RefreshStatuses() ([]dto.StatusChange, api_error.ApiErr)
+	GetStatuses() ([]dto.FeatureStatus, api_error.ApiErr)
+	GetStatusId(string) (string, api_error.ApiErr)
+	GetStatusName(string) (string, api_error.ApiErr)
+	ValidateStatusNames([]string) api_error.ApiErr
+	HandleFeatureEvent(dto.FeatureEvent)
+}
+
+type DefaultPbStatusService struct {
+	repo    domain.PbApiRepository
+	cfg     *config.AppConfig
+	catalog *statusCatalog
+}
+
+type statusCatalog struct {
+	sync.RWMutex
+	loaded   bool
+	statuses []dto.FeatureStatus
+	byId     map[string]string
+	// misses holds names and ids that were still unknown after a refresh, so they do not trigger another one right away
+	misses map[string]time.Time
+}
+
+func NewPbStatusService(c *config.AppConfig, r domain.PbApiRepository) DefaultPbStatusService {
+	return DefaultPbStatusService{
+		repo:    r,
+		cfg:     c,
+		catalog: &statusCatalog{misses: make(map[string]time.Time)},
+	}
+}
+
+// RefreshStatuses reloads the status catalogue and reports statuses that were added, renamed or removed since the last load
+func (ss DefaultPbStatusService) RefreshStatuses() ([]dto.StatusChange, api_error.ApiErr) {
+	statuses, err := ss.repo.GetFeatureStatuses()
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[string]string)
+	for _, s := range statuses {
+		byId[s.ID] = s.Name
+	}
+	ss.catalog.Lock()
+	changes := []dto.StatusChange{}
+	if ss.catalog.loaded {
+		changes = diffStatuses(ss.catalog.byId, byId)
+	}
+	ss.catalog.statuses = statuses
+	ss.catalog.byId = byId
+	ss.catalog.loaded = true
+	if len(changes) > 0 {
+		ss.catalog.misses = make(map[string]time.Time)
+	}
+	ss.catalog.Unlock()
+	for _, c := range changes {
+		switch c.Change {
+		case dto.StatusRenamed:
+			logger.Warn(fmt.Sprintf("Feature status %v was renamed from \"%v\" to \"%v\"", c.ID, c.OldName, c.NewName))
+		case dto.StatusRemoved:
+			logger.Warn(fmt.Sprintf("Feature status \"%v\" (%v) was removed", c.OldName, c.ID))
+		default:
+			logger.Info(fmt.Sprintf("Feature status \"%v\" (%v) was added", c.NewName, c.ID))
+		}
+	}
+	return changes, nil
+}
+
+func (ss DefaultPbStatusService) GetStatuses() ([]dto.FeatureStatus, api_error.ApiErr) {
+	err := ss.ensureLoaded()
+	if err != nil {
+		return nil, err
+	}
+	ss.catalog.RLock()
+	defer ss.catalog.RUnlock()
+	return ss.catalog.statuses, nil
+}
+
+// GetStatusId resolves a status name (case-insensitive) to its ID. Names that are not unique cannot be resolved.
+func (ss DefaultPbStatusService) GetStatusId(name string) (string, api_error.ApiErr) {
+	err := ss.ensureLoaded()
+	if err != nil {
+		return "", err
+	}
+	ids := ss.findByName(name)
+	missKey := "name:" + strings.ToLower(strings.TrimSpace(name))
+	if len(ids) == 0 && ss.refreshOnMiss(missKey) {
+		if _, err := ss.RefreshStatuses(); err != nil {
+			return "", err
+		}
+		ids = ss.findByName(name)
+		if len(ids) == 0 {
+			ss.recordMiss(missKey)
+		}
+	}
+	switch len(ids) {
+	case 0:
+		msg := fmt.Sprintf("No feature status with name \"%v\" found", name)
+		logger.Error(msg, nil)
+		return "", api_error.NewNotFoundError(msg)
+	case 1:
+		return ids[0], nil
+	default:
+		msg := fmt.Sprintf("Feature status name \"%v\" is ambiguous", name)
+		logger.Error(msg, nil)
+		return "", api_error.NewBadRequestError(msg)
+	}
+}
+
+func (ss DefaultPbStatusService) GetStatusName(id string) (string, api_error.ApiErr) {
+	err := ss.ensureLoaded()
+	if err != nil {
+		return "", err
+	}
+	name, found := ss.nameById(id)
+	missKey := "id:" + id
+	if !found && ss.refreshOnMiss(missKey) {
+		if _, err := ss.RefreshStatuses(); err != nil {
+			return "", err
+		}
+		name, found = ss.nameById(id)
+		if !found {
+			ss.recordMiss(missKey)
+		}
+	}
+	if !found {
+		msg := fmt.Sprintf("No feature status with id %v found", id)
+		logger.Error(msg, nil)
+		return "", api_error.NewNotFoundError(msg)
+	}
+	return name, nil
+}
+
+// ValidateStatusNames makes sure every given name resolves to exactly one feature status
+func (ss DefaultPbStatusService) ValidateStatusNames(names []string) api_error.ApiErr {
+	invalid := []string{}
+	for _, name := range names {
+		if _, err := ss.GetStatusId(name); err != nil {
+			if err.StatusCode() >= 500 {
+				return err
+			}
+			invalid = append(invalid, name)
+		}
+	}
+	if len(invalid) > 0 {
+		msg := fmt.Sprintf("Unknown or ambiguous feature status names: \"%v\"", strings.Join(invalid, "\", \""))
+		logger.Error(msg, nil)
+		return api_error.NewBadRequestError(msg)
+	}
+	return nil
+}
+
+// HandleFeatureEvent reloads the catalogue if a feature carries a status that is not known yet
+func (ss DefaultPbStatusService) HandleFeatureEvent(event dto.FeatureEvent) {
+	if event.Feature == nil || event.Feature.Status.ID == "" {
+		return
+	}
+	ss.catalog.RLock()
+	loaded := ss.catalog.loaded
+	_, found := ss.catalog.byId[event.Feature.Status.ID]
+	ss.catalog.RUnlock()
+	if loaded && !found {
+		if _, err := ss.RefreshStatuses(); err != nil {
+			logger.Error("Could not refresh feature statuses", err)
+		}
+	}
+}
+
+func (ss DefaultPbStatusService) ensureLoaded() api_error.ApiErr {
+	ss.catalog.RLock()
+	loaded := ss.catalog.loaded
+	ss.catalog.RUnlock()
+	if loaded {
+		return nil
+	}
+	_, err := ss.RefreshStatuses()
+	return err
+}
+
+// refreshOnMiss tells whether an unknown name or id may reload the catalogue. Without this, every lookup of a
+// misspelled name in a rule or payload would fetch all statuses from the API again.
+func (ss DefaultPbStatusService) refreshOnMiss(key string) bool {
+	ss.catalog.RLock()
+	defer ss.catalog.RUnlock()
+	missedAt, found := ss.catalog.misses[key]
+	return !found || date.GetNowUtc().Sub(missedAt) >= time.Duration(ss.cfg.Statuses.MissRefreshInterval)*time.Second
+}
+
+func (ss DefaultPbStatusService) recordMiss(key string) {
+	ss.catalog.Lock()
+	defer ss.catalog.Unlock()
+	now := date.GetNowUtc()
+	for k, missedAt := range ss.catalog.misses {
+		if now.Sub(missedAt) >= time.Duration(ss.cfg.Statuses.MissRefreshInterval)*time.Second {
+			delete(ss.catalog.misses, k)
+		}
+	}
+	ss.catalog.misses[key] = now
+}
+
+func (ss DefaultPbStatusService) findByName(name string) []string {
+	ss.catalog.RLock()
+	defer ss.catalog.RUnlock()
+	ids := []string{}
+	for _, s := range ss.catalog.statuses {
+		if strings.EqualFold(strings.TrimSpace(name), s.Name) {
+			ids = append(ids, s.ID)
+		}
+	}
+	return ids
+}
+
+func (ss DefaultPbStatusService) nameById(id string) (string, bool) {
+	ss.catalog.RLock()
+	defer ss.catalog.RUnlock()
+	name, found := ss.catalog.byId[id]
+	return name, found
+}
+
+func diffStatuses(old map[string]string, new map[string]string) []dto.StatusChange {
+	changes := []dto.StatusChange{}
+	for id, oldName := range old {
+		newName, found := new[id]
+		if !found {
+			changes = append(changes, dto.StatusChange{ID: id, Change: dto.StatusRemoved, OldName: oldName})
+		} else if newName != oldName {
+			changes = append(changes, dto.StatusChange{ID: id, Change: dto.StatusRenamed, OldName: oldName, NewName: newName})
+		}
+	}
+	for id, newName := range new {
+		if _, found := old[id]; !found {
+			changes = append(changes, dto.StatusChange{ID: id, Change: dto.StatusAdded, NewName: newName})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ss DefaultPbStatusService
+)
+
+func setupStatuses(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	ss = NewPbStatusService(&cfg, mockPbApiRepo)
+	return func() {
+		pbApiCtrl.Finish()
+	}
+}
+
+func testStatuses() []dto.FeatureStatus {
+	return []dto.FeatureStatus{
+		{ID: "s1", Name: "New idea"},
+		{ID: "s2", Name: "In progress"},
+		{ID: "s3", Name: "Done"},
+		{ID: "s4", Name: "Done"},
+	}
+}
+
+func Test_GetStatusId_KnownName_Returns_Id(t *testing.T) {
+	teardown := setupStatuses(t)
+	defer teardown()
+
+	mockPbApiRepo.EXPECT().GetFeatureStatuses().Return(testStatuses(), nil)
+
+	id, err := ss.GetStatusId("in Progress")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "s2", id)
+}
+
+func Test_GetStatusId_UnknownName_Refreshes_Returns_NotFoundError(t *testing.T) {
+	teardown := setupStatuses(t)
+	defer teardown()
+
+	mockPbApiRepo.EXPECT().GetFeatureStatuses().Return(testStatuses(), nil).Times(2)
+
+	id, err := ss.GetStatusId("Shipped")
+
+	assert.EqualValues(t, "", id)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+}
+
+func Test_GetStatusId_UnknownNameAgain_DoesNotRefresh(t *testing.T) {
+	teardown := setupStatuses(t)
+	defer teardown()
+	cfg.Statuses.MissRefreshInterval = 300
+	defer func() { cfg.Statuses.MissRefreshInterval = 0 }()
+
+	mockPbApiRepo.EXPECT().GetFeatureStatuses().Return(testStatuses(), nil).Times(3)
+
+	_, err1 := ss.GetStatusId("Shipped")
+	_, err2 := ss.GetStatusId("shipped ")
+	_, err3 := ss.GetStatusName("s9")
+	_, err4 := ss.GetStatusName("s9")
+
+	assert.EqualValues(t, http.StatusNotFound, err1.StatusCode())
+	assert.EqualValues(t, http.StatusNotFound, err2.StatusCode())
+	assert.EqualValues(t, http.StatusNotFound, err3.StatusCode())
+	assert.EqualValues(t, http.StatusNotFound, err4.StatusCode())
+}
+
+func Test_GetStatusId_AmbiguousName_Returns_BadRequestError(t *testing.T) {
+	teardown := setupStatuses(t)
+	defer teardown()
+
+	mockPbApiRepo.EXPECT().GetFeatureStatuses().Return(testStatuses(), nil)
+
+	_, err := ss.GetStatusId("Done")
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Feature status name \"Done\" is ambiguous", err.Message())
+}
+
+func Test_GetStatusName_Returns_Name(t *testing.T) {
+	teardown := setupStatuses(t)
+	defer teardown()
+
+	mockPbApiRepo.EXPECT().GetFeatureStatuses().Return(testStatuses(), nil)
+
+	name, err := ss.GetStatusName("s1")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "New idea", name)
+}
+
+func Test_RefreshStatuses_Detects_Changes(t *testing.T) {
+	teardown := setupStatuses(t)
+	defer teardown()
+	changed := []dto.FeatureStatus{
+		{ID: "s1", Name: "Idea"},
+		{ID: "s2", Name: "In progress"},
+		{ID: "s5", Name: "Released"},
+	}
+
+	mockPbApiRepo.EXPECT().GetFeatureStatuses().Return(testStatuses()[:3], nil)
+	mockPbApiRepo.EXPECT().GetFeatureStatuses().Return(changed, nil)
+
+	first, err1 := ss.RefreshStatuses()
+	second, err2 := ss.RefreshStatuses()
+
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.EqualValues(t, 0, len(first))
+	assert.ElementsMatch(t, []dto.StatusChange{
+		{ID: "s1", Change: dto.StatusRenamed, OldName: "New idea", NewName: "Idea"},
+		{ID: "s3", Change: dto.StatusRemoved, OldName: "Done"},
+		{ID: "s5", Change: dto.StatusAdded, NewName: "Released"},
+	}, second)
+}
+
+func Test_ValidateStatusNames_Typo_Returns_BadRequestError(t *testing.T) {
+	teardown := setupStatuses(t)
+	defer teardown()
+
+	mockPbApiRepo.EXPECT().GetFeatureStatuses().Return(testStatuses(), nil).Times(2)
+
+	err := ss.ValidateStatusNames([]string{"New idea", "In porgress"})
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Unknown or ambiguous feature status names: \"In porgress\"", err.Message())
+}
+
+func Test_ValidateStatusNames_RepoFails_Returns_Error(t *testing.T) {
+	teardown := setupStatuses(t)
+	defer teardown()
+	apiError := api_error.NewInternalServerError("something went wrong", nil)
+
+	mockPbApiRepo.EXPECT().GetFeatureStatuses().Return(nil, apiError)
+
+	err := ss.ValidateStatusNames([]string{"New idea"})
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, apiError.Message(), err.Message())
+}
+
+func Test_HandleFeatureEvent_UnknownStatus_Refreshes_Catalog(t *testing.T) {
+	teardown := setupStatuses(t)
+	defer teardown()
+	event := dto.FeatureEvent{ID: "f1", Feature: &dto.Feature{ID: "f1", Status: dto.FeatureStatus{ID: "s9"}}}
+
+	mockPbApiRepo.EXPECT().GetFeatureStatuses().Return(testStatuses(), nil).Times(2)
+
+	ss.GetStatuses()
+	ss.HandleFeatureEvent(event)
+}
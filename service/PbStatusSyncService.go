@@ -0,0 +1,207 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbStatusSyncService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbStatusSyncService
+type PbStatusSyncService interface {
+	HandleFeatureEvent(dto.FeatureEvent)
+	HandleTrackerEvent(dto.TrackerEvent) api_error.ApiErr
+	MappedStatusNames() []string
+}
+
+// DefaultPbStatusSyncService keeps the status of features and their linked tracker issues in sync. Writes of
+// its own are remembered so the notifications they cause are not synced back. Conflicts are detected when a
+// tracker change arrives while the feature status has changed since the last sync as well.
+type DefaultPbStatusSyncService struct {
+	repo          domain.PbApiRepository
+	tracker       domain.IssueTrackerRepository
+	links         PbTrackerService
+	statuses      PbStatusService
+	cfg           *config.AppConfig
+	mapping       statusMapping
+	pbEchoes      *echoGuard
+	trackerEchoes *echoGuard
+	states        *syncStates
+}
+
+type syncStates struct {
+	sync.Mutex
+	byFeature map[string]syncState
+}
+
+type syncState struct {
+	PbStatus      string
+	TrackerStatus string
+	SyncedAt      time.Time
+}
+
+func NewPbStatusSyncService(c *config.AppConfig, r domain.PbApiRepository, t domain.IssueTrackerRepository, l PbTrackerService, s PbStatusService) (DefaultPbStatusSyncService, api_error.ApiErr) {
+	window := time.Duration(c.StatusSync.EchoWindow) * time.Second
+	ss := DefaultPbStatusSyncService{
+		repo:          r,
+		tracker:       t,
+		links:         l,
+		statuses:      s,
+		cfg:           c,
+		mapping:       newStatusMapping(c.StatusSync.Mapping),
+		pbEchoes:      newEchoGuard(window),
+		trackerEchoes: newEchoGuard(window),
+		states: &syncStates{
+			byFeature: make(map[string]syncState),
+		},
+	}
+	if len(ss.mapping.invalid) > 0 {
+		msg := fmt.Sprintf("Invalid status mapping entries: %v", strings.Join(ss.mapping.invalid, ", "))
+		logger.Error(msg, nil)
+		return ss, api_error.NewInternalServerError(msg, nil)
+	}
+	if c.StatusSync.ConflictWinner != dto.ConflictProductboardWins && c.StatusSync.ConflictWinner != dto.ConflictTrackerWins {
+		msg := fmt.Sprintf("Unknown status conflict winner %v", c.StatusSync.ConflictWinner)
+		logger.Error(msg, nil)
+		return ss, api_error.NewInternalServerError(msg, nil)
+	}
+	return ss, nil
+}
+
+func (ss DefaultPbStatusSyncService) MappedStatusNames() []string {
+	return ss.mapping.productboardNames()
+}
+
+// HandleFeatureEvent pushes the status of a linked feature to its issue when it changed in Productboard
+func (ss DefaultPbStatusSyncService) HandleFeatureEvent(event dto.FeatureEvent) {
+	if event.Feature == nil {
+		return
+	}
+	link, found := ss.links.GetLink(event.ID)
+	if !found {
+		return
+	}
+	current := event.Feature.Status.Name
+	trackerStatus, mapped := ss.mapping.toTracker(current)
+	if ss.pbEchoes.isEcho(event.ID, current) {
+		logger.Info(fmt.Sprintf("Ignoring echo of status %v written to feature %v", current, event.ID))
+		ss.recordSync(event.ID, current, trackerStatus)
+		return
+	}
+	state, known := ss.state(event.ID)
+	if known && strings.EqualFold(state.PbStatus, current) {
+		return
+	}
+	if !mapped {
+		logger.Warn(fmt.Sprintf("No tracker status mapped to status %v of feature %v", current, event.ID))
+		return
+	}
+	if known && state.TrackerStatus == trackerStatus {
+		ss.recordSync(event.ID, current, trackerStatus)
+		return
+	}
+	if err := ss.updateIssue(link.Key, *event.Feature, trackerStatus); err != nil {
+		logger.Error(fmt.Sprintf("Could not sync status of feature %v to issue %v", event.ID, link.Key), err)
+	}
+}
+
+// HandleTrackerEvent applies the status of an issue to its linked feature. Events older than the last sync are ignored.
+func (ss DefaultPbStatusSyncService) HandleTrackerEvent(event dto.TrackerEvent) api_error.ApiErr {
+	featureId, found := ss.links.GetFeatureId(event.Key)
+	if !found {
+		msg := fmt.Sprintf("No feature linked to issue %v", event.Key)
+		logger.Error(msg, nil)
+		return api_error.NewNotFoundError(msg)
+	}
+	if ss.trackerEchoes.isEcho(event.Key, event.Status) {
+		logger.Info(fmt.Sprintf("Ignoring echo of status %v written to issue %v", event.Status, event.Key))
+		return nil
+	}
+	state, known := ss.state(featureId)
+	if known && !event.UpdatedAt.IsZero() && event.UpdatedAt.Before(state.SyncedAt) {
+		logger.Info(fmt.Sprintf("Ignoring stale status %v of issue %v", event.Status, event.Key))
+		return nil
+	}
+	pbStatus, found := ss.mapping.toProductboard(event.Status)
+	if !found {
+		msg := fmt.Sprintf("No Productboard status mapped to tracker status %v", event.Status)
+		logger.Error(msg, nil)
+		return api_error.NewBadRequestError(msg)
+	}
+	feature, err := ss.repo.GetFeature(featureId)
+	if err != nil {
+		return err
+	}
+	current := feature.Status.Name
+	if strings.EqualFold(current, pbStatus) {
+		ss.recordSync(featureId, current, event.Status)
+		return nil
+	}
+	if known && !strings.EqualFold(current, state.PbStatus) {
+		if ss.cfg.StatusSync.ConflictWinner == dto.ConflictProductboardWins {
+			logger.Warn(fmt.Sprintf("Status of feature %v and issue %v both changed, keeping %v from Productboard", featureId, event.Key, current))
+			trackerStatus, mapped := ss.mapping.toTracker(current)
+			if !mapped {
+				logger.Warn(fmt.Sprintf("No tracker status mapped to status %v of feature %v", current, featureId))
+				return nil
+			}
+			return ss.updateIssue(event.Key, *feature, trackerStatus)
+		}
+		logger.Warn(fmt.Sprintf("Status of feature %v and issue %v both changed, keeping %v from tracker", featureId, event.Key, event.Status))
+	}
+	return ss.updateFeature(featureId, pbStatus, event.Status)
+}
+
+func (ss DefaultPbStatusSyncService) updateIssue(key string, feature dto.Feature, trackerStatus string) api_error.ApiErr {
+	issue := featureToIssue(feature, ss.mapping)
+	ss.trackerEchoes.expect(key, trackerStatus)
+	if err := ss.tracker.UpdateIssue(key, issue); err != nil {
+		ss.trackerEchoes.forget(key)
+		return err
+	}
+	logger.Info(fmt.Sprintf("Set status of issue %v to %v", key, trackerStatus))
+	ss.recordSync(feature.ID, feature.Status.Name, trackerStatus)
+	return nil
+}
+
+func (ss DefaultPbStatusSyncService) updateFeature(featureId string, pbStatus string, trackerStatus string) api_error.ApiErr {
+	statusId, err := ss.statuses.GetStatusId(pbStatus)
+	if err != nil {
+		return err
+	}
+	ss.pbEchoes.expect(featureId, pbStatus)
+	update := dto.FeatureUpdate{
+		Status: &dto.StatusRef{ID: statusId},
+	}
+	if _, err := ss.repo.UpdateFeature(featureId, update); err != nil {
+		ss.pbEchoes.forget(featureId)
+		return err
+	}
+	logger.Info(fmt.Sprintf("Set status of feature %v to %v", featureId, pbStatus))
+	ss.recordSync(featureId, pbStatus, trackerStatus)
+	return nil
+}
+
+func (ss DefaultPbStatusSyncService) state(featureId string) (syncState, bool) {
+	ss.states.Lock()
+	defer ss.states.Unlock()
+	state, found := ss.states.byFeature[featureId]
+	return state, found
+}
+
+func (ss DefaultPbStatusSyncService) recordSync(featureId string, pbStatus string, trackerStatus string) {
+	ss.states.Lock()
+	defer ss.states.Unlock()
+	ss.states.byFeature[featureId] = syncState{
+		PbStatus:      pbStatus,
+		TrackerStatus: trackerStatus,
+		SyncedAt:      date.GetNowUtc(),
+	}
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/repository"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	sys          DefaultPbStatusSyncService
+	syncStatuses = []dto.FeatureStatus{{ID: "s1", Name: "Planned"}, {ID: "s2", Name: "In progress"}, {ID: "s3", Name: "Released"}}
+)
+
+func setupStatusSync(t *testing.T) func() {
+	teardown := setupTracker(t)
+	cfg.StatusSync.Mapping = []string{"Planned=todo", "In progress=doing", "Released=done"}
+	cfg.StatusSync.ConflictWinner = dto.ConflictProductboardWins
+	cfg.StatusSync.EchoWindow = 60
+	ts, _ = NewPbTrackerService(&cfg, mockPbApiRepo, tracker, repository.NewIssueLinkRepository(filepath.Join(t.TempDir(), "links.json")))
+	var err api_error.ApiErr
+	sys, err = NewPbStatusSyncService(&cfg, mockPbApiRepo, tracker, ts, NewPbStatusService(&cfg, mockPbApiRepo))
+	assert.Nil(t, err)
+	return func() {
+		cfg.StatusSync.Mapping = nil
+		teardown()
+	}
+}
+
+func linkedFeature(t *testing.T, status string) dto.Feature {
+	feature := dto.Feature{ID: "f1", Name: "Dark mode", Status: dto.FeatureStatus{Name: status}}
+	mockPbApiRepo.EXPECT().GetFeature("f1").Return(&feature, nil)
+	_, err := ts.PushFeature(pushAction())
+	assert.Nil(t, err)
+	return feature
+}
+
+func featureEvent(feature dto.Feature) dto.FeatureEvent {
+	return dto.FeatureEvent{
+		ID:        feature.ID,
+		EventType: dto.PbEventTypes["featureUpdate"],
+		Feature:   &feature,
+	}
+}
+
+func Test_NewPbStatusSyncService_InvalidMapping_Returns_Error(t *testing.T) {
+	teardown := setupStatusSync(t)
+	defer teardown()
+	cfg.StatusSync.Mapping = []string{"Planned=todo", "broken"}
+
+	_, err := NewPbStatusSyncService(&cfg, mockPbApiRepo, tracker, ts, NewPbStatusService(&cfg, mockPbApiRepo))
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Invalid status mapping entries: broken", err.Message())
+}
+
+func Test_statusMapping_Translates_Both_Ways(t *testing.T) {
+	mapping := newStatusMapping([]string{"Planned=todo", " Next = todo "})
+
+	toTracker, found := mapping.toTracker("planned")
+	toPb, _ := mapping.toProductboard("TODO")
+	_, unknown := mapping.toTracker("Released")
+
+	assert.True(t, found)
+	assert.EqualValues(t, "todo", toTracker)
+	assert.EqualValues(t, "Planned", toPb)
+	assert.False(t, unknown)
+	assert.EqualValues(t, []string{"Planned", "Next"}, mapping.productboardNames())
+}
+
+func Test_HandleFeatureEvent_StatusChange_Updates_Issue(t *testing.T) {
+	teardown := setupStatusSync(t)
+	defer teardown()
+	feature := linkedFeature(t, "Planned")
+	feature.Status.Name = "In progress"
+
+	sys.HandleFeatureEvent(featureEvent(feature))
+
+	issue, _ := tracker.GetIssue("MEM-1")
+	assert.EqualValues(t, "doing", issue.Status)
+}
+
+func Test_HandleFeatureEvent_UnlinkedFeature_Is_Ignored(t *testing.T) {
+	teardown := setupStatusSync(t)
+	defer teardown()
+
+	sys.HandleFeatureEvent(featureEvent(dto.Feature{ID: "f2", Status: dto.FeatureStatus{Name: "Released"}}))
+
+	_, found := tracker.GetIssue("MEM-1")
+	assert.False(t, found)
+}
+
+func Test_HandleTrackerEvent_UnknownIssue_Returns_NotFoundError(t *testing.T) {
+	teardown := setupStatusSync(t)
+	defer teardown()
+
+	err := sys.HandleTrackerEvent(dto.TrackerEvent{Key: "MEM-9", Status: "done"})
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "No feature linked to issue MEM-9", err.Message())
+}
+
+func Test_HandleTrackerEvent_UnmappedStatus_Returns_BadRequestError(t *testing.T) {
+	teardown := setupStatusSync(t)
+	defer teardown()
+	linkedFeature(t, "Planned")
+
+	err := sys.HandleTrackerEvent(dto.TrackerEvent{Key: "MEM-1", Status: "wontfix"})
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "No Productboard status mapped to tracker status wontfix", err.Message())
+}
+
+func Test_HandleTrackerEvent_StatusChange_Updates_Feature_And_Ignores_Echo(t *testing.T) {
+	teardown := setupStatusSync(t)
+	defer teardown()
+	feature := linkedFeature(t, "Planned")
+	mockPbApiRepo.EXPECT().GetFeature("f1").Return(&feature, nil)
+	mockPbApiRepo.EXPECT().GetFeatureStatuses().Return(syncStatuses, nil)
+	mockPbApiRepo.EXPECT().UpdateFeature("f1", dto.FeatureUpdate{Status: &dto.StatusRef{ID: "s3"}}).Return(&feature, nil)
+
+	err := sys.HandleTrackerEvent(dto.TrackerEvent{Key: "MEM-1", Status: "done"})
+	feature.Status.Name = "Released"
+	sys.HandleFeatureEvent(featureEvent(feature))
+
+	assert.Nil(t, err)
+	issue, _ := tracker.GetIssue("MEM-1")
+	assert.EqualValues(t, "todo", issue.Status)
+}
+
+func Test_HandleTrackerEvent_EchoOfOwnWrite_Is_Ignored(t *testing.T) {
+	teardown := setupStatusSync(t)
+	defer teardown()
+	feature := linkedFeature(t, "Planned")
+	feature.Status.Name = "In progress"
+	sys.HandleFeatureEvent(featureEvent(feature))
+
+	err := sys.HandleTrackerEvent(dto.TrackerEvent{Key: "MEM-1", Status: "doing"})
+
+	assert.Nil(t, err)
+}
+
+func Test_HandleTrackerEvent_EchoInOtherCase_Is_Ignored(t *testing.T) {
+	teardown := setupStatusSync(t)
+	defer teardown()
+	feature := linkedFeature(t, "Planned")
+	feature.Status.Name = "In progress"
+	sys.HandleFeatureEvent(featureEvent(feature))
+
+	err := sys.HandleTrackerEvent(dto.TrackerEvent{Key: "MEM-1", Status: "DOING"})
+
+	assert.Nil(t, err)
+}
+
+func Test_HandleTrackerEvent_Conflict_ProductboardWins(t *testing.T) {
+	teardown := setupStatusSync(t)
+	defer teardown()
+	feature := linkedFeature(t, "Planned")
+	feature.Status.Name = "In progress"
+	sys.HandleFeatureEvent(featureEvent(feature))
+	changed := feature
+	changed.Status.Name = "Released"
+	mockPbApiRepo.EXPECT().GetFeature("f1").Return(&changed, nil)
+
+	err := sys.HandleTrackerEvent(dto.TrackerEvent{Key: "MEM-1", Status: "todo"})
+
+	assert.Nil(t, err)
+	issue, _ := tracker.GetIssue("MEM-1")
+	assert.EqualValues(t, "done", issue.Status)
+}
+
+func Test_HandleTrackerEvent_Conflict_TrackerWins(t *testing.T) {
+	teardown := setupStatusSync(t)
+	defer teardown()
+	cfg.StatusSync.ConflictWinner = dto.ConflictTrackerWins
+	feature := linkedFeature(t, "Planned")
+	feature.Status.Name = "In progress"
+	sys.HandleFeatureEvent(featureEvent(feature))
+	changed := feature
+	changed.Status.Name = "Released"
+	mockPbApiRepo.EXPECT().GetFeature("f1").Return(&changed, nil)
+	mockPbApiRepo.EXPECT().GetFeatureStatuses().Return(syncStatuses, nil)
+	mockPbApiRepo.EXPECT().UpdateFeature("f1", dto.FeatureUpdate{Status: &dto.StatusRef{ID: "s1"}}).Return(&changed, nil)
+
+	err := sys.HandleTrackerEvent(dto.TrackerEvent{Key: "MEM-1", Status: "todo"})
+
+	assert.Nil(t, err)
+}
+
+func Test_HandleTrackerEvent_StaleEvent_Is_Ignored(t *testing.T) {
+	teardown := setupStatusSync(t)
+	defer teardown()
+	feature := linkedFeature(t, "Planned")
+	feature.Status.Name = "In progress"
+	sys.HandleFeatureEvent(featureEvent(feature))
+
+	err := sys.HandleTrackerEvent(dto.TrackerEvent{Key: "MEM-1", Status: "done", UpdatedAt: time.Now().Add(-time.Hour)})
+
+	assert.Nil(t, err)
+}
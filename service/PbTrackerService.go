@@ -0,0 +1,180 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/johannes-kuhfuss/pbreact/config"
+	"github.com/johannes-kuhfuss/pbreact/domain"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+//go:generate mockgen -destination=../mocks/service/mockPbTrackerService.go -package=service github.com/johannes-kuhfuss/pbreact/service PbTrackerService
+type PbTrackerService interface {
+	PushFeature(dto.ActionNotification) (*dto.Connection, api_error.ApiErr)
+	UnlinkFeature(dto.ActionNotification) (*dto.Connection, api_error.ApiErr)
+	DismissFeature(dto.ActionNotification) (*dto.Connection, api_error.ApiErr)
+	GetLink(string) (*dto.IssueRef, bool)
+	GetFeatureId(string) (string, bool)
+}
+
+type DefaultPbTrackerService struct {
+	repo      domain.PbApiRepository
+	tracker   domain.IssueTrackerRepository
+	linkStore domain.IssueLinkRepository
+	cfg       *config.AppConfig
+	mapping   statusMapping
+	links     *issueLinks
+}
+
+type issueLinks struct {
+	sync.RWMutex
+	byFeature map[string]dto.IssueRef
+	byIssue   map[string]string
+	// pushing holds the features an issue is being created for; the channel is closed once the push is done
+	pushing map[string]chan bool
+}
+
+// NewPbTrackerService loads the links pushed before, so status sync and pushes keep working across restarts
+func NewPbTrackerService(c *config.AppConfig, r domain.PbApiRepository, t domain.IssueTrackerRepository, l domain.IssueLinkRepository) (DefaultPbTrackerService, api_error.ApiErr) {
+	ts := DefaultPbTrackerService{
+		repo:      r,
+		tracker:   t,
+		linkStore: l,
+		cfg:       c,
+		mapping:   newStatusMapping(c.StatusSync.Mapping),
+		links: &issueLinks{
+			byFeature: make(map[string]dto.IssueRef),
+			byIssue:   make(map[string]string),
+			pushing:   make(map[string]chan bool),
+		},
+	}
+	saved, err := l.Load()
+	if err != nil {
+		return ts, err
+	}
+	for featureId, link := range saved {
+		ts.links.byFeature[featureId] = link
+		ts.links.byIssue[link.Key] = featureId
+	}
+	return ts, nil
+}
+
+// PushFeature creates a tracker issue from the feature behind a push button and links it to the feature.
+// Features that are already linked keep their issue. A push while another one for the same feature is still
+// running waits for it, so clicking twice does not create two issues.
+func (ts DefaultPbTrackerService) PushFeature(action dto.ActionNotification) (*dto.Connection, api_error.ApiErr) {
+	featureId := action.Feature.ID
+	for {
+		ts.links.Lock()
+		if link, found := ts.links.byFeature[featureId]; found {
+			ts.links.Unlock()
+			logger.Info(fmt.Sprintf("Feature %v is already linked to issue %v", featureId, link.Key))
+			return issueConnection(link), nil
+		}
+		running, busy := ts.links.pushing[featureId]
+		if !busy {
+			ts.links.pushing[featureId] = make(chan bool)
+			ts.links.Unlock()
+			break
+		}
+		ts.links.Unlock()
+		<-running
+	}
+	defer func() {
+		ts.links.Lock()
+		close(ts.links.pushing[featureId])
+		delete(ts.links.pushing, featureId)
+		ts.links.Unlock()
+	}()
+	feature, err := ts.repo.GetFeature(featureId)
+	if err != nil {
+		return nil, err
+	}
+	link, err := ts.tracker.CreateIssue(featureToIssue(*feature, ts.mapping))
+	if err != nil {
+		return nil, err
+	}
+	ts.links.Lock()
+	ts.links.byFeature[featureId] = *link
+	ts.links.byIssue[link.Key] = featureId
+	ts.saveLinks()
+	ts.links.Unlock()
+	logger.Info(fmt.Sprintf("Linked feature %v to issue %v", featureId, link.Key))
+	return issueConnection(*link), nil
+}
+
+// UnlinkFeature removes the link between a feature and its issue. The issue itself is left untouched.
+func (ts DefaultPbTrackerService) UnlinkFeature(action dto.ActionNotification) (*dto.Connection, api_error.ApiErr) {
+	ts.links.Lock()
+	defer ts.links.Unlock()
+	if link, found := ts.links.byFeature[action.Feature.ID]; found {
+		logger.Info(fmt.Sprintf("Unlinked feature %v from issue %v", action.Feature.ID, link.Key))
+		delete(ts.links.byFeature, action.Feature.ID)
+		delete(ts.links.byIssue, link.Key)
+		ts.saveLinks()
+	}
+	return &dto.Connection{
+		State: dto.ConnectionInitial,
+	}, nil
+}
+
+// DismissFeature clears the error a failed push left on the feature by resetting its connection to the initial state
+func (ts DefaultPbTrackerService) DismissFeature(action dto.ActionNotification) (*dto.Connection, api_error.ApiErr) {
+	logger.Info(fmt.Sprintf("Dismissed connection state of feature %v", action.Feature.ID))
+	return &dto.Connection{
+		State: dto.ConnectionInitial,
+	}, nil
+}
+
+func (ts DefaultPbTrackerService) GetLink(featureId string) (*dto.IssueRef, bool) {
+	ts.links.RLock()
+	defer ts.links.RUnlock()
+	link, found := ts.links.byFeature[featureId]
+	if !found {
+		return nil, false
+	}
+	return &link, true
+}
+
+// GetFeatureId looks up the feature an issue is linked to
+func (ts DefaultPbTrackerService) GetFeatureId(issueKey string) (string, bool) {
+	ts.links.RLock()
+	defer ts.links.RUnlock()
+	featureId, found := ts.links.byIssue[issueKey]
+	return featureId, found
+}
+
+// saveLinks persists the links; the lock has to be held. A failed save is logged only, the link exists in the tracker anyway.
+func (ts DefaultPbTrackerService) saveLinks() {
+	if err := ts.linkStore.Save(ts.links.byFeature); err != nil {
+		logger.Error("Could not save issue links", err)
+	}
+}
+
+// featureToIssue builds the tracker view of a feature. Unmapped statuses are passed on by their Productboard name.
+func featureToIssue(feature dto.Feature, mapping statusMapping) dto.Issue {
+	status, found := mapping.toTracker(feature.Status.Name)
+	if !found {
+		status = feature.Status.Name
+	}
+	return dto.Issue{
+		Title:       feature.Name,
+		Description: feature.Description,
+		FeatureId:   feature.ID,
+		FeatureUrl:  feature.Links.Html,
+		Status:      status,
+	}
+}
+
+func issueConnection(link dto.IssueRef) *dto.Connection {
+	return &dto.Connection{
+		State:      dto.ConnectionConnected,
+		Label:      link.Key,
+		HoverLabel: fmt.Sprintf("Open %v", link.Key),
+		Tooltip:    fmt.Sprintf("Linked to issue %v", link.Key),
+		TargetUrl:  link.Url,
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/pbreact/dto"
+	"github.com/johannes-kuhfuss/pbreact/mocks/domain"
+	"github.com/johannes-kuhfuss/pbreact/repository"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ts      DefaultPbTrackerService
+	tracker repository.MemoryTrackerRepository
+)
+
+func setupTracker(t *testing.T) func() {
+	pbApiCtrl = gomock.NewController(t)
+	mockPbApiRepo = domain.NewMockPbApiRepository(pbApiCtrl)
+	tracker = repository.NewMemoryTrackerRepository()
+	ts, _ = NewPbTrackerService(&cfg, mockPbApiRepo, tracker, repository.NewIssueLinkRepository(filepath.Join(t.TempDir(), "links.json")))
+	return func() {
+		pbApiCtrl.Finish()
+	}
+}
+
+func Test_PushFeature_Creates_Issue_And_Returns_Connected(t *testing.T) {
+	teardown := setupTracker(t)
+	defer teardown()
+	feature := dto.Feature{ID: "f1", Name: "Dark mode", Description: "<p>Please</p>", Status: dto.FeatureStatus{Name: "Planned"}}
+	mockPbApiRepo.EXPECT().GetFeature("f1").Return(&feature, nil)
+
+	conn, err := ts.PushFeature(pushAction())
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, dto.ConnectionConnected, conn.State)
+	assert.EqualValues(t, "MEM-1", conn.Label)
+	assert.EqualValues(t, "memory://issues/MEM-1", conn.TargetUrl)
+	issue, closed := tracker.GetIssue("MEM-1")
+	assert.False(t, closed)
+	assert.EqualValues(t, "Dark mode", issue.Title)
+	assert.EqualValues(t, "Planned", issue.Status)
+}
+
+func Test_PushFeature_AlreadyLinked_Returns_ExistingIssue(t *testing.T) {
+	teardown := setupTracker(t)
+	defer teardown()
+	mockPbApiRepo.EXPECT().GetFeature("f1").Return(&dto.Feature{ID: "f1", Name: "Dark mode"}, nil).Times(1)
+	ts.PushFeature(pushAction())
+
+	conn, err := ts.PushFeature(pushAction())
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "MEM-1", conn.Label)
+}
+
+func Test_PushFeature_Twice_At_Once_Creates_OneIssue(t *testing.T) {
+	teardown := setupTracker(t)
+	defer teardown()
+	mockPbApiRepo.EXPECT().GetFeature("f1").DoAndReturn(func(string) (*dto.Feature, api_error.ApiErr) {
+		time.Sleep(50 * time.Millisecond)
+		return &dto.Feature{ID: "f1", Name: "Dark mode"}, nil
+	}).Times(1)
+	labels := make([]string, 2)
+	var wg sync.WaitGroup
+
+	for i := range labels {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			conn, _ := ts.PushFeature(pushAction())
+			labels[i] = conn.Label
+		}(i)
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, []string{"MEM-1", "MEM-1"}, labels)
+	second, _ := tracker.GetIssue("MEM-2")
+	assert.Nil(t, second)
+}
+
+func Test_PushFeature_FeatureError_Returns_Error(t *testing.T) {
+	teardown := setupTracker(t)
+	defer teardown()
+	apiError := api_error.NewNotFoundError("feature not found")
+	mockPbApiRepo.EXPECT().GetFeature("f1").Return(nil, apiError)
+
+	conn, err := ts.PushFeature(pushAction())
+
+	assert.Nil(t, conn)
+	assert.EqualValues(t, apiError, err)
+	_, found := ts.GetLink("f1")
+	assert.False(t, found)
+}
+
+func Test_UnlinkFeature_Removes_Link(t *testing.T) {
+	teardown := setupTracker(t)
+	defer teardown()
+	mockPbApiRepo.EXPECT().GetFeature("f1").Return(&dto.Feature{ID: "f1", Name: "Dark mode"}, nil)
+	ts.PushFeature(pushAction())
+
+	conn, err := ts.UnlinkFeature(dto.ActionNotification{Trigger: dto.TriggerUnlink, Feature: dto.ActionFeature{ID: "f1"}})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, dto.ConnectionInitial, conn.State)
+	_, found := ts.GetLink("f1")
+	assert.False(t, found)
+	_, closed := tracker.GetIssue("MEM-1")
+	assert.False(t, closed)
+}
+
+func Test_NewPbTrackerService_Loads_SavedLinks(t *testing.T) {
+	teardown := setupTracker(t)
+	defer teardown()
+	links := repository.NewIssueLinkRepository(filepath.Join(t.TempDir(), "links.json"))
+	first, _ := NewPbTrackerService(&cfg, mockPbApiRepo, tracker, links)
+	mockPbApiRepo.EXPECT().GetFeature("f1").Return(&dto.Feature{ID: "f1", Name: "Dark mode"}, nil).Times(1)
+	first.PushFeature(pushAction())
+
+	restarted, err := NewPbTrackerService(&cfg, mockPbApiRepo, tracker, links)
+	conn, _ := restarted.PushFeature(pushAction())
+	featureId, found := restarted.GetFeatureId("MEM-1")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "MEM-1", conn.Label)
+	assert.True(t, found)
+	assert.EqualValues(t, "f1", featureId)
+}
+
+func Test_UnlinkFeature_Removes_SavedLink(t *testing.T) {
+	teardown := setupTracker(t)
+	defer teardown()
+	links := repository.NewIssueLinkRepository(filepath.Join(t.TempDir(), "links.json"))
+	ts, _ = NewPbTrackerService(&cfg, mockPbApiRepo, tracker, links)
+	mockPbApiRepo.EXPECT().GetFeature("f1").Return(&dto.Feature{ID: "f1", Name: "Dark mode"}, nil)
+	ts.PushFeature(pushAction())
+
+	ts.UnlinkFeature(dto.ActionNotification{Trigger: dto.TriggerUnlink, Feature: dto.ActionFeature{ID: "f1"}})
+	saved, _ := links.Load()
+	_, found := ts.GetFeatureId("MEM-1")
+
+	assert.EqualValues(t, 0, len(saved))
+	assert.False(t, found)
+}
+
+func Test_DismissFeature_Returns_InitialConnection(t *testing.T) {
+	teardown := setupTracker(t)
+	defer teardown()
+
+	conn, err := ts.DismissFeature(dto.ActionNotification{Trigger: dto.TriggerDismiss, Feature: dto.ActionFeature{ID: "f1"}})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, dto.Connection{State: dto.ConnectionInitial}, *conn)
+}
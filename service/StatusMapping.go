@@ -0,0 +1,51 @@
+package service
+
+import (
+	"strings"
+)
+
+// statusMapping translates between Productboard status names and tracker statuses. It is configured as an
+// ordered list of "productboard=tracker" pairs; the first pair matching a tracker status wins on the way back.
+type statusMapping struct {
+	pairs   [][2]string
+	invalid []string
+}
+
+func newStatusMapping(entries []string) statusMapping {
+	mapping := statusMapping{}
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			mapping.invalid = append(mapping.invalid, entry)
+			continue
+		}
+		mapping.pairs = append(mapping.pairs, [2]string{strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])})
+	}
+	return mapping
+}
+
+func (m statusMapping) toTracker(pbStatus string) (string, bool) {
+	for _, pair := range m.pairs {
+		if strings.EqualFold(pair[0], pbStatus) {
+			return pair[1], true
+		}
+	}
+	return "", false
+}
+
+func (m statusMapping) toProductboard(trackerStatus string) (string, bool) {
+	for _, pair := range m.pairs {
+		if strings.EqualFold(pair[1], trackerStatus) {
+			return pair[0], true
+		}
+	}
+	return "", false
+}
+
+func (m statusMapping) productboardNames() []string {
+	names := []string{}
+	for _, pair := range m.pairs {
+		names = append(names, pair[0])
+	}
+	return names
+}